@@ -5,37 +5,117 @@ import (
 	"backend/internal/db"
 	"backend/internal/handler"
 	"backend/internal/middleware"
+	"backend/internal/realtime"
 	"backend/internal/repo"
 	"backend/internal/server"
+	"backend/internal/server/router/access"
+	"backend/internal/server/router/authz"
+	"backend/internal/server/router/calendar"
+	"backend/internal/server/router/candidate"
+	"backend/internal/server/router/careers"
+	"backend/internal/server/router/comment"
+	"backend/internal/server/router/interview"
 	"backend/internal/server/router/invite"
+	"backend/internal/server/router/job"
+	"backend/internal/server/router/notification"
+	"backend/internal/server/router/pipeline"
+	"backend/internal/server/router/role"
+	"backend/internal/server/router/scorecard"
+	"backend/internal/server/router/team"
 	"backend/internal/server/router/user"
 	"backend/internal/usecase"
+	"backend/internal/worker"
 	"backend/pkg/config"
 	"backend/pkg/hash"
 	"backend/pkg/logger"
+	"backend/pkg/mailer"
+	"backend/pkg/ocr"
+	"backend/pkg/profile"
 	"backend/pkg/rbac"
+	"backend/pkg/storage"
 	"backend/pkg/svc"
 	"backend/pkg/token"
 	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 )
 
 type repos struct {
-	user   repo.UserRepository
-	invite repo.InviteRepository
+	user      repo.UserRepository
+	invite    repo.InviteRepository
+	role      repo.RoleRepository
+	access    repo.AccessRepository
+	activity  repo.ActivityRepository
+	job       repo.JobRepository
+	team      repo.TeamRepository
+	candidate repo.CandidateRepository
+	task      repo.TaskRepository
+	profile   repo.ProfileRepository
+	board     repo.BoardRepository
+	pipeline  repo.PipelineRepository
+	duplicate repo.DuplicateRepository
+	search    repo.SearchRepository
+	imports   repo.ImportRepository
+	comment   repo.CommentRepository
+	notify    repo.NotificationRepository
+	scorecard repo.ScorecardRepository
+	interview repo.InterviewRepository
+	timeline  repo.TimelineRepository
 }
 
 type usecases struct {
-	auth   usecase.AuthUseCase
-	invite usecase.InviteUseCase
+	auth       usecase.AuthUseCase
+	invite     usecase.InviteUseCase
+	role       usecase.RoleUseCase
+	authz      usecase.AuthzUseCase
+	job        usecase.JobUseCase
+	access     usecase.AccessUseCase
+	team       usecase.TeamUseCase
+	careers    usecase.CareersUseCase
+	apply      usecase.ApplicationUseCase
+	candidate  usecase.CandidateUseCase
+	extraction usecase.ExtractionUseCase
+	profile    usecase.ProfileUseCase
+	board      usecase.BoardUseCase
+	pipeline   usecase.PipelineUseCase
+	events     usecase.EventsUseCase
+	duplicate  usecase.DuplicateUseCase
+	search     usecase.SearchUseCase
+	imports    usecase.ImportUseCase
+	comment    usecase.CommentUseCase
+	notify     usecase.NotificationUseCase
+	scorecard  usecase.ScorecardUseCase
+	interview  usecase.InterviewUseCase
+	timeline   usecase.TimelineUseCase
 }
 
 type handlers struct {
-	auth   *handler.AuthHandler
-	invite *handler.InviteHandler
+	auth      *handler.AuthHandler
+	invite    *handler.InviteHandler
+	role      *handler.RoleHandler
+	authz     *handler.AuthzHandler
+	job       *handler.JobHandler
+	access    *handler.AccessHandler
+	team      *handler.TeamHandler
+	careers   *handler.CareersHandler
+	apply     *handler.ApplicationHandler
+	candidate *handler.CandidateHandler
+	profile   *handler.ProfileHandler
+	board     *handler.BoardHandler
+	pipeline  *handler.PipelineHandler
+	events    *handler.EventsHandler
+	duplicate *handler.DuplicateHandler
+	search    *handler.SearchHandler
+	imports   *handler.ImportHandler
+	comment   *handler.CommentHandler
+	notify    *handler.NotificationHandler
+	scorecard *handler.ScorecardHandler
+	interview *handler.InterviewHandler
+	timeline  *handler.TimelineHandler
 }
 
 type infrastructureComponents struct {
@@ -44,6 +124,11 @@ type infrastructureComponents struct {
 	pool      *db.PostgresClient
 	redisPool *db.RedisClient
 	casbin    *rbac.CasbinClient
+	storage   storage.Storage
+	ocr       ocr.Engine
+	extractor profile.Extractor
+	mailer    mailer.Mailer
+	hub       *realtime.Hub
 }
 
 type utilityComponents struct {
@@ -75,16 +160,23 @@ func run(ctx context.Context) error {
 
 	repos := initRepositories(infra)
 	usecases := initUseCases(infra, utils, repos)
-	handlers, sessionMiddleware := initHandlers(infra, utils, usecases)
+	handlers, sessionMiddleware := initHandlers(infra, utils, repos, usecases)
 
-	apiServer := createApiServer(ctx, infra.cfg, utils.t, infra.log, handlers, sessionMiddleware)
+	apiServer := createApiServer(ctx, infra, utils.t, handlers, sessionMiddleware)
+	extractionWorker := worker.NewExtraction(infra.log.Log, &infra.cfg.Worker, infra.pool, usecases.extraction)
+	importWorker := worker.NewImport(infra.log.Log, &infra.cfg.Worker, infra.pool, usecases.imports)
+	interviewMailWorker := worker.NewInterviewMail(infra.log.Log, &infra.cfg.Worker, infra.pool, usecases.interview)
 
 	if err := svc.Run(ctx, infra.log.Log, []svc.Service{
 		infra.log,
 		infra.pool,
 		infra.redisPool,
 		infra.casbin,
+		infra.hub,
 		apiServer,
+		extractionWorker,
+		importWorker,
+		interviewMailWorker,
 	}); err != nil {
 		return fmt.Errorf("run service error: %w", err)
 	}
@@ -117,7 +209,32 @@ func initInfrastructure() (*infrastructureComponents, error) {
 		return nil, fmt.Errorf("create redis error: %w", err)
 	}
 
-	casbinClient := rbac.NewCasbinClient(zapLog.Log, pool.ConnConfig(), "casbin/model.conf")
+	casbinClient := rbac.NewCasbinClient(
+		zapLog.Log,
+		pool.ConnConfig(),
+		"casbin/model.conf",
+		rbac.WithPolicySync(conf.Casbin.PolicySync),
+	)
+
+	fileStorage, err := storage.New(conf.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("create storage error: %w", err)
+	}
+
+	ocrEngine, err := ocr.New(conf.OCR)
+	if err != nil {
+		return nil, fmt.Errorf("create ocr engine error: %w", err)
+	}
+
+	extractor, err := profile.NewExtractor(conf.LLM)
+	if err != nil {
+		return nil, fmt.Errorf("create profile extractor error: %w", err)
+	}
+
+	mailSender, err := mailer.New(conf.Mailer)
+	if err != nil {
+		return nil, fmt.Errorf("create mailer error: %w", err)
+	}
 
 	return &infrastructureComponents{
 		cfg:       conf,
@@ -125,6 +242,11 @@ func initInfrastructure() (*infrastructureComponents, error) {
 		pool:      pool,
 		redisPool: redisPool,
 		casbin:    casbinClient,
+		storage:   fileStorage,
+		ocr:       ocrEngine,
+		extractor: extractor,
+		mailer:    mailSender,
+		hub:       realtime.NewHub(zapLog.Log, &conf.Realtime, pool),
 	}, nil
 }
 
@@ -151,38 +273,104 @@ func initUtilities(infra *infrastructureComponents) (*utilityComponents, error)
 
 func initRepositories(infra *infrastructureComponents) repos {
 	return repos{
-		user:   repo.NewUserRepo(infra.pool),
-		invite: repo.NewInviteRepo(infra.pool),
+		user:      repo.NewUserRepo(infra.pool),
+		invite:    repo.NewInviteRepo(infra.pool),
+		role:      repo.NewRoleRepo(infra.pool),
+		access:    repo.NewAccessRepo(infra.pool),
+		activity:  repo.NewActivityRepo(infra.pool),
+		job:       repo.NewJobRepo(infra.pool),
+		team:      repo.NewTeamRepo(infra.pool),
+		candidate: repo.NewCandidateRepo(infra.pool),
+		task:      repo.NewTaskRepo(infra.pool),
+		profile:   repo.NewProfileRepo(infra.pool),
+		board:     repo.NewBoardRepo(infra.pool),
+		pipeline:  repo.NewPipelineRepo(infra.pool),
+		duplicate: repo.NewDuplicateRepo(infra.pool),
+		search:    repo.NewSearchRepo(infra.pool),
+		imports:   repo.NewImportRepo(infra.pool),
+		comment:   repo.NewCommentRepo(infra.pool),
+		notify:    repo.NewNotificationRepo(infra.pool),
+		scorecard: repo.NewScorecardRepo(infra.pool),
+		interview: repo.NewInterviewRepo(infra.pool),
+		timeline:  repo.NewTimelineRepo(infra.pool),
 	}
 }
 
 func initUseCases(infra *infrastructureComponents, utils *utilityComponents, r repos) usecases {
+	careers := usecase.NewCareersUseCase(&infra.cfg.Careers, r.team, r.job, utils.cacheManager)
+
 	return usecases{
-		auth:   usecase.NewAuthUseCase(r.user, utils.cacheManager, utils.t, utils.h, infra.casbin),
-		invite: usecase.NewInviteUseCase(infra.cfg, r.invite, utils.cacheManager, utils.t, utils.h, infra.casbin),
+		auth:       usecase.NewAuthUseCase(r.user, utils.cacheManager, utils.t, utils.h, infra.casbin),
+		invite:     usecase.NewInviteUseCase(infra.cfg, r.invite, utils.cacheManager, utils.t, utils.h, infra.casbin),
+		role:       usecase.NewRoleUseCase(r.role, infra.casbin),
+		authz:      usecase.NewAuthzUseCase(r.role, r.access, r.activity, infra.casbin),
+		job:        usecase.NewJobUseCase(r.job, infra.casbin, utils.cacheManager),
+		access:     usecase.NewAccessUseCase(r.access, r.role, infra.casbin),
+		team:       usecase.NewTeamUseCase(r.team, utils.cacheManager),
+		careers:    careers,
+		apply:      usecase.NewApplicationUseCase(r.candidate, r.pipeline, careers, infra.storage),
+		candidate:  usecase.NewCandidateUseCase(&infra.cfg.Storage, r.candidate, r.pipeline, infra.storage),
+		extraction: usecase.NewExtractionUseCase(infra.cfg, r.task, infra.storage, infra.ocr, infra.extractor),
+		profile:    usecase.NewProfileUseCase(r.profile),
+		board:      usecase.NewBoardUseCase(r.board, r.pipeline),
+		pipeline:   usecase.NewPipelineUseCase(r.pipeline, infra.casbin),
+		events:     usecase.NewEventsUseCase(&infra.cfg.Realtime, infra.hub, r.access, infra.casbin),
+		duplicate:  usecase.NewDuplicateUseCase(r.duplicate, r.candidate, infra.storage),
+		search:     usecase.NewSearchUseCase(r.search),
+		imports:    usecase.NewImportUseCase(infra.cfg, r.imports, r.pipeline, infra.storage),
+		comment:    usecase.NewCommentUseCase(r.comment, infra.casbin),
+		notify:     usecase.NewNotificationUseCase(r.notify),
+		scorecard:  usecase.NewScorecardUseCase(r.scorecard, r.pipeline),
+		interview:  usecase.NewInterviewUseCase(infra.cfg, r.interview, infra.casbin, infra.mailer),
+		timeline:   usecase.NewTimelineUseCase(r.timeline),
 	}
 }
 
-func initHandlers(infra *infrastructureComponents, utils *utilityComponents, u usecases) (handlers, middleware.Middleware) {
+func initHandlers(infra *infrastructureComponents, utils *utilityComponents, r repos, u usecases) (handlers, middleware.Middleware) {
 	middleware := middleware.NewMiddleware(
 		infra.log,
 		infra.redisPool,
 		utils.cacheManager,
 		infra.casbin,
+		r.access,
+		r.activity,
 	)
 
 	h := handlers{
-		auth:   handler.NewAuthHandler(&infra.cfg.Server, infra.log.Log, u.auth),
-		invite: handler.NewInviteHandler(&infra.cfg.Server, infra.log.Log, u.invite),
+		auth:      handler.NewAuthHandler(&infra.cfg.Server, infra.log.Log, u.auth),
+		invite:    handler.NewInviteHandler(&infra.cfg.Server, infra.log.Log, u.invite),
+		role:      handler.NewRoleHandler(&infra.cfg.Server, infra.log.Log, u.role),
+		authz:     handler.NewAuthzHandler(&infra.cfg.Server, infra.log.Log, u.authz),
+		job:       handler.NewJobHandler(&infra.cfg.Server, infra.log.Log, u.job),
+		access:    handler.NewAccessHandler(&infra.cfg.Server, infra.log.Log, u.access),
+		team:      handler.NewTeamHandler(&infra.cfg.Server, infra.log.Log, u.team),
+		careers:   handler.NewCareersHandler(&infra.cfg.Server, infra.log.Log, u.careers),
+		apply:     handler.NewApplicationHandler(&infra.cfg.Server, infra.log.Log, &infra.cfg.Storage, u.apply),
+		candidate: handler.NewCandidateHandler(&infra.cfg.Server, infra.log.Log, u.candidate),
+		profile:   handler.NewProfileHandler(&infra.cfg.Server, infra.log.Log, u.profile),
+		board:     handler.NewBoardHandler(&infra.cfg.Server, infra.log.Log, u.board),
+		pipeline:  handler.NewPipelineHandler(&infra.cfg.Server, infra.log.Log, u.pipeline),
+		events:    handler.NewEventsHandler(&infra.cfg.Server, infra.log.Log, &infra.cfg.Realtime, u.events),
+		duplicate: handler.NewDuplicateHandler(&infra.cfg.Server, infra.log.Log, u.duplicate),
+		search:    handler.NewSearchHandler(&infra.cfg.Server, infra.log.Log, u.search),
+		imports:   handler.NewImportHandler(&infra.cfg.Server, infra.log.Log, &infra.cfg.Import, u.imports),
+		comment:   handler.NewCommentHandler(&infra.cfg.Server, infra.log.Log, u.comment),
+		notify:    handler.NewNotificationHandler(&infra.cfg.Server, infra.log.Log, u.notify),
+		scorecard: handler.NewScorecardHandler(&infra.cfg.Server, infra.log.Log, u.scorecard),
+		interview: handler.NewInterviewHandler(&infra.cfg.Server, infra.log.Log, u.interview),
+		timeline:  handler.NewTimelineHandler(&infra.cfg.Server, infra.log.Log, u.timeline),
 	}
 
 	return h, middleware
 }
 
-func createApiServer(ctx context.Context, cfg *config.Config, t *token.JWTtoken, log *logger.Log, h handlers, middleware middleware.Middleware) *server.Api {
+func createApiServer(ctx context.Context, infra *infrastructureComponents, t *token.JWTtoken, h handlers, middleware middleware.Middleware) *server.Api {
+	cfg := infra.cfg
+
 	return server.NewApiServer(
 		&cfg.Server,
-		server.WithLogger(log.Log),
+		server.WithLogger(infra.log.Log),
+		server.WithHealthCheck("/api/v1/health", infra.pool, infra.redisPool, infra.casbin),
 		server.WithRouterGroup(ctx, "/auth",
 			user.NewRouter(
 				h.auth,
@@ -198,9 +386,167 @@ func createApiServer(ctx context.Context, cfg *config.Config, t *token.JWTtoken,
 				middleware.RBAC(),
 			),
 		),
+		server.WithRouterGroup(ctx, "/roles",
+			role.NewRouter(
+				h.role,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/jobs",
+			job.NewRouter(
+				h.job,
+				h.board,
+				h.pipeline,
+				h.events,
+				h.scorecard,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/candidates",
+			candidate.NewRouter(
+				h.candidate,
+				h.profile,
+				h.board,
+				h.duplicate,
+				h.search,
+				h.imports,
+				h.timeline,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/comments",
+			comment.NewRouter(
+				h.comment,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/scorecards",
+			scorecard.NewRouter(
+				h.scorecard,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/interviews",
+			interview.NewRouter(
+				h.interview,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/calendar",
+			calendar.NewRouter(
+				h.interview,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				feedLimit(cfg, middleware),
+				middleware.Session(t),
+			),
+		),
+		server.WithRouterGroup(ctx, "/notifications",
+			notification.NewRouter(
+				h.notify,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+			),
+		),
+		server.WithRouterGroup(ctx, "/pipelines",
+			pipeline.NewRouter(
+				h.pipeline,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/access",
+			access.NewRouter(
+				h.access,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/team",
+			team.NewRouter(
+				h.team,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
+		server.WithRouterGroup(ctx, "/careers",
+			careers.NewRouter(
+				h.careers,
+				h.apply,
+				middleware.RateLimit(cfg.RateLimit["public"]),
+				applyLimits(cfg, middleware)...,
+			),
+		),
+		server.WithRouterGroup(ctx, "/rbac",
+			authz.NewRouter(
+				h.authz,
+				middleware.RateLimit(cfg.RateLimit["api"]),
+				middleware.Session(t),
+				middleware.RBAC(),
+			),
+		),
 	)
 }
 
+// feedLimit guards the public calendar feeds with counters of their own, so
+// calendar applications polling feeds do not use up the careers limit.
+func feedLimit(cfg *config.Config, m middleware.Middleware) echo.MiddlewareFunc {
+	return m.RateLimit(cfg.RateLimit["public"], middleware.WithScope("calendar"))
+}
+
+// defaultApplyLimits are used when the config has no "apply" or
+// "apply-email" limit; an empty limit would reject every application.
+var defaultApplyLimits = map[string]config.RateLimit{
+	"apply":       {Requests: 10, Window: time.Hour},
+	"apply-email": {Requests: 3, Window: 24 * time.Hour},
+}
+
+// applyLimits guards the public application form: a body size limit, a
+// per-IP limit and a per-email limit, each with its own counters.
+func applyLimits(cfg *config.Config, m middleware.Middleware) []echo.MiddlewareFunc {
+	return []echo.MiddlewareFunc{
+		m.BodyLimit(maxUploadSize(&cfg.Storage)),
+		m.RateLimit(applyLimit(cfg, "apply"), middleware.WithScope("apply")),
+		m.RateLimit(
+			applyLimit(cfg, "apply-email"),
+			middleware.WithScope("apply_email"),
+			middleware.WithKey(middleware.FormValueKey("email")),
+		),
+	}
+}
+
+func applyLimit(cfg *config.Config, name string) config.RateLimit {
+	if limit, ok := cfg.RateLimit[name]; ok && limit.Requests > 0 && limit.Window > 0 {
+		return limit
+	}
+
+	return defaultApplyLimits[name]
+}
+
+// maxUploadSize is the request body limit for uploads: the file limit plus
+// room for the other multipart fields.
+func maxUploadSize(cfg *config.Storage) int64 {
+	if cfg.MaxFileSize > 0 {
+		return cfg.MaxFileSize + 1<<20
+	}
+
+	return handler.DefaultMaxFileSize + 1<<20
+}
+
 func loadKey(path string) (jwk.Key, error) {
 	keySet, err := jwk.ReadFile(path, jwk.WithPEM(true))
 	if err != nil {
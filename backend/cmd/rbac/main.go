@@ -0,0 +1,200 @@
+// Command rbac manages the Casbin policies seeded from casbin/policy.csv.
+//
+// Usage:
+//
+//	rbac [flags] validate         check policy.csv against model.conf
+//	rbac [flags] diff             show changes between policy.csv and the database
+//	rbac [flags] apply [-prune]   add missing rules and remove retired ones;
+//	                              with -prune also remove stored managed rules
+//	                              absent from policy.csv
+//
+// Only rules in the "*" domain are managed by the file. Team-specific grants
+// (role assignments, custom roles) are never touched. Rules listed in
+// casbin/policy_retired.csv were dropped from policy.csv and are removed
+// from the database without -prune, as the API does on startup.
+package main
+
+import (
+	"backend/internal/db"
+	"backend/pkg/config"
+	"backend/pkg/logger"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "path to the application config")
+	modelPath := flag.String("model", "casbin/model.conf", "path to the Casbin model")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] validate|diff|apply [-prune]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), *configPath, *modelPath, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatalf("rbac: %v", err)
+	}
+}
+
+func run(ctx context.Context, configPath, modelPath, command string, args []string) error {
+	switch command {
+	case "validate":
+		return validate(modelPath)
+	case "diff":
+		return withClient(ctx, configPath, modelPath, func(c *rbac.CasbinClient) error {
+			diff, err := loadDiff(c)
+			if err != nil {
+				return err
+			}
+
+			printDiff(diff)
+
+			return nil
+		})
+	case "apply":
+		fs := flag.NewFlagSet("apply", flag.ExitOnError)
+		prune := fs.Bool("prune", false, "remove stored managed rules that are absent from policy.csv")
+		_ = fs.Parse(args)
+
+		return withClient(ctx, configPath, modelPath, func(c *rbac.CasbinClient) error {
+			diff, err := loadDiff(c)
+			if err != nil {
+				return err
+			}
+
+			if !*prune {
+				diff.Removed = nil
+			}
+
+			printDiff(diff)
+
+			if err := c.ApplyPolicy(diff, *prune); err != nil {
+				return fmt.Errorf("apply policy: %w", err)
+			}
+
+			fmt.Printf("applied: %d added, %d removed\n", len(diff.Added), len(diff.Removed)+len(diff.Retired))
+
+			return nil
+		})
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+func validate(modelPath string) error {
+	policyPath := rbac.PolicyPathFor(modelPath)
+
+	rules, err := rbac.ReadPolicyFile(policyPath)
+	if err != nil {
+		return err
+	}
+
+	if err := rbac.ValidatePolicy(modelPath, rules); err != nil {
+		return fmt.Errorf("invalid policy file:\n%w", err)
+	}
+
+	fmt.Printf("%s: %d rules OK\n", policyPath, len(rules))
+
+	retiredPath := rbac.RetiredPolicyPathFor(modelPath)
+
+	retired, err := rbac.ReadRetiredPolicy(retiredPath)
+	if err != nil {
+		return err
+	}
+
+	if err := errors.Join(rbac.ValidatePolicy(modelPath, retired), rbac.ValidateRetired(rules, retired)); err != nil {
+		return fmt.Errorf("invalid retired policy file:\n%w", err)
+	}
+
+	fmt.Printf("%s: %d retired rules OK\n", retiredPath, len(retired))
+
+	return nil
+}
+
+func loadDiff(c *rbac.CasbinClient) (rbac.PolicyDiff, error) {
+	rules, err := rbac.ReadPolicyFile(c.PolicyPath())
+	if err != nil {
+		return rbac.PolicyDiff{}, err
+	}
+
+	if err := rbac.ValidatePolicy(c.ModelPath(), rules); err != nil {
+		return rbac.PolicyDiff{}, fmt.Errorf("invalid policy file:\n%w", err)
+	}
+
+	retired, err := rbac.ReadRetiredPolicy(c.RetiredPolicyPath())
+	if err != nil {
+		return rbac.PolicyDiff{}, err
+	}
+
+	if err := errors.Join(rbac.ValidatePolicy(c.ModelPath(), retired), rbac.ValidateRetired(rules, retired)); err != nil {
+		return rbac.PolicyDiff{}, fmt.Errorf("invalid retired policy file:\n%w", err)
+	}
+
+	d, err := c.DiffPolicy(rules, retired)
+	if err != nil {
+		return rbac.PolicyDiff{}, fmt.Errorf("diff policy: %w", err)
+	}
+
+	return d, nil
+}
+
+func printDiff(d rbac.PolicyDiff) {
+	if d.Empty() {
+		fmt.Println("no changes")
+		return
+	}
+
+	for _, r := range d.Added {
+		fmt.Printf("+ %s\n", r)
+	}
+
+	for _, r := range d.Removed {
+		fmt.Printf("- %s\n", r)
+	}
+
+	for _, r := range d.Retired {
+		fmt.Printf("- %s (retired)\n", r)
+	}
+}
+
+// withClient connects to the policy store without syncing policy.csv,
+// so the command decides what gets written.
+func withClient(ctx context.Context, configPath, modelPath string, f func(c *rbac.CasbinClient) error) error {
+	conf, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	zapLog, err := logger.New(
+		logger.WithLevel(conf.Logger.Level),
+		logger.WithStdOut(true),
+	)
+	if err != nil {
+		return fmt.Errorf("create logger: %w", err)
+	}
+
+	pool, err := db.NewDb(zapLog.Log, conf)
+	if err != nil {
+		return fmt.Errorf("create db: %w", err)
+	}
+
+	c := rbac.NewCasbinClient(zapLog.Log, pool.ConnConfig(), modelPath, rbac.WithPolicySync(rbac.PolicySyncNone))
+
+	// Stop closes the adapter and both watcher connections; the CLI never
+	// runs the listener, so nothing else would release them.
+	if err := c.Init(ctx); err != nil {
+		return errors.Join(fmt.Errorf("init casbin: %w", err), c.Stop(ctx))
+	}
+
+	return errors.Join(f(c), c.Stop(ctx))
+}
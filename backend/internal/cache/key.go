@@ -28,4 +28,8 @@ func (k Key[T]) WithTTL(ttl time.Duration) Key[T] {
 var (
 	SessionKey   = NewKey[domain.Session]("session")
 	RateLimitKey = NewKey[int64]("rate_limit")
+	// CareersKey — публичная страница вакансий по ID организации.
+	CareersKey = NewKey[domain.CareersBoard]("careers")
+	// CareersSlugKey — ID организации по slug страницы вакансий.
+	CareersSlugKey = NewKey[string]("careers_slug")
 )
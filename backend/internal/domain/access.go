@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// JobAccess describes how the caller relates to a job referenced by a route.
+type JobAccess struct {
+	JobID   string `db:"job_id"`
+	TeamID  string `db:"team_id"`
+	Granted bool   `db:"granted"`
+}
+
+// JobScope restricts list queries to the jobs a caller may see.
+// It is computed by middleware.RBAC and stored in the request context.
+type JobScope struct {
+	UserID  string
+	TeamID  string
+	AllJobs bool
+}
+
+// Per-job roles stored in hiring.t_job_access.roles. They describe what a
+// member does on the job; visibility comes from the grant itself.
+const (
+	JobRoleInterviewer   = "interviewer"
+	JobRoleCoordinator   = "coordinator"
+	JobRoleDecisionMaker = "decision_maker"
+)
+
+// JobRoles is the catalogue of per-job roles.
+var JobRoles = []string{JobRoleInterviewer, JobRoleCoordinator, JobRoleDecisionMaker}
+
+// JobGrant is a row in hiring.t_job_access joined with the member and job.
+type JobGrant struct {
+	JobID     string    `json:"job_id" db:"job_id"`
+	JobTitle  string    `json:"job_title" db:"job_title"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Email     string    `json:"email" db:"email"`
+	FirstName string    `json:"first_name" db:"first_name"`
+	LastName  string    `json:"last_name" db:"last_name"`
+	Roles     []string  `json:"roles" db:"roles"`
+	GrantedBy *string   `json:"granted_by,omitempty" db:"granted_by"`
+	GrantedAt time.Time `json:"granted_at" db:"granted_at"`
+}
+
+// Member is a team member as shown in access listings.
+type Member struct {
+	ID        string `json:"id" db:"id"`
+	Email     string `json:"email" db:"email"`
+	FirstName string `json:"first_name" db:"first_name"`
+	LastName  string `json:"last_name" db:"last_name"`
+	Role      string `json:"role" db:"role"`
+}
+
+// JobMembers lists who can see a job: explicit grants plus members whose
+// role carries the jobs:all permission.
+type JobMembers struct {
+	Grants  []JobGrant `json:"grants"`
+	AllJobs []Member   `json:"all_jobs"`
+}
+
+// MemberJobs lists the jobs a member can see. When AllJobs is set the member
+// sees every job of the team and Grants only carries per-job roles.
+type MemberJobs struct {
+	AllJobs bool       `json:"all_jobs"`
+	Grants  []JobGrant `json:"grants"`
+}
+
+// GrantParams is the input DTO for granting job access.
+type GrantParams struct {
+	TeamID    string
+	UserID    string
+	GrantedBy string
+	Roles     []string
+}
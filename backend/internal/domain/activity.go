@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// Actor types mirror the actor_type enum.
+const (
+	ActorAI     = "ai_agent"
+	ActorSystem = "system"
+	ActorUser   = "user"
+)
+
+// Action codes stored in hiring.t_action_types.
+const (
+	ActionAccessDenied     = "access_denied"
+	ActionJobStatusChanged = "job_status_changed"
+	ActionCandidateApplied = "candidate_applied"
+	ActionCandidateCreated = "candidate_created"
+	ActionCandidateUpdated = "candidate_updated"
+	ActionCandidateDeleted = "candidate_deleted"
+	ActionCandidateMerged  = "candidate_merged"
+
+	ActionCandidateImportStarted = "candidate_import_started"
+	ActionCandidateImported      = "candidate_imported"
+
+	ActionDuplicateDismissed = "duplicate_dismissed"
+
+	ActionProfileCorrected           = "profile_corrected"
+	ActionProfileExtractionRequested = "profile_extraction_requested"
+
+	ActionPipelineChanged = "pipeline_changed"
+
+	ActionScorecardTemplateChanged = "scorecard_template_changed"
+
+	ActionInterviewScheduled   = "interview_scheduled"
+	ActionInterviewRescheduled = "interview_rescheduled"
+	ActionInterviewCancelled   = "interview_cancelled"
+)
+
+// Activity is a row in hiring.t_activity_logs joined with its action code
+// and, for user actors, the actor's name.
+type Activity struct {
+	ID        string         `json:"id" db:"id"`
+	TeamID    string         `json:"-" db:"team_id"`
+	ActorType string         `json:"actor_type" db:"actor_type"`
+	ActorID   *string        `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName string         `json:"actor_name,omitempty" db:"actor_name"`
+	Action    string         `json:"action" db:"action"`
+	TargetID  *string        `json:"target_id,omitempty" db:"target_id"`
+	Details   map[string]any `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ActivityFilter selects a page of a team's activity log, newest first.
+type ActivityFilter struct {
+	TeamID string
+	Action string
+	Limit  int
+	Offset int
+}
+
+// AccessCheck describes a request to authorize: an explain request or an
+// open event stream.
+type AccessCheck struct {
+	UserID string
+	TeamID string
+	Path   string
+	Method string
+	JobID  string
+}
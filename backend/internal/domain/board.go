@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// BoardCard is a candidate as shown on the job board.
+type BoardCard struct {
+	ID             string     `json:"id" db:"id"`
+	FirstName      string     `json:"first_name" db:"first_name"`
+	LastName       string     `json:"last_name" db:"last_name"`
+	Email          string     `json:"email" db:"email"`
+	Status         string     `json:"status" db:"status"`
+	KanbanPosition float64    `json:"kanban_position" db:"kanban_position"`
+	MatchScore     *int       `json:"match_score" db:"match_score"`
+	Source         string     `json:"source" db:"source"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// BoardStage is a board column. Total counts all candidates in the stage;
+// Candidates holds the first of them by position. Type is empty for stages
+// that are not part of the job pipeline.
+type BoardStage struct {
+	Stage      string      `json:"stage"`
+	Type       string      `json:"type,omitempty"`
+	Total      int         `json:"total"`
+	Candidates []BoardCard `json:"candidates"`
+}
+
+// Board is the pipeline of a job grouped by stage.
+type Board struct {
+	JobID  string       `json:"job_id"`
+	Stages []BoardStage `json:"stages"`
+}
+
+// CandidateMove places a candidate in Status right after AfterID or right
+// before BeforeID, both cards of that stage, or at the end of the stage when
+// neither is set. Candidates in one of the Blocked stages may not move.
+type CandidateMove struct {
+	CandidateID string
+	Status      string
+	AfterID     string
+	BeforeID    string
+	Blocked     []string
+}
+
+// StatusChange is a row in hiring.t_status_history. ChangedBy is empty for
+// changes made by the system.
+type StatusChange struct {
+	ID            string    `json:"id" db:"id"`
+	CandidateID   string    `json:"candidate_id" db:"candidate_id"`
+	OldStatus     string    `json:"old_status" db:"old_status"`
+	NewStatus     string    `json:"new_status" db:"new_status"`
+	ChangedBy     *string   `json:"changed_by,omitempty" db:"changed_by"`
+	ChangedByName string    `json:"changed_by_name,omitempty" db:"changed_by_name"`
+	ChangedAt     time.Time `json:"changed_at" db:"changed_at"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CandidateStageNew is the first stage of the built-in pipeline.
+const CandidateStageNew = "new"
+
+// Candidate sources stored in hiring.t_candidates.source.
+const (
+	CandidateSourceManual  = "manual"
+	CandidateSourceCareers = "careers"
+	CandidateSourceImport  = "import"
+)
+
+// Candidate is a row in hiring.t_candidates.
+type Candidate struct {
+	ID             string     `json:"id" db:"id"`
+	JobID          string     `json:"job_id" db:"job_id"`
+	FirstName      string     `json:"first_name" db:"first_name"`
+	LastName       string     `json:"last_name" db:"last_name"`
+	Email          string     `json:"email" db:"email"`
+	Phone          string     `json:"phone" db:"phone"`
+	ResumeFileKey  string     `json:"-" db:"resume_file_key"`
+	ResumeFileName string     `json:"resume_file_name,omitempty" db:"resume_file_name"`
+	Status         string     `json:"status" db:"status"`
+	KanbanPosition float64    `json:"kanban_position" db:"kanban_position"`
+	Source         string     `json:"source" db:"source"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// CandidateDetails is a candidate joined with its structured profile from
+// hiring.t_candidate_profiles, its AI score from ai_engine.t_candidate_scores
+// and the state of its resume task. Profile and score are empty until the
+// resume is processed. ParsedByOCR marks text recognized from a scan, which
+// may contain recognition errors.
+type CandidateDetails struct {
+	Candidate
+	Profile          json.RawMessage `json:"profile,omitempty" db:"profile"`
+	MatchScore       *int            `json:"match_score" db:"match_score"`
+	AnalyzedAt       *time.Time      `json:"analyzed_at,omitempty" db:"analyzed_at"`
+	ParsedLanguage   string          `json:"parsed_language,omitempty" db:"parsed_language"`
+	ParsedAt         *time.Time      `json:"parsed_at,omitempty" db:"parsed_at"`
+	ParsedByOCR      bool            `json:"parsed_by_ocr" db:"parsed_by_ocr"`
+	OCRConfidence    *float64        `json:"ocr_confidence,omitempty" db:"ocr_confidence"`
+	ProcessingStatus string          `json:"processing_status,omitempty" db:"processing_status"`
+	ProcessingError  string          `json:"processing_error,omitempty" db:"processing_error"`
+}
+
+// CandidateParams is the input DTO for creating or editing a candidate.
+// JobID and Status are only used on create.
+type CandidateParams struct {
+	JobID     string
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	Status    string
+}
+
+// CandidateFilter selects a page of the candidates visible within Scope.
+// Empty fields do not filter; a score bound excludes unscored candidates.
+type CandidateFilter struct {
+	Scope       JobScope
+	JobID       string
+	Status      string
+	MinScore    *int
+	MaxScore    *int
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Cursor      *Cursor
+	Limit       int
+}
+
+// Application is a submission of the public apply form.
+type Application struct {
+	Slug      string
+	JobID     string
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	FileName  string
+	IP        string
+	UserAgent string
+}
@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// Visibilities of a candidate comment stored in
+// hiring.t_candidate_comments.visibility.
+const (
+	CommentPrivate = "private"
+	CommentTeam    = "team"
+)
+
+// Actions of a comment revision stored in
+// hiring.t_candidate_comment_revisions.action.
+const (
+	RevisionEdited  = "edited"
+	RevisionDeleted = "deleted"
+)
+
+// Mention is a team member mentioned in a comment.
+type Mention struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// Comment is a row in hiring.t_candidate_comments with its author and
+// mentions. Top-level comments carry their replies, oldest first. The body of
+// a deleted comment is empty; it is listed only while it has replies.
+type Comment struct {
+	ID          string     `json:"id" db:"id"`
+	CandidateID string     `json:"candidate_id" db:"candidate_id"`
+	ParentID    *string    `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID    *string    `json:"author_id,omitempty" db:"author_id"`
+	AuthorName  string     `json:"author_name,omitempty" db:"author_name"`
+	Visibility  string     `json:"visibility" db:"visibility"`
+	Body        string     `json:"body" db:"body"`
+	Mentions    []Mention  `json:"mentions" db:"mentions"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	EditedAt    *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Replies     []Comment  `json:"replies,omitempty" db:"-"`
+}
+
+// CommentParams is the input DTO for a new comment. A reply takes the
+// visibility of its thread; Visibility is ignored for it.
+type CommentParams struct {
+	CandidateID string
+	ParentID    string
+	Visibility  string
+	Body        string
+	Mentions    []string
+}
+
+// CommentUpdate is the input DTO for editing a comment. Mentions replaces the
+// mentioned members; the newly added ones are notified.
+type CommentUpdate struct {
+	Body     string
+	Mentions []string
+}
+
+// CommentFilter selects a page of the threads of a candidate visible to the
+// caller, newest first.
+type CommentFilter struct {
+	Scope       JobScope
+	CandidateID string
+	Limit       int
+	Offset      int
+}
+
+// CommentRevision is a row in hiring.t_candidate_comment_revisions: the body
+// a comment had before it was edited or deleted.
+type CommentRevision struct {
+	ID            string    `json:"id" db:"id"`
+	Action        string    `json:"action" db:"action"`
+	Body          string    `json:"body" db:"body"`
+	ChangedBy     *string   `json:"changed_by,omitempty" db:"changed_by"`
+	ChangedByName string    `json:"changed_by_name,omitempty" db:"changed_by_name"`
+	ChangedAt     time.Time `json:"changed_at" db:"changed_at"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Statuses of a suspected duplicate pair stored in
+// hiring.t_candidate_duplicates.status. A merged pair is removed together
+// with the merged candidate.
+const (
+	DuplicatePending   = "pending"
+	DuplicateDismissed = "dismissed"
+)
+
+// Signals that made two candidates look like the same person.
+const (
+	DuplicateReasonEmail  = "email"
+	DuplicateReasonPhone  = "phone"
+	DuplicateReasonName   = "name"
+	DuplicateReasonResume = "resume"
+)
+
+// DuplicateCandidate is one side of a suspected duplicate pair.
+type DuplicateCandidate struct {
+	ID        string `json:"id"`
+	JobID     string `json:"job_id"`
+	JobTitle  string `json:"job_title"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+}
+
+// Duplicate is a row in hiring.t_candidate_duplicates with both candidates.
+// Score ranges from 0 to 1; Reasons lists the signals that matched.
+type Duplicate struct {
+	ID               string             `json:"id" db:"id"`
+	Candidate        DuplicateCandidate `json:"candidate" db:"candidate"`
+	Duplicate        DuplicateCandidate `json:"duplicate" db:"duplicate"`
+	Score            float64            `json:"score" db:"score"`
+	Reasons          []string           `json:"reasons" db:"reasons"`
+	NameSimilarity   *float64           `json:"name_similarity,omitempty" db:"name_similarity"`
+	ResumeSimilarity *float64           `json:"resume_similarity,omitempty" db:"resume_similarity"`
+	Status           string             `json:"status" db:"status"`
+	DetectedAt       time.Time          `json:"detected_at" db:"detected_at"`
+	ResolvedAt       *time.Time         `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy       *string            `json:"resolved_by,omitempty" db:"resolved_by"`
+}
+
+// DuplicateFilter selects a page of the review queue. Only pairs whose both
+// candidates are within Scope are listed; CandidateID limits the queue to the
+// pairs of one candidate.
+type DuplicateFilter struct {
+	Scope       JobScope
+	Status      string
+	JobID       string
+	CandidateID string
+	MinScore    *float64
+	Limit       int
+	Offset      int
+}
+
+// CandidateMerge is a row in hiring.t_candidate_merges: the audit record of
+// MergedID being folded into CandidateID. Snapshot keeps the merged
+// candidate, its profile and score as they were before the merge.
+type CandidateMerge struct {
+	ID           string          `json:"id" db:"id"`
+	CandidateID  *string         `json:"candidate_id" db:"candidate_id"`
+	MergedID     string          `json:"merged_id" db:"merged_id"`
+	MergedJobID  *string         `json:"merged_job_id,omitempty" db:"merged_job_id"`
+	Snapshot     json.RawMessage `json:"snapshot" db:"snapshot"`
+	MergedBy     *string         `json:"merged_by,omitempty" db:"merged_by"`
+	MergedByName string          `json:"merged_by_name,omitempty" db:"merged_by_name"`
+	MergedAt     time.Time       `json:"merged_at" db:"merged_at"`
+}
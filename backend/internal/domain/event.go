@@ -0,0 +1,30 @@
+package domain
+
+// Board event types published on the board_events channel.
+const (
+	EventCandidateCreated = "candidate.created"
+	EventCandidateMoved   = "candidate.moved"
+	EventCandidateScored  = "candidate.scored"
+	EventCandidateDeleted = "candidate.deleted"
+	// EventAccessChanged announces a changed job grant of UserID. It is not
+	// sent to clients.
+	EventAccessChanged = "access.changed"
+	// EventResync tells clients that events may have been missed and the
+	// board should be reloaded.
+	EventResync = "resync"
+)
+
+// BoardEvent is a change of a job board. Fields that do not apply to the
+// event type are empty.
+type BoardEvent struct {
+	Type           string   `json:"type"`
+	JobID          string   `json:"job_id"`
+	CandidateID    string   `json:"candidate_id,omitempty"`
+	UserID         string   `json:"user_id,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	OldStatus      string   `json:"old_status,omitempty"`
+	KanbanPosition *float64 `json:"kanban_position,omitempty"`
+	MatchScore     *int     `json:"match_score,omitempty"`
+	FirstName      string   `json:"first_name,omitempty"`
+	LastName       string   `json:"last_name,omitempty"`
+}
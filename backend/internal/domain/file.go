@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+// Download is a stored file opened for streaming to the client. The caller
+// must close Body.
+type Download struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// Upload is a file received from the client.
+type Upload struct {
+	Name string
+	Size int64
+	Body io.Reader
+}
+
+// SignedURL is a temporary link to download a file directly from storage.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
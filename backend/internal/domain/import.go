@@ -0,0 +1,124 @@
+package domain
+
+import "time"
+
+// Duplicate policies of a candidate import, applied when a row matches a
+// candidate of the job by email or phone. With ImportCreateDuplicates the
+// row is created anyway and the pair lands in the duplicates queue.
+const (
+	ImportSkipDuplicates   = "skip"
+	ImportMergeDuplicates  = "merge"
+	ImportCreateDuplicates = "create"
+)
+
+// Outcomes of an import row stored in hiring.t_candidate_import_rows.outcome.
+const (
+	ImportRowCreated = "created"
+	ImportRowMerged  = "merged"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "failed"
+)
+
+// WorkflowImport is the workflow of a bulk candidate import. Its tasks have
+// workflow_id "import:<import id>".
+const WorkflowImport = "import"
+
+// ImportWorkflowID returns the workflow_id of an import task.
+func ImportWorkflowID(importID string) string {
+	return WorkflowImport + ":" + importID
+}
+
+// CandidateImport is a row in hiring.t_candidate_imports together with the
+// state of its task and the number of rows per outcome. TotalRows is set
+// once the worker has read the files.
+type CandidateImport struct {
+	ID              string     `json:"id" db:"id"`
+	TeamID          string     `json:"-" db:"team_id"`
+	JobID           string     `json:"job_id" db:"job_id"`
+	JobTitle        string     `json:"job_title" db:"job_title"`
+	DuplicatePolicy string     `json:"duplicate_policy" db:"duplicate_policy"`
+	Stage           string     `json:"stage,omitempty" db:"stage"`
+	CSVFileKey      string     `json:"-" db:"csv_file_key"`
+	CSVFileName     string     `json:"csv_file_name,omitempty" db:"csv_file_name"`
+	ArchiveFileKey  string     `json:"-" db:"archive_file_key"`
+	ArchiveFileName string     `json:"archive_file_name,omitempty" db:"archive_file_name"`
+	Status          string     `json:"status" db:"status"`
+	ProgressPercent int        `json:"progress_percent" db:"progress_percent"`
+	ErrorMessage    string     `json:"error_message,omitempty" db:"error_message"`
+	TotalRows       *int       `json:"total_rows" db:"total_rows"`
+	Created         int        `json:"created" db:"created"`
+	Merged          int        `json:"merged" db:"merged"`
+	Skipped         int        `json:"skipped" db:"skipped"`
+	Failed          int        `json:"failed" db:"failed"`
+	CreatedBy       *string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// ImportParams is the input DTO for starting an import. Stage applies to rows
+// without a stage of their own; empty means the first stage of the job.
+type ImportParams struct {
+	JobID           string
+	DuplicatePolicy string
+	Stage           string
+	CSVFileName     string
+	ArchiveFileName string
+}
+
+// ImportFilter selects a page of the imports within Scope, newest first.
+type ImportFilter struct {
+	Scope  JobScope
+	JobID  string
+	Limit  int
+	Offset int
+}
+
+// ImportRow is a row in hiring.t_candidate_import_rows: the outcome of one
+// source row. CandidateID is the created, merged or matching candidate.
+type ImportRow struct {
+	Row         int       `json:"row" db:"row_no"`
+	FileName    string    `json:"file_name,omitempty" db:"file_name"`
+	FirstName   string    `json:"first_name,omitempty" db:"first_name"`
+	LastName    string    `json:"last_name,omitempty" db:"last_name"`
+	Email       string    `json:"email,omitempty" db:"email"`
+	Phone       string    `json:"phone,omitempty" db:"phone"`
+	Outcome     string    `json:"outcome" db:"outcome"`
+	CandidateID *string   `json:"candidate_id,omitempty" db:"candidate_id"`
+	Message     string    `json:"message,omitempty" db:"message"`
+	ProcessedAt time.Time `json:"processed_at" db:"processed_at"`
+}
+
+// ImportRowFilter selects a page of the rows of an import in file order.
+type ImportRowFilter struct {
+	Scope    JobScope
+	ImportID string
+	Outcome  string
+	Limit    int
+	Offset   int
+}
+
+// ImportTask is a claimed import task together with its import. Done is the
+// last row recorded by an earlier attempt.
+type ImportTask struct {
+	TaskID          string  `db:"task_id"`
+	ImportID        string  `db:"import_id"`
+	TeamID          string  `db:"team_id"`
+	JobID           string  `db:"job_id"`
+	CreatedBy       *string `db:"created_by"`
+	DuplicatePolicy string  `db:"duplicate_policy"`
+	Stage           string  `db:"stage"`
+	CSVFileKey      string  `db:"csv_file_key"`
+	ArchiveFileKey  string  `db:"archive_file_key"`
+	TotalRows       *int    `db:"total_rows"`
+	Done            int     `db:"done"`
+	Attempts        int     `db:"attempts"`
+}
+
+// ImportEntry is a source row parsed by the worker. ResumeFileKey is set once
+// the resume of the row has been stored.
+type ImportEntry struct {
+	ImportRow
+	Stage          string
+	ResumeFileKey  string
+	ResumeFileName string
+}
@@ -0,0 +1,157 @@
+package domain
+
+import "time"
+
+// Statuses of an interview.
+const (
+	InterviewScheduled = "scheduled"
+	InterviewCancelled = "cancelled"
+)
+
+// Bounds of an interview.
+const (
+	MinInterviewDuration = 5 * time.Minute
+	MaxInterviewDuration = 8 * time.Hour
+	MaxInterviewers      = 20
+)
+
+// Kinds of interview conflicts. ConflictInterview is another interview of an
+// interviewer, ConflictCandidate another interview of the candidate and
+// ConflictUnavailable a slot outside an interviewer's availability.
+const (
+	ConflictInterview   = "interview"
+	ConflictCandidate   = "candidate"
+	ConflictUnavailable = "unavailable"
+)
+
+// Kinds of invitation emails: the first invitation, an update of the
+// interview, and the cancellation sent when the interview is cancelled or the
+// recipient is no longer invited.
+const (
+	InterviewMailInvite = "invite"
+	InterviewMailUpdate = "update"
+	InterviewMailCancel = "cancel"
+)
+
+// Recipients of invitation emails.
+const (
+	RecipientCandidate   = "candidate"
+	RecipientInterviewer = "interviewer"
+)
+
+// WorkflowInterviewMail is the workflow of sending an invitation email. Its
+// tasks have workflow_id "interview-mail:<mail id>".
+const WorkflowInterviewMail = "interview-mail"
+
+// InterviewMailWorkflowID returns the workflow_id of an email task.
+func InterviewMailWorkflowID(mailID string) string {
+	return WorkflowInterviewMail + ":" + mailID
+}
+
+// Interviewer is a team member taking part in an interview.
+type Interviewer struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// Interview is a row in hiring.t_interviews with its candidate, job and
+// interviewers. Sequence is the iCalendar SEQUENCE of its invitations.
+type Interview struct {
+	ID             string        `json:"id" db:"id"`
+	TeamID         string        `json:"-" db:"team_id"`
+	CandidateID    string        `json:"candidate_id" db:"candidate_id"`
+	CandidateName  string        `json:"candidate_name" db:"candidate_name"`
+	CandidateEmail string        `json:"candidate_email,omitempty" db:"candidate_email"`
+	JobID          string        `json:"job_id" db:"job_id"`
+	JobTitle       string        `json:"job_title" db:"job_title"`
+	Stage          string        `json:"stage" db:"stage"`
+	Title          string        `json:"title" db:"title"`
+	StartsAt       time.Time     `json:"starts_at" db:"starts_at"`
+	EndsAt         time.Time     `json:"ends_at" db:"ends_at"`
+	Timezone       string        `json:"timezone" db:"timezone"`
+	Location       string        `json:"location,omitempty" db:"location"`
+	VideoURL       string        `json:"video_url,omitempty" db:"video_url"`
+	Notes          string        `json:"notes,omitempty" db:"notes"`
+	Status         string        `json:"status" db:"status"`
+	Sequence       int           `json:"sequence" db:"sequence"`
+	CancelReason   string        `json:"cancel_reason,omitempty" db:"cancel_reason"`
+	Interviewers   []Interviewer `json:"interviewers" db:"interviewers"`
+	CreatedBy      *string       `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// InterviewParams is the input DTO for scheduling an interview and, without
+// CandidateID and Stage, for changing one. An empty Stage means the stage the
+// candidate is in and an empty Title a title naming the candidate and the
+// job. Force books the slot despite conflicts; Notify emails invitations to
+// the candidate and the interviewers.
+type InterviewParams struct {
+	CandidateID  string
+	Stage        string
+	Title        string
+	StartsAt     time.Time
+	Duration     time.Duration
+	Timezone     string
+	Location     string
+	VideoURL     string
+	Notes        string
+	Interviewers []string
+	Force        bool
+	Notify       bool
+}
+
+// InterviewFilter selects interviews within Scope. InterviewerID limits them
+// to those of a member; From and To to those overlapping the period.
+type InterviewFilter struct {
+	Scope         JobScope
+	CandidateID   string
+	JobID         string
+	InterviewerID string
+	Status        string
+	From          *time.Time
+	To            *time.Time
+	Limit         int
+	Offset        int
+}
+
+// InterviewConflict is a reason a slot does not suit. UserID is empty for
+// conflicts of the candidate; InterviewID is set for conflicting interviews.
+type InterviewConflict struct {
+	Kind        string    `json:"kind" db:"kind"`
+	UserID      *string   `json:"user_id,omitempty" db:"user_id"`
+	InterviewID *string   `json:"interview_id,omitempty" db:"interview_id"`
+	StartsAt    time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt      time.Time `json:"ends_at" db:"ends_at"`
+}
+
+// AvailabilityWindow is a row in hiring.t_availability_windows: a period in
+// which a member is free for interviews.
+type AvailabilityWindow struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CalendarFeed is the subscription URL of a member's interview calendar.
+// The token is only known when the feed is created.
+type CalendarFeed struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InterviewMailTask is a claimed task sending an invitation email.
+type InterviewMailTask struct {
+	TaskID        string `db:"task_id"`
+	MailID        string `db:"mail_id"`
+	InterviewID   string `db:"interview_id"`
+	Kind          string `db:"kind"`
+	RecipientType string `db:"recipient_type"`
+	Email         string `db:"email"`
+	Name          string `db:"name"`
+	Attempts      int    `db:"attempts"`
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Job lifecycle statuses, enforced by chk_jobs_status.
+const (
+	JobStatusDraft           = "draft"
+	JobStatusPendingApproval = "pending_approval"
+	JobStatusOpen            = "open"
+	JobStatusPaused          = "paused"
+	JobStatusClosed          = "closed"
+	JobStatusFilled          = "filled"
+)
+
+// jobTransitions lists the statuses reachable from each status.
+// closed and filled are final.
+var jobTransitions = map[string][]string{
+	JobStatusDraft:           {JobStatusPendingApproval, JobStatusOpen},
+	JobStatusPendingApproval: {JobStatusOpen, JobStatusDraft},
+	JobStatusOpen:            {JobStatusPaused, JobStatusClosed, JobStatusFilled},
+	JobStatusPaused:          {JobStatusOpen, JobStatusClosed, JobStatusFilled},
+}
+
+// CanTransitionJob reports whether a job may move from one status to another.
+func CanTransitionJob(from, to string) bool {
+	return slices.Contains(jobTransitions[from], to)
+}
+
+// JobAcceptsCandidates reports whether a job in the status takes public
+// applications and runs AI screening.
+func JobAcceptsCandidates(status string) bool {
+	return status == JobStatusOpen
+}
+
+// Work formats mirror the work_format_type enum.
+const (
+	WorkFormatRemote = "remote"
+	WorkFormatOffice = "office"
+	WorkFormatHybrid = "hybrid"
+)
+
+// Job is a row in hiring.t_jobs. Optional text columns are returned as empty
+// strings.
+type Job struct {
+	ID                    string          `json:"id" db:"id"`
+	TeamID                string          `json:"-" db:"team_id"`
+	Title                 string          `json:"title" db:"title"`
+	Department            string          `json:"department" db:"department"`
+	WorkFormat            string          `json:"work_format" db:"work_format"`
+	Description           string          `json:"description" db:"description"`
+	ExtractedRequirements json.RawMessage `json:"extracted_requirements,omitempty" db:"extracted_requirements"`
+	Status                string          `json:"status" db:"status"`
+	CloseReason           string          `json:"close_reason,omitempty" db:"close_reason"`
+	ClosedAt              *time.Time      `json:"closed_at,omitempty" db:"closed_at"`
+	ApprovedBy            *string         `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt            *time.Time      `json:"approved_at,omitempty" db:"approved_at"`
+	PublishedAt           *time.Time      `json:"published_at,omitempty" db:"published_at"`
+	CreatedBy             *string         `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt             time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// JobParams is the input DTO for creating or editing a job. The status is
+// changed only through JobStatusChange.
+type JobParams struct {
+	Title       string
+	Department  string
+	WorkFormat  string
+	Description string
+}
+
+// JobStatusChange is a validated lifecycle transition applied by the repo.
+type JobStatusChange struct {
+	From   string
+	To     string
+	Reason string
+	// ActorID is the member performing the change.
+	ActorID string
+	// Approved marks an opening by a member allowed to approve jobs.
+	Approved bool
+}
+
+// JobSettings are the team-wide job options.
+type JobSettings struct {
+	ApprovalRequired bool `json:"approval_required" db:"job_approval_required"`
+}
+
+// JobFilter selects a page of the jobs visible within Scope.
+// Empty fields do not filter.
+type JobFilter struct {
+	Scope      JobScope
+	Status     string
+	Department string
+	WorkFormat string
+	Limit      int
+	Offset     int
+}
+
+// Page is one page of a list together with the total number of matches.
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Notification types stored in hiring.t_notifications.type.
+const (
+	NotificationMention = "comment.mention"
+	NotificationReply   = "comment.reply"
+)
+
+// Notification is a row in hiring.t_notifications with what the member needs
+// to render it: the actor, the candidate and the start of the comment.
+type Notification struct {
+	ID            string     `json:"id" db:"id"`
+	Type          string     `json:"type" db:"type"`
+	ActorID       *string    `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName     string     `json:"actor_name,omitempty" db:"actor_name"`
+	CandidateID   *string    `json:"candidate_id,omitempty" db:"candidate_id"`
+	CandidateName string     `json:"candidate_name,omitempty" db:"candidate_name"`
+	JobID         *string    `json:"job_id,omitempty" db:"job_id"`
+	CommentID     *string    `json:"comment_id,omitempty" db:"comment_id"`
+	Excerpt       string     `json:"excerpt,omitempty" db:"excerpt"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ReadAt        *time.Time `json:"read_at,omitempty" db:"read_at"`
+}
+
+// NotificationFilter selects a page of a member's notifications, newest
+// first.
+type NotificationFilter struct {
+	UserID string
+	Unread bool
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Stage types mirror the stage_type enum. The type tells the rest of the
+// system what a stage means regardless of what the team calls it.
+const (
+	StageTypeNew       = "new"
+	StageTypeScreening = "screening"
+	StageTypeInterview = "interview"
+	StageTypeOffer     = "offer"
+	StageTypeHired     = "hired"
+	StageTypeRejected  = "rejected"
+)
+
+// StageTypes lists the stage types in their usual pipeline order.
+var StageTypes = []string{
+	StageTypeNew,
+	StageTypeScreening,
+	StageTypeInterview,
+	StageTypeOffer,
+	StageTypeHired,
+	StageTypeRejected,
+}
+
+// Where a job's pipeline comes from.
+const (
+	PipelineSourceJob      = "job"
+	PipelineSourceTemplate = "template"
+	PipelineSourceBuiltin  = "builtin"
+)
+
+// MaxPipelineStages is the most stages a pipeline may have.
+const MaxPipelineStages = 30
+
+// IsStageType reports whether t is one of the stage types.
+func IsStageType(t string) bool {
+	return slices.Contains(StageTypes, t)
+}
+
+// Stage is a row in hiring.t_pipeline_stages. Name is the value stored in
+// hiring.t_candidates.status. Transitions lists the stages a candidate may
+// move to from this one; nil means the default rules apply.
+type Stage struct {
+	ID          string   `json:"id,omitempty" db:"id"`
+	Name        string   `json:"name" db:"name"`
+	Type        string   `json:"type" db:"type"`
+	Position    int      `json:"position" db:"position"`
+	Transitions []string `json:"transitions" db:"transitions"`
+}
+
+// Stages is an ordered pipeline.
+type Stages []Stage
+
+// DefaultStages is the pipeline of teams that have not configured one.
+func DefaultStages() Stages {
+	return Stages{
+		{Name: CandidateStageNew, Type: StageTypeNew, Position: 0},
+		{Name: "screening", Type: StageTypeScreening, Position: 1},
+		{Name: "interview", Type: StageTypeInterview, Position: 2},
+		{Name: "offer", Type: StageTypeOffer, Position: 3},
+		{Name: "hired", Type: StageTypeHired, Position: 4},
+		{Name: "rejected", Type: StageTypeRejected, Position: 5},
+	}
+}
+
+// Names returns the stage names in pipeline order.
+func (s Stages) Names() []string {
+	names := make([]string, len(s))
+	for i, stage := range s {
+		names[i] = stage.Name
+	}
+
+	return names
+}
+
+// Find returns the stage called name.
+func (s Stages) Find(name string) (Stage, bool) {
+	i := slices.IndexFunc(s, func(stage Stage) bool { return stage.Name == name })
+	if i < 0 {
+		return Stage{}, false
+	}
+
+	return s[i], true
+}
+
+// First returns the stage new candidates enter.
+func (s Stages) First() string {
+	if len(s) == 0 {
+		return CandidateStageNew
+	}
+
+	return s[0].Name
+}
+
+// CanMove reports whether a candidate may move from one stage to another.
+// Staying in a stage is always allowed, and so is leaving a stage that is not
+// part of the pipeline, so candidates left behind by older data can be
+// cleaned up. Explicit transitions replace the default rules, which are:
+// nobody leaves a hired stage, and a hired stage is entered from an offer
+// stage when the pipeline has one.
+func (s Stages) CanMove(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	source, ok := s.Find(from)
+	if !ok {
+		return true
+	}
+
+	target, ok := s.Find(to)
+	if !ok {
+		return false
+	}
+
+	if source.Transitions != nil {
+		return slices.Contains(source.Transitions, to)
+	}
+
+	if source.Type == StageTypeHired {
+		return false
+	}
+
+	if target.Type == StageTypeHired && s.hasType(StageTypeOffer) {
+		return source.Type == StageTypeOffer
+	}
+
+	return true
+}
+
+// BlockedSources returns the stages from which a candidate may not move to
+// the stage called to.
+func (s Stages) BlockedSources(to string) []string {
+	var blocked []string
+	for _, stage := range s {
+		if !s.CanMove(stage.Name, to) {
+			blocked = append(blocked, stage.Name)
+		}
+	}
+
+	return blocked
+}
+
+func (s Stages) hasType(t string) bool {
+	return slices.ContainsFunc(s, func(stage Stage) bool { return stage.Type == t })
+}
+
+// PipelineTemplate is a row in hiring.t_pipeline_templates with its stages.
+// Jobs without a template of their own use the team's default template, or
+// DefaultStages when the team has none.
+type PipelineTemplate struct {
+	ID        string    `json:"id" db:"id"`
+	TeamID    string    `json:"-" db:"team_id"`
+	Name      string    `json:"name" db:"name"`
+	IsDefault bool      `json:"is_default" db:"is_default"`
+	Stages    Stages    `json:"stages" db:"-"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// JobPipeline is the pipeline a job's board follows. TemplateID is set when
+// the stages come from a template.
+type JobPipeline struct {
+	JobID      string  `json:"job_id"`
+	Source     string  `json:"source"`
+	TemplateID *string `json:"template_id,omitempty"`
+	Stages     Stages  `json:"stages"`
+}
+
+// StageMigration describes how candidates follow a pipeline change: Renames
+// and Reassign map old stage names to new ones, and Removed lists the stages
+// that disappear without a target, which must be empty of candidates.
+type StageMigration struct {
+	Renames  map[string]string
+	Reassign map[string]string
+	Removed  []string
+}
+
+// Moves returns the old-to-new stage mapping applied to candidates.
+func (m StageMigration) Moves() map[string]string {
+	moves := make(map[string]string, len(m.Renames)+len(m.Reassign))
+	for from, to := range m.Renames {
+		moves[from] = to
+	}
+
+	for from, to := range m.Reassign {
+		moves[from] = to
+	}
+
+	return moves
+}
+
+// DefaultChange is how the jobs without a template follow a change of the
+// team default. FromID and Version identify the default the migration was
+// worked out from; FromID is nil when it was DefaultStages.
+type DefaultChange struct {
+	FromID    *string
+	Version   time.Time
+	Migration StageMigration
+}
+
+// PipelineParams is the input DTO for creating or editing a template.
+// Stages keep their ID across edits so a changed name is a rename; Reassign
+// maps the names of deleted stages to the stage their candidates move to.
+type PipelineParams struct {
+	Name      string
+	IsDefault bool
+	Stages    Stages
+	Reassign  map[string]string
+}
+
+// JobPipelineParams selects a job's pipeline: its own Stages, the template
+// TemplateID, or, when both are empty, the team default.
+type JobPipelineParams struct {
+	TemplateID string
+	Stages     Stages
+	Reassign   map[string]string
+}
+
+// JobPipelineChange is what the repo applies when a job's pipeline changes.
+type JobPipelineChange struct {
+	JobID      string
+	TemplateID *string
+	Stages     Stages
+	Migration  StageMigration
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CandidateProfile is a row in hiring.t_candidate_profiles. Extracted is the
+// last document produced from the resume text, Corrections is a JSON merge
+// patch of recruiter edits on top of it and Data is the result of applying
+// one to the other, kept by the database. All three follow the profile
+// schema of SchemaVersion.
+type CandidateProfile struct {
+	CandidateID      string          `json:"candidate_id" db:"candidate_id"`
+	Data             json.RawMessage `json:"data" db:"structured_data"`
+	Extracted        json.RawMessage `json:"extracted" db:"extracted_data"`
+	Corrections      json.RawMessage `json:"corrections" db:"corrections"`
+	SchemaVersion    *int            `json:"schema_version,omitempty" db:"schema_version"`
+	Provider         string          `json:"provider,omitempty" db:"provider"`
+	ExtractedAt      *time.Time      `json:"extracted_at,omitempty" db:"extracted_at"`
+	CorrectedAt      *time.Time      `json:"corrected_at,omitempty" db:"corrected_at"`
+	CorrectedBy      *string         `json:"corrected_by,omitempty" db:"corrected_by"`
+	ProcessingStatus string          `json:"processing_status,omitempty" db:"processing_status"`
+	ProcessingError  string          `json:"processing_error,omitempty" db:"processing_error"`
+}
+
+// ExtractedProfile is a validated profile document produced by Provider.
+// Contacts are the details found in the resume; they fill the blank contact
+// fields of the candidate, e.g. of one imported from a resume alone.
+type ExtractedProfile struct {
+	Data          json.RawMessage
+	SchemaVersion int
+	Provider      string
+	Contacts      CandidateContacts
+}
+
+// CandidateContacts are the contact fields of a candidate.
+type CandidateContacts struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+}
+
+// ProfileCorrection replaces the corrections of a candidate profile.
+// Previous is the value the new corrections were computed from; Fields
+// names the top-level profile fields that changed, for the activity log.
+type ProfileCorrection struct {
+	CandidateID string
+	Previous    json.RawMessage
+	Corrections json.RawMessage
+	Fields      []string
+}
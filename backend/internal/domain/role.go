@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Named permissions that can be combined into roles. Every name is backed by
+// a "perm:<name>" subject in casbin/policy.csv that owns the concrete
+// path/method rules.
+const (
+	PermCandidatesRead  = "candidates:read"
+	PermCandidatesWrite = "candidates:write"
+	PermJobsRead        = "jobs:read"
+	PermJobsWrite       = "jobs:write"
+	PermJobsAll         = "jobs:all"
+	PermJobsApprove     = "jobs:approve"
+	PermInvitesWrite    = "invites:write"
+	PermRolesRead       = "roles:read"
+	PermRolesWrite      = "roles:write"
+	PermAuditRead       = "audit:read"
+	PermAccessRead      = "access:read"
+	PermAccessWrite     = "access:write"
+	PermTeamRead        = "team:read"
+	PermTeamWrite       = "team:write"
+	PermPipelinesRead   = "pipelines:read"
+	PermPipelinesWrite  = "pipelines:write"
+	PermCommentsRead    = "comments:read"
+	PermCommentsWrite   = "comments:write"
+	PermScorecardsRead  = "scorecards:read"
+	PermScorecardsWrite = "scorecards:write"
+	PermInterviewsRead  = "interviews:read"
+	PermInterviewsWrite = "interviews:write"
+)
+
+// Permissions is the catalogue of permissions a custom role may be built from.
+var Permissions = []string{
+	PermCandidatesRead,
+	PermCandidatesWrite,
+	PermJobsRead,
+	PermJobsWrite,
+	PermJobsAll,
+	PermJobsApprove,
+	PermInvitesWrite,
+	PermRolesRead,
+	PermRolesWrite,
+	PermAuditRead,
+	PermAccessRead,
+	PermAccessWrite,
+	PermTeamRead,
+	PermTeamWrite,
+	PermPipelinesRead,
+	PermPipelinesWrite,
+	PermCommentsRead,
+	PermCommentsWrite,
+	PermScorecardsRead,
+	PermScorecardsWrite,
+	PermInterviewsRead,
+	PermInterviewsWrite,
+}
+
+// BuiltinRoles mirrors the user_role enum. Their permissions are defined in
+// casbin/policy.csv for every team and cannot be edited through the API.
+var BuiltinRoles = []string{"owner", "admin", "recruiter", "hiring_manager"}
+
+// IsPermission reports whether name is part of the permission catalogue.
+func IsPermission(name string) bool {
+	return slices.Contains(Permissions, name)
+}
+
+// IsBuiltinRole reports whether name is one of the built-in roles.
+func IsBuiltinRole(name string) bool {
+	return slices.Contains(BuiltinRoles, name)
+}
+
+// Role is either a built-in role or a row in auth.t_roles.
+// Built-in roles have no ID and are shared by all teams.
+type Role struct {
+	ID          string    `json:"id,omitempty" db:"id"`
+	TeamID      string    `json:"-" db:"team_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Permissions []string  `json:"permissions" db:"permissions"`
+	Builtin     bool      `json:"builtin" db:"-"`
+	CreatedAt   time.Time `json:"created_at,omitzero" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero" db:"updated_at"`
+}
+
+// RoleParams is the input DTO for creating or editing a custom role.
+type RoleParams struct {
+	Name        string
+	Description string
+	Permissions []string
+}
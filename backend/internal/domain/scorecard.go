@@ -0,0 +1,142 @@
+package domain
+
+import "time"
+
+// Overall recommendations of a scorecard, from the most negative.
+const (
+	RecommendStrongNo  = "strong_no"
+	RecommendNo        = "no"
+	RecommendYes       = "yes"
+	RecommendStrongYes = "strong_yes"
+)
+
+// Recommendations lists the recommendations from the most negative.
+var Recommendations = []string{RecommendStrongNo, RecommendNo, RecommendYes, RecommendStrongYes}
+
+// Bounds of a criterion's rating scale. Ratings run from 1 to Scale.
+const (
+	MinRatingScale   = 2
+	MaxRatingScale   = 10
+	MaxScorecardSize = 30
+)
+
+// Criterion is one item of a scorecard template. Labels, when set, names
+// every point of the scale from 1 up. CommentRequired makes interviewers
+// explain their rating.
+type Criterion struct {
+	Key             string   `json:"key"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	Scale           int      `json:"scale"`
+	Labels          []string `json:"labels,omitempty"`
+	CommentRequired bool     `json:"comment_required"`
+}
+
+// ScorecardTemplate is a row in hiring.t_scorecard_templates: the criteria
+// interviewers rate candidates on in a stage of a job. In blind mode a member
+// sees the others' scorecards of a candidate in the stage only after
+// submitting their own; decision makers of the job see them all.
+type ScorecardTemplate struct {
+	ID        string      `json:"id" db:"id"`
+	JobID     string      `json:"job_id" db:"job_id"`
+	Stage     string      `json:"stage" db:"stage"`
+	Blind     bool        `json:"blind" db:"blind"`
+	Criteria  []Criterion `json:"criteria" db:"criteria"`
+	UpdatedBy *string     `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// ScorecardTemplateParams is the input DTO for setting the template of a
+// stage.
+type ScorecardTemplateParams struct {
+	JobID    string
+	Stage    string
+	Blind    bool
+	Criteria []Criterion
+}
+
+// Rating is an interviewer's rating of one criterion.
+type Rating struct {
+	Key     string `json:"key"`
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Scorecard is a row in hiring.t_scorecards: the evaluation of a candidate by
+// one interviewer in one stage. Criteria is the template as it was when the
+// scorecard was last submitted. Score is the ratings on a 0-100 scale.
+type Scorecard struct {
+	ID              string      `json:"id" db:"id"`
+	CandidateID     string      `json:"candidate_id" db:"candidate_id"`
+	JobID           string      `json:"job_id" db:"job_id"`
+	Stage           string      `json:"stage" db:"stage"`
+	InterviewerID   *string     `json:"interviewer_id,omitempty" db:"interviewer_id"`
+	InterviewerName string      `json:"interviewer_name,omitempty" db:"interviewer_name"`
+	Criteria        []Criterion `json:"criteria" db:"criteria"`
+	Ratings         []Rating    `json:"ratings" db:"ratings"`
+	Recommendation  string      `json:"recommendation" db:"recommendation"`
+	Summary         string      `json:"summary,omitempty" db:"summary"`
+	Score           int         `json:"score" db:"score"`
+	SubmittedAt     time.Time   `json:"submitted_at" db:"submitted_at"`
+	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// ScorecardParams is the input DTO for submitting a scorecard. Submitting
+// again for the same stage replaces the interviewer's scorecard.
+type ScorecardParams struct {
+	CandidateID    string
+	Stage          string
+	Ratings        []Rating
+	Recommendation string
+	Summary        string
+}
+
+// CriterionSummary is the average rating of a criterion over a set of
+// scorecards on a 0-100 scale, so that ratings given before the scale of the
+// criterion changed still count.
+type CriterionSummary struct {
+	Key   string  `json:"key"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+	Count int     `json:"count"`
+}
+
+// ScorecardSummary aggregates the visible scorecards of a candidate in a
+// stage. Hidden counts the scorecards withheld by blind mode; they are not
+// part of the figures.
+type ScorecardSummary struct {
+	Stage           string             `json:"stage"`
+	Count           int                `json:"count"`
+	Hidden          int                `json:"hidden"`
+	Score           *float64           `json:"score"`
+	Recommendations map[string]int     `json:"recommendations"`
+	Criteria        []CriterionSummary `json:"criteria"`
+}
+
+// CandidateScorecards is what a member sees of a candidate's evaluations.
+type CandidateScorecards struct {
+	Scorecards []Scorecard        `json:"scorecards"`
+	Stages     []ScorecardSummary `json:"stages"`
+	Overall    *ScorecardSummary  `json:"overall"`
+}
+
+// ScorecardComparison sets the candidates of a job side by side. With a
+// stage, Criteria is that stage's template and candidates carry a summary
+// per criterion.
+type ScorecardComparison struct {
+	JobID      string              `json:"job_id"`
+	Stage      string              `json:"stage,omitempty"`
+	Criteria   []Criterion         `json:"criteria,omitempty"`
+	Candidates []ComparedCandidate `json:"candidates"`
+}
+
+// ComparedCandidate is a row of a scorecard comparison.
+type ComparedCandidate struct {
+	CandidateID string           `json:"candidate_id" db:"candidate_id"`
+	FirstName   string           `json:"first_name" db:"first_name"`
+	LastName    string           `json:"last_name" db:"last_name"`
+	Status      string           `json:"status" db:"status"`
+	MatchScore  *int             `json:"match_score" db:"match_score"`
+	Summary     ScorecardSummary `json:"summary" db:"-"`
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Score bands used by the search facets. Unscored candidates have not been
+// analysed yet.
+const (
+	ScoreBandHigh     = "80-100"
+	ScoreBandGood     = "60-79"
+	ScoreBandFair     = "40-59"
+	ScoreBandLow      = "0-39"
+	ScoreBandUnscored = "unscored"
+)
+
+// ScoreBands lists the score bands from the best down.
+var ScoreBands = []string{ScoreBandHigh, ScoreBandGood, ScoreBandFair, ScoreBandLow, ScoreBandUnscored}
+
+// Search facets.
+const (
+	FacetJob        = "job"
+	FacetStage      = "stage"
+	FacetScoreBand  = "score_band"
+	FacetLocation   = "location"
+	FacetWorkFormat = "work_format"
+)
+
+// CandidateSearch selects a page of the candidates within Scope matching
+// Query. Each facet list keeps the candidates matching any of its values;
+// empty lists do not filter.
+type CandidateSearch struct {
+	Scope       JobScope
+	Query       string
+	JobIDs      []string
+	Stages      []string
+	ScoreBands  []string
+	Locations   []string
+	WorkFormats []string
+	Cursor      *SearchCursor
+	Limit       int
+}
+
+// SearchHit is a candidate matching a search. Headline is an HTML-escaped
+// excerpt of the resume with the matched words in <mark> tags.
+type SearchHit struct {
+	Candidate
+	JobTitle   string  `json:"job_title" db:"job_title"`
+	WorkFormat string  `json:"work_format,omitempty" db:"work_format"`
+	Location   string  `json:"location,omitempty" db:"location"`
+	MatchScore *int    `json:"match_score" db:"match_score"`
+	Rank       float32 `json:"rank" db:"rank"`
+	Headline   string  `json:"headline,omitempty" db:"headline"`
+}
+
+// FacetValue is the number of matches sharing a value of a facet. Label is
+// the display name where the value is an id.
+type FacetValue struct {
+	Value string `json:"value" db:"value"`
+	Label string `json:"label,omitempty" db:"label"`
+	Count int    `json:"count" db:"count"`
+}
+
+// SearchPage is a page of search hits, best first. Facets count all matches
+// of the query within scope regardless of the facet filters and are only
+// returned with the first page.
+type SearchPage struct {
+	CursorPage[SearchHit]
+	Facets map[string][]FacetValue `json:"facets,omitempty"`
+}
+
+// SearchCursor is the position of the last hit of a search page ordered by
+// rank DESC, id DESC.
+type SearchCursor struct {
+	Rank float32
+	ID   string
+}
+
+// Encode returns the opaque form of the cursor.
+func (c SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(float64(c.Rank), 'g', -1, 32) + ":" + c.ID
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSearchCursor parses a cursor produced by SearchCursor.Encode.
+func DecodeSearchCursor(s string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	rank, id, ok := strings.Cut(string(raw), ":")
+	if !ok || uuid.Validate(id) != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	value, err := strconv.ParseFloat(rank, 32)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &SearchCursor{
+		Rank: float32(value),
+		ID:   id,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// Processing task statuses mirror the task_status enum.
+const (
+	TaskPending    = "pending"
+	TaskExtracting = "extracting"
+	TaskAnalyzing  = "analyzing"
+	TaskCompleted  = "completed"
+	TaskFailed     = "failed"
+	TaskCancelled  = "cancelled"
+	TaskImporting  = "importing"
+)
+
+// WorkflowResume is the workflow of parsing and scoring a candidate's resume.
+// Its tasks have workflow_id "resume:<candidate id>".
+const WorkflowResume = "resume"
+
+// ProcessingTask is a row in ai_engine.t_processing_tasks.
+type ProcessingTask struct {
+	ID              string     `json:"id" db:"id"`
+	WorkflowID      string     `json:"workflow_id" db:"workflow_id"`
+	EntityID        string     `json:"entity_id" db:"entity_id"`
+	Status          string     `json:"status" db:"status"`
+	ProgressPercent int        `json:"progress_percent" db:"progress_percent"`
+	ErrorMessage    string     `json:"error_message,omitempty" db:"error_message"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// ResumeWorkflowID returns the workflow_id of a candidate's resume task.
+func ResumeWorkflowID(candidateID string) string {
+	return WorkflowResume + ":" + candidateID
+}
+
+// ResumeTask is a claimed resume task together with the candidate's file.
+// Stage is the task status: TaskExtracting turns the file into text,
+// TaskAnalyzing turns Text into a structured profile.
+type ResumeTask struct {
+	TaskID      string `db:"task_id"`
+	CandidateID string `db:"candidate_id"`
+	Stage       string `db:"stage"`
+	FileKey     string `db:"file_key"`
+	FileName    string `db:"file_name"`
+	Text        string `db:"parsed_text"`
+	Attempts    int    `db:"attempts"`
+}
+
+// ResumeText is the result of extracting text from a resume. OCRPages is
+// set when the text was recognized from page images of a scanned document.
+type ResumeText struct {
+	Text     string
+	Language string
+	OCRPages []OCRPage
+}
+
+// OCRPage is the recognition result of one page, stored in
+// ai_engine.t_ocr_pages. Confidence is the engine's mean word confidence,
+// 0 to 100.
+type OCRPage struct {
+	Page       int     `json:"page" db:"page"`
+	Engine     string  `json:"engine" db:"engine"`
+	Confidence float64 `json:"confidence" db:"confidence"`
+	Chars      int     `json:"chars" db:"chars"`
+}
+
+// OCRConfidence returns the mean confidence of the pages, or nil when the
+// text was not recognized.
+func (t *ResumeText) OCRConfidence() *float64 {
+	if len(t.OCRPages) == 0 {
+		return nil
+	}
+
+	var sum float64
+	for _, p := range t.OCRPages {
+		sum += p.Confidence
+	}
+
+	mean := sum / float64(len(t.OCRPages))
+
+	return &mean
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Team is a row in auth.t_teams with its careers page settings.
+type Team struct {
+	ID        string       `json:"id" db:"id"`
+	Name      string       `json:"name" db:"name"`
+	Slug      string       `json:"slug" db:"slug"`
+	Locale    string       `json:"locale" db:"locale"`
+	Branding  TeamBranding `json:"branding" db:"branding"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// TeamBranding is stored as JSONB in auth.t_teams.branding and shown on the
+// public careers page and in job feeds.
+type TeamBranding struct {
+	LogoURL      string `json:"logo_url,omitempty"`
+	PrimaryColor string `json:"primary_color,omitempty"`
+	Website      string `json:"website,omitempty"`
+	Tagline      string `json:"tagline,omitempty"`
+}
+
+// TeamParams is the input DTO for editing team settings.
+type TeamParams struct {
+	Name     string
+	Slug     string
+	Locale   string
+	Branding TeamBranding
+}
+
+// CareersTeam is the public part of a team shown on its careers page.
+type CareersTeam struct {
+	Name     string       `json:"name"`
+	Slug     string       `json:"slug"`
+	Locale   string       `json:"locale"`
+	Branding TeamBranding `json:"branding"`
+}
+
+// CareersJob is the public representation of an open job.
+type CareersJob struct {
+	ID          string    `json:"id" db:"id"`
+	Title       string    `json:"title" db:"title"`
+	Department  string    `json:"department,omitempty" db:"department"`
+	WorkFormat  string    `json:"work_format,omitempty" db:"work_format"`
+	Description string    `json:"description" db:"description"`
+	PublishedAt time.Time `json:"published_at" db:"published_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	URL         string    `json:"url" db:"-"`
+}
+
+// CareersBoard is the cached content of a team's careers page.
+type CareersBoard struct {
+	Team CareersTeam  `json:"team"`
+	URL  string       `json:"url"`
+	Jobs []CareersJob `json:"jobs"`
+}
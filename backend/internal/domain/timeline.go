@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// Kinds of timeline entries. TimelineScore is an AI analysis of the
+// candidate with the match score it gave and the previous one, and
+// TimelineActivity an entry of the activity log about the candidate.
+const (
+	TimelineStatusChange  = "status_change"
+	TimelineCommunication = "communication"
+	TimelineScore         = "score"
+	TimelineScorecard     = "scorecard"
+	TimelineComment       = "comment"
+	TimelineInterview     = "interview"
+	TimelineActivity      = "activity"
+)
+
+// TimelineKinds lists every kind of timeline entry.
+var TimelineKinds = []string{
+	TimelineStatusChange,
+	TimelineCommunication,
+	TimelineScore,
+	TimelineScorecard,
+	TimelineComment,
+	TimelineInterview,
+	TimelineActivity,
+}
+
+// TimelineEntry is an event in the history of a candidate. ID is the id of
+// the row it comes from; Details depend on Kind.
+type TimelineEntry struct {
+	Kind       string         `json:"kind" db:"kind"`
+	ID         string         `json:"id" db:"id"`
+	OccurredAt time.Time      `json:"occurred_at" db:"occurred_at"`
+	ActorType  string         `json:"actor_type" db:"actor_type"`
+	ActorID    *string        `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName  string         `json:"actor_name,omitempty" db:"actor_name"`
+	Details    map[string]any `json:"details" db:"details"`
+}
+
+// TimelineFilter selects a page of a candidate's timeline, newest first.
+// Empty Kinds selects every kind.
+type TimelineFilter struct {
+	Scope       JobScope
+	CandidateID string
+	Kinds       []string
+	Limit       int
+	Offset      int
+}
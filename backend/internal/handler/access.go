@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AccessHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.AccessUseCase
+}
+
+func NewAccessHandler(cfg *config.Server, log *zap.Logger, usecase usecase.AccessUseCase) *AccessHandler {
+	return &AccessHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type grantRequest struct {
+	JobID  string   `param:"jobId"  validate:"required,uuid"`
+	UserID string   `param:"userId" validate:"required,uuid"`
+	Roles  []string `json:"roles"   validate:"dive,oneof=interviewer coordinator decision_maker"`
+}
+
+type memberRequest struct {
+	UserID string `param:"userId" validate:"required,uuid"`
+}
+
+type departmentGrantRequest struct {
+	UserID     string   `param:"userId"     validate:"required,uuid"`
+	Department string   `json:"department"  validate:"required,max=128"`
+	Roles      []string `json:"roles"       validate:"dive,oneof=interviewer coordinator decision_maker"`
+}
+
+func (i *AccessHandler) GetJobMembers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		members, err := i.usecase.ListJobMembers(c.Request().Context(), session.TeamID, req.JobID)
+		if err != nil {
+			return accessError(err)
+		}
+
+		return c.JSON(http.StatusOK, members)
+	}
+}
+
+func (i *AccessHandler) GetMemberJobs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req memberRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		jobs, err := i.usecase.ListMemberJobs(c.Request().Context(), jobScopeFromContext(c), req.UserID)
+		if err != nil {
+			return accessError(err)
+		}
+
+		return c.JSON(http.StatusOK, jobs)
+	}
+}
+
+func (i *AccessHandler) PutGrant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req grantRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.GrantAccess(c.Request().Context(), sessionFromContext(c), req.JobID, req.UserID, req.Roles); err != nil {
+			return accessError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (i *AccessHandler) DeleteGrant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req grantRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		if err := i.usecase.RevokeAccess(c.Request().Context(), session.TeamID, req.JobID, req.UserID); err != nil {
+			return accessError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (i *AccessHandler) PostDepartmentGrant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req departmentGrantRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		n, err := i.usecase.GrantDepartment(c.Request().Context(), jobScopeFromContext(c), req.UserID, req.Department, req.Roles)
+		if err != nil {
+			return accessError(err)
+		}
+
+		return c.JSON(http.StatusOK, map[string]int{"granted": n})
+	}
+}
+
+func accessError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrMemberNotFound), errors.Is(err, usecase.ErrGrantNotFound),
+		errors.Is(err, usecase.ErrJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrUnknownJobRole):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("access error: %w", err))
+	}
+}
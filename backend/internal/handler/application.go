@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxFileSize applies when storage.max-file-size is not configured.
+const DefaultMaxFileSize = 10 << 20
+
+type ApplicationHandler struct {
+	cfg         *config.Server
+	log         *zap.Logger
+	maxFileSize int64
+	usecase     usecase.ApplicationUseCase
+}
+
+func NewApplicationHandler(cfg *config.Server, log *zap.Logger, storage *config.Storage, usecase usecase.ApplicationUseCase) *ApplicationHandler {
+	maxFileSize := storage.MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = DefaultMaxFileSize
+	}
+
+	return &ApplicationHandler{
+		cfg:         cfg,
+		log:         log,
+		maxFileSize: maxFileSize,
+		usecase:     usecase,
+	}
+}
+
+type applyRequest struct {
+	Slug      string `param:"slug"      validate:"required,max=64"`
+	JobID     string `param:"jobId"     validate:"required,uuid"`
+	FirstName string `form:"first_name" validate:"required,max=100"`
+	LastName  string `form:"last_name"  validate:"required,max=100"`
+	Email     string `form:"email"      validate:"required,email,max=254"`
+	Phone     string `form:"phone"      validate:"omitempty,e164"`
+	// Website is a honeypot: the field is hidden on the form, so only bots
+	// fill it in.
+	Website string `form:"website"`
+}
+
+// PostApply accepts a multipart application with a "resume" file. Bot
+// submissions caught by the honeypot get the same response as real ones.
+func (i *ApplicationHandler) PostApply() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req applyRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if req.Website != "" {
+			i.log.Info("apply honeypot triggered", zap.String("ip", c.RealIP()), zap.String("job_id", req.JobID))
+			return c.JSON(http.StatusAccepted, map[string]string{"status": "received"})
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		header, err := c.FormFile("resume")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("resume file is required: %w", err))
+		}
+
+		if header.Size > i.maxFileSize {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "resume file is too large")
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("open resume: %w", err))
+		}
+		defer file.Close()
+
+		err = i.usecase.Apply(c.Request().Context(), domain.Application{
+			Slug:      req.Slug,
+			JobID:     req.JobID,
+			FirstName: strings.TrimSpace(req.FirstName),
+			LastName:  strings.TrimSpace(req.LastName),
+			Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+			Phone:     req.Phone,
+			FileName:  header.Filename,
+			IP:        c.RealIP(),
+			UserAgent: c.Request().UserAgent(),
+		}, file, header.Size)
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrTeamNotFound), errors.Is(err, usecase.ErrJobNotFound):
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			case errors.Is(err, usecase.ErrJobClosed):
+				return echo.NewHTTPError(http.StatusGone, err.Error())
+			case errors.Is(err, usecase.ErrAlreadyApplied):
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			case errors.Is(err, usecase.ErrUnsupportedFile):
+				return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+			case errors.Is(err, usecase.ErrFileTooLarge):
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+			default:
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("apply error: %w", err))
+			}
+		}
+
+		return c.JSON(http.StatusAccepted, map[string]string{"status": "received"})
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AuthzHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.AuthzUseCase
+}
+
+func NewAuthzHandler(cfg *config.Server, log *zap.Logger, usecase usecase.AuthzUseCase) *AuthzHandler {
+	return &AuthzHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type explainRequest struct {
+	UserID string `query:"user_id" validate:"required,uuid"`
+	Path   string `query:"path"    validate:"required,startswith=/"`
+	Method string `query:"method"  validate:"required,oneof=GET POST PUT PATCH DELETE get post put patch delete"`
+	JobID  string `query:"job_id"  validate:"omitempty,uuid"`
+}
+
+// GetExplain reports how the policy decides a request of another user.
+// The check always runs in the caller's team: admin is a per-team role.
+func (i *AuthzHandler) GetExplain() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req explainRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		explanation, err := i.usecase.Explain(c.Request().Context(), domain.AccessCheck{
+			UserID: req.UserID,
+			TeamID: session.TeamID,
+			Path:   req.Path,
+			Method: strings.ToUpper(req.Method),
+			JobID:  req.JobID,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrMemberNotFound), errors.Is(err, usecase.ErrJobNotFound):
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			default:
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("explain error: %w", err))
+			}
+		}
+
+		return c.JSON(http.StatusOK, explanation)
+	}
+}
+
+func (i *AuthzHandler) GetDenials() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req pageRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		denials, err := i.usecase.ListDenials(c.Request().Context(), session.TeamID, req.limit(), req.Offset)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("list denials error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, denials)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type BoardHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.BoardUseCase
+}
+
+func NewBoardHandler(cfg *config.Server, log *zap.Logger, usecase usecase.BoardUseCase) *BoardHandler {
+	return &BoardHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type boardRequest struct {
+	JobID      string `param:"jobId"       validate:"required,uuid"`
+	StageLimit int    `query:"stage_limit" validate:"omitempty,min=1,max=500"`
+}
+
+type moveRequest struct {
+	CandidateID string `param:"candidateId" validate:"required,uuid"`
+	Status      string `json:"status"       validate:"required,max=32"`
+	AfterID     string `json:"after_id"     validate:"omitempty,uuid"`
+	BeforeID    string `json:"before_id"    validate:"omitempty,uuid,excluded_with=AfterID"`
+}
+
+// GetBoard returns the job's candidates grouped by stage, each stage ordered
+// by board position.
+func (i *BoardHandler) GetBoard() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req boardRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		board, err := i.usecase.GetBoard(c.Request().Context(), sessionFromContext(c).TeamID, req.JobID, req.StageLimit)
+		if err != nil {
+			return boardError(err)
+		}
+
+		return c.JSON(http.StatusOK, board)
+	}
+}
+
+// PostMove moves a card to a stage, right after after_id or right before
+// before_id, or to the end of the stage when neither is given.
+func (i *BoardHandler) PostMove() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req moveRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		candidate, err := i.usecase.MoveCandidate(c.Request().Context(), sessionFromContext(c), domain.CandidateMove{
+			CandidateID: req.CandidateID,
+			Status:      strings.TrimSpace(req.Status),
+			AfterID:     req.AfterID,
+			BeforeID:    req.BeforeID,
+		})
+		if err != nil {
+			return boardError(err)
+		}
+
+		return c.JSON(http.StatusOK, candidate)
+	}
+}
+
+func (i *BoardHandler) GetStatusHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		history, err := i.usecase.ListStatusHistory(c.Request().Context(), sessionFromContext(c).TeamID, req.CandidateID)
+		if err != nil {
+			return boardError(err)
+		}
+
+		return c.JSON(http.StatusOK, history)
+	}
+}
+
+func boardError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrJobNotFound), errors.Is(err, usecase.ErrCandidateNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrMoveAnchorNotFound):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, usecase.ErrUnknownStage), errors.Is(err, usecase.ErrTransitionNotAllowed):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("board error: %w", err))
+	}
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CandidateHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.CandidateUseCase
+}
+
+func NewCandidateHandler(cfg *config.Server, log *zap.Logger, usecase usecase.CandidateUseCase) *CandidateHandler {
+	return &CandidateHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type candidateRequest struct {
+	CandidateID string `param:"candidateId" validate:"omitempty,uuid"`
+	JobID       string `json:"job_id"       validate:"omitempty,uuid"`
+	FirstName   string `json:"first_name"   validate:"required,max=100"`
+	LastName    string `json:"last_name"    validate:"required,max=100"`
+	Email       string `json:"email"        validate:"omitempty,email,max=254"`
+	Phone       string `json:"phone"        validate:"omitempty,e164"`
+	Status      string `json:"status"       validate:"omitempty,max=32"`
+}
+
+type candidateIDRequest struct {
+	CandidateID string `param:"candidateId" validate:"required,uuid"`
+}
+
+type candidateListRequest struct {
+	Cursor      string `query:"cursor"       validate:"omitempty,max=256"`
+	Limit       int    `query:"limit"        validate:"omitempty,min=1,max=200"`
+	JobID       string `query:"job_id"       validate:"omitempty,uuid"`
+	Status      string `query:"status"       validate:"omitempty,max=32"`
+	MinScore    *int   `query:"min_score"    validate:"omitempty,min=0,max=100"`
+	MaxScore    *int   `query:"max_score"    validate:"omitempty,min=0,max=100"`
+	CreatedFrom string `query:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `query:"created_to"   validate:"omitempty,datetime=2006-01-02"`
+}
+
+// GetCandidates lists candidates of the jobs visible to the caller, newest
+// first. created_from and created_to are inclusive dates.
+func (i *CandidateHandler) GetCandidates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		filter, err := req.filter()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		filter.Scope = jobScopeFromContext(c)
+
+		page, err := i.usecase.ListCandidates(c.Request().Context(), filter)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("list candidates error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+func (i *CandidateHandler) GetCandidate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		candidate, err := i.usecase.GetCandidate(c.Request().Context(), session.TeamID, req.CandidateID)
+		if err != nil {
+			return candidateError(err)
+		}
+
+		return c.JSON(http.StatusOK, candidate)
+	}
+}
+
+func (i *CandidateHandler) PostCandidate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if req.JobID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "incorrect data: job_id is required")
+		}
+
+		candidate, err := i.usecase.CreateCandidate(c.Request().Context(), jobScopeFromContext(c), req.params())
+		if err != nil {
+			return candidateError(err)
+		}
+
+		return c.JSON(http.StatusCreated, candidate)
+	}
+}
+
+func (i *CandidateHandler) PutCandidate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		candidate, err := i.usecase.UpdateCandidate(c.Request().Context(), sessionFromContext(c), req.CandidateID, req.params())
+		if err != nil {
+			return candidateError(err)
+		}
+
+		return c.JSON(http.StatusOK, candidate)
+	}
+}
+
+func (i *CandidateHandler) DeleteCandidate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.DeleteCandidate(c.Request().Context(), sessionFromContext(c), req.CandidateID); err != nil {
+			return candidateError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetResume streams the candidate's resume through the API, so access is
+// checked like any other candidate route.
+func (i *CandidateHandler) GetResume() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		file, err := i.usecase.OpenResume(c.Request().Context(), session.TeamID, req.CandidateID)
+		if err != nil {
+			return candidateError(err)
+		}
+		defer file.Body.Close()
+
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = echo.MIMEOctetStream
+		}
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+		header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+		header.Set(echo.HeaderCacheControl, "private, no-store")
+
+		if file.Size > 0 {
+			header.Set(echo.HeaderContentLength, strconv.FormatInt(file.Size, 10))
+		}
+
+		return c.Stream(http.StatusOK, contentType, file.Body)
+	}
+}
+
+func (i *CandidateHandler) GetResumeURL() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		url, err := i.usecase.ResumeURL(c.Request().Context(), session.TeamID, req.CandidateID)
+		if err != nil {
+			return candidateError(err)
+		}
+
+		return c.JSON(http.StatusOK, url)
+	}
+}
+
+func (r candidateRequest) params() domain.CandidateParams {
+	return domain.CandidateParams{
+		JobID:     r.JobID,
+		FirstName: strings.TrimSpace(r.FirstName),
+		LastName:  strings.TrimSpace(r.LastName),
+		Email:     strings.ToLower(strings.TrimSpace(r.Email)),
+		Phone:     r.Phone,
+		Status:    r.Status,
+	}
+}
+
+func (r candidateListRequest) filter() (domain.CandidateFilter, error) {
+	filter := domain.CandidateFilter{
+		JobID:    r.JobID,
+		Status:   r.Status,
+		MinScore: r.MinScore,
+		MaxScore: r.MaxScore,
+		Limit:    pageRequest{Limit: r.Limit}.limit(),
+	}
+
+	if r.MinScore != nil && r.MaxScore != nil && *r.MinScore > *r.MaxScore {
+		return filter, errors.New("min_score is greater than max_score")
+	}
+
+	if r.Cursor != "" {
+		cursor, err := domain.DecodeCursor(r.Cursor)
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Cursor = cursor
+	}
+
+	if r.CreatedFrom != "" {
+		from, _ := time.Parse(time.DateOnly, r.CreatedFrom)
+		filter.CreatedFrom = &from
+	}
+
+	if r.CreatedTo != "" {
+		to, _ := time.Parse(time.DateOnly, r.CreatedTo)
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+
+	return filter, nil
+}
+
+func candidateError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCandidateNotFound), errors.Is(err, usecase.ErrJobNotFound),
+		errors.Is(err, usecase.ErrResumeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrCandidateDuplicate):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, usecase.ErrUnknownStage):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, usecase.ErrPresignUnsupported):
+		return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("candidate error: %w", err))
+	}
+}
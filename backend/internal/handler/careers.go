@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CareersHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.CareersUseCase
+}
+
+func NewCareersHandler(cfg *config.Server, log *zap.Logger, usecase usecase.CareersUseCase) *CareersHandler {
+	return &CareersHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type careersRequest struct {
+	Slug string `param:"slug" validate:"required,max=64"`
+}
+
+type careersJobRequest struct {
+	Slug  string `param:"slug"  validate:"required,max=64"`
+	JobID string `param:"jobId" validate:"required,uuid"`
+	Embed string `query:"embed" validate:"omitempty,oneof=html"`
+}
+
+func (i *CareersHandler) GetBoard() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req careersRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		board, err := i.usecase.GetBoard(c.Request().Context(), req.Slug)
+		if err != nil {
+			return careersError(err)
+		}
+
+		i.setCache(c)
+
+		return c.JSON(http.StatusOK, board)
+	}
+}
+
+func (i *CareersHandler) GetJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req careersJobRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		board, job, err := i.usecase.GetJob(c.Request().Context(), req.Slug, req.JobID)
+		if err != nil {
+			return careersError(err)
+		}
+
+		i.setCache(c)
+
+		return c.JSON(http.StatusOK, map[string]any{
+			"team": board.Team,
+			"job":  job,
+		})
+	}
+}
+
+// GetJobPosting returns schema.org JobPosting JSON-LD. With ?embed=html the
+// markup is wrapped in a script tag ready to be pasted into a page.
+func (i *CareersHandler) GetJobPosting() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req careersJobRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		posting, err := i.usecase.JobPosting(c.Request().Context(), req.Slug, req.JobID)
+		if err != nil {
+			return careersError(err)
+		}
+
+		// json.Marshal escapes <, > and &, so the output is safe inside <script>.
+		body, err := json.Marshal(posting)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("marshal job posting: %w", err))
+		}
+
+		i.setCache(c)
+
+		if req.Embed == "html" {
+			return c.HTML(http.StatusOK, `<script type="application/ld+json">`+string(body)+`</script>`)
+		}
+
+		return c.Blob(http.StatusOK, "application/ld+json; charset=UTF-8", body)
+	}
+}
+
+func (i *CareersHandler) GetRSS() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req careersRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		f, err := i.usecase.Feed(c.Request().Context(), req.Slug)
+		if err != nil {
+			return careersError(err)
+		}
+
+		body, err := f.RSS()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("rss error: %w", err))
+		}
+
+		i.setCache(c)
+
+		return c.Blob(http.StatusOK, "application/rss+xml; charset=UTF-8", body)
+	}
+}
+
+func (i *CareersHandler) GetAtom() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req careersRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		f, err := i.usecase.Feed(c.Request().Context(), req.Slug)
+		if err != nil {
+			return careersError(err)
+		}
+
+		self := c.Scheme() + "://" + c.Request().Host + c.Request().URL.Path
+
+		body, err := f.Atom(self)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("atom error: %w", err))
+		}
+
+		i.setCache(c)
+
+		return c.Blob(http.StatusOK, "application/atom+xml; charset=UTF-8", body)
+	}
+}
+
+func (i *CareersHandler) setCache(c echo.Context) {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age="+strconv.Itoa(int(i.usecase.CacheTTL().Seconds())))
+}
+
+func careersError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrTeamNotFound), errors.Is(err, usecase.ErrJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("careers error: %w", err))
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CommentHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.CommentUseCase
+}
+
+func NewCommentHandler(cfg *config.Server, log *zap.Logger, usecase usecase.CommentUseCase) *CommentHandler {
+	return &CommentHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type commentListRequest struct {
+	pageRequest
+	CandidateID string `param:"candidateId" validate:"required,uuid"`
+}
+
+type commentCreateRequest struct {
+	CandidateID string   `param:"candidateId" validate:"required,uuid"`
+	ParentID    string   `json:"parent_id"    validate:"omitempty,uuid"`
+	Visibility  string   `json:"visibility"   validate:"omitempty,oneof=private team"`
+	Body        string   `json:"body"         validate:"required,max=10000"`
+	Mentions    []string `json:"mentions"     validate:"omitempty,max=50,dive,uuid"`
+}
+
+type commentUpdateRequest struct {
+	CommentID string   `param:"commentId" validate:"required,uuid"`
+	Body      string   `json:"body"       validate:"required,max=10000"`
+	Mentions  []string `json:"mentions"   validate:"omitempty,max=50,dive,uuid"`
+}
+
+type commentIDRequest struct {
+	CommentID string `param:"commentId" validate:"required,uuid"`
+}
+
+// GetComments lists the comment threads of a candidate visible to the
+// caller, newest first: team comments and the caller's private notes.
+func (i *CommentHandler) GetComments() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req commentListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListComments(c.Request().Context(), domain.CommentFilter{
+			Scope:       jobScopeFromContext(c),
+			CandidateID: req.CandidateID,
+			Limit:       req.limit(),
+			Offset:      req.Offset,
+		})
+		if err != nil {
+			return commentError(err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+// PostComment adds a comment to a candidate, or a reply when parent_id is
+// set. visibility defaults to team. mentions lists the ids of the team
+// members to notify; private notes cannot mention anyone.
+func (i *CommentHandler) PostComment() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req commentCreateRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		req.Body = strings.TrimSpace(req.Body)
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if req.Visibility == "" {
+			req.Visibility = domain.CommentTeam
+		}
+
+		comment, err := i.usecase.CreateComment(c.Request().Context(), jobScopeFromContext(c), domain.CommentParams{
+			CandidateID: req.CandidateID,
+			ParentID:    req.ParentID,
+			Visibility:  req.Visibility,
+			Body:        req.Body,
+			Mentions:    req.Mentions,
+		})
+		if err != nil {
+			return commentError(err)
+		}
+
+		return c.JSON(http.StatusCreated, comment)
+	}
+}
+
+// PutComment replaces the body and mentions of the caller's comment. The
+// previous body is kept in the comment's history.
+func (i *CommentHandler) PutComment() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req commentUpdateRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		req.Body = strings.TrimSpace(req.Body)
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		comment, err := i.usecase.UpdateComment(c.Request().Context(), jobScopeFromContext(c), req.CommentID, domain.CommentUpdate{
+			Body:     req.Body,
+			Mentions: req.Mentions,
+		})
+		if err != nil {
+			return commentError(err)
+		}
+
+		return c.JSON(http.StatusOK, comment)
+	}
+}
+
+func (i *CommentHandler) DeleteComment() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req commentIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.DeleteComment(c.Request().Context(), jobScopeFromContext(c), req.CommentID); err != nil {
+			return commentError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetRevisions returns the earlier bodies of a comment, oldest first,
+// including the last one of a deleted comment.
+func (i *CommentHandler) GetRevisions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req commentIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		revisions, err := i.usecase.ListRevisions(c.Request().Context(), jobScopeFromContext(c), req.CommentID)
+		if err != nil {
+			return commentError(err)
+		}
+
+		return c.JSON(http.StatusOK, revisions)
+	}
+}
+
+func commentError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCandidateNotFound), errors.Is(err, usecase.ErrCommentNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrCommentForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, usecase.ErrMentionNotMember), errors.Is(err, usecase.ErrMentionNoAccess),
+		errors.Is(err, usecase.ErrPrivateMention):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("comment error: %w", err))
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"backend/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// sessionFromContext returns the caller identity stored by middleware.Session.
+func sessionFromContext(c echo.Context) domain.Session {
+	return domain.Session{
+		UserID: c.Get("id").(string),
+		TeamID: c.Get("team_id").(string),
+		Role:   c.Get("role").(string),
+	}
+}
+
+// jobScopeFromContext returns the job visibility computed by middleware.RBAC.
+func jobScopeFromContext(c echo.Context) domain.JobScope {
+	return c.Get("job_scope").(domain.JobScope)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type DuplicateHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.DuplicateUseCase
+}
+
+func NewDuplicateHandler(cfg *config.Server, log *zap.Logger, usecase usecase.DuplicateUseCase) *DuplicateHandler {
+	return &DuplicateHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type duplicateListRequest struct {
+	pageRequest
+	CandidateID string   `param:"candidateId" validate:"omitempty,uuid"`
+	Status      string   `query:"status"      validate:"omitempty,oneof=pending dismissed all"`
+	JobID       string   `query:"job_id"      validate:"omitempty,uuid"`
+	MinScore    *float64 `query:"min_score"   validate:"omitempty,min=0,max=1"`
+}
+
+type duplicateIDRequest struct {
+	DuplicateID string `param:"duplicateId" validate:"required,uuid"`
+}
+
+type mergeRequest struct {
+	CandidateID string `param:"candidateId" validate:"required,uuid"`
+	SourceID    string `json:"source_id"    validate:"required,uuid"`
+}
+
+// GetDuplicates lists the review queue of suspected duplicates, most likely
+// first, or under a candidate path only the pairs of that candidate. status
+// defaults to pending; "all" lists dismissed pairs as well.
+func (i *DuplicateHandler) GetDuplicates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req duplicateListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListDuplicates(c.Request().Context(), req.filter(jobScopeFromContext(c)))
+		if err != nil {
+			return duplicateError(err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+// PostDetect compares the candidate with the rest of the team again and
+// returns its pending duplicates.
+func (i *DuplicateHandler) PostDetect() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		duplicates, err := i.usecase.DetectDuplicates(c.Request().Context(), jobScopeFromContext(c), req.CandidateID)
+		if err != nil {
+			return duplicateError(err)
+		}
+
+		return c.JSON(http.StatusOK, duplicates)
+	}
+}
+
+func (i *DuplicateHandler) PostDismiss() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req duplicateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.DismissDuplicate(c.Request().Context(), jobScopeFromContext(c), req.DuplicateID); err != nil {
+			return duplicateError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// PostMerge merges the candidate source_id into the candidate of the path,
+// which is kept, and returns the kept candidate.
+func (i *DuplicateHandler) PostMerge() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req mergeRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		candidate, err := i.usecase.MergeCandidates(c.Request().Context(), jobScopeFromContext(c), req.CandidateID, req.SourceID)
+		if err != nil {
+			return duplicateError(err)
+		}
+
+		return c.JSON(http.StatusOK, candidate)
+	}
+}
+
+// GetMerges returns the audit records of the candidates merged into the
+// candidate.
+func (i *DuplicateHandler) GetMerges() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		merges, err := i.usecase.ListMerges(c.Request().Context(), sessionFromContext(c).TeamID, req.CandidateID)
+		if err != nil {
+			return duplicateError(err)
+		}
+
+		return c.JSON(http.StatusOK, merges)
+	}
+}
+
+func (r duplicateListRequest) filter(scope domain.JobScope) domain.DuplicateFilter {
+	status := r.Status
+	switch status {
+	case "":
+		status = domain.DuplicatePending
+	case "all":
+		status = ""
+	}
+
+	return domain.DuplicateFilter{
+		Scope:       scope,
+		Status:      status,
+		JobID:       r.JobID,
+		CandidateID: r.CandidateID,
+		MinScore:    r.MinScore,
+		Limit:       r.limit(),
+		Offset:      r.Offset,
+	}
+}
+
+func duplicateError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCandidateNotFound), errors.Is(err, usecase.ErrDuplicateNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrMergeSelf):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("duplicate error: %w", err))
+	}
+}
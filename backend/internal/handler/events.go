@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const defaultHeartbeat = 25 * time.Second
+
+type EventsHandler struct {
+	cfg       *config.Server
+	log       *zap.Logger
+	heartbeat time.Duration
+	usecase   usecase.EventsUseCase
+}
+
+func NewEventsHandler(cfg *config.Server, log *zap.Logger, rt *config.Realtime, usecase usecase.EventsUseCase) *EventsHandler {
+	heartbeat := rt.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+
+	return &EventsHandler{
+		cfg:       cfg,
+		log:       log,
+		heartbeat: heartbeat,
+		usecase:   usecase,
+	}
+}
+
+// GetEvents streams the job's board events as server-sent events until the
+// client disconnects or the stream is closed by the server. Events missed
+// between connections are not replayed: clients reload the board after
+// reconnecting and on a resync event.
+func (i *EventsHandler) GetEvents() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		events, err := i.usecase.Subscribe(c.Request().Context(), domain.AccessCheck{
+			UserID: session.UserID,
+			TeamID: session.TeamID,
+			Path:   c.Path(),
+			Method: c.Request().Method,
+			JobID:  req.JobID,
+		})
+		if err != nil {
+			if errors.Is(err, usecase.ErrJobNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("events error: %w", err))
+		}
+
+		// The stream outlives the server write timeout.
+		_ = http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{})
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentType, "text/event-stream")
+		header.Set(echo.HeaderCacheControl, "no-cache")
+		header.Set("X-Accel-Buffering", "no")
+		c.Response().WriteHeader(http.StatusOK)
+
+		if _, err := fmt.Fprint(c.Response(), "retry: 3000\n\n"); err != nil {
+			return nil
+		}
+
+		c.Response().Flush()
+
+		heartbeat := time.NewTicker(i.heartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					i.log.Error("marshal board event", zap.Error(err))
+					continue
+				}
+
+				if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+					return nil
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Response(), ": ping\n\n"); err != nil {
+					return nil
+				}
+			}
+
+			c.Response().Flush()
+		}
+	}
+}
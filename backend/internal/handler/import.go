@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultMaxImportArchiveSize applies when import.max-archive-size is
+	// not configured.
+	defaultMaxImportArchiveSize = 512 << 20
+	maxImportCSVSize            = 16 << 20
+)
+
+type ImportHandler struct {
+	cfg            *config.Server
+	log            *zap.Logger
+	maxArchiveSize int64
+	usecase        usecase.ImportUseCase
+}
+
+func NewImportHandler(cfg *config.Server, log *zap.Logger, imports *config.Import, usecase usecase.ImportUseCase) *ImportHandler {
+	maxArchiveSize := imports.MaxArchiveSize
+	if maxArchiveSize == 0 {
+		maxArchiveSize = defaultMaxImportArchiveSize
+	}
+
+	return &ImportHandler{
+		cfg:            cfg,
+		log:            log,
+		maxArchiveSize: maxArchiveSize,
+		usecase:        usecase,
+	}
+}
+
+type importRequest struct {
+	JobID      string `form:"job_id"     validate:"required,uuid"`
+	Duplicates string `form:"duplicates" validate:"omitempty,oneof=skip merge create"`
+	Stage      string `form:"stage"      validate:"omitempty,max=32"`
+}
+
+type importListRequest struct {
+	pageRequest
+	JobID string `query:"job_id" validate:"omitempty,uuid"`
+}
+
+type importIDRequest struct {
+	ImportID string `param:"importId" validate:"required,uuid"`
+}
+
+type importRowsRequest struct {
+	pageRequest
+	ImportID string `param:"importId" validate:"required,uuid"`
+	Outcome  string `query:"outcome"  validate:"omitempty,oneof=created merged skipped failed"`
+}
+
+// PostImport starts a bulk import into a job from a multipart form with a
+// "csv" file of candidates, a "archive" ZIP of resumes, or both. With both,
+// the resume column of the CSV names files in the archive. duplicates says
+// what to do with rows that match a candidate of the job by email or phone
+// and defaults to skip. The import runs in the background; its progress is
+// read from GET /imports/:importId.
+func (i *ImportHandler) PostImport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req importRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		csvFile, file, err := i.formFile(c, "csv", maxImportCSVSize)
+		if err != nil {
+			return err
+		}
+
+		if file != nil {
+			defer file.Close()
+		}
+
+		archive, file, err := i.formFile(c, "archive", i.maxArchiveSize)
+		if err != nil {
+			return err
+		}
+
+		if file != nil {
+			defer file.Close()
+		}
+
+		if req.Duplicates == "" {
+			req.Duplicates = domain.ImportSkipDuplicates
+		}
+
+		imp, err := i.usecase.StartImport(c.Request().Context(), jobScopeFromContext(c), domain.ImportParams{
+			JobID:           req.JobID,
+			DuplicatePolicy: req.Duplicates,
+			Stage:           strings.TrimSpace(req.Stage),
+		}, csvFile, archive)
+		if err != nil {
+			return importError(err)
+		}
+
+		return c.JSON(http.StatusAccepted, imp)
+	}
+}
+
+// formFile opens an optional file of the form. It returns nils if the form
+// has no such file; otherwise the caller closes the returned file.
+func (i *ImportHandler) formFile(c echo.Context, name string, maxSize int64) (*domain.Upload, multipart.File, error) {
+	header, err := c.FormFile(name)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("read %s file: %w", name, err))
+	}
+
+	if header.Size > maxSize {
+		return nil, nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, name+" file is too large")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("open %s file: %w", name, err))
+	}
+
+	return &domain.Upload{Name: header.Filename, Size: header.Size, Body: file}, file, nil
+}
+
+// GetImports lists the imports of the jobs visible to the caller, newest
+// first.
+func (i *ImportHandler) GetImports() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req importListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListImports(c.Request().Context(), domain.ImportFilter{
+			Scope:  jobScopeFromContext(c),
+			JobID:  req.JobID,
+			Limit:  req.limit(),
+			Offset: req.Offset,
+		})
+		if err != nil {
+			return importError(err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+// GetImport returns the import with its progress and the number of rows per
+// outcome.
+func (i *ImportHandler) GetImport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req importIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		imp, err := i.usecase.GetImport(c.Request().Context(), jobScopeFromContext(c), req.ImportID)
+		if err != nil {
+			return importError(err)
+		}
+
+		return c.JSON(http.StatusOK, imp)
+	}
+}
+
+// GetImportRows lists the processed rows of an import in file order,
+// optionally only those with the given outcome.
+func (i *ImportHandler) GetImportRows() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req importRowsRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListImportRows(c.Request().Context(), domain.ImportRowFilter{
+			Scope:    jobScopeFromContext(c),
+			ImportID: req.ImportID,
+			Outcome:  req.Outcome,
+			Limit:    req.limit(),
+			Offset:   req.Offset,
+		})
+		if err != nil {
+			return importError(err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+// GetImportReport downloads the outcome of every processed row as CSV.
+func (i *ImportHandler) GetImportReport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req importIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		file, err := i.usecase.ImportReport(c.Request().Context(), jobScopeFromContext(c), req.ImportID)
+		if err != nil {
+			return importError(err)
+		}
+		defer file.Body.Close()
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+		header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+		header.Set(echo.HeaderCacheControl, "private, no-store")
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(file.Size, 10))
+
+		return c.Stream(http.StatusOK, file.ContentType, file.Body)
+	}
+}
+
+func importError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrImportNotFound), errors.Is(err, usecase.ErrJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrImportNoFiles), errors.Is(err, usecase.ErrUnknownStage):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrUnsupportedImport):
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, usecase.ErrImportTooLarge):
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("import error: %w", err))
+	}
+}
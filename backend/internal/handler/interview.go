@@ -0,0 +1,481 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	calendarContentType = "text/calendar; charset=utf-8"
+	// defaultAvailabilityPeriod is the period listed when no end is given.
+	defaultAvailabilityPeriod = 30 * 24 * time.Hour
+)
+
+type InterviewHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.InterviewUseCase
+}
+
+func NewInterviewHandler(cfg *config.Server, log *zap.Logger, usecase usecase.InterviewUseCase) *InterviewHandler {
+	return &InterviewHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type interviewListRequest struct {
+	pageRequest
+	CandidateID   string    `query:"candidate_id"   validate:"omitempty,uuid"`
+	JobID         string    `query:"job_id"         validate:"omitempty,uuid"`
+	InterviewerID string    `query:"interviewer_id" validate:"omitempty,uuid"`
+	Status        string    `query:"status"         validate:"omitempty,oneof=scheduled cancelled"`
+	From          time.Time `query:"from"`
+	To            time.Time `query:"to"`
+}
+
+type interviewIDRequest struct {
+	InterviewID string `param:"interviewId" validate:"required,uuid"`
+}
+
+type interviewSlotRequest struct {
+	Title           string    `json:"title"            validate:"omitempty,max=200"`
+	StartsAt        time.Time `json:"starts_at"        validate:"required"`
+	DurationMinutes int       `json:"duration_minutes" validate:"required,min=5,max=480"`
+	Timezone        string    `json:"timezone"         validate:"omitempty,max=64"`
+	Location        string    `json:"location"         validate:"omitempty,max=500"`
+	VideoURL        string    `json:"video_url"        validate:"omitempty,url,max=2000"`
+	Notes           string    `json:"notes"            validate:"omitempty,max=10000"`
+	Interviewers    []string  `json:"interviewers"     validate:"required,min=1,max=20,dive,uuid"`
+	Force           bool      `json:"force"`
+	Notify          *bool     `json:"notify"`
+}
+
+func (r *interviewSlotRequest) params() domain.InterviewParams {
+	return domain.InterviewParams{
+		Title:        r.Title,
+		StartsAt:     r.StartsAt,
+		Duration:     time.Duration(r.DurationMinutes) * time.Minute,
+		Timezone:     r.Timezone,
+		Location:     r.Location,
+		VideoURL:     r.VideoURL,
+		Notes:        r.Notes,
+		Interviewers: r.Interviewers,
+		Force:        r.Force,
+		Notify:       r.Notify == nil || *r.Notify,
+	}
+}
+
+type interviewRequest struct {
+	interviewSlotRequest
+	CandidateID string `json:"candidate_id" validate:"required,uuid"`
+	Stage       string `json:"stage"        validate:"omitempty,max=32"`
+}
+
+type interviewUpdateRequest struct {
+	interviewSlotRequest
+	InterviewID string `param:"interviewId" validate:"required,uuid"`
+}
+
+type interviewConflictsRequest struct {
+	CandidateID     string    `query:"candidate_id"     validate:"required,uuid"`
+	InterviewID     string    `query:"interview_id"     validate:"omitempty,uuid"`
+	StartsAt        time.Time `query:"starts_at"        validate:"required"`
+	DurationMinutes int       `query:"duration_minutes" validate:"required,min=5,max=480"`
+	Interviewers    []string  `query:"interviewers"     validate:"required,min=1,max=20,dive,uuid"`
+}
+
+type interviewCancelRequest struct {
+	InterviewID string `param:"interviewId" validate:"required,uuid"`
+	Reason      string `json:"reason"       validate:"omitempty,max=1000"`
+	Notify      *bool  `json:"notify"`
+}
+
+type availabilityListRequest struct {
+	UserID string    `query:"user_id" validate:"omitempty,uuid"`
+	From   time.Time `query:"from"`
+	To     time.Time `query:"to"`
+}
+
+type availabilityRequest struct {
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at"   validate:"required"`
+}
+
+type availabilityIDRequest struct {
+	WindowID string `param:"windowId" validate:"required,uuid"`
+}
+
+type calendarFeedRequest struct {
+	Token string `param:"token" validate:"required,max=64"`
+}
+
+// GetInterviews lists the interviews of the jobs the user can see by start
+// time. from and to limit them to those overlapping the period.
+func (i *InterviewHandler) GetInterviews() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		filter := domain.InterviewFilter{
+			Scope:         jobScopeFromContext(c),
+			CandidateID:   req.CandidateID,
+			JobID:         req.JobID,
+			InterviewerID: req.InterviewerID,
+			Status:        req.Status,
+			Limit:         req.limit(),
+			Offset:        req.Offset,
+		}
+
+		if !req.From.IsZero() {
+			filter.From = &req.From
+		}
+
+		if !req.To.IsZero() {
+			filter.To = &req.To
+		}
+
+		page, err := i.usecase.ListInterviews(c.Request().Context(), filter)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+// PostInterview schedules an interview with a candidate. A slot taken by
+// another interview of the candidate or an interviewer, or outside an
+// interviewer's availability, is refused with the conflicts unless force is
+// set. notify, true by default, emails invitations to everyone.
+func (i *InterviewHandler) PostInterview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		params := req.params()
+		params.CandidateID = req.CandidateID
+		params.Stage = strings.TrimSpace(req.Stage)
+
+		interview, err := i.usecase.ScheduleInterview(c.Request().Context(), jobScopeFromContext(c), params)
+		if err != nil {
+			return interviewConflictResponse(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, interview)
+	}
+}
+
+// GetInterviewConflicts checks a slot before booking it.
+func (i *InterviewHandler) GetInterviewConflicts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewConflictsRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		conflicts, err := i.usecase.CheckConflicts(c.Request().Context(), jobScopeFromContext(c), req.InterviewID, domain.InterviewParams{
+			CandidateID:  req.CandidateID,
+			StartsAt:     req.StartsAt,
+			Duration:     time.Duration(req.DurationMinutes) * time.Minute,
+			Interviewers: req.Interviewers,
+		})
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusOK, map[string]any{
+			"available": len(conflicts) == 0,
+			"conflicts": conflicts,
+		})
+	}
+}
+
+func (i *InterviewHandler) GetInterview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		interview, err := i.usecase.GetInterview(c.Request().Context(), jobScopeFromContext(c), req.InterviewID)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusOK, interview)
+	}
+}
+
+// PutInterview reschedules an interview: its time, place and interviewers
+// are replaced. With notify the candidate and the interviewers receive
+// updated invitations, added interviewers new ones and removed interviewers
+// cancellations.
+func (i *InterviewHandler) PutInterview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewUpdateRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		interview, err := i.usecase.RescheduleInterview(c.Request().Context(), jobScopeFromContext(c), req.InterviewID, req.params())
+		if err != nil {
+			return interviewConflictResponse(c, err)
+		}
+
+		return c.JSON(http.StatusOK, interview)
+	}
+}
+
+// PostInterviewCancel cancels an interview; notify, true by default, emails
+// the cancellation to everyone invited.
+func (i *InterviewHandler) PostInterviewCancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewCancelRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		interview, err := i.usecase.CancelInterview(c.Request().Context(), jobScopeFromContext(c), req.InterviewID, req.Reason, req.Notify == nil || *req.Notify)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusOK, interview)
+	}
+}
+
+// GetInterviewCalendar downloads the interview as an .ics file.
+func (i *InterviewHandler) GetInterviewCalendar() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req interviewIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		data, err := i.usecase.InterviewCalendar(c.Request().Context(), jobScopeFromContext(c), req.InterviewID)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": "interview.ics"}))
+		header.Set(echo.HeaderCacheControl, "private, no-store")
+
+		return c.Blob(http.StatusOK, calendarContentType, data)
+	}
+}
+
+// GetAvailability lists the availability windows of a member, the user by
+// default, overlapping the period from now or from for 30 days or until to.
+func (i *InterviewHandler) GetAvailability() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req availabilityListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		if req.UserID == "" {
+			req.UserID = session.UserID
+		}
+
+		if req.From.IsZero() {
+			req.From = time.Now()
+		}
+
+		if req.To.IsZero() {
+			req.To = req.From.Add(defaultAvailabilityPeriod)
+		}
+
+		windows, err := i.usecase.ListAvailability(c.Request().Context(), session.TeamID, req.UserID, req.From, req.To)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusOK, windows)
+	}
+}
+
+// PostAvailability adds a window in which the user is free for interviews.
+// Once a member has windows, interviews outside them are conflicts.
+func (i *InterviewHandler) PostAvailability() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req availabilityRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		window, err := i.usecase.AddAvailability(c.Request().Context(), sessionFromContext(c).UserID, req.StartsAt, req.EndsAt)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusCreated, window)
+	}
+}
+
+func (i *InterviewHandler) DeleteAvailability() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req availabilityIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.DeleteAvailability(c.Request().Context(), sessionFromContext(c).UserID, req.WindowID); err != nil {
+			return interviewError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// PostCalendarFeed issues the subscription URL of the user's interview
+// calendar. The URL is only shown once; issuing a new one revokes the old.
+func (i *InterviewHandler) PostCalendarFeed() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		feed, err := i.usecase.CreateCalendarFeed(c.Request().Context(), sessionFromContext(c).UserID)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		return c.JSON(http.StatusCreated, feed)
+	}
+}
+
+func (i *InterviewHandler) DeleteCalendarFeed() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := i.usecase.DeleteCalendarFeed(c.Request().Context(), sessionFromContext(c).UserID); err != nil {
+			return interviewError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetCalendarFeed serves a subscribed calendar. The token in the URL is the
+// only credential, so calendar applications need no session.
+func (i *InterviewHandler) GetCalendarFeed() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req calendarFeedRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		req.Token = strings.TrimSuffix(req.Token, ".ics")
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		data, err := i.usecase.CalendarFeed(c.Request().Context(), req.Token)
+		if err != nil {
+			return interviewError(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderCacheControl, "private, max-age=300")
+
+		return c.Blob(http.StatusOK, calendarContentType, data)
+	}
+}
+
+// interviewConflictResponse answers a refused booking with the conflicts so
+// the client can offer to book anyway.
+func interviewConflictResponse(c echo.Context, err error) error {
+	var conflict *usecase.InterviewConflictError
+
+	if errors.As(err, &conflict) {
+		return c.JSON(http.StatusConflict, map[string]any{
+			"message":   conflict.Error(),
+			"conflicts": conflict.Conflicts,
+		})
+	}
+
+	return interviewError(err)
+}
+
+func interviewError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInterviewNotFound),
+		errors.Is(err, usecase.ErrCandidateNotFound),
+		errors.Is(err, usecase.ErrAvailabilityNotFound),
+		errors.Is(err, usecase.ErrCalendarFeedNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidInterview),
+		errors.Is(err, usecase.ErrInvalidAvailability),
+		errors.Is(err, usecase.ErrInterviewerNotMember),
+		errors.Is(err, usecase.ErrInterviewerNoAccess):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrInterviewCancelled), errors.Is(err, usecase.ErrInterviewConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("interview error: %w", err))
+	}
+}
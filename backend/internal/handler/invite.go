@@ -82,6 +82,10 @@ func (i *InviteHandler) PostInvite() echo.HandlerFunc {
 			Role:   req.Role,
 			JobIDs: req.JobIDs,
 		}); err != nil {
+			if errors.Is(err, usecase.ErrJobNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			}
+
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("invite error: %w", err))
 		}
 
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type JobHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.JobUseCase
+}
+
+func NewJobHandler(cfg *config.Server, log *zap.Logger, usecase usecase.JobUseCase) *JobHandler {
+	return &JobHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type jobRequest struct {
+	JobID       string `param:"jobId"       validate:"omitempty,uuid"`
+	Title       string `json:"title"        validate:"required,min=2,max=255"`
+	Department  string `json:"department"   validate:"max=128"`
+	WorkFormat  string `json:"work_format"  validate:"omitempty,oneof=remote office hybrid"`
+	Description string `json:"description"  validate:"max=20000"`
+}
+
+type jobStatusRequest struct {
+	JobID  string `param:"jobId"  validate:"required,uuid"`
+	Status string `json:"status"  validate:"required,oneof=draft pending_approval open paused closed filled"`
+	Reason string `json:"reason"  validate:"max=1000"`
+}
+
+type jobSettingsRequest struct {
+	ApprovalRequired *bool `json:"approval_required" validate:"required"`
+}
+
+type jobIDRequest struct {
+	JobID string `param:"jobId" validate:"required,uuid"`
+}
+
+type jobListRequest struct {
+	pageRequest
+	Status     string `query:"status"      validate:"omitempty,max=32"`
+	Department string `query:"department"  validate:"omitempty,max=128"`
+	WorkFormat string `query:"work_format" validate:"omitempty,oneof=remote office hybrid"`
+}
+
+func (i *JobHandler) GetJobs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListJobs(c.Request().Context(), domain.JobFilter{
+			Scope:      jobScopeFromContext(c),
+			Status:     req.Status,
+			Department: req.Department,
+			WorkFormat: req.WorkFormat,
+			Limit:      req.limit(),
+			Offset:     req.Offset,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("list jobs error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+func (i *JobHandler) GetJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		job, err := i.usecase.GetJob(c.Request().Context(), session.TeamID, req.JobID)
+		if err != nil {
+			return jobError(err)
+		}
+
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+func (i *JobHandler) PostJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		job, err := i.usecase.CreateJob(c.Request().Context(), jobScopeFromContext(c), req.params())
+		if err != nil {
+			return jobError(err)
+		}
+
+		return c.JSON(http.StatusCreated, job)
+	}
+}
+
+func (i *JobHandler) PutJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		job, err := i.usecase.UpdateJob(c.Request().Context(), session.TeamID, req.JobID, req.params())
+		if err != nil {
+			return jobError(err)
+		}
+
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+func (i *JobHandler) DeleteJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		if err := i.usecase.DeleteJob(c.Request().Context(), session.TeamID, req.JobID); err != nil {
+			return jobError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (i *JobHandler) PostStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobStatusRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		job, err := i.usecase.ChangeStatus(c.Request().Context(), sessionFromContext(c), req.JobID, req.Status, req.Reason)
+		if err != nil {
+			return jobError(err)
+		}
+
+		return c.JSON(http.StatusOK, job)
+	}
+}
+
+func (i *JobHandler) GetSettings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session := sessionFromContext(c)
+
+		settings, err := i.usecase.GetSettings(c.Request().Context(), session.TeamID)
+		if err != nil {
+			return jobError(err)
+		}
+
+		return c.JSON(http.StatusOK, settings)
+	}
+}
+
+func (i *JobHandler) PutSettings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobSettingsRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		settings, err := i.usecase.UpdateSettings(c.Request().Context(), sessionFromContext(c), domain.JobSettings{
+			ApprovalRequired: *req.ApprovalRequired,
+		})
+		if err != nil {
+			return jobError(err)
+		}
+
+		return c.JSON(http.StatusOK, settings)
+	}
+}
+
+func (r jobRequest) params() domain.JobParams {
+	return domain.JobParams{
+		Title:       r.Title,
+		Department:  r.Department,
+		WorkFormat:  r.WorkFormat,
+		Description: r.Description,
+	}
+}
+
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidJobTransition), errors.Is(err, usecase.ErrCloseReasonRequired):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, usecase.ErrJobApprovalRequired), errors.Is(err, usecase.ErrNotJobApprover):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, usecase.ErrJobStatusConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("job error: %w", err))
+	}
+}
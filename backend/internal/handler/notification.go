@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type NotificationHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(cfg *config.Server, log *zap.Logger, usecase usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type notificationListRequest struct {
+	pageRequest
+	Unread bool `query:"unread"`
+}
+
+type notificationIDRequest struct {
+	NotificationID string `param:"notificationId" validate:"required,uuid"`
+}
+
+// GetNotifications lists the caller's notifications, newest first. With
+// unread=true only unread ones are listed and total is the unread count.
+func (i *NotificationHandler) GetNotifications() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req notificationListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListNotifications(c.Request().Context(), domain.NotificationFilter{
+			UserID: sessionFromContext(c).UserID,
+			Unread: req.Unread,
+			Limit:  req.limit(),
+			Offset: req.Offset,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("notification error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+func (i *NotificationHandler) PostRead() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req notificationIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.MarkRead(c.Request().Context(), sessionFromContext(c).UserID, req.NotificationID); err != nil {
+			if errors.Is(err, usecase.ErrNotificationNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("notification error: %w", err))
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// PostReadAll marks every unread notification of the caller as read.
+func (i *NotificationHandler) PostReadAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		count, err := i.usecase.MarkAllRead(c.Request().Context(), sessionFromContext(c).UserID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("notification error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, map[string]int{"updated": count})
+	}
+}
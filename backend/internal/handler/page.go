@@ -0,0 +1,20 @@
+package handler
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// pageRequest holds the offset pagination parameters shared by list routes.
+type pageRequest struct {
+	Limit  int `query:"limit"  validate:"omitempty,min=1,max=200"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+}
+
+func (p pageRequest) limit() int {
+	if p.Limit == 0 {
+		return defaultPageLimit
+	}
+
+	return min(p.Limit, maxPageLimit)
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type PipelineHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.PipelineUseCase
+}
+
+func NewPipelineHandler(cfg *config.Server, log *zap.Logger, usecase usecase.PipelineUseCase) *PipelineHandler {
+	return &PipelineHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type stageRequest struct {
+	ID          string   `json:"id"          validate:"omitempty,uuid"`
+	Name        string   `json:"name"        validate:"required,max=32"`
+	Type        string   `json:"type"        validate:"required,oneof=new screening interview offer hired rejected"`
+	Transitions []string `json:"transitions" validate:"omitempty,max=30,dive,required,max=32"`
+}
+
+type pipelineRequest struct {
+	TemplateID string            `param:"pipelineId" validate:"omitempty,uuid"`
+	Name       string            `json:"name"       validate:"required,min=2,max=100"`
+	IsDefault  bool              `json:"is_default"`
+	Stages     []stageRequest    `json:"stages"     validate:"required,min=1,max=30,dive"`
+	Reassign   map[string]string `json:"reassign"   validate:"omitempty,max=30,dive,keys,required,max=32,endkeys,required,max=32"`
+}
+
+type pipelineIDRequest struct {
+	TemplateID string `param:"pipelineId" validate:"required,uuid"`
+}
+
+type jobPipelineRequest struct {
+	JobID      string            `param:"jobId"        validate:"required,uuid"`
+	TemplateID string            `json:"template_id"   validate:"omitempty,uuid"`
+	Stages     []stageRequest    `json:"stages"        validate:"omitempty,max=30,excluded_with=TemplateID,dive"`
+	Reassign   map[string]string `json:"reassign"      validate:"omitempty,max=30,dive,keys,required,max=32,endkeys,required,max=32"`
+}
+
+func (r stageRequest) toStage() domain.Stage {
+	return domain.Stage{
+		ID:          r.ID,
+		Name:        r.Name,
+		Type:        r.Type,
+		Transitions: r.Transitions,
+	}
+}
+
+func toStages(stages []stageRequest) domain.Stages {
+	if stages == nil {
+		return nil
+	}
+
+	result := make(domain.Stages, len(stages))
+	for i, stage := range stages {
+		result[i] = stage.toStage()
+	}
+
+	return result
+}
+
+func (r pipelineRequest) toParams() domain.PipelineParams {
+	return domain.PipelineParams{
+		Name:      r.Name,
+		IsDefault: r.IsDefault,
+		Stages:    toStages(r.Stages),
+		Reassign:  r.Reassign,
+	}
+}
+
+func (i *PipelineHandler) GetTemplates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		templates, err := i.usecase.ListTemplates(c.Request().Context(), sessionFromContext(c).TeamID)
+		if err != nil {
+			return pipelineError(err)
+		}
+
+		return c.JSON(http.StatusOK, templates)
+	}
+}
+
+func (i *PipelineHandler) GetTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req pipelineIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		template, err := i.usecase.GetTemplate(c.Request().Context(), sessionFromContext(c).TeamID, req.TemplateID)
+		if err != nil {
+			return pipelineError(err)
+		}
+
+		return c.JSON(http.StatusOK, template)
+	}
+}
+
+func (i *PipelineHandler) PostTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req pipelineRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		template, err := i.usecase.CreateTemplate(c.Request().Context(), sessionFromContext(c), req.toParams())
+		if err != nil {
+			return pipelineError(err)
+		}
+
+		return c.JSON(http.StatusCreated, template)
+	}
+}
+
+// PutTemplate replaces the template's stages. Stages sent with their id keep
+// their candidates under a new name; reassign maps the names of dropped
+// stages to the stage their candidates move to.
+func (i *PipelineHandler) PutTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req pipelineRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if req.TemplateID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "incorrect data: pipelineId is required")
+		}
+
+		template, err := i.usecase.UpdateTemplate(c.Request().Context(), sessionFromContext(c), req.TemplateID, req.toParams())
+		if err != nil {
+			return pipelineError(err)
+		}
+
+		return c.JSON(http.StatusOK, template)
+	}
+}
+
+func (i *PipelineHandler) DeleteTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req pipelineIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.DeleteTemplate(c.Request().Context(), sessionFromContext(c), req.TemplateID); err != nil {
+			return pipelineError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (i *PipelineHandler) GetJobPipeline() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		pipeline, err := i.usecase.GetJobPipeline(c.Request().Context(), sessionFromContext(c).TeamID, req.JobID)
+		if err != nil {
+			return pipelineError(err)
+		}
+
+		return c.JSON(http.StatusOK, pipeline)
+	}
+}
+
+// PutJobPipeline gives the job its own stages, assigns it the template
+// template_id or, when the body names neither, returns it to the team
+// default.
+func (i *PipelineHandler) PutJobPipeline() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobPipelineRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		pipeline, err := i.usecase.SetJobPipeline(c.Request().Context(), sessionFromContext(c), req.JobID, domain.JobPipelineParams{
+			TemplateID: req.TemplateID,
+			Stages:     toStages(req.Stages),
+			Reassign:   req.Reassign,
+		})
+		if err != nil {
+			return pipelineError(err)
+		}
+
+		return c.JSON(http.StatusOK, pipeline)
+	}
+}
+
+func pipelineError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrPipelineNotFound), errors.Is(err, usecase.ErrJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrNotTemplateEditor):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, usecase.ErrInvalidPipeline):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, usecase.ErrPipelineExists), errors.Is(err, usecase.ErrPipelineConflict),
+		errors.Is(err, usecase.ErrPipelineInUse), errors.Is(err, usecase.ErrStageInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("pipeline error: %w", err))
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"backend/pkg/profile"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// maxProfilePatchSize bounds a profile correction; a whole profile fits well
+// within it.
+const maxProfilePatchSize = 1 << 20
+
+type ProfileHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.ProfileUseCase
+}
+
+func NewProfileHandler(cfg *config.Server, log *zap.Logger, usecase usecase.ProfileUseCase) *ProfileHandler {
+	return &ProfileHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+// GetProfileSchema returns the JSON Schema of the current profile version.
+func (i *ProfileHandler) GetProfileSchema() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set("X-Profile-Schema-Version", fmt.Sprint(profile.Version))
+
+		return c.Blob(http.StatusOK, "application/schema+json", profile.Schema)
+	}
+}
+
+func (i *ProfileHandler) GetProfile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		p, err := i.usecase.GetProfile(c.Request().Context(), sessionFromContext(c).TeamID, req.CandidateID)
+		if err != nil {
+			return profileError(err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// PatchProfile corrects profile fields. The body is a JSON merge patch
+// (RFC 7396) of the profile: objects merge, null removes a field and arrays
+// are replaced as a whole.
+func (i *ProfileHandler) PatchProfile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := (&echo.DefaultBinder{}).BindPathParams(c, &req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxProfilePatchSize+1))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if len(body) > maxProfilePatchSize {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "profile patch is too large")
+		}
+
+		if !json.Valid(body) {
+			return echo.NewHTTPError(http.StatusBadRequest, "incorrect bind: body is not valid JSON")
+		}
+
+		p, err := i.usecase.CorrectProfile(c.Request().Context(), sessionFromContext(c), req.CandidateID, body)
+		if err != nil {
+			return profileError(err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// DeleteProfileCorrections drops the recruiters' corrections.
+func (i *ProfileHandler) DeleteProfileCorrections() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		p, err := i.usecase.ResetCorrections(c.Request().Context(), sessionFromContext(c), req.CandidateID)
+		if err != nil {
+			return profileError(err)
+		}
+
+		return c.JSON(http.StatusOK, p)
+	}
+}
+
+// PostProfileExtraction queues a new extraction of the profile from the
+// resume; corrections are applied on top of the result.
+func (i *ProfileHandler) PostProfileExtraction() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req candidateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.RequestExtraction(c.Request().Context(), sessionFromContext(c), req.CandidateID); err != nil {
+			return profileError(err)
+		}
+
+		return c.JSON(http.StatusAccepted, map[string]string{"status": "queued"})
+	}
+}
+
+func profileError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCandidateNotFound), errors.Is(err, usecase.ErrNoResumeText):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrProfileInvalid):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, usecase.ErrProfileConflict), errors.Is(err, usecase.ErrExtractionQueued),
+		errors.Is(err, usecase.ErrExtractionCancelled):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("profile error: %w", err))
+	}
+}
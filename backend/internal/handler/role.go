@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type RoleHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.RoleUseCase
+}
+
+func NewRoleHandler(cfg *config.Server, log *zap.Logger, usecase usecase.RoleUseCase) *RoleHandler {
+	return &RoleHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type roleRequest struct {
+	RoleID      string   `param:"roleId"     validate:"omitempty,uuid"`
+	Name        string   `json:"name"        validate:"required,min=2,max=64"`
+	Description string   `json:"description" validate:"max=512"`
+	Permissions []string `json:"permissions" validate:"required,dive,required"`
+}
+
+type roleIDRequest struct {
+	RoleID string `param:"roleId" validate:"required,uuid"`
+}
+
+type roleMemberRequest struct {
+	RoleID string `param:"roleId" validate:"required,uuid"`
+	UserID string `param:"userId" validate:"required,uuid"`
+}
+
+func (i *RoleHandler) GetPermissions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, i.usecase.ListPermissions())
+	}
+}
+
+func (i *RoleHandler) GetRoles() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session := sessionFromContext(c)
+
+		roles, err := i.usecase.ListRoles(c.Request().Context(), session.TeamID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("list roles error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, roles)
+	}
+}
+
+func (i *RoleHandler) PostRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req roleRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		role, err := i.usecase.CreateRole(c.Request().Context(), session.TeamID, domain.RoleParams{
+			Name:        req.Name,
+			Description: req.Description,
+			Permissions: req.Permissions,
+		})
+		if err != nil {
+			return roleError(err)
+		}
+
+		return c.JSON(http.StatusCreated, role)
+	}
+}
+
+func (i *RoleHandler) PutRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req roleRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		role, err := i.usecase.UpdateRole(c.Request().Context(), session.TeamID, req.RoleID, domain.RoleParams{
+			Name:        req.Name,
+			Description: req.Description,
+			Permissions: req.Permissions,
+		})
+		if err != nil {
+			return roleError(err)
+		}
+
+		return c.JSON(http.StatusOK, role)
+	}
+}
+
+func (i *RoleHandler) DeleteRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req roleIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		if err := i.usecase.DeleteRole(c.Request().Context(), session.TeamID, req.RoleID); err != nil {
+			return roleError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (i *RoleHandler) PutMember() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req roleMemberRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		if err := i.usecase.AssignRole(c.Request().Context(), session.TeamID, req.RoleID, req.UserID); err != nil {
+			return roleError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (i *RoleHandler) DeleteMember() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req roleMemberRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		if err := i.usecase.UnassignRole(c.Request().Context(), session.TeamID, req.RoleID, req.UserID); err != nil {
+			return roleError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrRoleNotFound), errors.Is(err, usecase.ErrMemberNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrRoleAlreadyExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, usecase.ErrUnknownPermission):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("role error: %w", err))
+	}
+}
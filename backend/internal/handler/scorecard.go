@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type ScorecardHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.ScorecardUseCase
+}
+
+func NewScorecardHandler(cfg *config.Server, log *zap.Logger, usecase usecase.ScorecardUseCase) *ScorecardHandler {
+	return &ScorecardHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type criterionRequest struct {
+	Key             string   `json:"key"              validate:"required,max=32"`
+	Name            string   `json:"name"             validate:"required,max=100"`
+	Description     string   `json:"description"      validate:"omitempty,max=1000"`
+	Scale           int      `json:"scale"            validate:"required,min=2,max=10"`
+	Labels          []string `json:"labels"           validate:"omitempty,max=10,dive,required,max=50"`
+	CommentRequired bool     `json:"comment_required"`
+}
+
+type scorecardTemplateRequest struct {
+	JobID    string             `param:"jobId"   validate:"required,uuid"`
+	Stage    string             `json:"stage"    validate:"required,max=32"`
+	Blind    *bool              `json:"blind"`
+	Criteria []criterionRequest `json:"criteria" validate:"required,min=1,max=30,dive"`
+}
+
+type scorecardTemplateIDRequest struct {
+	JobID string `param:"jobId" validate:"required,uuid"`
+	Stage string `query:"stage" validate:"required,max=32"`
+}
+
+type scorecardListRequest struct {
+	CandidateID string `param:"candidateId" validate:"required,uuid"`
+	Stage       string `query:"stage"       validate:"omitempty,max=32"`
+}
+
+type ratingRequest struct {
+	Key     string `json:"key"     validate:"required,max=32"`
+	Rating  int    `json:"rating"  validate:"required,min=1,max=10"`
+	Comment string `json:"comment" validate:"omitempty,max=2000"`
+}
+
+type scorecardRequest struct {
+	CandidateID    string          `param:"candidateId"   validate:"required,uuid"`
+	Stage          string          `json:"stage"          validate:"omitempty,max=32"`
+	Ratings        []ratingRequest `json:"ratings"        validate:"required,min=1,max=30,dive"`
+	Recommendation string          `json:"recommendation" validate:"required,oneof=strong_no no yes strong_yes"`
+	Summary        string          `json:"summary"        validate:"omitempty,max=10000"`
+}
+
+type scorecardComparisonRequest struct {
+	JobID string `param:"jobId" validate:"required,uuid"`
+	Stage string `query:"stage" validate:"omitempty,max=32"`
+}
+
+// GetScorecardTemplates lists the scorecard templates of a job.
+func (i *ScorecardHandler) GetScorecardTemplates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req jobIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		templates, err := i.usecase.ListTemplates(c.Request().Context(), sessionFromContext(c).TeamID, req.JobID)
+		if err != nil {
+			return scorecardError(err)
+		}
+
+		return c.JSON(http.StatusOK, templates)
+	}
+}
+
+// PutScorecardTemplate sets the criteria interviewers rate candidates on in
+// a stage of the job's pipeline. blind defaults to true.
+func (i *ScorecardHandler) PutScorecardTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req scorecardTemplateRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		req.Stage = strings.TrimSpace(req.Stage)
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		criteria := make([]domain.Criterion, len(req.Criteria))
+		for n, criterion := range req.Criteria {
+			criteria[n] = domain.Criterion{
+				Key:             criterion.Key,
+				Name:            strings.TrimSpace(criterion.Name),
+				Description:     strings.TrimSpace(criterion.Description),
+				Scale:           criterion.Scale,
+				Labels:          criterion.Labels,
+				CommentRequired: criterion.CommentRequired,
+			}
+		}
+
+		template, err := i.usecase.SaveTemplate(c.Request().Context(), sessionFromContext(c), domain.ScorecardTemplateParams{
+			JobID:    req.JobID,
+			Stage:    req.Stage,
+			Blind:    req.Blind == nil || *req.Blind,
+			Criteria: criteria,
+		})
+		if err != nil {
+			return scorecardError(err)
+		}
+
+		return c.JSON(http.StatusOK, template)
+	}
+}
+
+// DeleteScorecardTemplate removes the template of the stage given by the
+// stage query parameter. Scorecards already submitted are kept.
+func (i *ScorecardHandler) DeleteScorecardTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req scorecardTemplateIDRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		if err := i.usecase.DeleteTemplate(c.Request().Context(), sessionFromContext(c), req.JobID, req.Stage); err != nil {
+			return scorecardError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetCandidateTemplate returns the template to rate the candidate on in the
+// given stage, by default the stage the candidate is in.
+func (i *ScorecardHandler) GetCandidateTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req scorecardListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		template, err := i.usecase.GetCandidateTemplate(c.Request().Context(), jobScopeFromContext(c), req.CandidateID, req.Stage)
+		if err != nil {
+			return scorecardError(err)
+		}
+
+		return c.JSON(http.StatusOK, template)
+	}
+}
+
+// GetScorecards returns the candidate's scorecards the caller may read with
+// their aggregates, optionally only those of one stage. In blind stages the
+// others' scorecards stay hidden until the caller has submitted their own;
+// only their number is reported.
+func (i *ScorecardHandler) GetScorecards() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req scorecardListRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		scorecards, err := i.usecase.ListScorecards(c.Request().Context(), jobScopeFromContext(c), req.CandidateID, req.Stage)
+		if err != nil {
+			return scorecardError(err)
+		}
+
+		return c.JSON(http.StatusOK, scorecards)
+	}
+}
+
+// PutScorecard submits the caller's scorecard of the candidate in stage, by
+// default the stage the candidate is in, replacing an earlier one.
+func (i *ScorecardHandler) PutScorecard() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req scorecardRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		req.Stage = strings.TrimSpace(req.Stage)
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		ratings := make([]domain.Rating, len(req.Ratings))
+		for n, rating := range req.Ratings {
+			ratings[n] = domain.Rating{Key: rating.Key, Rating: rating.Rating, Comment: rating.Comment}
+		}
+
+		card, err := i.usecase.SubmitScorecard(c.Request().Context(), jobScopeFromContext(c), domain.ScorecardParams{
+			CandidateID:    req.CandidateID,
+			Stage:          req.Stage,
+			Ratings:        ratings,
+			Recommendation: req.Recommendation,
+			Summary:        req.Summary,
+		})
+		if err != nil {
+			return scorecardError(err)
+		}
+
+		return c.JSON(http.StatusOK, card)
+	}
+}
+
+// GetComparison compares the evaluated candidates of a job, optionally
+// within one stage.
+func (i *ScorecardHandler) GetComparison() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req scorecardComparisonRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		comparison, err := i.usecase.CompareScorecards(c.Request().Context(), jobScopeFromContext(c), req.JobID, req.Stage)
+		if err != nil {
+			return scorecardError(err)
+		}
+
+		return c.JSON(http.StatusOK, comparison)
+	}
+}
+
+func scorecardError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrJobNotFound), errors.Is(err, usecase.ErrCandidateNotFound),
+		errors.Is(err, usecase.ErrScorecardTemplateNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrUnknownStage):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrInvalidScorecardTemplate), errors.Is(err, usecase.ErrInvalidScorecard):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("scorecard error: %w", err))
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type SearchHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.SearchUseCase
+}
+
+func NewSearchHandler(cfg *config.Server, log *zap.Logger, usecase usecase.SearchUseCase) *SearchHandler {
+	return &SearchHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type searchRequest struct {
+	Query       string   `query:"q"           validate:"required,min=2,max=200"`
+	Cursor      string   `query:"cursor"      validate:"omitempty,max=256"`
+	Limit       int      `query:"limit"       validate:"omitempty,min=1,max=200"`
+	JobIDs      []string `query:"job_id"      validate:"omitempty,max=50,dive,uuid"`
+	Stages      []string `query:"stage"       validate:"omitempty,max=30,dive,required,max=32"`
+	ScoreBands  []string `query:"score_band"  validate:"omitempty,max=5,dive,oneof=80-100 60-79 40-59 0-39 unscored"`
+	Locations   []string `query:"location"    validate:"omitempty,max=20,dive,required,max=255"`
+	WorkFormats []string `query:"work_format" validate:"omitempty,max=3,dive,oneof=remote office hybrid"`
+}
+
+// GetSearch searches the candidates of the jobs visible to the caller by
+// name, email, profile and resume text. q accepts the web search syntax:
+// quoted phrases, "or" and a leading "-" to exclude a word. Facet parameters
+// may be repeated to select several values.
+func (i *SearchHandler) GetSearch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req searchRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		search, err := req.search()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		search.Scope = jobScopeFromContext(c)
+
+		page, err := i.usecase.SearchCandidates(c.Request().Context(), search)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("search error: %w", err))
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+func (r searchRequest) search() (domain.CandidateSearch, error) {
+	search := domain.CandidateSearch{
+		Query:       strings.TrimSpace(r.Query),
+		JobIDs:      r.JobIDs,
+		Stages:      r.Stages,
+		ScoreBands:  r.ScoreBands,
+		Locations:   r.Locations,
+		WorkFormats: r.WorkFormats,
+		Limit:       pageRequest{Limit: r.Limit}.limit(),
+	}
+
+	if r.Cursor != "" {
+		cursor, err := domain.DecodeSearchCursor(r.Cursor)
+		if err != nil {
+			return search, err
+		}
+
+		search.Cursor = cursor
+	}
+
+	return search, nil
+}
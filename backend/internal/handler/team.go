@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type TeamHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.TeamUseCase
+}
+
+func NewTeamHandler(cfg *config.Server, log *zap.Logger, usecase usecase.TeamUseCase) *TeamHandler {
+	return &TeamHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type teamRequest struct {
+	Name     string              `json:"name"     validate:"required,min=2,max=128"`
+	Slug     string              `json:"slug"     validate:"required,min=2,max=64"`
+	Locale   string              `json:"locale"   validate:"required,bcp47_language_tag"`
+	Branding teamBrandingRequest `json:"branding"`
+}
+
+type teamBrandingRequest struct {
+	LogoURL      string `json:"logo_url"      validate:"omitempty,url,max=512"`
+	PrimaryColor string `json:"primary_color" validate:"omitempty,hexcolor"`
+	Website      string `json:"website"       validate:"omitempty,url,max=512"`
+	Tagline      string `json:"tagline"       validate:"max=255"`
+}
+
+func (i *TeamHandler) GetTeam() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session := sessionFromContext(c)
+
+		team, err := i.usecase.GetTeam(c.Request().Context(), session.TeamID)
+		if err != nil {
+			return teamError(err)
+		}
+
+		return c.JSON(http.StatusOK, team)
+	}
+}
+
+func (i *TeamHandler) PutTeam() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req teamRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		session := sessionFromContext(c)
+
+		team, err := i.usecase.UpdateTeam(c.Request().Context(), session.TeamID, domain.TeamParams{
+			Name:   req.Name,
+			Slug:   req.Slug,
+			Locale: req.Locale,
+			Branding: domain.TeamBranding{
+				LogoURL:      req.Branding.LogoURL,
+				PrimaryColor: req.Branding.PrimaryColor,
+				Website:      req.Branding.Website,
+				Tagline:      req.Branding.Tagline,
+			},
+		})
+		if err != nil {
+			return teamError(err)
+		}
+
+		return c.JSON(http.StatusOK, team)
+	}
+}
+
+func teamError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrTeamNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidSlug):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrSlugTaken):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("team error: %w", err))
+	}
+}
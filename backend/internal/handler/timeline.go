@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"backend/internal/domain"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type TimelineHandler struct {
+	cfg     *config.Server
+	log     *zap.Logger
+	usecase usecase.TimelineUseCase
+}
+
+func NewTimelineHandler(cfg *config.Server, log *zap.Logger, usecase usecase.TimelineUseCase) *TimelineHandler {
+	return &TimelineHandler{
+		cfg:     cfg,
+		log:     log,
+		usecase: usecase,
+	}
+}
+
+type timelineRequest struct {
+	pageRequest
+	CandidateID string   `param:"candidateId" validate:"required,uuid"`
+	Kinds       []string `query:"kind"        validate:"omitempty,max=7,dive,oneof=status_change communication score scorecard comment interview activity"`
+}
+
+// GetTimeline lists the history of a candidate newest first: stage changes,
+// communications, scores, scorecards, comments, interviews and logged
+// actions, each with its actor. kind, repeated or comma separated, limits the
+// entries to some kinds.
+func (i *TimelineHandler) GetTimeline() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req timelineRequest
+
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect bind: %w", err))
+		}
+
+		var kinds []string
+		for _, kind := range req.Kinds {
+			kinds = append(kinds, strings.Split(kind, ",")...)
+		}
+
+		req.Kinds = kinds
+
+		if err := c.Validate(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("incorrect data: %w", err))
+		}
+
+		page, err := i.usecase.ListTimeline(c.Request().Context(), domain.TimelineFilter{
+			Scope:       jobScopeFromContext(c),
+			CandidateID: req.CandidateID,
+			Kinds:       req.Kinds,
+			Limit:       req.limit(),
+			Offset:      req.Offset,
+		})
+		if err != nil {
+			return timelineError(err)
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
+
+func timelineError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCandidateNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("timeline error: %w", err))
+	}
+}
@@ -3,20 +3,26 @@ package middleware
 import (
 	"backend/internal/cache"
 	"backend/internal/db"
+	"backend/internal/domain"
+	"backend/internal/repo"
 	"backend/pkg/config"
 	"backend/pkg/logger"
 	"backend/pkg/rbac"
 	"backend/pkg/token"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
 type Middleware interface {
-	RateLimit(rateLimit config.RateLimit) echo.MiddlewareFunc
+	RateLimit(rateLimit config.RateLimit, opts ...RateLimitOption) echo.MiddlewareFunc
+	BodyLimit(limit int64) echo.MiddlewareFunc
 	Session(t *token.JWTtoken) echo.MiddlewareFunc
 	RBAC() echo.MiddlewareFunc
 }
@@ -26,14 +32,63 @@ type middleware struct {
 	redisClient    *db.RedisClient
 	cacheManager   *cache.Manager
 	casbinEnforcer *rbac.CasbinClient
+	accessRepo     repo.AccessRepository
+	activityRepo   repo.ActivityRepository
 }
 
-func (m *middleware) RateLimit(rateLimit config.RateLimit) echo.MiddlewareFunc {
+// RateLimitOption customises what a RateLimit middleware counts.
+type RateLimitOption func(*rateLimitOptions)
+
+type rateLimitOptions struct {
+	scope string
+	key   func(c echo.Context) string
+}
+
+// WithScope keeps a separate counter for the limiter, so a stricter limit on
+// a few routes does not consume the budget of the general one.
+func WithScope(scope string) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.scope = scope
+	}
+}
+
+// WithKey counts requests per key instead of per client IP. Requests for
+// which key returns an empty string are not limited.
+func WithKey(key func(c echo.Context) string) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.key = key
+	}
+}
+
+// FormValueKey returns a key function reading a normalised form field,
+// e.g. the applicant's email.
+func FormValueKey(field string) func(c echo.Context) string {
+	return func(c echo.Context) string {
+		return strings.ToLower(strings.TrimSpace(c.FormValue(field)))
+	}
+}
+
+func (m *middleware) RateLimit(rateLimit config.RateLimit, opts ...RateLimitOption) echo.MiddlewareFunc {
+	o := rateLimitOptions{
+		key: func(c echo.Context) string { return c.RealIP() },
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			ipKey := c.RealIP()
+			key := o.key(c)
+			if key == "" {
+				return next(c)
+			}
+
+			if o.scope != "" {
+				key = o.scope + ":" + key
+			}
 
-			count, err := cache.IncrWithTTL(c.Request().Context(), m.cacheManager, cache.RateLimitKey, ipKey, rateLimit.Window)
+			count, err := cache.IncrWithTTL(c.Request().Context(), m.cacheManager, cache.RateLimitKey, key, rateLimit.Window)
 			if err != nil {
 				m.log.Error("rate limit error", zap.Error(err))
 				return echo.NewHTTPError(http.StatusServiceUnavailable, "service temporarily unavailable")
@@ -64,6 +119,23 @@ func (m *middleware) RateLimit(rateLimit config.RateLimit) echo.MiddlewareFunc {
 	}
 }
 
+// BodyLimit rejects request bodies larger than limit bytes. It must run
+// before anything that reads the body, including form-based rate limits.
+func (m *middleware) BodyLimit(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.ContentLength > limit {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large")
+			}
+
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
+			return next(c)
+		}
+	}
+}
+
 func (m *middleware) Session(t *token.JWTtoken) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -103,28 +175,122 @@ func (m *middleware) RBAC() echo.MiddlewareFunc {
 			obj := c.Path()
 			act := c.Request().Method
 
-			ok, err := m.casbinEnforcer.Enforce(userID, teamID, obj, act)
+			res, err := m.resource(c, userID)
+			if err != nil {
+				if errors.Is(err, repo.ErrJobNotFound) {
+					return echo.NewHTTPError(http.StatusNotFound, "job not found")
+				}
+
+				if errors.Is(err, repo.ErrCandidateNotFound) {
+					return echo.NewHTTPError(http.StatusNotFound, "candidate not found")
+				}
+
+				m.log.Error("rbac resource error", zap.Error(err))
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "service temporarily unavailable")
+			}
+
+			ok, err := m.casbinEnforcer.Enforce(userID, teamID, obj, act, res)
 			if err != nil {
 				m.log.Error("rbac error", zap.Error(err))
 				return echo.NewHTTPError(http.StatusServiceUnavailable, "service temporarily unavailable")
 			}
 
 			if !ok {
-				m.log.Warn("rbac denied", zap.String("user_id", userID), zap.String("team_id", teamID), zap.String("obj", obj), zap.String("act", act))
+				m.log.Warn("rbac denied", zap.String("user_id", userID), zap.String("team_id", teamID), zap.String("obj", obj), zap.String("act", act), zap.String("job_id", res.JobID))
+				m.logDenial(c, userID, teamID, res)
+
 				return echo.NewHTTPError(http.StatusForbidden, "access denied")
 			}
 
+			allJobs, err := m.casbinEnforcer.HasPermissionInDomain(userID, domain.PermJobsAll, teamID)
+			if err != nil {
+				m.log.Error("rbac scope error", zap.Error(err))
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "service temporarily unavailable")
+			}
+
+			c.Set("job_scope", domain.JobScope{
+				UserID:  userID,
+				TeamID:  teamID,
+				AllJobs: allJobs,
+			})
+
 			return next(c)
 		}
 	}
 }
 
-func NewMiddleware(log *logger.Log, redisClient *db.RedisClient, cacheManager *cache.Manager, casbinEnforcer *rbac.CasbinClient) Middleware {
+// logDenial records a rejected request in the team activity log so owners
+// can review it. A failed write is logged and does not change the response.
+func (m *middleware) logDenial(c echo.Context, userID, teamID string, res rbac.Resource) {
+	activity := &domain.Activity{
+		TeamID:    teamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &userID,
+		Action:    domain.ActionAccessDenied,
+		Details: map[string]any{
+			"route":  c.Path(),
+			"path":   c.Request().URL.Path,
+			"method": c.Request().Method,
+			"ip":     c.RealIP(),
+		},
+	}
+
+	if res.JobID != "" {
+		activity.TargetID = &res.JobID
+	}
+
+	if err := m.activityRepo.LogActivity(c.Request().Context(), activity); err != nil {
+		m.log.Error("rbac denial audit error", zap.Error(err))
+	}
+}
+
+// resource collects the object attributes evaluated by the Casbin matcher.
+// Candidate routes are checked against the candidate's job. Routes without a
+// job or candidate parameter produce an empty resource.
+func (m *middleware) resource(c echo.Context, userID string) (rbac.Resource, error) {
+	var (
+		access *domain.JobAccess
+		err    error
+	)
+
+	switch {
+	case c.Param("jobId") != "":
+		jobID := c.Param("jobId")
+		if err := uuid.Validate(jobID); err != nil {
+			return rbac.Resource{}, repo.ErrJobNotFound
+		}
+
+		access, err = m.accessRepo.GetJobAccess(c.Request().Context(), userID, jobID)
+	case c.Param("candidateId") != "":
+		candidateID := c.Param("candidateId")
+		if err := uuid.Validate(candidateID); err != nil {
+			return rbac.Resource{}, repo.ErrCandidateNotFound
+		}
+
+		access, err = m.accessRepo.GetCandidateAccess(c.Request().Context(), userID, candidateID)
+	default:
+		return rbac.Resource{}, nil
+	}
+
+	if err != nil {
+		return rbac.Resource{}, err
+	}
+
+	return rbac.Resource{
+		TeamID:  access.TeamID,
+		JobID:   access.JobID,
+		Granted: access.Granted,
+	}, nil
+}
+
+func NewMiddleware(log *logger.Log, redisClient *db.RedisClient, cacheManager *cache.Manager, casbinEnforcer *rbac.CasbinClient, accessRepo repo.AccessRepository, activityRepo repo.ActivityRepository) Middleware {
 	return &middleware{
 		log:            log.Log,
 		redisClient:    redisClient,
 		cacheManager:   cacheManager,
 		casbinEnforcer: casbinEnforcer,
+		accessRepo:     accessRepo,
+		activityRepo:   activityRepo,
 	}
 }
 
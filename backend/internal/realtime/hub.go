@@ -0,0 +1,255 @@
+package realtime
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"backend/pkg/config"
+	"backend/pkg/svc"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// eventsChannel is notified by the triggers of migration 000018 on
+	// candidate, score and job access changes.
+	eventsChannel = "board_events"
+
+	defaultBuffer = 64
+
+	listenMinBackoff = time.Second
+	listenMaxBackoff = 30 * time.Second
+)
+
+// Hub delivers board events to the streams open on this replica. Every
+// replica listens to the same Postgres channel, so a change made through any
+// of them, or by the AI engine, reaches all streams of the job.
+type Hub struct {
+	log      *zap.Logger
+	dbClient *db.PostgresClient
+	buffer   int
+
+	mu     sync.Mutex
+	jobs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the events of one job. Done is closed when the
+// subscription ends: on Close, when the client falls more than the buffer
+// behind, or when the hub stops.
+type Subscription struct {
+	hub    *Hub
+	jobID  string
+	events chan domain.BoardEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewHub(log *zap.Logger, cfg *config.Realtime, dbClient *db.PostgresClient) *Hub {
+	buffer := cfg.Buffer
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+
+	return &Hub{
+		log:      log,
+		dbClient: dbClient,
+		buffer:   buffer,
+		jobs:     make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+func (h *Hub) Name() string {
+	return "realtime"
+}
+
+func (h *Hub) DependsOn() []string {
+	return []string{"logger", "db"}
+}
+
+func (h *Hub) Init(ctx context.Context) error {
+	return nil
+}
+
+func (h *Hub) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// Run listens until ctx is cancelled, then ends every subscription so open
+// streams do not hold up the API server shutdown.
+func (h *Hub) Run(ctx context.Context) error {
+	h.listen(ctx)
+	h.closeAll()
+
+	return nil
+}
+
+func (h *Hub) Stop(ctx context.Context) error {
+	h.closeAll()
+
+	return nil
+}
+
+// Subscribe starts receiving the events of a job. On a stopped hub the
+// subscription is already done.
+func (h *Hub) Subscribe(jobID string) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		jobID:  jobID,
+		events: make(chan domain.BoardEvent, h.buffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(sub.done)
+		return sub
+	}
+
+	if h.jobs[jobID] == nil {
+		h.jobs[jobID] = make(map[*Subscription]struct{})
+	}
+
+	h.jobs[jobID][sub] = struct{}{}
+
+	return sub
+}
+
+// Events returns the channel the job's events arrive on. It is never closed;
+// wait on Done as well.
+func (s *Subscription) Events() <-chan domain.BoardEvent {
+	return s.events
+}
+
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+// remove drops the subscription; the caller holds h.mu.
+func (h *Hub) remove(s *Subscription) {
+	s.once.Do(func() { close(s.done) })
+
+	subs := h.jobs[s.jobID]
+	delete(subs, s)
+
+	if len(subs) == 0 {
+		delete(h.jobs, s.jobID)
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for _, subs := range h.jobs {
+		for s := range subs {
+			h.remove(s)
+		}
+	}
+}
+
+// publish hands the event to the subscriptions of its job. A subscription
+// whose buffer is full is ended rather than blocking the others.
+func (h *Hub) publish(event domain.BoardEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.jobs[event.JobID] {
+		select {
+		case s.events <- event:
+		default:
+			h.log.Warn("board stream fell behind", zap.String("job_id", event.JobID))
+			h.remove(s)
+		}
+	}
+}
+
+// resync tells every subscription that events may have been missed.
+func (h *Hub) resync() {
+	h.mu.Lock()
+	jobs := make([]string, 0, len(h.jobs))
+	for jobID := range h.jobs {
+		jobs = append(jobs, jobID)
+	}
+	h.mu.Unlock()
+
+	for _, jobID := range jobs {
+		h.publish(domain.BoardEvent{Type: domain.EventResync, JobID: jobID})
+	}
+}
+
+// listen publishes every notification until ctx is cancelled, reconnecting
+// with exponential backoff.
+func (h *Hub) listen(ctx context.Context) {
+	backoff := listenMinBackoff
+
+	for ctx.Err() == nil {
+		err := h.listenOnce(ctx, func() { backoff = listenMinBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+
+		h.log.Error("board events listener disconnected", zap.Error(err), zap.Duration("retry_in", backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (h *Hub) listenOnce(ctx context.Context, onConnect func()) error {
+	conn, err := pgx.ConnectConfig(ctx, h.dbClient.ConnConfig())
+	if err != nil {
+		return fmt.Errorf("connect listener: %w", err)
+	}
+
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{eventsChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen %q: %w", eventsChannel, err)
+	}
+
+	onConnect()
+
+	// Changes made while the listener was down are not announced again.
+	h.resync()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+
+		var event domain.BoardEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			h.log.Error("malformed board event", zap.Error(err))
+			continue
+		}
+
+		h.publish(event)
+	}
+}
+
+var _ svc.Service = (*Hub)(nil)
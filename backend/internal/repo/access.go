@@ -0,0 +1,315 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrJobNotFound   = errors.New("job not found")
+	ErrGrantNotFound = errors.New("job access grant not found")
+)
+
+type AccessRepository interface {
+	GetJobAccess(ctx context.Context, userID, jobID string) (*domain.JobAccess, error)
+	GetCandidateAccess(ctx context.Context, userID, candidateID string) (*domain.JobAccess, error)
+	ListMembers(ctx context.Context, teamID string) ([]domain.Member, error)
+	ListJobGrants(ctx context.Context, teamID, jobID string) ([]domain.JobGrant, error)
+	ListMemberGrants(ctx context.Context, scope domain.JobScope, userID string) ([]domain.JobGrant, error)
+	GrantJobAccess(ctx context.Context, jobID string, params domain.GrantParams) error
+	GrantDepartmentAccess(ctx context.Context, scope domain.JobScope, department string, params domain.GrantParams) (int, error)
+	RevokeJobAccess(ctx context.Context, teamID, jobID, userID string) error
+}
+
+type accessRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewAccessRepo(dbClient *db.PostgresClient) AccessRepository {
+	return &accessRepo{dbClient: dbClient}
+}
+
+func (r *accessRepo) GetJobAccess(ctx context.Context, userID, jobID string) (*domain.JobAccess, error) {
+	const query = `
+		SELECT
+			j.id AS job_id,
+			j.team_id,
+			EXISTS (
+				SELECT 1 FROM hiring.t_job_access a
+				WHERE a.job_id = j.id AND a.user_id = @user_id
+			) AS granted
+		FROM hiring.t_jobs j
+		WHERE j.id = @job_id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"user_id": userID,
+		"job_id":  jobID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query job access: %w", err)
+	}
+
+	access, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.JobAccess])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("scan job access: %w", err)
+	}
+
+	return &access, nil
+}
+
+// GetCandidateAccess resolves the job a candidate belongs to and how the
+// caller relates to it.
+func (r *accessRepo) GetCandidateAccess(ctx context.Context, userID, candidateID string) (*domain.JobAccess, error) {
+	const query = `
+		SELECT
+			j.id AS job_id,
+			j.team_id,
+			EXISTS (
+				SELECT 1 FROM hiring.t_job_access a
+				WHERE a.job_id = j.id AND a.user_id = @user_id
+			) AS granted
+		FROM hiring.t_candidates c
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		WHERE c.id = @candidate_id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"user_id":      userID,
+		"candidate_id": candidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query candidate access: %w", err)
+	}
+
+	access, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.JobAccess])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("scan candidate access: %w", err)
+	}
+
+	return &access, nil
+}
+
+func (r *accessRepo) ListMembers(ctx context.Context, teamID string) ([]domain.Member, error) {
+	const query = `
+		SELECT id, email, COALESCE(first_name, '') AS first_name,
+			COALESCE(last_name, '') AS last_name, role::text AS role
+		FROM auth.t_users
+		WHERE team_id = @team_id
+		ORDER BY email
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{"team_id": teamID})
+	if err != nil {
+		return nil, fmt.Errorf("query members: %w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Member])
+	if err != nil {
+		return nil, fmt.Errorf("scan members: %w", err)
+	}
+
+	return members, nil
+}
+
+const grantQuery = `
+	SELECT
+		a.job_id, j.title AS job_title, a.user_id, u.email,
+		COALESCE(u.first_name, '') AS first_name, COALESCE(u.last_name, '') AS last_name,
+		a.roles, a.granted_by, a.granted_at
+	FROM hiring.t_job_access a
+	JOIN hiring.t_jobs j ON j.id = a.job_id
+	JOIN auth.t_users u ON u.id = a.user_id
+	WHERE j.team_id = @team_id
+`
+
+func (r *accessRepo) ListJobGrants(ctx context.Context, teamID, jobID string) ([]domain.JobGrant, error) {
+	return r.listGrants(ctx, grantQuery+` AND a.job_id = @job_id ORDER BY u.email`, pgx.NamedArgs{
+		"team_id": teamID,
+		"job_id":  jobID,
+	})
+}
+
+// ListMemberGrants returns the grants of a member on the jobs visible within
+// scope.
+func (r *accessRepo) ListMemberGrants(ctx context.Context, scope domain.JobScope, userID string) ([]domain.JobGrant, error) {
+	args := pgx.NamedArgs{
+		"team_id": scope.TeamID,
+		"user_id": userID,
+	}
+
+	query := grantQuery + ` AND a.user_id = @user_id AND ` + jobScopeCondition("a.job_id", scope, args) + ` ORDER BY j.title`
+
+	return r.listGrants(ctx, query, args)
+}
+
+func (r *accessRepo) listGrants(ctx context.Context, query string, args pgx.NamedArgs) ([]domain.JobGrant, error) {
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query grants: %w", err)
+	}
+
+	grants, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.JobGrant])
+	if err != nil {
+		return nil, fmt.Errorf("scan grants: %w", err)
+	}
+
+	return grants, nil
+}
+
+// GrantJobAccess creates the grant or replaces the roles of an existing one.
+// The member must already be checked to belong to params.TeamID; a job
+// outside the team is reported as ErrJobNotFound.
+func (r *accessRepo) GrantJobAccess(ctx context.Context, jobID string, params domain.GrantParams) error {
+	const query = `
+		INSERT INTO hiring.t_job_access (user_id, job_id, roles, granted_by)
+		SELECT u.id, j.id, @roles, @granted_by
+		FROM auth.t_users u
+		JOIN hiring.t_jobs j ON j.team_id = u.team_id
+		WHERE u.team_id = @team_id AND u.id = @user_id AND j.id = @job_id
+		ON CONFLICT (user_id, job_id) DO UPDATE
+		SET roles = EXCLUDED.roles
+	`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"team_id":    params.TeamID,
+		"user_id":    params.UserID,
+		"job_id":     jobID,
+		"roles":      params.Roles,
+		"granted_by": params.GrantedBy,
+	})
+	if err != nil {
+		return fmt.Errorf("insert grant: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// GrantDepartmentAccess grants every job of the department visible within
+// scope to the member, who must already be checked to belong to the team, so
+// that callers limited to their grants cannot reach other jobs through it.
+// Existing grants keep their roles and gain params.Roles. It returns the
+// number of grants created or updated.
+func (r *accessRepo) GrantDepartmentAccess(ctx context.Context, scope domain.JobScope, department string, params domain.GrantParams) (int, error) {
+	args := pgx.NamedArgs{
+		"team_id":    params.TeamID,
+		"user_id":    params.UserID,
+		"department": department,
+		"roles":      params.Roles,
+		"granted_by": params.GrantedBy,
+	}
+
+	query := `
+		INSERT INTO hiring.t_job_access (user_id, job_id, roles, granted_by)
+		SELECT @user_id, j.id, @roles, @granted_by
+		FROM hiring.t_jobs j
+		WHERE j.team_id = @team_id AND j.department = @department
+			AND ` + jobScopeCondition("j.id", scope, args) + `
+		ON CONFLICT (user_id, job_id) DO UPDATE
+		SET roles = ARRAY(
+			SELECT DISTINCT unnest(hiring.t_job_access.roles || EXCLUDED.roles) ORDER BY 1
+		)
+	`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("insert department grants: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *accessRepo) RevokeJobAccess(ctx context.Context, teamID, jobID, userID string) error {
+	const query = `
+		DELETE FROM hiring.t_job_access a
+		USING hiring.t_jobs j
+		WHERE j.id = a.job_id AND j.team_id = @team_id
+		  AND a.job_id = @job_id AND a.user_id = @user_id
+	`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"job_id":  jobID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete grant: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrGrantNotFound
+	}
+
+	return nil
+}
+
+// jobScopeCondition returns a WHERE fragment limiting jobColumn to the jobs
+// visible within scope and registers its arguments in args. Callers with the
+// jobs:all permission only get the team filter.
+func jobScopeCondition(jobColumn string, scope domain.JobScope, args pgx.NamedArgs) string {
+	args["scope_team_id"] = scope.TeamID
+
+	cond := jobColumn + ` IN (SELECT id FROM hiring.t_jobs WHERE team_id = @scope_team_id)`
+	if scope.AllJobs {
+		return cond
+	}
+
+	args["scope_user_id"] = scope.UserID
+
+	return cond + ` AND ` + jobColumn + ` IN (SELECT job_id FROM hiring.t_job_access WHERE user_id = @scope_user_id)`
+}
+
+// memberJobCondition returns a WHERE fragment that holds when the member in
+// userColumn may see the job in jobColumn: the member is one of allJobs, the
+// members whose roles grant jobs:all, or has a grant on the job. It registers
+// its arguments in args.
+func memberJobCondition(userColumn, jobColumn string, allJobs []string, args pgx.NamedArgs) string {
+	if allJobs == nil {
+		allJobs = []string{}
+	}
+
+	args["all_jobs_users"] = allJobs
+
+	return `(` + userColumn + ` = ANY(@all_jobs_users::uuid[]) OR EXISTS (
+		SELECT 1 FROM hiring.t_job_access ja WHERE ja.user_id = ` + userColumn + ` AND ja.job_id = ` + jobColumn + `
+	))`
+}
+
+// withoutAccess counts the members among userIDs who cannot see the
+// candidate: those neither in allJobs nor granted its job.
+func withoutAccess(ctx context.Context, tx pgx.Tx, candidateID string, userIDs, allJobs []string) (int, error) {
+	args := pgx.NamedArgs{
+		"candidate_id": candidateID,
+		"users":        userIDs,
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM unnest(@users::uuid[]) AS u(id)
+		JOIN hiring.t_candidates cand ON cand.id = @candidate_id
+		WHERE NOT ` + memberJobCondition("u.id", "cand.job_id", allJobs, args)
+
+	var n int
+	if err := tx.QueryRow(ctx, query, args).Scan(&n); err != nil {
+		return 0, fmt.Errorf("check candidate access: %w", err)
+	}
+
+	return n, nil
+}
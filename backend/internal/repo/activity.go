@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrUnknownAction = errors.New("unknown action type")
+
+type ActivityRepository interface {
+	LogActivity(ctx context.Context, activity *domain.Activity) error
+	ListActivities(ctx context.Context, filter domain.ActivityFilter) ([]domain.Activity, error)
+}
+
+type activityRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewActivityRepo(dbClient *db.PostgresClient) ActivityRepository {
+	return &activityRepo{dbClient: dbClient}
+}
+
+func (r *activityRepo) LogActivity(ctx context.Context, activity *domain.Activity) error {
+	const query = `
+		INSERT INTO hiring.t_activity_logs (team_id, actor_type, actor_id, action_id, target_id, details)
+		SELECT @team_id, @actor_type, @actor_id, id, @target_id, @details
+		FROM hiring.t_action_types
+		WHERE code = @action
+		RETURNING id, created_at
+	`
+
+	err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id":    activity.TeamID,
+		"actor_type": activity.ActorType,
+		"actor_id":   activity.ActorID,
+		"action":     activity.Action,
+		"target_id":  activity.TargetID,
+		"details":    activity.Details,
+	}).Scan(&activity.ID, &activity.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %q", ErrUnknownAction, activity.Action)
+		}
+
+		return fmt.Errorf("insert activity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *activityRepo) ListActivities(ctx context.Context, filter domain.ActivityFilter) ([]domain.Activity, error) {
+	query := `
+		SELECT
+			l.id, l.team_id, l.actor_type, l.actor_id,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS actor_name,
+			t.code AS action, l.target_id, l.details, l.created_at
+		FROM hiring.t_activity_logs l
+		JOIN hiring.t_action_types t ON t.id = l.action_id
+		LEFT JOIN auth.t_users u ON l.actor_type = 'user' AND u.id = l.actor_id
+		WHERE l.team_id = @team_id
+	`
+
+	args := pgx.NamedArgs{
+		"team_id": filter.TeamID,
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	}
+
+	if filter.Action != "" {
+		query += ` AND t.code = @action`
+		args["action"] = filter.Action
+	}
+
+	query += `
+		ORDER BY l.created_at DESC, l.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query activities: %w", err)
+	}
+
+	activities, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Activity])
+	if err != nil {
+		return nil, fmt.Errorf("scan activities: %w", err)
+	}
+
+	return activities, nil
+}
+
+// logActivity writes an activity row inside tx so that it commits together
+// with the change it describes.
+func logActivity(ctx context.Context, tx pgx.Tx, activity *domain.Activity) error {
+	const query = `
+		INSERT INTO hiring.t_activity_logs (team_id, actor_type, actor_id, action_id, target_id, details)
+		SELECT @team_id, @actor_type, @actor_id, id, @target_id, @details
+		FROM hiring.t_action_types
+		WHERE code = @action
+	`
+
+	tag, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"team_id":    activity.TeamID,
+		"actor_type": activity.ActorType,
+		"actor_id":   activity.ActorID,
+		"action":     activity.Action,
+		"target_id":  activity.TargetID,
+		"details":    activity.Details,
+	})
+	if err != nil {
+		return fmt.Errorf("insert activity: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %q", ErrUnknownAction, activity.Action)
+	}
+
+	return nil
+}
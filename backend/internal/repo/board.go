@@ -0,0 +1,368 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrMoveAnchorNotFound   = errors.New("neighbour candidate is not in the target stage")
+	ErrTransitionNotAllowed = errors.New("stage transition is not allowed")
+)
+
+const (
+	// boardGap is the distance between neighbouring cards after a
+	// rebalance and between a card added to the edge of a stage and its
+	// neighbour.
+	boardGap = 1000.0
+	// boardMinGap is the closest two positions may get before the stage is
+	// renumbered; halving the gap from boardGap reaches it after about 30
+	// moves into the same slot.
+	boardMinGap = 1e-6
+)
+
+type BoardRepository interface {
+	GetBoard(ctx context.Context, teamID, jobID string, stageLimit int) ([]domain.BoardCard, map[string]int, error)
+	MoveCandidate(ctx context.Context, session domain.Session, move domain.CandidateMove) (*domain.Candidate, error)
+	ListStatusHistory(ctx context.Context, teamID, candidateID string) ([]domain.StatusChange, error)
+}
+
+type boardRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewBoardRepo(dbClient *db.PostgresClient) BoardRepository {
+	return &boardRepo{dbClient: dbClient}
+}
+
+type boardRow struct {
+	domain.BoardCard
+	StageTotal int `db:"stage_total"`
+}
+
+// GetBoard returns up to stageLimit cards of every stage of the job ordered
+// by position, and the number of candidates in each stage.
+func (r *boardRepo) GetBoard(ctx context.Context, teamID, jobID string, stageLimit int) ([]domain.BoardCard, map[string]int, error) {
+	const selectJob = `SELECT 1 FROM hiring.t_jobs WHERE team_id = @team_id AND id = @id`
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, selectJob, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      jobID,
+	}).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrJobNotFound
+		}
+
+		return nil, nil, fmt.Errorf("select job: %w", err)
+	}
+
+	const query = `
+		SELECT id, first_name, last_name, email, status, kanban_position, match_score,
+			source, created_at, updated_at, stage_total
+		FROM (
+			SELECT c.id,
+				COALESCE(c.first_name, '') AS first_name,
+				COALESCE(c.last_name, '') AS last_name,
+				COALESCE(c.email, '') AS email,
+				COALESCE(c.status, '') AS status,
+				COALESCE(c.kanban_position, 0) AS kanban_position,
+				s.match_score, c.source, c.created_at, c.updated_at,
+				COUNT(*) OVER (PARTITION BY c.status) AS stage_total,
+				ROW_NUMBER() OVER (PARTITION BY c.status ORDER BY c.kanban_position, c.id) AS stage_row
+			FROM hiring.t_candidates c
+			LEFT JOIN ai_engine.t_candidate_scores s ON s.candidate_id = c.id
+			WHERE c.job_id = @job_id
+		) board
+		WHERE stage_row <= @limit
+		ORDER BY status, kanban_position, id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"job_id": jobID,
+		"limit":  stageLimit,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("query board: %w", err)
+	}
+
+	board, err := pgx.CollectRows(rows, pgx.RowToStructByName[boardRow])
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan board: %w", err)
+	}
+
+	cards := make([]domain.BoardCard, len(board))
+	totals := make(map[string]int)
+
+	for i, row := range board {
+		cards[i] = row.BoardCard
+		totals[row.Status] = row.StageTotal
+	}
+
+	return cards, totals, nil
+}
+
+type boardPosition struct {
+	ID       string  `db:"id"`
+	Position float64 `db:"kanban_position"`
+}
+
+// MoveCandidate places the candidate between its new neighbours and records
+// a stage change in hiring.t_status_history, all in one transaction. Moves
+// within a job are serialised by an advisory lock taken before any row lock,
+// so two recruiters dragging cards of the same board cannot compute the same
+// slot or deadlock on a rebalance. When the neighbours are too close to
+// split, the whole stage is renumbered first. The current stage is checked
+// against move.Blocked under the row lock.
+func (r *boardRepo) MoveCandidate(ctx context.Context, session domain.Session, move domain.CandidateMove) (*domain.Candidate, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const selectJob = `
+		SELECT c.job_id
+		FROM hiring.t_candidates c
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		WHERE j.team_id = @team_id AND c.id = @id
+	`
+
+	var jobID string
+	if err := tx.QueryRow(ctx, selectJob, pgx.NamedArgs{
+		"team_id": session.TeamID,
+		"id":      move.CandidateID,
+	}).Scan(&jobID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("select candidate: %w", err)
+	}
+
+	const lock = `SELECT pg_advisory_xact_lock(hashtextextended('board:' || @job_id::text, 0))`
+
+	if _, err := tx.Exec(ctx, lock, pgx.NamedArgs{"job_id": jobID}); err != nil {
+		return nil, fmt.Errorf("lock board: %w", err)
+	}
+
+	const selectCandidate = `
+		SELECT COALESCE(status, '') FROM hiring.t_candidates
+		WHERE id = @id AND job_id = @job_id
+		FOR UPDATE
+	`
+
+	var oldStatus string
+	if err := tx.QueryRow(ctx, selectCandidate, pgx.NamedArgs{
+		"id":     move.CandidateID,
+		"job_id": jobID,
+	}).Scan(&oldStatus); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("lock candidate: %w", err)
+	}
+
+	if oldStatus != move.Status && slices.Contains(move.Blocked, oldStatus) {
+		return nil, ErrTransitionNotAllowed
+	}
+
+	const selectStage = `
+		SELECT id, COALESCE(kanban_position, 0) AS kanban_position
+		FROM hiring.t_candidates
+		WHERE job_id = @job_id AND status = @status AND id <> @id
+		ORDER BY kanban_position, id
+	`
+
+	rows, err := tx.Query(ctx, selectStage, pgx.NamedArgs{
+		"job_id": jobID,
+		"status": move.Status,
+		"id":     move.CandidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query stage: %w", err)
+	}
+
+	stage, err := pgx.CollectRows(rows, pgx.RowToStructByName[boardPosition])
+	if err != nil {
+		return nil, fmt.Errorf("scan stage: %w", err)
+	}
+
+	index, err := moveIndex(stage, move)
+	if err != nil {
+		return nil, err
+	}
+
+	position, ok := slotPosition(stage, index)
+	if !ok {
+		if err := r.rebalance(ctx, tx, stage, index); err != nil {
+			return nil, err
+		}
+
+		position = float64(index+1) * boardGap
+	}
+
+	query := `
+		UPDATE hiring.t_candidates c
+		SET status = @status, kanban_position = @position, updated_at = NOW()
+		WHERE c.id = @id
+		RETURNING ` + candidateColumns
+
+	rows, err = tx.Query(ctx, query, pgx.NamedArgs{
+		"id":       move.CandidateID,
+		"status":   move.Status,
+		"position": position,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update candidate: %w", err)
+	}
+
+	candidate, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Candidate])
+	if err != nil {
+		return nil, fmt.Errorf("scan candidate: %w", err)
+	}
+
+	if oldStatus != move.Status {
+		const insertHistory = `
+			INSERT INTO hiring.t_status_history (candidate_id, old_status, new_status, changed_by)
+			VALUES (@candidate_id, NULLIF(@old_status, ''), @new_status, @changed_by)
+		`
+
+		if _, err := tx.Exec(ctx, insertHistory, pgx.NamedArgs{
+			"candidate_id": move.CandidateID,
+			"old_status":   oldStatus,
+			"new_status":   move.Status,
+			"changed_by":   session.UserID,
+		}); err != nil {
+			return nil, fmt.Errorf("insert status history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &candidate, nil
+}
+
+// moveIndex returns the slot of the moved card among the other cards of the
+// target stage.
+func moveIndex(stage []boardPosition, move domain.CandidateMove) (int, error) {
+	anchor := move.AfterID
+	if anchor == "" {
+		anchor = move.BeforeID
+	}
+
+	if anchor == "" {
+		return len(stage), nil
+	}
+
+	i := slices.IndexFunc(stage, func(p boardPosition) bool { return p.ID == anchor })
+	if i < 0 {
+		return 0, ErrMoveAnchorNotFound
+	}
+
+	if move.AfterID != "" {
+		return i + 1, nil
+	}
+
+	return i, nil
+}
+
+// slotPosition returns a position strictly between the neighbours of slot
+// index. It reports false when the neighbours are too close to split.
+func slotPosition(stage []boardPosition, index int) (float64, bool) {
+	switch {
+	case len(stage) == 0:
+		return boardGap, true
+	case index == 0:
+		return stage[0].Position - boardGap, true
+	case index == len(stage):
+		return stage[index-1].Position + boardGap, true
+	}
+
+	prev, next := stage[index-1].Position, stage[index].Position
+	if next-prev < boardMinGap {
+		return 0, false
+	}
+
+	mid := prev + (next-prev)/2
+	if mid <= prev || mid >= next {
+		return 0, false
+	}
+
+	return mid, true
+}
+
+// rebalance renumbers the stage boardGap apart, leaving slot index free for
+// the moved card.
+func (r *boardRepo) rebalance(ctx context.Context, tx pgx.Tx, stage []boardPosition, index int) error {
+	ids := make([]string, len(stage))
+	positions := make([]float64, len(stage))
+
+	for i, p := range stage {
+		slot := i + 1
+		if i >= index {
+			slot++
+		}
+
+		ids[i] = p.ID
+		positions[i] = float64(slot) * boardGap
+	}
+
+	const query = `
+		UPDATE hiring.t_candidates c
+		SET kanban_position = v.position
+		FROM unnest(@ids::uuid[], @positions::float8[]) AS v(id, position)
+		WHERE c.id = v.id
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"ids":       ids,
+		"positions": positions,
+	}); err != nil {
+		return fmt.Errorf("rebalance stage: %w", err)
+	}
+
+	return nil
+}
+
+// ListStatusHistory returns the stage changes of a candidate, newest first.
+func (r *boardRepo) ListStatusHistory(ctx context.Context, teamID, candidateID string) ([]domain.StatusChange, error) {
+	const query = `
+		SELECT h.id, h.candidate_id,
+			COALESCE(h.old_status, '') AS old_status,
+			COALESCE(h.new_status, '') AS new_status,
+			h.changed_by,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS changed_by_name,
+			h.changed_at
+		FROM hiring.t_status_history h
+		JOIN hiring.t_candidates c ON c.id = h.candidate_id
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		LEFT JOIN auth.t_users u ON u.id = h.changed_by
+		WHERE j.team_id = @team_id AND h.candidate_id = @id
+		ORDER BY h.changed_at DESC, h.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      candidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query status history: %w", err)
+	}
+
+	history, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.StatusChange])
+	if err != nil {
+		return nil, fmt.Errorf("scan status history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,550 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrJobClosed          = errors.New("job is not accepting applications")
+	ErrAlreadyApplied     = errors.New("candidate already applied to this job")
+	ErrCandidateNotFound  = errors.New("candidate not found")
+	ErrCandidateDuplicate = errors.New("candidate with this email already exists for the job")
+)
+
+type CandidateRepository interface {
+	CheckApplication(ctx context.Context, jobID, email string) (teamID string, applied bool, err error)
+	CreateApplication(ctx context.Context, app domain.Application, candidate *domain.Candidate) error
+	ListCandidates(ctx context.Context, filter domain.CandidateFilter) ([]domain.CandidateDetails, error)
+	GetCandidate(ctx context.Context, teamID, candidateID string) (*domain.CandidateDetails, error)
+	CreateCandidate(ctx context.Context, scope domain.JobScope, candidate *domain.Candidate) error
+	UpdateCandidate(ctx context.Context, session domain.Session, candidate *domain.Candidate) error
+	DeleteCandidate(ctx context.Context, session domain.Session, candidateID string) (*domain.Candidate, error)
+}
+
+type candidateRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewCandidateRepo(dbClient *db.PostgresClient) CandidateRepository {
+	return &candidateRepo{dbClient: dbClient}
+}
+
+// CheckApplication returns the team owning the job and whether email has
+// already applied to it.
+func (r *candidateRepo) CheckApplication(ctx context.Context, jobID, email string) (string, bool, error) {
+	const query = `
+		SELECT j.team_id, EXISTS (
+			SELECT 1 FROM hiring.t_candidates c
+			WHERE c.job_id = j.id AND LOWER(c.email) = LOWER(@email)
+		)
+		FROM hiring.t_jobs j
+		WHERE j.id = @job_id
+	`
+
+	var (
+		teamID string
+		exists bool
+	)
+
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"job_id": jobID,
+		"email":  email,
+	}).Scan(&teamID, &exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", false, ErrJobNotFound
+		}
+
+		return "", false, fmt.Errorf("check application: %w", err)
+	}
+
+	return teamID, exists, nil
+}
+
+// CreateApplication stores a public application in one transaction: it
+// re-checks that the job of the team with app.Slug is open, serialises
+// submissions of the same email to the job with an advisory lock, inserts the
+// candidate at the end of its stage, queues the resume task, logs the
+// activity and records suspected duplicates from the team's other jobs.
+func (r *candidateRepo) CreateApplication(ctx context.Context, app domain.Application, candidate *domain.Candidate) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const selectJob = `
+		SELECT j.team_id, j.status
+		FROM hiring.t_jobs j
+		JOIN auth.t_teams t ON t.id = j.team_id
+		WHERE t.slug = @slug AND j.id = @job_id
+		FOR SHARE OF j
+	`
+
+	var teamID, status string
+	if err := tx.QueryRow(ctx, selectJob, pgx.NamedArgs{
+		"slug":   app.Slug,
+		"job_id": app.JobID,
+	}).Scan(&teamID, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("select job: %w", err)
+	}
+
+	if !domain.JobAcceptsCandidates(status) {
+		return ErrJobClosed
+	}
+
+	const lock = `SELECT pg_advisory_xact_lock(hashtextextended(@job_id::text || ':' || LOWER(@email::text), 0))`
+
+	if _, err := tx.Exec(ctx, lock, pgx.NamedArgs{
+		"job_id": app.JobID,
+		"email":  app.Email,
+	}); err != nil {
+		return fmt.Errorf("lock application: %w", err)
+	}
+
+	const duplicate = `
+		SELECT EXISTS (
+			SELECT 1 FROM hiring.t_candidates
+			WHERE job_id = @job_id AND LOWER(email) = LOWER(@email)
+		)
+	`
+
+	var exists bool
+	if err := tx.QueryRow(ctx, duplicate, pgx.NamedArgs{
+		"job_id": app.JobID,
+		"email":  app.Email,
+	}).Scan(&exists); err != nil {
+		return fmt.Errorf("check application: %w", err)
+	}
+
+	if exists {
+		return ErrAlreadyApplied
+	}
+
+	const insertCandidate = `
+		INSERT INTO hiring.t_candidates (
+			job_id, first_name, last_name, email, phone,
+			resume_file_key, resume_file_name, status, kanban_position, source
+		)
+		VALUES (
+			@job_id, @first_name, @last_name, @email, NULLIF(@phone, ''),
+			@resume_file_key, @resume_file_name, @status,
+			(SELECT COALESCE(MAX(kanban_position), 0) + 1000 FROM hiring.t_candidates WHERE job_id = @job_id AND status = @status),
+			@source
+		)
+		RETURNING id, kanban_position, created_at
+	`
+
+	if err := tx.QueryRow(ctx, insertCandidate, pgx.NamedArgs{
+		"job_id":           app.JobID,
+		"first_name":       candidate.FirstName,
+		"last_name":        candidate.LastName,
+		"email":            candidate.Email,
+		"phone":            candidate.Phone,
+		"resume_file_key":  candidate.ResumeFileKey,
+		"resume_file_name": candidate.ResumeFileName,
+		"status":           candidate.Status,
+		"source":           candidate.Source,
+	}).Scan(&candidate.ID, &candidate.KanbanPosition, &candidate.CreatedAt); err != nil {
+		return fmt.Errorf("insert candidate: %w", err)
+	}
+
+	const insertTask = `
+		INSERT INTO ai_engine.t_processing_tasks (workflow_id, entity_id, status, updated_at)
+		VALUES (@workflow_id, @entity_id, 'pending', NOW())
+	`
+
+	if _, err := tx.Exec(ctx, insertTask, pgx.NamedArgs{
+		"workflow_id": domain.ResumeWorkflowID(candidate.ID),
+		"entity_id":   candidate.ID,
+	}); err != nil {
+		return fmt.Errorf("insert processing task: %w", err)
+	}
+
+	const insertActivity = `
+		INSERT INTO hiring.t_activity_logs (team_id, actor_type, action_id, target_id, details)
+		SELECT @team_id, 'system', id, @candidate_id, @details
+		FROM hiring.t_action_types
+		WHERE code = @action
+	`
+
+	if _, err := tx.Exec(ctx, insertActivity, pgx.NamedArgs{
+		"team_id":      teamID,
+		"candidate_id": candidate.ID,
+		"action":       domain.ActionCandidateApplied,
+		"details": map[string]any{
+			"job_id":     app.JobID,
+			"ip":         app.IP,
+			"user_agent": app.UserAgent,
+		},
+	}); err != nil {
+		return fmt.Errorf("insert activity: %w", err)
+	}
+
+	if err := detectDuplicates(ctx, tx, candidate.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	candidate.JobID = app.JobID
+
+	return nil
+}
+
+const candidateColumns = `
+	c.id, c.job_id,
+	COALESCE(c.first_name, '') AS first_name,
+	COALESCE(c.last_name, '') AS last_name,
+	COALESCE(c.email, '') AS email,
+	COALESCE(c.phone, '') AS phone,
+	COALESCE(c.resume_file_key, '') AS resume_file_key,
+	COALESCE(c.resume_file_name, '') AS resume_file_name,
+	COALESCE(c.status, '') AS status,
+	COALESCE(c.kanban_position, 0) AS kanban_position,
+	c.source, c.created_at, c.updated_at
+`
+
+const candidateDetailsFrom = `
+	FROM hiring.t_candidates c
+	JOIN hiring.t_jobs j ON j.id = c.job_id
+	LEFT JOIN hiring.t_candidate_profiles p ON p.candidate_id = c.id
+	LEFT JOIN ai_engine.t_candidate_scores s ON s.candidate_id = c.id
+	LEFT JOIN ai_engine.t_processing_tasks pt ON pt.workflow_id = 'resume:' || c.id
+`
+
+const candidateDetailsColumns = candidateColumns + `,
+	p.structured_data AS profile,
+	s.match_score, s.analyzed_at,
+	COALESCE(c.parsed_language, '') AS parsed_language, c.parsed_at,
+	c.parsed_by_ocr, c.ocr_confidence::float8 AS ocr_confidence,
+	COALESCE(pt.status::text, '') AS processing_status,
+	COALESCE(pt.error_message, '') AS processing_error
+`
+
+// ListCandidates returns up to filter.Limit+1 candidates after filter.Cursor,
+// newest first. The extra row tells the caller whether another page exists.
+func (r *candidateRepo) ListCandidates(ctx context.Context, filter domain.CandidateFilter) ([]domain.CandidateDetails, error) {
+	args := pgx.NamedArgs{}
+
+	where := ` WHERE j.team_id = @team_id AND ` + jobScopeCondition("c.job_id", filter.Scope, args)
+	args["team_id"] = filter.Scope.TeamID
+
+	if filter.JobID != "" {
+		where += ` AND c.job_id = @job_id`
+		args["job_id"] = filter.JobID
+	}
+
+	if filter.Status != "" {
+		where += ` AND c.status = @status`
+		args["status"] = filter.Status
+	}
+
+	if filter.MinScore != nil {
+		where += ` AND s.match_score >= @min_score`
+		args["min_score"] = *filter.MinScore
+	}
+
+	if filter.MaxScore != nil {
+		where += ` AND s.match_score <= @max_score`
+		args["max_score"] = *filter.MaxScore
+	}
+
+	if filter.CreatedFrom != nil {
+		where += ` AND c.created_at >= @created_from`
+		args["created_from"] = *filter.CreatedFrom
+	}
+
+	if filter.CreatedTo != nil {
+		where += ` AND c.created_at < @created_to`
+		args["created_to"] = *filter.CreatedTo
+	}
+
+	if filter.Cursor != nil {
+		where += ` AND (c.created_at, c.id) < (@cursor_created_at, @cursor_id)`
+		args["cursor_created_at"] = filter.Cursor.CreatedAt
+		args["cursor_id"] = filter.Cursor.ID
+	}
+
+	args["limit"] = filter.Limit + 1
+
+	query := `SELECT ` + candidateDetailsColumns + candidateDetailsFrom + where + `
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT @limit
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query candidates: %w", err)
+	}
+
+	candidates, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.CandidateDetails])
+	if err != nil {
+		return nil, fmt.Errorf("scan candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+func (r *candidateRepo) GetCandidate(ctx context.Context, teamID, candidateID string) (*domain.CandidateDetails, error) {
+	query := `SELECT ` + candidateDetailsColumns + candidateDetailsFrom + `
+		WHERE j.team_id = @team_id AND c.id = @id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      candidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query candidate: %w", err)
+	}
+
+	candidate, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.CandidateDetails])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("scan candidate: %w", err)
+	}
+
+	return &candidate, nil
+}
+
+// CreateCandidate adds a candidate to a job within scope. The email check
+// shares the advisory lock of CreateApplication so that a manual entry and a
+// public application cannot both create the same person.
+func (r *candidateRepo) CreateCandidate(ctx context.Context, scope domain.JobScope, candidate *domain.Candidate) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{"job_id": candidate.JobID}
+
+	selectJob := `
+		SELECT 1 FROM hiring.t_jobs j
+		WHERE j.id = @job_id AND ` + jobScopeCondition("j.id", scope, args) + `
+		FOR SHARE
+	`
+
+	var found int
+	if err := tx.QueryRow(ctx, selectJob, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("select job: %w", err)
+	}
+
+	if candidate.Email != "" {
+		const lock = `SELECT pg_advisory_xact_lock(hashtextextended(@job_id::text || ':' || LOWER(@email::text), 0))`
+
+		if _, err := tx.Exec(ctx, lock, pgx.NamedArgs{
+			"job_id": candidate.JobID,
+			"email":  candidate.Email,
+		}); err != nil {
+			return fmt.Errorf("lock candidate: %w", err)
+		}
+
+		const duplicate = `
+			SELECT EXISTS (
+				SELECT 1 FROM hiring.t_candidates
+				WHERE job_id = @job_id AND LOWER(email) = LOWER(@email)
+			)
+		`
+
+		var exists bool
+		if err := tx.QueryRow(ctx, duplicate, pgx.NamedArgs{
+			"job_id": candidate.JobID,
+			"email":  candidate.Email,
+		}).Scan(&exists); err != nil {
+			return fmt.Errorf("check candidate: %w", err)
+		}
+
+		if exists {
+			return ErrCandidateDuplicate
+		}
+	}
+
+	const insertCandidate = `
+		INSERT INTO hiring.t_candidates (
+			job_id, first_name, last_name, email, phone, status, kanban_position, source
+		)
+		VALUES (
+			@job_id, @first_name, @last_name, NULLIF(@email, ''), NULLIF(@phone, ''), @status,
+			(SELECT COALESCE(MAX(kanban_position), 0) + 1000 FROM hiring.t_candidates WHERE job_id = @job_id AND status = @status),
+			@source
+		)
+		RETURNING id, kanban_position, created_at
+	`
+
+	if err := tx.QueryRow(ctx, insertCandidate, pgx.NamedArgs{
+		"job_id":     candidate.JobID,
+		"first_name": candidate.FirstName,
+		"last_name":  candidate.LastName,
+		"email":      candidate.Email,
+		"phone":      candidate.Phone,
+		"status":     candidate.Status,
+		"source":     candidate.Source,
+	}).Scan(&candidate.ID, &candidate.KanbanPosition, &candidate.CreatedAt); err != nil {
+		return fmt.Errorf("insert candidate: %w", err)
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionCandidateCreated,
+		TargetID:  &candidate.ID,
+		Details:   map[string]any{"job_id": candidate.JobID},
+	}); err != nil {
+		return err
+	}
+
+	if err := detectDuplicates(ctx, tx, candidate.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCandidate edits the contact details of a candidate. The stage and
+// position are left untouched.
+func (r *candidateRepo) UpdateCandidate(ctx context.Context, session domain.Session, candidate *domain.Candidate) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		UPDATE hiring.t_candidates c
+		SET first_name = @first_name,
+			last_name = @last_name,
+			email = NULLIF(@email, ''),
+			phone = NULLIF(@phone, ''),
+			updated_at = NOW()
+		FROM hiring.t_jobs j
+		WHERE j.id = c.job_id AND j.team_id = @team_id AND c.id = @id
+		RETURNING ` + candidateColumns
+
+	rows, err := tx.Query(ctx, query, pgx.NamedArgs{
+		"team_id":    session.TeamID,
+		"id":         candidate.ID,
+		"first_name": candidate.FirstName,
+		"last_name":  candidate.LastName,
+		"email":      candidate.Email,
+		"phone":      candidate.Phone,
+	})
+	if err != nil {
+		return fmt.Errorf("update candidate: %w", err)
+	}
+
+	updated, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Candidate])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCandidateNotFound
+		}
+
+		return fmt.Errorf("scan candidate: %w", err)
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionCandidateUpdated,
+		TargetID:  &updated.ID,
+		Details:   map[string]any{"job_id": updated.JobID},
+	}); err != nil {
+		return err
+	}
+
+	if err := detectDuplicates(ctx, tx, updated.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	*candidate = updated
+
+	return nil
+}
+
+// DeleteCandidate removes a candidate with its profile, score and tasks and
+// returns the deleted row so the caller can remove the resume file.
+func (r *candidateRepo) DeleteCandidate(ctx context.Context, session domain.Session, candidateID string) (*domain.Candidate, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		DELETE FROM hiring.t_candidates c
+		USING hiring.t_jobs j
+		WHERE j.id = c.job_id AND j.team_id = @team_id AND c.id = @id
+		RETURNING ` + candidateColumns
+
+	rows, err := tx.Query(ctx, query, pgx.NamedArgs{
+		"team_id": session.TeamID,
+		"id":      candidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("delete candidate: %w", err)
+	}
+
+	deleted, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Candidate])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("scan candidate: %w", err)
+	}
+
+	const deleteTasks = `DELETE FROM ai_engine.t_processing_tasks WHERE entity_id = @id`
+
+	if _, err := tx.Exec(ctx, deleteTasks, pgx.NamedArgs{"id": candidateID}); err != nil {
+		return nil, fmt.Errorf("delete processing tasks: %w", err)
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionCandidateDeleted,
+		TargetID:  &deleted.ID,
+		Details: map[string]any{
+			"job_id": deleted.JobID,
+			"name":   deleted.FirstName + " " + deleted.LastName,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &deleted, nil
+}
@@ -0,0 +1,545 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("only the author can change a comment")
+	ErrMentionNotMember = errors.New("mentioned user is not a member of the team")
+	ErrMentionNoAccess  = errors.New("mentioned user cannot see the candidate")
+	ErrPrivateMention   = errors.New("private notes cannot mention members")
+)
+
+type CommentRepository interface {
+	ListComments(ctx context.Context, filter domain.CommentFilter) ([]domain.Comment, int, error)
+	GetComment(ctx context.Context, scope domain.JobScope, commentID string) (*domain.Comment, error)
+	CreateComment(ctx context.Context, scope domain.JobScope, params domain.CommentParams, allJobs []string) (string, error)
+	UpdateComment(ctx context.Context, scope domain.JobScope, commentID string, update domain.CommentUpdate, allJobs []string) error
+	DeleteComment(ctx context.Context, scope domain.JobScope, commentID string) error
+	ListRevisions(ctx context.Context, scope domain.JobScope, commentID string) ([]domain.CommentRevision, error)
+}
+
+type commentRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewCommentRepo(dbClient *db.PostgresClient) CommentRepository {
+	return &commentRepo{dbClient: dbClient}
+}
+
+const commentColumns = `
+	c.id, c.candidate_id, c.parent_id, c.author_id,
+	COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS author_name,
+	c.visibility,
+	CASE WHEN c.deleted_at IS NULL THEN c.body ELSE '' END AS body,
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'user_id', m.user_id,
+			'name', COALESCE(NULLIF(TRIM(CONCAT_WS(' ', mu.first_name, mu.last_name)), ''), mu.email)
+		) ORDER BY mu.first_name, mu.last_name, mu.email)
+		FROM hiring.t_candidate_comment_mentions m
+		JOIN auth.t_users mu ON mu.id = m.user_id
+		WHERE m.comment_id = c.id AND c.deleted_at IS NULL
+	), '[]') AS mentions,
+	c.created_at, c.edited_at, c.deleted_at
+`
+
+const commentFrom = `
+	FROM hiring.t_candidate_comments c
+	JOIN hiring.t_candidates cand ON cand.id = c.candidate_id
+	LEFT JOIN auth.t_users u ON u.id = c.author_id
+`
+
+// commentScopeCondition limits comments to the jobs within scope and to
+// those the caller may read: team comments and the caller's private notes.
+func commentScopeCondition(scope domain.JobScope, args pgx.NamedArgs) string {
+	args["user_id"] = scope.UserID
+
+	return jobScopeCondition("cand.job_id", scope, args) + ` AND (c.visibility = 'team' OR c.author_id = @user_id)`
+}
+
+// ListComments returns a page of the top-level comments of a candidate,
+// newest first, each with its replies, and the total number of threads.
+// Deleted comments are left out unless replies remain under them.
+func (r *commentRepo) ListComments(ctx context.Context, filter domain.CommentFilter) ([]domain.Comment, int, error) {
+	args := pgx.NamedArgs{"candidate_id": filter.CandidateID}
+
+	where := ` WHERE c.candidate_id = @candidate_id AND c.parent_id IS NULL AND ` + commentScopeCondition(filter.Scope, args) + `
+		AND (c.deleted_at IS NULL OR EXISTS (
+			SELECT 1 FROM hiring.t_candidate_comments rc WHERE rc.parent_id = c.id AND rc.deleted_at IS NULL
+		))`
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, `SELECT COUNT(*)`+commentFrom+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count comments: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := `SELECT ` + commentColumns + commentFrom + where + `
+		ORDER BY c.created_at DESC, c.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query comments: %w", err)
+	}
+
+	comments, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Comment])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan comments: %w", err)
+	}
+
+	if len(comments) == 0 {
+		return comments, total, nil
+	}
+
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	// Replies share the visibility of their thread, so a visible thread has
+	// only visible replies.
+	replyQuery := `SELECT ` + commentColumns + commentFrom + `
+		WHERE c.parent_id = ANY(@ids::uuid[]) AND c.deleted_at IS NULL
+		ORDER BY c.created_at, c.id
+	`
+
+	rows, err = r.dbClient.Pool.Query(ctx, replyQuery, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		return nil, 0, fmt.Errorf("query replies: %w", err)
+	}
+
+	replies, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Comment])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan replies: %w", err)
+	}
+
+	threads := make(map[string]*domain.Comment, len(comments))
+	for i := range comments {
+		threads[comments[i].ID] = &comments[i]
+	}
+
+	for _, reply := range replies {
+		if thread, ok := threads[*reply.ParentID]; ok {
+			thread.Replies = append(thread.Replies, reply)
+		}
+	}
+
+	return comments, total, nil
+}
+
+// GetComment returns a comment the caller may read, without its replies.
+func (r *commentRepo) GetComment(ctx context.Context, scope domain.JobScope, commentID string) (*domain.Comment, error) {
+	args := pgx.NamedArgs{"id": commentID}
+
+	query := `SELECT ` + commentColumns + commentFrom + `
+		WHERE c.id = @id AND c.deleted_at IS NULL AND ` + commentScopeCondition(scope, args)
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query comment: %w", err)
+	}
+
+	comment, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Comment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+
+		return nil, fmt.Errorf("scan comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// CreateComment adds a comment and notifies the mentioned members and, for
+// a reply in a team thread, the author of the comment replied to. A reply to
+// a reply joins the thread of its top-level comment. Only members who can see
+// the candidate are notified: those in allJobs and those with a grant on its
+// job. It returns the id of the new comment.
+func (r *commentRepo) CreateComment(ctx context.Context, scope domain.JobScope, params domain.CommentParams, allJobs []string) (string, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{"candidate_id": params.CandidateID}
+
+	selectCandidate := `SELECT 1 FROM hiring.t_candidates cand WHERE cand.id = @candidate_id AND ` + jobScopeCondition("cand.job_id", scope, args)
+
+	var found int
+	if err := tx.QueryRow(ctx, selectCandidate, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrCandidateNotFound
+		}
+
+		return "", fmt.Errorf("select candidate: %w", err)
+	}
+
+	var (
+		parentID     *string
+		parentAuthor *string
+		visibility   = params.Visibility
+	)
+
+	if params.ParentID != "" {
+		args := pgx.NamedArgs{"id": params.ParentID, "candidate_id": params.CandidateID}
+
+		selectParent := `
+			SELECT COALESCE(c.parent_id, c.id), c.visibility, c.author_id
+			FROM hiring.t_candidate_comments c
+			JOIN hiring.t_candidates cand ON cand.id = c.candidate_id
+			WHERE c.id = @id AND c.candidate_id = @candidate_id AND c.deleted_at IS NULL AND ` + commentScopeCondition(scope, args)
+
+		var rootID string
+		if err := tx.QueryRow(ctx, selectParent, args).Scan(&rootID, &visibility, &parentAuthor); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return "", ErrCommentNotFound
+			}
+
+			return "", fmt.Errorf("select parent comment: %w", err)
+		}
+
+		parentID = &rootID
+	}
+
+	if visibility == domain.CommentPrivate && len(params.Mentions) > 0 {
+		return "", ErrPrivateMention
+	}
+
+	const insert = `
+		INSERT INTO hiring.t_candidate_comments (candidate_id, parent_id, author_id, visibility, body)
+		VALUES (@candidate_id, @parent_id, @author_id, @visibility, @body)
+		RETURNING id
+	`
+
+	var commentID string
+	if err := tx.QueryRow(ctx, insert, pgx.NamedArgs{
+		"candidate_id": params.CandidateID,
+		"parent_id":    parentID,
+		"author_id":    scope.UserID,
+		"visibility":   visibility,
+		"body":         params.Body,
+	}).Scan(&commentID); err != nil {
+		return "", fmt.Errorf("insert comment: %w", err)
+	}
+
+	if err := addMentions(ctx, tx, scope.TeamID, params.CandidateID, commentID, params.Mentions, allJobs); err != nil {
+		return "", err
+	}
+
+	notification := &domain.Notification{
+		Type:        domain.NotificationMention,
+		ActorID:     &scope.UserID,
+		CandidateID: &params.CandidateID,
+		CommentID:   &commentID,
+	}
+
+	if err := notify(ctx, tx, scope.TeamID, notification, except(params.Mentions, scope.UserID)); err != nil {
+		return "", err
+	}
+
+	if parentAuthor != nil && *parentAuthor != scope.UserID && !slices.Contains(params.Mentions, *parentAuthor) {
+		denied, err := withoutAccess(ctx, tx, params.CandidateID, []string{*parentAuthor}, allJobs)
+		if err != nil {
+			return "", err
+		}
+
+		if denied == 0 {
+			notification.Type = domain.NotificationReply
+
+			if err := notify(ctx, tx, scope.TeamID, notification, []string{*parentAuthor}); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return commentID, nil
+}
+
+// lockComment locks a live comment the caller may read and checks that the
+// caller wrote it. It returns the comment's body, visibility and candidate.
+func lockComment(ctx context.Context, tx pgx.Tx, scope domain.JobScope, commentID string) (body, visibility, candidateID string, err error) {
+	args := pgx.NamedArgs{"id": commentID}
+
+	query := `
+		SELECT c.author_id, c.body, c.visibility, c.candidate_id
+		FROM hiring.t_candidate_comments c
+		JOIN hiring.t_candidates cand ON cand.id = c.candidate_id
+		WHERE c.id = @id AND c.deleted_at IS NULL AND ` + commentScopeCondition(scope, args) + `
+		FOR UPDATE OF c
+	`
+
+	var authorID *string
+	if err := tx.QueryRow(ctx, query, args).Scan(&authorID, &body, &visibility, &candidateID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", "", ErrCommentNotFound
+		}
+
+		return "", "", "", fmt.Errorf("select comment: %w", err)
+	}
+
+	if authorID == nil || *authorID != scope.UserID {
+		return "", "", "", ErrCommentForbidden
+	}
+
+	return body, visibility, candidateID, nil
+}
+
+// UpdateComment replaces the body and mentions of the caller's comment. The
+// previous body is kept as a revision. Members no longer mentioned lose their
+// unread notification; newly mentioned members are notified and must see the
+// candidate, as in CreateComment.
+func (r *commentRepo) UpdateComment(ctx context.Context, scope domain.JobScope, commentID string, update domain.CommentUpdate, allJobs []string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	body, visibility, candidateID, err := lockComment(ctx, tx, scope, commentID)
+	if err != nil {
+		return err
+	}
+
+	if visibility == domain.CommentPrivate && len(update.Mentions) > 0 {
+		return ErrPrivateMention
+	}
+
+	if update.Body != body {
+		const revision = `
+			INSERT INTO hiring.t_candidate_comment_revisions (comment_id, action, body, changed_by)
+			VALUES (@id, @action, @body, @user_id)
+		`
+
+		if _, err := tx.Exec(ctx, revision, pgx.NamedArgs{
+			"id":      commentID,
+			"action":  domain.RevisionEdited,
+			"body":    body,
+			"user_id": scope.UserID,
+		}); err != nil {
+			return fmt.Errorf("insert revision: %w", err)
+		}
+
+		const updateBody = `UPDATE hiring.t_candidate_comments SET body = @body, edited_at = NOW() WHERE id = @id`
+
+		if _, err := tx.Exec(ctx, updateBody, pgx.NamedArgs{"id": commentID, "body": update.Body}); err != nil {
+			return fmt.Errorf("update comment: %w", err)
+		}
+	}
+
+	const selectMentions = `SELECT user_id::text FROM hiring.t_candidate_comment_mentions WHERE comment_id = @id`
+
+	rows, err := tx.Query(ctx, selectMentions, pgx.NamedArgs{"id": commentID})
+	if err != nil {
+		return fmt.Errorf("query mentions: %w", err)
+	}
+
+	current, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("scan mentions: %w", err)
+	}
+
+	var added, removed []string
+
+	for _, userID := range update.Mentions {
+		if !slices.Contains(current, userID) {
+			added = append(added, userID)
+		}
+	}
+
+	for _, userID := range current {
+		if !slices.Contains(update.Mentions, userID) {
+			removed = append(removed, userID)
+		}
+	}
+
+	if len(removed) > 0 {
+		removal := []struct{ name, query string }{
+			{"mentions", `DELETE FROM hiring.t_candidate_comment_mentions WHERE comment_id = @id AND user_id = ANY(@users::uuid[])`},
+			{"notifications", `
+				DELETE FROM hiring.t_notifications
+				WHERE comment_id = @id AND type = @type AND user_id = ANY(@users::uuid[]) AND read_at IS NULL
+			`},
+		}
+
+		for _, remove := range removal {
+			if _, err := tx.Exec(ctx, remove.query, pgx.NamedArgs{
+				"id":    commentID,
+				"type":  domain.NotificationMention,
+				"users": removed,
+			}); err != nil {
+				return fmt.Errorf("delete %s: %w", remove.name, err)
+			}
+		}
+	}
+
+	if err := addMentions(ctx, tx, scope.TeamID, candidateID, commentID, added, allJobs); err != nil {
+		return err
+	}
+
+	if err := notify(ctx, tx, scope.TeamID, &domain.Notification{
+		Type:        domain.NotificationMention,
+		ActorID:     &scope.UserID,
+		CandidateID: &candidateID,
+		CommentID:   &commentID,
+	}, except(added, scope.UserID)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteComment marks the caller's comment deleted and keeps its last body
+// as a revision. Replies stay in the thread; notifications about the comment
+// are removed.
+func (r *commentRepo) DeleteComment(ctx context.Context, scope domain.JobScope, commentID string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	body, _, _, err := lockComment(ctx, tx, scope, commentID)
+	if err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"id":      commentID,
+		"action":  domain.RevisionDeleted,
+		"body":    body,
+		"user_id": scope.UserID,
+	}
+
+	steps := []struct{ name, query string }{
+		{"revision", `
+			INSERT INTO hiring.t_candidate_comment_revisions (comment_id, action, body, changed_by)
+			VALUES (@id, @action, @body, @user_id)
+		`},
+		{"comment", `UPDATE hiring.t_candidate_comments SET deleted_at = NOW() WHERE id = @id`},
+		{"notifications", `DELETE FROM hiring.t_notifications WHERE comment_id = @id`},
+	}
+
+	for _, step := range steps {
+		if _, err := tx.Exec(ctx, step.query, args); err != nil {
+			return fmt.Errorf("delete %s: %w", step.name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListRevisions returns the earlier bodies of a comment the caller may read,
+// oldest first. Deleted comments keep their history.
+func (r *commentRepo) ListRevisions(ctx context.Context, scope domain.JobScope, commentID string) ([]domain.CommentRevision, error) {
+	args := pgx.NamedArgs{"id": commentID}
+
+	selectComment := `
+		SELECT 1
+		FROM hiring.t_candidate_comments c
+		JOIN hiring.t_candidates cand ON cand.id = c.candidate_id
+		WHERE c.id = @id AND ` + commentScopeCondition(scope, args)
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, selectComment, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+
+		return nil, fmt.Errorf("select comment: %w", err)
+	}
+
+	const query = `
+		SELECT rv.id, rv.action, rv.body, rv.changed_by,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS changed_by_name,
+			rv.changed_at
+		FROM hiring.t_candidate_comment_revisions rv
+		LEFT JOIN auth.t_users u ON u.id = rv.changed_by
+		WHERE rv.comment_id = @id
+		ORDER BY rv.changed_at, rv.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{"id": commentID})
+	if err != nil {
+		return nil, fmt.Errorf("query revisions: %w", err)
+	}
+
+	revisions, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.CommentRevision])
+	if err != nil {
+		return nil, fmt.Errorf("scan revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// addMentions records the mentioned members of a comment. Every user must be
+// a member of the team who can see the candidate.
+func addMentions(ctx context.Context, tx pgx.Tx, teamID, candidateID, commentID string, userIDs, allJobs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO hiring.t_candidate_comment_mentions (comment_id, user_id)
+		SELECT @comment_id, u.id
+		FROM auth.t_users u
+		WHERE u.team_id = @team_id AND u.id = ANY(@users::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+
+	tag, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"comment_id": commentID,
+		"team_id":    teamID,
+		"users":      userIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("insert mentions: %w", err)
+	}
+
+	if tag.RowsAffected() != int64(len(userIDs)) {
+		return ErrMentionNotMember
+	}
+
+	denied, err := withoutAccess(ctx, tx, candidateID, userIDs, allJobs)
+	if err != nil {
+		return err
+	}
+
+	if denied > 0 {
+		return ErrMentionNoAccess
+	}
+
+	return nil
+}
+
+// except returns ids without id.
+func except(ids []string, id string) []string {
+	return slices.DeleteFunc(slices.Clone(ids), func(v string) bool { return v == id })
+}
@@ -0,0 +1,525 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrDuplicateNotFound = errors.New("duplicate pair not found")
+
+type DuplicateRepository interface {
+	ListDuplicates(ctx context.Context, filter domain.DuplicateFilter) ([]domain.Duplicate, int, error)
+	DetectDuplicates(ctx context.Context, teamID, candidateID string) (int, error)
+	DismissDuplicate(ctx context.Context, scope domain.JobScope, duplicateID string) error
+	MergeCandidates(ctx context.Context, scope domain.JobScope, targetID, sourceID string) (*domain.Candidate, error)
+	ListMerges(ctx context.Context, teamID, candidateID string) ([]domain.CandidateMerge, error)
+}
+
+type duplicateRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewDuplicateRepo(dbClient *db.PostgresClient) DuplicateRepository {
+	return &duplicateRepo{dbClient: dbClient}
+}
+
+// detectDuplicates refreshes the suspected duplicates of a candidate within
+// tx. The rules live in hiring.f_detect_duplicates.
+func detectDuplicates(ctx context.Context, tx pgx.Tx, candidateID string) error {
+	const query = `SELECT hiring.f_detect_duplicates(@id)`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{"id": candidateID}); err != nil {
+		return fmt.Errorf("detect duplicates: %w", err)
+	}
+
+	return nil
+}
+
+func duplicateSide(c, j string) string {
+	return `json_build_object(
+		'id', ` + c + `.id,
+		'job_id', ` + c + `.job_id,
+		'job_title', ` + j + `.title,
+		'first_name', COALESCE(` + c + `.first_name, ''),
+		'last_name', COALESCE(` + c + `.last_name, ''),
+		'email', COALESCE(` + c + `.email, ''),
+		'phone', COALESCE(` + c + `.phone, ''),
+		'status', COALESCE(` + c + `.status, ''),
+		'source', ` + c + `.source
+	)`
+}
+
+var duplicateColumns = `
+	d.id,
+	` + duplicateSide("a", "ja") + ` AS candidate,
+	` + duplicateSide("b", "jb") + ` AS duplicate,
+	d.score, d.reasons, d.name_similarity, d.resume_similarity,
+	d.status, d.detected_at, d.resolved_at, d.resolved_by
+`
+
+const duplicateFrom = `
+	FROM hiring.t_candidate_duplicates d
+	JOIN hiring.t_candidates a ON a.id = d.candidate_id
+	JOIN hiring.t_jobs ja ON ja.id = a.job_id
+	JOIN hiring.t_candidates b ON b.id = d.duplicate_id
+	JOIN hiring.t_jobs jb ON jb.id = b.job_id
+`
+
+// ListDuplicates returns a page of suspected pairs whose both candidates are
+// within filter.Scope, most likely duplicates first, and the total number of
+// matches.
+func (r *duplicateRepo) ListDuplicates(ctx context.Context, filter domain.DuplicateFilter) ([]domain.Duplicate, int, error) {
+	args := pgx.NamedArgs{}
+
+	where := ` WHERE d.team_id = @team_id AND ` + jobScopeCondition("a.job_id", filter.Scope, args) +
+		` AND ` + jobScopeCondition("b.job_id", filter.Scope, args)
+	args["team_id"] = filter.Scope.TeamID
+
+	if filter.Status != "" {
+		where += ` AND d.status = @status`
+		args["status"] = filter.Status
+	}
+
+	if filter.JobID != "" {
+		where += ` AND (a.job_id = @job_id OR b.job_id = @job_id)`
+		args["job_id"] = filter.JobID
+	}
+
+	if filter.CandidateID != "" {
+		where += ` AND @candidate_id IN (d.candidate_id, d.duplicate_id)`
+		args["candidate_id"] = filter.CandidateID
+	}
+
+	if filter.MinScore != nil {
+		where += ` AND d.score >= @min_score`
+		args["min_score"] = *filter.MinScore
+	}
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, `SELECT COUNT(*)`+duplicateFrom+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count duplicates: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := `SELECT ` + duplicateColumns + duplicateFrom + where + `
+		ORDER BY d.score DESC, d.detected_at DESC, d.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query duplicates: %w", err)
+	}
+
+	duplicates, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Duplicate])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan duplicates: %w", err)
+	}
+
+	return duplicates, total, nil
+}
+
+// DetectDuplicates compares a candidate of the team with the others again,
+// for instance after its resume embeddings were replaced, and returns the
+// number of suspected pairs.
+func (r *duplicateRepo) DetectDuplicates(ctx context.Context, teamID, candidateID string) (int, error) {
+	const query = `
+		SELECT hiring.f_detect_duplicates(c.id)
+		FROM hiring.t_candidates c
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		WHERE j.team_id = @team_id AND c.id = @id
+	`
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      candidateID,
+	}).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrCandidateNotFound
+		}
+
+		return 0, fmt.Errorf("detect duplicates: %w", err)
+	}
+
+	return found, nil
+}
+
+// DismissDuplicate marks a pair within scope as two different people, so
+// detection keeps it out of the pending queue.
+func (r *duplicateRepo) DismissDuplicate(ctx context.Context, scope domain.JobScope, duplicateID string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{
+		"id":      duplicateID,
+		"team_id": scope.TeamID,
+		"user_id": scope.UserID,
+	}
+
+	query := `
+		UPDATE hiring.t_candidate_duplicates d
+		SET status = 'dismissed',
+			resolved_at = NOW(),
+			resolved_by = @user_id
+		FROM hiring.t_candidates a, hiring.t_candidates b
+		WHERE d.id = @id AND d.team_id = @team_id
+			AND a.id = d.candidate_id AND b.id = d.duplicate_id
+			AND ` + jobScopeCondition("a.job_id", scope, args) + `
+			AND ` + jobScopeCondition("b.job_id", scope, args) + `
+		RETURNING d.candidate_id, d.duplicate_id
+	`
+
+	var candidateID, otherID string
+	if err := tx.QueryRow(ctx, query, args).Scan(&candidateID, &otherID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDuplicateNotFound
+		}
+
+		return fmt.Errorf("dismiss duplicate: %w", err)
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionDuplicateDismissed,
+		TargetID:  &candidateID,
+		Details: map[string]any{
+			"duplicate_id": otherID,
+			"pair_id":      duplicateID,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// MergeCandidates folds the source candidate into the target one and
+// removes the source. The target keeps its job, stage and contact details;
+// blank contact fields, the resume when the target has none, the profile
+// corrections, the stage history, communications, chats, comments,
+// interviews with their invitations and the scorecards an interviewer has not
+// also given the target in the same stage are taken over from the source. The
+// extracted profile and the score are taken over only when the target has
+// none, the score only within the same job since it rates the candidate
+// against the job. The source as it was is kept in hiring.t_candidate_merges.
+// Returns the removed source.
+func (r *duplicateRepo) MergeCandidates(ctx context.Context, scope domain.JobScope, targetID, sourceID string) (*domain.Candidate, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{"ids": []string{targetID, sourceID}}
+
+	// Rows are locked in id order so that two merges of the same pair in
+	// opposite directions cannot deadlock.
+	lock := `
+		SELECT ` + candidateColumns + `
+		FROM hiring.t_candidates c
+		WHERE c.id = ANY(@ids::uuid[]) AND ` + jobScopeCondition("c.job_id", scope, args) + `
+		ORDER BY c.id
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, lock, args)
+	if err != nil {
+		return nil, fmt.Errorf("lock candidates: %w", err)
+	}
+
+	locked, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Candidate])
+	if err != nil {
+		return nil, fmt.Errorf("scan candidates: %w", err)
+	}
+
+	var target, source *domain.Candidate
+	for i := range locked {
+		switch locked[i].ID {
+		case targetID:
+			target = &locked[i]
+		case sourceID:
+			source = &locked[i]
+		}
+	}
+
+	if target == nil || source == nil {
+		return nil, ErrCandidateNotFound
+	}
+
+	pair := pgx.NamedArgs{
+		"team_id":   scope.TeamID,
+		"user_id":   scope.UserID,
+		"target_id": targetID,
+		"source_id": sourceID,
+	}
+
+	const audit = `
+		INSERT INTO hiring.t_candidate_merges (team_id, candidate_id, merged_id, merged_job_id, snapshot, merged_by)
+		SELECT @team_id, @target_id, c.id, c.job_id, jsonb_build_object(
+			'candidate', to_jsonb(c) - 'email_norm' - 'phone_norm' - 'name_norm',
+			'profile', to_jsonb(p),
+			'score', to_jsonb(s),
+			'score_factors', (SELECT jsonb_agg(to_jsonb(f)) FROM ai_engine.t_score_factors f WHERE f.candidate_id = c.id)
+		), @user_id
+		FROM hiring.t_candidates c
+		LEFT JOIN hiring.t_candidate_profiles p ON p.candidate_id = c.id
+		LEFT JOIN ai_engine.t_candidate_scores s ON s.candidate_id = c.id
+		WHERE c.id = @source_id
+	`
+
+	if _, err := tx.Exec(ctx, audit, pair); err != nil {
+		return nil, fmt.Errorf("insert merge audit: %w", err)
+	}
+
+	const contacts = `
+		UPDATE hiring.t_candidates c
+		SET first_name = COALESCE(NULLIF(c.first_name, ''), src.first_name),
+			last_name = COALESCE(NULLIF(c.last_name, ''), src.last_name),
+			email = COALESCE(c.email, src.email),
+			phone = COALESCE(c.phone, src.phone),
+			updated_at = NOW()
+		FROM hiring.t_candidates src
+		WHERE c.id = @target_id AND src.id = @source_id
+	`
+
+	if _, err := tx.Exec(ctx, contacts, pair); err != nil {
+		return nil, fmt.Errorf("merge contacts: %w", err)
+	}
+
+	if target.ResumeFileKey == "" && source.ResumeFileKey != "" {
+		if err := takeOverResume(ctx, tx, targetID, sourceID); err != nil {
+			return nil, err
+		}
+	}
+
+	const deleteTasks = `DELETE FROM ai_engine.t_processing_tasks WHERE entity_id = @source_id`
+
+	if _, err := tx.Exec(ctx, deleteTasks, pair); err != nil {
+		return nil, fmt.Errorf("delete processing tasks: %w", err)
+	}
+
+	const profile = `
+		INSERT INTO hiring.t_candidate_profiles AS p (
+			candidate_id, extracted_data, corrections, schema_version, provider,
+			extracted_at, corrected_at, corrected_by, updated_at
+		)
+		SELECT @target_id, extracted_data, corrections, schema_version, provider,
+			extracted_at, corrected_at, corrected_by, NOW()
+		FROM hiring.t_candidate_profiles
+		WHERE candidate_id = @source_id
+		ON CONFLICT (candidate_id) DO UPDATE
+		SET extracted_data = COALESCE(p.extracted_data, EXCLUDED.extracted_data),
+			schema_version = CASE WHEN p.extracted_data IS NULL THEN EXCLUDED.schema_version ELSE p.schema_version END,
+			provider = CASE WHEN p.extracted_data IS NULL THEN EXCLUDED.provider ELSE p.provider END,
+			extracted_at = CASE WHEN p.extracted_data IS NULL THEN EXCLUDED.extracted_at ELSE p.extracted_at END,
+			corrections = hiring.f_jsonb_merge_patch(EXCLUDED.corrections, p.corrections),
+			corrected_at = GREATEST(p.corrected_at, EXCLUDED.corrected_at),
+			corrected_by = COALESCE(p.corrected_by, EXCLUDED.corrected_by),
+			updated_at = NOW()
+	`
+
+	if _, err := tx.Exec(ctx, profile, pair); err != nil {
+		return nil, fmt.Errorf("merge profile: %w", err)
+	}
+
+	if target.JobID == source.JobID {
+		if err := takeOverScore(ctx, tx, targetID, sourceID); err != nil {
+			return nil, err
+		}
+	}
+
+	moves := []struct{ name, query string }{
+		{"status history", `UPDATE hiring.t_status_history SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"communications", `UPDATE ai_engine.t_communications SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"chat sessions", `UPDATE ai_engine.t_chat_sessions SET target_candidate_id = @target_id WHERE target_candidate_id = @source_id`},
+		{"comments", `UPDATE hiring.t_candidate_comments SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"notifications", `UPDATE hiring.t_notifications SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"interviews", `UPDATE hiring.t_interviews SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"scorecards", `
+			UPDATE hiring.t_scorecards s SET candidate_id = @target_id
+			WHERE s.candidate_id = @source_id AND NOT EXISTS (
+				SELECT 1 FROM hiring.t_scorecards t
+				WHERE t.candidate_id = @target_id AND t.stage = s.stage AND t.interviewer_id = s.interviewer_id
+			)
+		`},
+	}
+
+	for _, move := range moves {
+		if _, err := tx.Exec(ctx, move.query, pair); err != nil {
+			return nil, fmt.Errorf("move %s: %w", move.name, err)
+		}
+	}
+
+	const deleteSource = `DELETE FROM hiring.t_candidates WHERE id = @source_id`
+
+	if _, err := tx.Exec(ctx, deleteSource, pair); err != nil {
+		return nil, fmt.Errorf("delete merged candidate: %w", err)
+	}
+
+	if err := detectDuplicates(ctx, tx, targetID); err != nil {
+		return nil, err
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionCandidateMerged,
+		TargetID:  &target.ID,
+		Details: map[string]any{
+			"job_id":        target.JobID,
+			"merged_id":     source.ID,
+			"merged_job_id": source.JobID,
+			"name":          source.FirstName + " " + source.LastName,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return source, nil
+}
+
+// takeOverResume moves the resume of the source to a target that has none,
+// together with its parsed text, OCR pages, embeddings and resume task.
+func takeOverResume(ctx context.Context, tx pgx.Tx, targetID, sourceID string) error {
+	args := pgx.NamedArgs{
+		"target_id":       targetID,
+		"source_id":       sourceID,
+		"target_workflow": domain.ResumeWorkflowID(targetID),
+		"source_workflow": domain.ResumeWorkflowID(sourceID),
+	}
+
+	steps := []struct{ name, query string }{
+		{"resume", `
+			UPDATE hiring.t_candidates c
+			SET resume_file_key = src.resume_file_key,
+				resume_file_name = src.resume_file_name,
+				parsed_text = src.parsed_text,
+				parsed_language = src.parsed_language,
+				parsed_at = src.parsed_at,
+				parsed_by_ocr = src.parsed_by_ocr,
+				ocr_confidence = src.ocr_confidence
+			FROM hiring.t_candidates src
+			WHERE c.id = @target_id AND src.id = @source_id
+		`},
+		{"ocr pages", `DELETE FROM ai_engine.t_ocr_pages WHERE candidate_id = @target_id`},
+		{"ocr pages", `UPDATE ai_engine.t_ocr_pages SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"embeddings", `UPDATE ai_engine.t_resume_embeddings SET candidate_id = @target_id WHERE candidate_id = @source_id`},
+		{"resume task", `DELETE FROM ai_engine.t_processing_tasks WHERE workflow_id = @target_workflow`},
+		{"resume task", `
+			UPDATE ai_engine.t_processing_tasks
+			SET workflow_id = @target_workflow, entity_id = @target_id
+			WHERE workflow_id = @source_workflow
+		`},
+	}
+
+	for _, step := range steps {
+		if _, err := tx.Exec(ctx, step.query, args); err != nil {
+			return fmt.Errorf("move %s: %w", step.name, err)
+		}
+	}
+
+	return nil
+}
+
+// takeOverScore gives the target the score of the source, with its factors
+// and history, when the target has not been scored.
+func takeOverScore(ctx context.Context, tx pgx.Tx, targetID, sourceID string) error {
+	args := pgx.NamedArgs{
+		"target_id": targetID,
+		"source_id": sourceID,
+	}
+
+	const insertScore = `
+		INSERT INTO ai_engine.t_candidate_scores (candidate_id, match_score, analyzed_at)
+		SELECT @target_id, match_score, analyzed_at
+		FROM ai_engine.t_candidate_scores
+		WHERE candidate_id = @source_id
+		ON CONFLICT (candidate_id) DO NOTHING
+	`
+
+	tag, err := tx.Exec(ctx, insertScore, args)
+	if err != nil {
+		return fmt.Errorf("move score: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	const moveFactors = `UPDATE ai_engine.t_score_factors SET candidate_id = @target_id WHERE candidate_id = @source_id`
+
+	if _, err := tx.Exec(ctx, moveFactors, args); err != nil {
+		return fmt.Errorf("move score factors: %w", err)
+	}
+
+	// The insert above recorded the latest score in the history of the
+	// target again, so that analysis is not moved twice.
+	const moveHistory = `
+		UPDATE ai_engine.t_candidate_score_history h SET candidate_id = @target_id
+		WHERE h.candidate_id = @source_id AND NOT EXISTS (
+			SELECT 1 FROM ai_engine.t_candidate_score_history t
+			WHERE t.candidate_id = @target_id AND t.analyzed_at = h.analyzed_at
+				AND t.match_score IS NOT DISTINCT FROM h.match_score
+		)
+	`
+
+	if _, err := tx.Exec(ctx, moveHistory, args); err != nil {
+		return fmt.Errorf("move score history: %w", err)
+	}
+
+	return nil
+}
+
+// ListMerges returns the candidates merged into a candidate of the team,
+// newest first. The storage key and text of the merged resume are left out
+// of the snapshots.
+func (r *duplicateRepo) ListMerges(ctx context.Context, teamID, candidateID string) ([]domain.CandidateMerge, error) {
+	const query = `
+		SELECT m.id, m.candidate_id, m.merged_id, m.merged_job_id,
+			m.snapshot #- '{candidate,resume_file_key}' #- '{candidate,parsed_text}' AS snapshot,
+			m.merged_by,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS merged_by_name,
+			m.merged_at
+		FROM hiring.t_candidate_merges m
+		LEFT JOIN auth.t_users u ON u.id = m.merged_by
+		WHERE m.team_id = @team_id AND m.candidate_id = @id
+		ORDER BY m.merged_at DESC, m.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      candidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query merges: %w", err)
+	}
+
+	merges, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.CandidateMerge])
+	if err != nil {
+		return nil, fmt.Errorf("scan merges: %w", err)
+	}
+
+	return merges, nil
+}
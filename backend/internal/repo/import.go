@@ -0,0 +1,682 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrImportNotFound = errors.New("import not found")
+
+type ImportRepository interface {
+	CreateImport(ctx context.Context, scope domain.JobScope, imp *domain.CandidateImport) error
+	ListImports(ctx context.Context, filter domain.ImportFilter) ([]domain.CandidateImport, int, error)
+	GetImport(ctx context.Context, scope domain.JobScope, importID string) (*domain.CandidateImport, error)
+	ListImportRows(ctx context.Context, filter domain.ImportRowFilter) ([]domain.ImportRow, int, error)
+	ClaimImportTask(ctx context.Context, lease time.Duration) (*domain.ImportTask, error)
+	SetImportTotal(ctx context.Context, importID string, total int) error
+	ImportCandidate(ctx context.Context, task *domain.ImportTask, entry *domain.ImportEntry) (attached bool, err error)
+	RecordImportRow(ctx context.Context, importID string, row *domain.ImportRow) error
+	TouchImport(ctx context.Context, taskID string, progress int, lease time.Duration) error
+	CompleteImport(ctx context.Context, task *domain.ImportTask) error
+	FailImport(ctx context.Context, taskID, message string, retry bool) error
+}
+
+type importRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewImportRepo(dbClient *db.PostgresClient) ImportRepository {
+	return &importRepo{dbClient: dbClient}
+}
+
+const importColumns = `
+	i.id, i.team_id, i.job_id,
+	COALESCE(j.title, '') AS job_title,
+	i.duplicate_policy,
+	COALESCE(i.stage, '') AS stage,
+	COALESCE(i.csv_file_key, '') AS csv_file_key,
+	COALESCE(i.csv_file_name, '') AS csv_file_name,
+	COALESCE(i.archive_file_key, '') AS archive_file_key,
+	COALESCE(i.archive_file_name, '') AS archive_file_name,
+	COALESCE(t.status::text, 'pending') AS status,
+	COALESCE(t.progress_percent, 0) AS progress_percent,
+	COALESCE(t.error_message, '') AS error_message,
+	i.total_rows,
+	r.created, r.merged, r.skipped, r.failed,
+	i.created_by, i.created_at, i.finished_at
+`
+
+const importFrom = `
+	FROM hiring.t_candidate_imports i
+	JOIN hiring.t_jobs j ON j.id = i.job_id
+	LEFT JOIN ai_engine.t_processing_tasks t ON t.workflow_id = 'import:' || i.id
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) FILTER (WHERE outcome = 'created') AS created,
+			COUNT(*) FILTER (WHERE outcome = 'merged') AS merged,
+			COUNT(*) FILTER (WHERE outcome = 'skipped') AS skipped,
+			COUNT(*) FILTER (WHERE outcome = 'failed') AS failed
+		FROM hiring.t_candidate_import_rows
+		WHERE import_id = i.id
+	) r
+`
+
+// CreateImport stores an import of a job within scope whose files are
+// already uploaded and queues its task.
+func (r *importRepo) CreateImport(ctx context.Context, scope domain.JobScope, imp *domain.CandidateImport) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{"job_id": imp.JobID}
+
+	selectJob := `
+		SELECT COALESCE(j.title, '') FROM hiring.t_jobs j
+		WHERE j.id = @job_id AND ` + jobScopeCondition("j.id", scope, args) + `
+		FOR SHARE
+	`
+
+	if err := tx.QueryRow(ctx, selectJob, args).Scan(&imp.JobTitle); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("select job: %w", err)
+	}
+
+	const insertImport = `
+		INSERT INTO hiring.t_candidate_imports (
+			id, team_id, job_id, duplicate_policy, stage,
+			csv_file_key, csv_file_name, archive_file_key, archive_file_name, created_by
+		)
+		VALUES (
+			@id, @team_id, @job_id, @duplicate_policy, NULLIF(@stage, ''),
+			NULLIF(@csv_file_key, ''), NULLIF(@csv_file_name, ''),
+			NULLIF(@archive_file_key, ''), NULLIF(@archive_file_name, ''), @created_by
+		)
+		RETURNING created_at
+	`
+
+	if err := tx.QueryRow(ctx, insertImport, pgx.NamedArgs{
+		"id":                imp.ID,
+		"team_id":           scope.TeamID,
+		"job_id":            imp.JobID,
+		"duplicate_policy":  imp.DuplicatePolicy,
+		"stage":             imp.Stage,
+		"csv_file_key":      imp.CSVFileKey,
+		"csv_file_name":     imp.CSVFileName,
+		"archive_file_key":  imp.ArchiveFileKey,
+		"archive_file_name": imp.ArchiveFileName,
+		"created_by":        imp.CreatedBy,
+	}).Scan(&imp.CreatedAt); err != nil {
+		return fmt.Errorf("insert import: %w", err)
+	}
+
+	const insertTask = `
+		INSERT INTO ai_engine.t_processing_tasks (workflow_id, entity_id, status, updated_at)
+		VALUES (@workflow_id, @entity_id, 'pending', NOW())
+	`
+
+	if _, err := tx.Exec(ctx, insertTask, pgx.NamedArgs{
+		"workflow_id": domain.ImportWorkflowID(imp.ID),
+		"entity_id":   imp.ID,
+	}); err != nil {
+		return fmt.Errorf("insert processing task: %w", err)
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionCandidateImportStarted,
+		TargetID:  &imp.ID,
+		Details: map[string]any{
+			"job_id":            imp.JobID,
+			"duplicate_policy":  imp.DuplicatePolicy,
+			"csv_file_name":     imp.CSVFileName,
+			"archive_file_name": imp.ArchiveFileName,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	imp.TeamID = scope.TeamID
+	imp.Status = domain.TaskPending
+
+	return nil
+}
+
+func (r *importRepo) ListImports(ctx context.Context, filter domain.ImportFilter) ([]domain.CandidateImport, int, error) {
+	args := pgx.NamedArgs{}
+
+	where := ` WHERE i.team_id = @team_id AND ` + jobScopeCondition("i.job_id", filter.Scope, args)
+	args["team_id"] = filter.Scope.TeamID
+
+	if filter.JobID != "" {
+		where += ` AND i.job_id = @job_id`
+		args["job_id"] = filter.JobID
+	}
+
+	const count = `SELECT COUNT(*) FROM hiring.t_candidate_imports i`
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, count+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count imports: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := `SELECT ` + importColumns + importFrom + where + `
+		ORDER BY i.created_at DESC, i.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query imports: %w", err)
+	}
+
+	imports, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.CandidateImport])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan imports: %w", err)
+	}
+
+	return imports, total, nil
+}
+
+func (r *importRepo) GetImport(ctx context.Context, scope domain.JobScope, importID string) (*domain.CandidateImport, error) {
+	args := pgx.NamedArgs{"id": importID, "team_id": scope.TeamID}
+
+	query := `SELECT ` + importColumns + importFrom + `
+		WHERE i.id = @id AND i.team_id = @team_id AND ` + jobScopeCondition("i.job_id", scope, args)
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query import: %w", err)
+	}
+
+	imp, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.CandidateImport])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrImportNotFound
+		}
+
+		return nil, fmt.Errorf("scan import: %w", err)
+	}
+
+	return &imp, nil
+}
+
+// ListImportRows returns the rows of an import within scope in file order.
+// A zero filter.Limit returns all of them, e.g. for the summary report.
+func (r *importRepo) ListImportRows(ctx context.Context, filter domain.ImportRowFilter) ([]domain.ImportRow, int, error) {
+	args := pgx.NamedArgs{"id": filter.ImportID, "team_id": filter.Scope.TeamID}
+
+	selectImport := `
+		SELECT 1 FROM hiring.t_candidate_imports i
+		WHERE i.id = @id AND i.team_id = @team_id AND ` + jobScopeCondition("i.job_id", filter.Scope, args)
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, selectImport, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, ErrImportNotFound
+		}
+
+		return nil, 0, fmt.Errorf("select import: %w", err)
+	}
+
+	where := ` WHERE import_id = @id`
+
+	if filter.Outcome != "" {
+		where += ` AND outcome = @outcome`
+		args["outcome"] = filter.Outcome
+	}
+
+	const count = `SELECT COUNT(*) FROM hiring.t_candidate_import_rows`
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, count+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count import rows: %w", err)
+	}
+
+	query := `
+		SELECT row_no,
+			COALESCE(file_name, '') AS file_name,
+			COALESCE(first_name, '') AS first_name,
+			COALESCE(last_name, '') AS last_name,
+			COALESCE(email, '') AS email,
+			COALESCE(phone, '') AS phone,
+			outcome, candidate_id,
+			COALESCE(message, '') AS message,
+			processed_at
+		FROM hiring.t_candidate_import_rows` + where + `
+		ORDER BY row_no
+	`
+
+	if filter.Limit > 0 {
+		query += ` LIMIT @limit OFFSET @offset`
+		args["limit"] = filter.Limit
+		args["offset"] = filter.Offset
+	}
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query import rows: %w", err)
+	}
+
+	importRows, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.ImportRow])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan import rows: %w", err)
+	}
+
+	return importRows, total, nil
+}
+
+// ClaimImportTask leases the oldest waiting import task, also one whose lease
+// expired, in the same way as ClaimResumeTask. Tasks whose import was removed
+// with its job are passed over.
+func (r *importRepo) ClaimImportTask(ctx context.Context, lease time.Duration) (*domain.ImportTask, error) {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks t
+		SET status = 'importing',
+			attempts = t.attempts + 1,
+			locked_until = NOW() + make_interval(secs => @lease),
+			updated_at = NOW()
+		FROM hiring.t_candidate_imports i
+		WHERE i.id = t.entity_id
+		  AND t.id = (
+			SELECT pt.id FROM ai_engine.t_processing_tasks pt
+			JOIN hiring.t_candidate_imports ci ON ci.id = pt.entity_id
+			WHERE pt.workflow_id LIKE @prefix
+			  AND pt.status IN ('pending', 'importing')
+			  AND (pt.locked_until IS NULL OR pt.locked_until < NOW())
+			ORDER BY pt.updated_at NULLS FIRST
+			LIMIT 1
+			FOR UPDATE OF pt SKIP LOCKED
+		  )
+		RETURNING t.id AS task_id, i.id AS import_id, i.team_id, i.job_id, i.created_by,
+			i.duplicate_policy,
+			COALESCE(i.stage, '') AS stage,
+			COALESCE(i.csv_file_key, '') AS csv_file_key,
+			COALESCE(i.archive_file_key, '') AS archive_file_key,
+			i.total_rows,
+			(SELECT COALESCE(MAX(row_no), 0) FROM hiring.t_candidate_import_rows WHERE import_id = i.id) AS done,
+			t.attempts
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"prefix": domain.WorkflowImport + ":%",
+		"lease":  lease.Seconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim task: %w", err)
+	}
+
+	task, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.ImportTask])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoTask
+		}
+
+		return nil, fmt.Errorf("scan task: %w", err)
+	}
+
+	return &task, nil
+}
+
+func (r *importRepo) SetImportTotal(ctx context.Context, importID string, total int) error {
+	const query = `UPDATE hiring.t_candidate_imports SET total_rows = @total WHERE id = @id`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":    importID,
+		"total": total,
+	}); err != nil {
+		return fmt.Errorf("update import: %w", err)
+	}
+
+	return nil
+}
+
+// ImportCandidate applies one row of an import in a transaction together
+// with its outcome record. A candidate of the job with the row's email or
+// phone is a duplicate: it is left alone under the skip policy, gets its
+// blank contacts and missing resume from the row under the merge policy, and
+// is ignored under the create policy. It reports whether the row's resume
+// was attached to a candidate; otherwise the caller removes the file. The
+// outcome is stored on entry.
+func (r *importRepo) ImportCandidate(ctx context.Context, task *domain.ImportTask, entry *domain.ImportEntry) (bool, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if entry.Email != "" {
+		const lock = `SELECT pg_advisory_xact_lock(hashtextextended(@job_id::text || ':' || LOWER(@email::text), 0))`
+
+		if _, err := tx.Exec(ctx, lock, pgx.NamedArgs{
+			"job_id": task.JobID,
+			"email":  entry.Email,
+		}); err != nil {
+			return false, fmt.Errorf("lock candidate: %w", err)
+		}
+	}
+
+	const selectDuplicate = `
+		SELECT id, COALESCE(resume_file_key, '') <> ''
+		FROM hiring.t_candidates
+		WHERE job_id = @job_id
+		  AND (email_norm = NULLIF(LOWER(BTRIM(@email::text)), '')
+			OR phone_norm = NULLIF(REGEXP_REPLACE(@phone::text, '[^0-9]', '', 'g'), ''))
+		ORDER BY email_norm = NULLIF(LOWER(BTRIM(@email::text)), '') DESC NULLS LAST, created_at
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	var (
+		existingID string
+		hasResume  bool
+		found      = true
+	)
+
+	if err := tx.QueryRow(ctx, selectDuplicate, pgx.NamedArgs{
+		"job_id": task.JobID,
+		"email":  entry.Email,
+		"phone":  entry.Phone,
+	}).Scan(&existingID, &hasResume); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("select duplicate: %w", err)
+		}
+
+		found = false
+	}
+
+	var attached bool
+
+	switch {
+	case found && task.DuplicatePolicy == domain.ImportSkipDuplicates:
+		entry.Outcome = domain.ImportRowSkipped
+		entry.CandidateID = &existingID
+		entry.Message = "matches an existing candidate of the job"
+	case found && task.DuplicatePolicy == domain.ImportMergeDuplicates:
+		if err := mergeImportRow(ctx, tx, existingID, entry); err != nil {
+			return false, err
+		}
+
+		entry.Outcome = domain.ImportRowMerged
+		entry.CandidateID = &existingID
+		attached = !hasResume && entry.ResumeFileKey != ""
+	default:
+		id, err := insertImportRow(ctx, tx, task, entry)
+		if err != nil {
+			return false, err
+		}
+
+		entry.Outcome = domain.ImportRowCreated
+		entry.CandidateID = &id
+		attached = entry.ResumeFileKey != ""
+	}
+
+	if attached {
+		const upsertTask = `
+			INSERT INTO ai_engine.t_processing_tasks (workflow_id, entity_id, status, updated_at)
+			VALUES (@workflow_id, @entity_id, 'pending', NOW())
+			ON CONFLICT (workflow_id) DO UPDATE
+			SET status = 'pending',
+				progress_percent = 0,
+				attempts = 0,
+				locked_until = NULL,
+				error_message = NULL,
+				updated_at = NOW()
+		`
+
+		if _, err := tx.Exec(ctx, upsertTask, pgx.NamedArgs{
+			"workflow_id": domain.ResumeWorkflowID(*entry.CandidateID),
+			"entity_id":   *entry.CandidateID,
+		}); err != nil {
+			return false, fmt.Errorf("upsert processing task: %w", err)
+		}
+	}
+
+	if entry.Outcome != domain.ImportRowSkipped {
+		activity := &domain.Activity{
+			TeamID:    task.TeamID,
+			ActorType: domain.ActorSystem,
+			Action:    domain.ActionCandidateImported,
+			TargetID:  entry.CandidateID,
+			Details: map[string]any{
+				"job_id":    task.JobID,
+				"import_id": task.ImportID,
+				"outcome":   entry.Outcome,
+			},
+		}
+
+		if task.CreatedBy != nil {
+			activity.ActorType = domain.ActorUser
+			activity.ActorID = task.CreatedBy
+		}
+
+		if err := logActivity(ctx, tx, activity); err != nil {
+			return false, err
+		}
+
+		if err := detectDuplicates(ctx, tx, *entry.CandidateID); err != nil {
+			return false, err
+		}
+	}
+
+	if err := insertImportOutcome(ctx, tx, task.ImportID, &entry.ImportRow); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return attached, nil
+}
+
+// mergeImportRow fills the blank contacts of an existing candidate from an
+// import row and gives it the row's resume if it has none.
+func mergeImportRow(ctx context.Context, tx pgx.Tx, candidateID string, entry *domain.ImportEntry) error {
+	const query = `
+		UPDATE hiring.t_candidates
+		SET first_name = COALESCE(NULLIF(first_name, ''), NULLIF(@first_name, '')),
+			last_name = COALESCE(NULLIF(last_name, ''), NULLIF(@last_name, '')),
+			email = COALESCE(NULLIF(email, ''), NULLIF(@email, '')),
+			phone = COALESCE(NULLIF(phone, ''), NULLIF(@phone, '')),
+			resume_file_name = CASE
+				WHEN COALESCE(resume_file_key, '') = '' AND @resume_file_key <> '' THEN @resume_file_name
+				ELSE resume_file_name
+			END,
+			resume_file_key = COALESCE(NULLIF(resume_file_key, ''), NULLIF(@resume_file_key, '')),
+			updated_at = NOW()
+		WHERE id = @id
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"id":               candidateID,
+		"first_name":       entry.FirstName,
+		"last_name":        entry.LastName,
+		"email":            entry.Email,
+		"phone":            entry.Phone,
+		"resume_file_key":  entry.ResumeFileKey,
+		"resume_file_name": entry.ResumeFileName,
+	}); err != nil {
+		return fmt.Errorf("merge candidate: %w", err)
+	}
+
+	return nil
+}
+
+// insertImportRow creates the candidate of an import row at the end of its
+// stage and returns its id.
+func insertImportRow(ctx context.Context, tx pgx.Tx, task *domain.ImportTask, entry *domain.ImportEntry) (string, error) {
+	const query = `
+		INSERT INTO hiring.t_candidates (
+			job_id, first_name, last_name, email, phone,
+			resume_file_key, resume_file_name, status, kanban_position, source
+		)
+		VALUES (
+			@job_id, NULLIF(@first_name, ''), NULLIF(@last_name, ''), NULLIF(@email, ''), NULLIF(@phone, ''),
+			NULLIF(@resume_file_key, ''), NULLIF(@resume_file_name, ''), @status,
+			(SELECT COALESCE(MAX(kanban_position), 0) + 1000 FROM hiring.t_candidates WHERE job_id = @job_id AND status = @status),
+			@source
+		)
+		RETURNING id
+	`
+
+	var id string
+	if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+		"job_id":           task.JobID,
+		"first_name":       entry.FirstName,
+		"last_name":        entry.LastName,
+		"email":            entry.Email,
+		"phone":            entry.Phone,
+		"resume_file_key":  entry.ResumeFileKey,
+		"resume_file_name": entry.ResumeFileName,
+		"status":           entry.Stage,
+		"source":           domain.CandidateSourceImport,
+	}).Scan(&id); err != nil {
+		return "", fmt.Errorf("insert candidate: %w", err)
+	}
+
+	return id, nil
+}
+
+func insertImportOutcome(ctx context.Context, tx pgx.Tx, importID string, row *domain.ImportRow) error {
+	const query = `
+		INSERT INTO hiring.t_candidate_import_rows (
+			import_id, row_no, file_name, first_name, last_name, email, phone, outcome, candidate_id, message
+		)
+		VALUES (
+			@import_id, @row_no, NULLIF(@file_name, ''), NULLIF(@first_name, ''), NULLIF(@last_name, ''),
+			NULLIF(@email, ''), NULLIF(@phone, ''), @outcome, @candidate_id, NULLIF(@message, '')
+		)
+		ON CONFLICT (import_id, row_no) DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"import_id":    importID,
+		"row_no":       row.Row,
+		"file_name":    row.FileName,
+		"first_name":   row.FirstName,
+		"last_name":    row.LastName,
+		"email":        row.Email,
+		"phone":        row.Phone,
+		"outcome":      row.Outcome,
+		"candidate_id": row.CandidateID,
+		"message":      row.Message,
+	}); err != nil {
+		return fmt.Errorf("insert import row: %w", err)
+	}
+
+	return nil
+}
+
+// RecordImportRow stores the outcome of a row that did not reach the
+// database, e.g. one that failed validation.
+func (r *importRepo) RecordImportRow(ctx context.Context, importID string, row *domain.ImportRow) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := insertImportOutcome(ctx, tx, importID, row); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// TouchImport reports progress of a running import and renews its lease.
+func (r *importRepo) TouchImport(ctx context.Context, taskID string, progress int, lease time.Duration) error {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks
+		SET progress_percent = @progress,
+			locked_until = NOW() + make_interval(secs => @lease),
+			updated_at = NOW()
+		WHERE id = @id AND status = 'importing'
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":       taskID,
+		"progress": progress,
+		"lease":    lease.Seconds(),
+	}); err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *importRepo) CompleteImport(ctx context.Context, task *domain.ImportTask) error {
+	const query = `
+		WITH task AS (
+			UPDATE ai_engine.t_processing_tasks
+			SET status = 'completed',
+				progress_percent = 100,
+				locked_until = NULL,
+				error_message = NULL,
+				updated_at = NOW()
+			WHERE id = @task_id AND status = 'importing'
+			RETURNING entity_id
+		)
+		UPDATE hiring.t_candidate_imports
+		SET finished_at = NOW()
+		WHERE id IN (SELECT entity_id FROM task)
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{"task_id": task.TaskID}); err != nil {
+		return fmt.Errorf("complete import: %w", err)
+	}
+
+	return nil
+}
+
+// FailImport records the error of a claimed import. With retry the import
+// waits at the end of the queue and resumes after its last recorded row,
+// otherwise it finishes as failed with the rows done so far.
+func (r *importRepo) FailImport(ctx context.Context, taskID, message string, retry bool) error {
+	const query = `
+		WITH task AS (
+			UPDATE ai_engine.t_processing_tasks
+			SET status = (CASE WHEN @retry THEN 'pending' ELSE 'failed' END)::task_status,
+				locked_until = NULL,
+				error_message = @message,
+				updated_at = NOW()
+			WHERE id = @id AND status = 'importing'
+			RETURNING entity_id
+		)
+		UPDATE hiring.t_candidate_imports
+		SET finished_at = NOW()
+		WHERE id IN (SELECT entity_id FROM task) AND NOT @retry
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":      taskID,
+		"message": message,
+		"retry":   retry,
+	}); err != nil {
+		return fmt.Errorf("fail import: %w", err)
+	}
+
+	return nil
+}
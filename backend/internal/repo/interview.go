@@ -0,0 +1,967 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrInterviewNotFound    = errors.New("interview not found")
+	ErrInterviewCancelled   = errors.New("interview is cancelled")
+	ErrInterviewerNotMember = errors.New("interviewer is not a member of the team")
+	ErrInterviewerNoAccess  = errors.New("interviewer cannot see the candidate")
+	ErrInterviewConflict    = errors.New("interview conflicts with other appointments")
+	ErrAvailabilityNotFound = errors.New("availability window not found")
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+)
+
+// InterviewConflictError lists why a slot cannot be booked. It matches
+// ErrInterviewConflict.
+type InterviewConflictError struct {
+	Conflicts []domain.InterviewConflict
+}
+
+func (e *InterviewConflictError) Error() string {
+	return fmt.Sprintf("interview conflicts with %d other appointments", len(e.Conflicts))
+}
+
+func (e *InterviewConflictError) Is(target error) bool {
+	return target == ErrInterviewConflict
+}
+
+type InterviewRepository interface {
+	ListInterviews(ctx context.Context, filter domain.InterviewFilter) ([]domain.Interview, int, error)
+	GetInterview(ctx context.Context, scope domain.JobScope, interviewID string) (*domain.Interview, error)
+	CheckConflicts(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams) ([]domain.InterviewConflict, error)
+	CreateInterview(ctx context.Context, scope domain.JobScope, params domain.InterviewParams, allJobs []string) (string, error)
+	UpdateInterview(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams, allJobs []string) error
+	CancelInterview(ctx context.Context, scope domain.JobScope, interviewID, reason string, notify bool) error
+
+	ListAvailability(ctx context.Context, teamID, userID string, from, to time.Time) ([]domain.AvailabilityWindow, error)
+	AddAvailability(ctx context.Context, window *domain.AvailabilityWindow) error
+	DeleteAvailability(ctx context.Context, userID, windowID string) error
+
+	SaveCalendarFeed(ctx context.Context, userID string, tokenHash []byte) (time.Time, error)
+	DeleteCalendarFeed(ctx context.Context, userID string) error
+	FeedInterviews(ctx context.Context, tokenHash []byte, from, to time.Time) ([]domain.Interview, error)
+
+	ClaimInterviewMailTask(ctx context.Context, lease time.Duration) (*domain.InterviewMailTask, error)
+	FindInterview(ctx context.Context, interviewID string) (*domain.Interview, error)
+	CompleteInterviewMail(ctx context.Context, taskID string) error
+	FailInterviewMail(ctx context.Context, taskID, message string, retry bool) error
+}
+
+type interviewRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewInterviewRepo(dbClient *db.PostgresClient) InterviewRepository {
+	return &interviewRepo{dbClient: dbClient}
+}
+
+type interviewQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const interviewColumns = `
+	i.id, i.team_id, i.candidate_id,
+	COALESCE(NULLIF(TRIM(CONCAT_WS(' ', c.first_name, c.last_name)), ''), c.email, '') AS candidate_name,
+	COALESCE(c.email, '') AS candidate_email,
+	c.job_id, j.title AS job_title, i.stage, i.title, i.starts_at, i.ends_at, i.timezone,
+	COALESCE(i.location, '') AS location,
+	COALESCE(i.video_url, '') AS video_url,
+	COALESCE(i.notes, '') AS notes,
+	i.status, i.sequence,
+	COALESCE(i.cancel_reason, '') AS cancel_reason,
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'user_id', u.id,
+			'name', COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email),
+			'email', u.email
+		) ORDER BY u.email)
+		FROM hiring.t_interview_interviewers x
+		JOIN auth.t_users u ON u.id = x.user_id
+		WHERE x.interview_id = i.id
+	), '[]') AS interviewers,
+	i.created_by, i.created_at, i.updated_at
+`
+
+const interviewFrom = `
+	FROM hiring.t_interviews i
+	JOIN hiring.t_candidates c ON c.id = i.candidate_id
+	JOIN hiring.t_jobs j ON j.id = c.job_id
+`
+
+// ListInterviews returns a page of the interviews within scope by start time
+// and the total number of matches.
+func (r *interviewRepo) ListInterviews(ctx context.Context, filter domain.InterviewFilter) ([]domain.Interview, int, error) {
+	args := pgx.NamedArgs{}
+
+	where := ` WHERE ` + jobScopeCondition("c.job_id", filter.Scope, args)
+
+	if filter.CandidateID != "" {
+		where += ` AND i.candidate_id = @candidate_id`
+		args["candidate_id"] = filter.CandidateID
+	}
+
+	if filter.JobID != "" {
+		where += ` AND c.job_id = @job_id`
+		args["job_id"] = filter.JobID
+	}
+
+	if filter.InterviewerID != "" {
+		where += ` AND EXISTS (
+			SELECT 1 FROM hiring.t_interview_interviewers x
+			WHERE x.interview_id = i.id AND x.user_id = @interviewer_id
+		)`
+		args["interviewer_id"] = filter.InterviewerID
+	}
+
+	if filter.Status != "" {
+		where += ` AND i.status = @status`
+		args["status"] = filter.Status
+	}
+
+	if filter.From != nil {
+		where += ` AND i.ends_at > @from`
+		args["from"] = *filter.From
+	}
+
+	if filter.To != nil {
+		where += ` AND i.starts_at < @to`
+		args["to"] = *filter.To
+	}
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, `SELECT COUNT(*)`+interviewFrom+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count interviews: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := `SELECT ` + interviewColumns + interviewFrom + where + `
+		ORDER BY i.starts_at, i.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query interviews: %w", err)
+	}
+
+	interviews, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Interview])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan interviews: %w", err)
+	}
+
+	return interviews, total, nil
+}
+
+func (r *interviewRepo) GetInterview(ctx context.Context, scope domain.JobScope, interviewID string) (*domain.Interview, error) {
+	args := pgx.NamedArgs{"id": interviewID}
+
+	query := `SELECT ` + interviewColumns + interviewFrom + `
+		WHERE i.id = @id AND ` + jobScopeCondition("c.job_id", scope, args)
+
+	return getInterview(ctx, r.dbClient.Pool, query, args)
+}
+
+// FindInterview returns an interview regardless of scope, for the worker
+// sending its invitations.
+func (r *interviewRepo) FindInterview(ctx context.Context, interviewID string) (*domain.Interview, error) {
+	query := `SELECT ` + interviewColumns + interviewFrom + ` WHERE i.id = @id`
+
+	return getInterview(ctx, r.dbClient.Pool, query, pgx.NamedArgs{"id": interviewID})
+}
+
+func getInterview(ctx context.Context, q interviewQuerier, query string, args pgx.NamedArgs) (*domain.Interview, error) {
+	rows, err := q.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query interview: %w", err)
+	}
+
+	interview, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Interview])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInterviewNotFound
+		}
+
+		return nil, fmt.Errorf("scan interview: %w", err)
+	}
+
+	return &interview, nil
+}
+
+// CheckConflicts reports what keeps the slot of params from being booked
+// for the candidate and the interviewers. interviewID, when set, is the
+// interview being moved and does not conflict with itself.
+func (r *interviewRepo) CheckConflicts(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams) ([]domain.InterviewConflict, error) {
+	args := pgx.NamedArgs{"id": params.CandidateID}
+
+	query := `SELECT 1 FROM hiring.t_candidates c WHERE c.id = @id AND ` + jobScopeCondition("c.job_id", scope, args)
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, query, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("select candidate: %w", err)
+	}
+
+	return findConflicts(ctx, r.dbClient.Pool, interviewID, params)
+}
+
+// CreateInterview books an interview and returns its id. Unless params.Force
+// is set the slot must be free for the candidate and every interviewer. With
+// params.Notify the invitations are queued in the same transaction.
+// Interviewers must see the candidate: allJobs lists those whose roles grant
+// jobs:all, the others need a grant on the candidate's job.
+func (r *interviewRepo) CreateInterview(ctx context.Context, scope domain.JobScope, params domain.InterviewParams, allJobs []string) (string, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{"id": params.CandidateID, "stage": params.Stage}
+
+	selectCandidate := `
+		SELECT COALESCE(NULLIF(@stage, ''), c.status, ''),
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', c.first_name, c.last_name)), ''), c.email, ''),
+			j.title
+		FROM hiring.t_candidates c
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		WHERE c.id = @id AND ` + jobScopeCondition("c.job_id", scope, args) + `
+		FOR UPDATE OF c
+	`
+
+	var stage, name, job string
+	if err := tx.QueryRow(ctx, selectCandidate, args).Scan(&stage, &name, &job); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrCandidateNotFound
+		}
+
+		return "", fmt.Errorf("select candidate: %w", err)
+	}
+
+	if err := bookSlot(ctx, tx, scope.TeamID, "", params, allJobs); err != nil {
+		return "", err
+	}
+
+	title := params.Title
+	if title == "" {
+		title = fmt.Sprintf("Interview: %s, %s", name, job)
+	}
+
+	const insert = `
+		INSERT INTO hiring.t_interviews
+			(team_id, candidate_id, stage, title, starts_at, ends_at, timezone, location, video_url, notes, created_by)
+		VALUES (@team_id, @candidate_id, @stage, LEFT(@title, 200), @starts_at, @ends_at, @timezone,
+			NULLIF(@location, ''), NULLIF(@video_url, ''), NULLIF(@notes, ''), @created_by)
+		RETURNING id
+	`
+
+	var id string
+	if err := tx.QueryRow(ctx, insert, pgx.NamedArgs{
+		"team_id":      scope.TeamID,
+		"candidate_id": params.CandidateID,
+		"stage":        stage,
+		"title":        title,
+		"starts_at":    params.StartsAt,
+		"ends_at":      params.StartsAt.Add(params.Duration),
+		"timezone":     params.Timezone,
+		"location":     params.Location,
+		"video_url":    params.VideoURL,
+		"notes":        params.Notes,
+		"created_by":   scope.UserID,
+	}).Scan(&id); err != nil {
+		return "", fmt.Errorf("insert interview: %w", err)
+	}
+
+	if err := addInterviewers(ctx, tx, id, params.Interviewers); err != nil {
+		return "", err
+	}
+
+	if params.Notify {
+		if err := queueCandidateMail(ctx, tx, id, domain.InterviewMailInvite); err != nil {
+			return "", err
+		}
+
+		if err := queueInterviewerMails(ctx, tx, id, domain.InterviewMailInvite, params.Interviewers); err != nil {
+			return "", err
+		}
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionInterviewScheduled,
+		TargetID:  &params.CandidateID,
+		Details: map[string]any{
+			"interview_id": id,
+			"stage":        stage,
+			"starts_at":    params.StartsAt,
+			"ends_at":      params.StartsAt.Add(params.Duration),
+			"interviewers": params.Interviewers,
+		},
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateInterview changes the time, place or interviewers of a scheduled
+// interview and raises its sequence. With params.Notify the candidate and the
+// remaining interviewers get an update, added interviewers an invitation and
+// removed ones a cancellation. Interviewers must see the candidate, as in
+// CreateInterview.
+func (r *interviewRepo) UpdateInterview(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams, allJobs []string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := lockInterview(ctx, tx, scope, interviewID)
+	if err != nil {
+		return err
+	}
+
+	params.CandidateID = current.CandidateID
+
+	if err := bookSlot(ctx, tx, scope.TeamID, interviewID, params, allJobs); err != nil {
+		return err
+	}
+
+	const update = `
+		UPDATE hiring.t_interviews
+		SET title = COALESCE(NULLIF(LEFT(@title, 200), ''), title),
+			starts_at = @starts_at,
+			ends_at = @ends_at,
+			timezone = @timezone,
+			location = NULLIF(@location, ''),
+			video_url = NULLIF(@video_url, ''),
+			notes = NULLIF(@notes, ''),
+			sequence = sequence + 1,
+			updated_at = NOW()
+		WHERE id = @id
+	`
+
+	if _, err := tx.Exec(ctx, update, pgx.NamedArgs{
+		"id":        interviewID,
+		"title":     params.Title,
+		"starts_at": params.StartsAt,
+		"ends_at":   params.StartsAt.Add(params.Duration),
+		"timezone":  params.Timezone,
+		"location":  params.Location,
+		"video_url": params.VideoURL,
+		"notes":     params.Notes,
+	}); err != nil {
+		return fmt.Errorf("update interview: %w", err)
+	}
+
+	var kept, added, removed []string
+
+	for _, userID := range params.Interviewers {
+		if slices.Contains(current.interviewers, userID) {
+			kept = append(kept, userID)
+		} else {
+			added = append(added, userID)
+		}
+	}
+
+	for _, userID := range current.interviewers {
+		if !slices.Contains(params.Interviewers, userID) {
+			removed = append(removed, userID)
+		}
+	}
+
+	const deleteInterviewers = `
+		DELETE FROM hiring.t_interview_interviewers
+		WHERE interview_id = @id AND user_id = ANY(@users::uuid[])
+	`
+
+	if _, err := tx.Exec(ctx, deleteInterviewers, pgx.NamedArgs{"id": interviewID, "users": removed}); err != nil {
+		return fmt.Errorf("delete interviewers: %w", err)
+	}
+
+	if err := addInterviewers(ctx, tx, interviewID, added); err != nil {
+		return err
+	}
+
+	if params.Notify {
+		if err := queueCandidateMail(ctx, tx, interviewID, domain.InterviewMailUpdate); err != nil {
+			return err
+		}
+
+		for kind, users := range map[string][]string{
+			domain.InterviewMailUpdate: kept,
+			domain.InterviewMailInvite: added,
+			domain.InterviewMailCancel: removed,
+		} {
+			if err := queueInterviewerMails(ctx, tx, interviewID, kind, users); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionInterviewRescheduled,
+		TargetID:  &current.CandidateID,
+		Details: map[string]any{
+			"interview_id":       interviewID,
+			"starts_at":          params.StartsAt,
+			"ends_at":            params.StartsAt.Add(params.Duration),
+			"previous_starts_at": current.StartsAt,
+			"interviewers":       params.Interviewers,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// CancelInterview cancels a scheduled interview and raises its sequence;
+// with notify everyone invited gets a cancellation.
+func (r *interviewRepo) CancelInterview(ctx context.Context, scope domain.JobScope, interviewID, reason string, notify bool) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := lockInterview(ctx, tx, scope, interviewID)
+	if err != nil {
+		return err
+	}
+
+	const update = `
+		UPDATE hiring.t_interviews
+		SET status = 'cancelled',
+			cancel_reason = NULLIF(@reason, ''),
+			sequence = sequence + 1,
+			updated_at = NOW()
+		WHERE id = @id
+	`
+
+	if _, err := tx.Exec(ctx, update, pgx.NamedArgs{"id": interviewID, "reason": reason}); err != nil {
+		return fmt.Errorf("cancel interview: %w", err)
+	}
+
+	if notify {
+		if err := queueCandidateMail(ctx, tx, interviewID, domain.InterviewMailCancel); err != nil {
+			return err
+		}
+
+		if err := queueInterviewerMails(ctx, tx, interviewID, domain.InterviewMailCancel, current.interviewers); err != nil {
+			return err
+		}
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    scope.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &scope.UserID,
+		Action:    domain.ActionInterviewCancelled,
+		TargetID:  &current.CandidateID,
+		Details: map[string]any{
+			"interview_id": interviewID,
+			"starts_at":    current.StartsAt,
+			"reason":       reason,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+type lockedInterview struct {
+	CandidateID  string
+	StartsAt     time.Time
+	interviewers []string
+}
+
+// lockInterview locks a scheduled interview within scope for a change.
+func lockInterview(ctx context.Context, tx pgx.Tx, scope domain.JobScope, interviewID string) (*lockedInterview, error) {
+	args := pgx.NamedArgs{"id": interviewID}
+
+	query := `
+		SELECT i.candidate_id, i.starts_at, i.status,
+			ARRAY(SELECT x.user_id::text FROM hiring.t_interview_interviewers x WHERE x.interview_id = i.id ORDER BY x.user_id)
+		FROM hiring.t_interviews i
+		JOIN hiring.t_candidates c ON c.id = i.candidate_id
+		WHERE i.id = @id AND ` + jobScopeCondition("c.job_id", scope, args) + `
+		FOR UPDATE OF i
+	`
+
+	var (
+		locked lockedInterview
+		status string
+	)
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&locked.CandidateID, &locked.StartsAt, &status, &locked.interviewers); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInterviewNotFound
+		}
+
+		return nil, fmt.Errorf("lock interview: %w", err)
+	}
+
+	if status == domain.InterviewCancelled {
+		return nil, ErrInterviewCancelled
+	}
+
+	return &locked, nil
+}
+
+// bookSlot checks that the interviewers belong to the team and can see the
+// candidate and, unless params.Force is set, that the slot is free. The
+// calendars of the candidate and the interviewers are locked in id order
+// first, so two bookings of the same person never both see the slot free.
+func bookSlot(ctx context.Context, tx pgx.Tx, teamID, interviewID string, params domain.InterviewParams, allJobs []string) error {
+	const countMembers = `SELECT COUNT(*) FROM auth.t_users WHERE team_id = @team_id AND id = ANY(@users::uuid[])`
+
+	var members int
+	if err := tx.QueryRow(ctx, countMembers, pgx.NamedArgs{
+		"team_id": teamID,
+		"users":   params.Interviewers,
+	}).Scan(&members); err != nil {
+		return fmt.Errorf("count interviewers: %w", err)
+	}
+
+	if members != len(params.Interviewers) {
+		return ErrInterviewerNotMember
+	}
+
+	denied, err := withoutAccess(ctx, tx, params.CandidateID, params.Interviewers, allJobs)
+	if err != nil {
+		return err
+	}
+
+	if denied > 0 {
+		return ErrInterviewerNoAccess
+	}
+
+	calendars := slices.Sorted(slices.Values(append([]string{params.CandidateID}, params.Interviewers...)))
+
+	const lock = `
+		SELECT pg_advisory_xact_lock(hashtextextended('calendar:' || id, 0))
+		FROM unnest(@ids::text[]) WITH ORDINALITY AS t(id, n)
+		ORDER BY n
+	`
+
+	if _, err := tx.Exec(ctx, lock, pgx.NamedArgs{"ids": calendars}); err != nil {
+		return fmt.Errorf("lock calendars: %w", err)
+	}
+
+	if params.Force {
+		return nil
+	}
+
+	conflicts, err := findConflicts(ctx, tx, interviewID, params)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return &InterviewConflictError{Conflicts: conflicts}
+	}
+
+	return nil
+}
+
+// findConflicts lists the scheduled interviews of the interviewers and of the
+// candidate overlapping the slot, and the interviewers who have availability
+// windows but none covering the whole slot.
+func findConflicts(ctx context.Context, q interviewQuerier, interviewID string, params domain.InterviewParams) ([]domain.InterviewConflict, error) {
+	const query = `
+		SELECT 'interview' AS kind, x.user_id::text AS user_id, i.id::text AS interview_id, i.starts_at, i.ends_at
+		FROM hiring.t_interviews i
+		JOIN hiring.t_interview_interviewers x ON x.interview_id = i.id
+		WHERE x.user_id = ANY(@users::uuid[])
+			AND i.status = 'scheduled'
+			AND i.id IS DISTINCT FROM NULLIF(@interview_id, '')::uuid
+			AND i.starts_at < @ends_at AND i.ends_at > @starts_at
+		UNION ALL
+		SELECT 'candidate', NULL, i.id::text, i.starts_at, i.ends_at
+		FROM hiring.t_interviews i
+		WHERE i.candidate_id = @candidate_id
+			AND i.status = 'scheduled'
+			AND i.id IS DISTINCT FROM NULLIF(@interview_id, '')::uuid
+			AND i.starts_at < @ends_at AND i.ends_at > @starts_at
+		UNION ALL
+		SELECT 'unavailable', u::text, NULL, @starts_at::timestamptz, @ends_at::timestamptz
+		FROM unnest(@users::uuid[]) AS u
+		WHERE EXISTS (SELECT 1 FROM hiring.t_availability_windows w WHERE w.user_id = u)
+			AND NOT EXISTS (
+				SELECT 1 FROM hiring.t_availability_windows w
+				WHERE w.user_id = u AND w.starts_at <= @starts_at AND w.ends_at >= @ends_at
+			)
+		ORDER BY 4, 1
+	`
+
+	rows, err := q.Query(ctx, query, pgx.NamedArgs{
+		"users":        params.Interviewers,
+		"candidate_id": params.CandidateID,
+		"interview_id": interviewID,
+		"starts_at":    params.StartsAt,
+		"ends_at":      params.StartsAt.Add(params.Duration),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query conflicts: %w", err)
+	}
+
+	conflicts, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.InterviewConflict])
+	if err != nil {
+		return nil, fmt.Errorf("scan conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+func addInterviewers(ctx context.Context, tx pgx.Tx, interviewID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO hiring.t_interview_interviewers (interview_id, user_id)
+		SELECT @id, u FROM unnest(@users::uuid[]) AS u
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{"id": interviewID, "users": userIDs}); err != nil {
+		return fmt.Errorf("insert interviewers: %w", err)
+	}
+
+	return nil
+}
+
+// queueCandidateMail queues an invitation email of the given kind to the
+// candidate of the interview, if the candidate has an email address.
+func queueCandidateMail(ctx context.Context, tx pgx.Tx, interviewID, kind string) error {
+	const query = `
+		WITH mails AS (
+			INSERT INTO hiring.t_interview_mails (interview_id, kind, recipient_type, email, name)
+			SELECT i.id, @kind, 'candidate', c.email,
+				LEFT(COALESCE(NULLIF(TRIM(CONCAT_WS(' ', c.first_name, c.last_name)), ''), ''), 200)
+			FROM hiring.t_interviews i
+			JOIN hiring.t_candidates c ON c.id = i.candidate_id
+			WHERE i.id = @id AND COALESCE(c.email, '') <> ''
+			RETURNING id
+		)
+		INSERT INTO ai_engine.t_processing_tasks (workflow_id, entity_id, status, updated_at)
+		SELECT @prefix || id::text, id, 'pending', NOW() FROM mails
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"id":     interviewID,
+		"kind":   kind,
+		"prefix": domain.WorkflowInterviewMail + ":",
+	}); err != nil {
+		return fmt.Errorf("queue candidate mail: %w", err)
+	}
+
+	return nil
+}
+
+// queueInterviewerMails queues an invitation email of the given kind to each
+// of the members in userIDs.
+func queueInterviewerMails(ctx context.Context, tx pgx.Tx, interviewID, kind string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	const query = `
+		WITH mails AS (
+			INSERT INTO hiring.t_interview_mails (interview_id, kind, recipient_type, email, name)
+			SELECT @id, @kind, 'interviewer', u.email,
+				LEFT(COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), ''), 200)
+			FROM auth.t_users u
+			WHERE u.id = ANY(@users::uuid[])
+			RETURNING id
+		)
+		INSERT INTO ai_engine.t_processing_tasks (workflow_id, entity_id, status, updated_at)
+		SELECT @prefix || id::text, id, 'pending', NOW() FROM mails
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"id":     interviewID,
+		"kind":   kind,
+		"users":  userIDs,
+		"prefix": domain.WorkflowInterviewMail + ":",
+	}); err != nil {
+		return fmt.Errorf("queue interviewer mails: %w", err)
+	}
+
+	return nil
+}
+
+// ListAvailability returns the availability windows of a member of the team
+// that overlap the period, by start time.
+func (r *interviewRepo) ListAvailability(ctx context.Context, teamID, userID string, from, to time.Time) ([]domain.AvailabilityWindow, error) {
+	const query = `
+		SELECT w.id, w.user_id, w.starts_at, w.ends_at, w.created_at
+		FROM hiring.t_availability_windows w
+		JOIN auth.t_users u ON u.id = w.user_id
+		WHERE u.team_id = @team_id AND w.user_id = @user_id
+			AND w.ends_at > @from AND w.starts_at < @to
+		ORDER BY w.starts_at, w.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"user_id": userID,
+		"from":    from,
+		"to":      to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query availability: %w", err)
+	}
+
+	windows, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.AvailabilityWindow])
+	if err != nil {
+		return nil, fmt.Errorf("scan availability: %w", err)
+	}
+
+	return windows, nil
+}
+
+func (r *interviewRepo) AddAvailability(ctx context.Context, window *domain.AvailabilityWindow) error {
+	const query = `
+		INSERT INTO hiring.t_availability_windows (user_id, starts_at, ends_at)
+		VALUES (@user_id, @starts_at, @ends_at)
+		RETURNING id, created_at
+	`
+
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"user_id":   window.UserID,
+		"starts_at": window.StartsAt,
+		"ends_at":   window.EndsAt,
+	}).Scan(&window.ID, &window.CreatedAt); err != nil {
+		return fmt.Errorf("insert availability: %w", err)
+	}
+
+	return nil
+}
+
+func (r *interviewRepo) DeleteAvailability(ctx context.Context, userID, windowID string) error {
+	const query = `DELETE FROM hiring.t_availability_windows WHERE id = @id AND user_id = @user_id`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{"id": windowID, "user_id": userID})
+	if err != nil {
+		return fmt.Errorf("delete availability: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrAvailabilityNotFound
+	}
+
+	return nil
+}
+
+// SaveCalendarFeed sets the token of the member's feed, replacing an earlier
+// one, and returns when it was created.
+func (r *interviewRepo) SaveCalendarFeed(ctx context.Context, userID string, tokenHash []byte) (time.Time, error) {
+	const query = `
+		INSERT INTO hiring.t_calendar_feeds (user_id, token_hash)
+		VALUES (@user_id, @token_hash)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()
+		RETURNING created_at
+	`
+
+	var createdAt time.Time
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"user_id":    userID,
+		"token_hash": tokenHash,
+	}).Scan(&createdAt); err != nil {
+		return time.Time{}, fmt.Errorf("save calendar feed: %w", err)
+	}
+
+	return createdAt, nil
+}
+
+func (r *interviewRepo) DeleteCalendarFeed(ctx context.Context, userID string) error {
+	const query = `DELETE FROM hiring.t_calendar_feeds WHERE user_id = @user_id`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return fmt.Errorf("delete calendar feed: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrCalendarFeedNotFound
+	}
+
+	return nil
+}
+
+// FeedInterviews returns the interviews of the member owning the feed token
+// that overlap the period, cancelled ones included so that subscribed
+// calendars drop them.
+func (r *interviewRepo) FeedInterviews(ctx context.Context, tokenHash []byte, from, to time.Time) ([]domain.Interview, error) {
+	const selectFeed = `
+		SELECT f.user_id, u.team_id
+		FROM hiring.t_calendar_feeds f
+		JOIN auth.t_users u ON u.id = f.user_id
+		WHERE f.token_hash = @token_hash
+	`
+
+	var userID, teamID string
+	if err := r.dbClient.Pool.QueryRow(ctx, selectFeed, pgx.NamedArgs{"token_hash": tokenHash}).Scan(&userID, &teamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCalendarFeedNotFound
+		}
+
+		return nil, fmt.Errorf("select calendar feed: %w", err)
+	}
+
+	query := `SELECT ` + interviewColumns + interviewFrom + `
+		WHERE i.team_id = @team_id
+			AND i.ends_at > @from AND i.starts_at < @to
+			AND EXISTS (
+				SELECT 1 FROM hiring.t_interview_interviewers x
+				WHERE x.interview_id = i.id AND x.user_id = @user_id
+			)
+		ORDER BY i.starts_at, i.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"user_id": userID,
+		"from":    from,
+		"to":      to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query feed interviews: %w", err)
+	}
+
+	interviews, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Interview])
+	if err != nil {
+		return nil, fmt.Errorf("scan feed interviews: %w", err)
+	}
+
+	return interviews, nil
+}
+
+// ClaimInterviewMailTask leases the oldest waiting invitation email, also one
+// whose lease expired, in the same way as ClaimResumeTask. Tasks whose email
+// was removed with its interview are passed over.
+func (r *interviewRepo) ClaimInterviewMailTask(ctx context.Context, lease time.Duration) (*domain.InterviewMailTask, error) {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks t
+		SET status = 'sending',
+			attempts = t.attempts + 1,
+			locked_until = NOW() + make_interval(secs => @lease),
+			updated_at = NOW()
+		FROM hiring.t_interview_mails m
+		WHERE m.id = t.entity_id
+		  AND t.id = (
+			SELECT pt.id FROM ai_engine.t_processing_tasks pt
+			JOIN hiring.t_interview_mails im ON im.id = pt.entity_id
+			WHERE pt.workflow_id LIKE @prefix
+			  AND pt.status IN ('pending', 'sending')
+			  AND (pt.locked_until IS NULL OR pt.locked_until < NOW())
+			ORDER BY pt.updated_at NULLS FIRST
+			LIMIT 1
+			FOR UPDATE OF pt SKIP LOCKED
+		  )
+		RETURNING t.id AS task_id, m.id AS mail_id, m.interview_id, m.kind, m.recipient_type,
+			m.email, m.name, t.attempts
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"prefix": domain.WorkflowInterviewMail + ":%",
+		"lease":  lease.Seconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim task: %w", err)
+	}
+
+	task, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.InterviewMailTask])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoTask
+		}
+
+		return nil, fmt.Errorf("scan task: %w", err)
+	}
+
+	return &task, nil
+}
+
+func (r *interviewRepo) CompleteInterviewMail(ctx context.Context, taskID string) error {
+	const query = `
+		WITH task AS (
+			UPDATE ai_engine.t_processing_tasks
+			SET status = 'completed',
+				progress_percent = 100,
+				locked_until = NULL,
+				error_message = NULL,
+				updated_at = NOW()
+			WHERE id = @task_id AND status = 'sending'
+			RETURNING entity_id
+		)
+		UPDATE hiring.t_interview_mails
+		SET sent_at = NOW()
+		WHERE id IN (SELECT entity_id FROM task)
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{"task_id": taskID}); err != nil {
+		return fmt.Errorf("complete mail: %w", err)
+	}
+
+	return nil
+}
+
+// FailInterviewMail records the error of a claimed email. With retry the
+// email waits at the end of the queue, otherwise it is given up.
+func (r *interviewRepo) FailInterviewMail(ctx context.Context, taskID, message string, retry bool) error {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks
+		SET status = (CASE WHEN @retry THEN 'pending' ELSE 'failed' END)::task_status,
+			locked_until = NULL,
+			error_message = @message,
+			updated_at = NOW()
+		WHERE id = @id AND status = 'sending'
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":      taskID,
+		"message": message,
+		"retry":   retry,
+	}); err != nil {
+		return fmt.Errorf("fail mail: %w", err)
+	}
+
+	return nil
+}
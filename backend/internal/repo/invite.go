@@ -50,6 +50,23 @@ func (r *inviteRepo) CreateInvite(ctx context.Context, invite *domain.Invite, jo
 	}
 
 	if len(jobIDs) > 0 {
+		const countJobs = `
+			SELECT COUNT(*) FROM hiring.t_jobs
+			WHERE team_id = @team_id AND id = ANY(@job_ids)
+		`
+
+		var found int
+		if err := tx.QueryRow(ctx, countJobs, pgx.NamedArgs{
+			"team_id": invite.TeamID,
+			"job_ids": jobIDs,
+		}).Scan(&found); err != nil {
+			return fmt.Errorf("count invite jobs: %w", err)
+		}
+
+		if found != len(jobIDs) {
+			return ErrJobNotFound
+		}
+
 		rows := make([][]any, len(jobIDs))
 		for i, jobID := range jobIDs {
 			rows[i] = []any{invite.ID, jobID}
@@ -0,0 +1,380 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrJobStatusConflict = errors.New("job status changed concurrently")
+
+type JobRepository interface {
+	ListJobs(ctx context.Context, filter domain.JobFilter) ([]domain.Job, int, error)
+	GetJob(ctx context.Context, teamID, jobID string) (*domain.Job, error)
+	CreateJob(ctx context.Context, job *domain.Job, grantCreator bool) error
+	UpdateJob(ctx context.Context, job *domain.Job) error
+	DeleteJob(ctx context.Context, teamID, jobID string) error
+	ChangeJobStatus(ctx context.Context, job *domain.Job, change domain.JobStatusChange) error
+	ListOpenJobs(ctx context.Context, teamID string) ([]domain.CareersJob, error)
+	GetJobSettings(ctx context.Context, teamID string) (*domain.JobSettings, error)
+	UpdateJobSettings(ctx context.Context, teamID string, settings *domain.JobSettings) error
+}
+
+type jobRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewJobRepo(dbClient *db.PostgresClient) JobRepository {
+	return &jobRepo{dbClient: dbClient}
+}
+
+const jobColumns = `
+	j.id, j.team_id, j.title,
+	COALESCE(j.department, '') AS department,
+	COALESCE(j.work_format::text, '') AS work_format,
+	COALESCE(j.description, '') AS description,
+	j.extracted_requirements,
+	j.status,
+	COALESCE(j.close_reason, '') AS close_reason, j.closed_at,
+	j.approved_by, j.approved_at, j.published_at,
+	j.created_by, j.created_at, j.updated_at
+`
+
+func (r *jobRepo) ListJobs(ctx context.Context, filter domain.JobFilter) ([]domain.Job, int, error) {
+	args := pgx.NamedArgs{}
+
+	where := ` WHERE j.team_id = @team_id AND ` + jobScopeCondition("j.id", filter.Scope, args)
+	args["team_id"] = filter.Scope.TeamID
+
+	if filter.Status != "" {
+		where += ` AND j.status = @status`
+		args["status"] = filter.Status
+	}
+
+	if filter.Department != "" {
+		where += ` AND j.department = @department`
+		args["department"] = filter.Department
+	}
+
+	if filter.WorkFormat != "" {
+		where += ` AND j.work_format = @work_format::work_format_type`
+		args["work_format"] = filter.WorkFormat
+	}
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM hiring.t_jobs j`+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count jobs: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := `SELECT ` + jobColumns + ` FROM hiring.t_jobs j` + where + `
+		ORDER BY j.created_at DESC, j.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query jobs: %w", err)
+	}
+
+	jobs, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Job])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan jobs: %w", err)
+	}
+
+	return jobs, total, nil
+}
+
+func (r *jobRepo) GetJob(ctx context.Context, teamID, jobID string) (*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM hiring.t_jobs j
+		WHERE j.team_id = @team_id AND j.id = @id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      jobID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query job: %w", err)
+	}
+
+	job, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Job])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("scan job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// CreateJob inserts the job and, when grantCreator is set, gives its author
+// access to it so that members without jobs:all can see what they created.
+func (r *jobRepo) CreateJob(ctx context.Context, job *domain.Job, grantCreator bool) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const insertJob = `
+		INSERT INTO hiring.t_jobs (team_id, title, department, work_format, description, created_by)
+		VALUES (
+			@team_id, @title, NULLIF(@department, ''), NULLIF(@work_format, '')::work_format_type,
+			NULLIF(@description, ''), @created_by
+		)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	if err := tx.QueryRow(ctx, insertJob, pgx.NamedArgs{
+		"team_id":     job.TeamID,
+		"title":       job.Title,
+		"department":  job.Department,
+		"work_format": job.WorkFormat,
+		"description": job.Description,
+		"created_by":  job.CreatedBy,
+	}).Scan(&job.ID, &job.Status, &job.CreatedAt, &job.UpdatedAt); err != nil {
+		return fmt.Errorf("insert job: %w", err)
+	}
+
+	if grantCreator && job.CreatedBy != nil {
+		const insertAccess = `INSERT INTO hiring.t_job_access (user_id, job_id) VALUES (@user_id, @job_id)`
+
+		if _, err := tx.Exec(ctx, insertAccess, pgx.NamedArgs{
+			"user_id": *job.CreatedBy,
+			"job_id":  job.ID,
+		}); err != nil {
+			return fmt.Errorf("insert job access: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) UpdateJob(ctx context.Context, job *domain.Job) error {
+	query := `
+		UPDATE hiring.t_jobs j
+		SET title = @title,
+			department = NULLIF(@department, ''),
+			work_format = NULLIF(@work_format, '')::work_format_type,
+			description = NULLIF(@description, ''),
+			updated_at = NOW()
+		WHERE j.team_id = @team_id AND j.id = @id
+		RETURNING ` + jobColumns
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id":     job.TeamID,
+		"id":          job.ID,
+		"title":       job.Title,
+		"department":  job.Department,
+		"work_format": job.WorkFormat,
+		"description": job.Description,
+	})
+	if err != nil {
+		return fmt.Errorf("update job: %w", err)
+	}
+
+	updated, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Job])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("scan job: %w", err)
+	}
+
+	*job = updated
+
+	return nil
+}
+
+func (r *jobRepo) DeleteJob(ctx context.Context, teamID, jobID string) error {
+	const query = `DELETE FROM hiring.t_jobs WHERE team_id = @team_id AND id = @id`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      jobID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete job: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// ChangeJobStatus moves the job from change.From to change.To and applies the
+// side effects in one transaction: AI tasks of the job's candidates are
+// cancelled when it stops accepting candidates, and those cancelled by a pause
+// are re-queued when the job reopens. The update fails with
+// ErrJobStatusConflict if the status was changed concurrently.
+func (r *jobRepo) ChangeJobStatus(ctx context.Context, job *domain.Job, change domain.JobStatusChange) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		UPDATE hiring.t_jobs j
+		SET status = @to,
+			close_reason = CASE WHEN @to IN ('closed', 'filled') THEN NULLIF(@reason, '') ELSE j.close_reason END,
+			closed_at = CASE WHEN @to IN ('closed', 'filled') THEN NOW() ELSE j.closed_at END,
+			approved_by = CASE WHEN @approved THEN @actor_id::uuid ELSE j.approved_by END,
+			approved_at = CASE WHEN @approved THEN NOW() ELSE j.approved_at END,
+			published_at = CASE WHEN @to = 'open' THEN COALESCE(j.published_at, NOW()) ELSE j.published_at END,
+			updated_at = NOW()
+		WHERE j.team_id = @team_id AND j.id = @id AND j.status = @from
+		RETURNING ` + jobColumns
+
+	rows, err := tx.Query(ctx, query, pgx.NamedArgs{
+		"team_id":  job.TeamID,
+		"id":       job.ID,
+		"from":     change.From,
+		"to":       change.To,
+		"reason":   change.Reason,
+		"approved": change.Approved,
+		"actor_id": change.ActorID,
+	})
+	if err != nil {
+		return fmt.Errorf("update job status: %w", err)
+	}
+
+	updated, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Job])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrJobStatusConflict
+		}
+
+		return fmt.Errorf("scan job: %w", err)
+	}
+
+	const cancelTasks = `
+		UPDATE ai_engine.t_processing_tasks
+		SET status = 'cancelled', cancelled_by_pause = @paused, locked_until = NULL, updated_at = NOW()
+		WHERE status IN ('pending', 'extracting', 'analyzing')
+		  AND entity_id IN (SELECT id FROM hiring.t_candidates WHERE job_id = @job_id)
+	`
+
+	// Only the tasks the pause cancelled go back to the queue; tasks
+	// cancelled for other reasons stay cancelled.
+	const requeueTasks = `
+		UPDATE ai_engine.t_processing_tasks
+		SET status = 'pending', cancelled_by_pause = FALSE, locked_until = NULL, updated_at = NOW()
+		WHERE status = 'cancelled' AND cancelled_by_pause
+		  AND entity_id IN (SELECT id FROM hiring.t_candidates WHERE job_id = @job_id)
+	`
+
+	switch {
+	case domain.JobAcceptsCandidates(change.From) && !domain.JobAcceptsCandidates(change.To):
+		_, err = tx.Exec(ctx, cancelTasks, pgx.NamedArgs{
+			"job_id": job.ID,
+			"paused": change.To == domain.JobStatusPaused,
+		})
+	case change.From == domain.JobStatusPaused && change.To == domain.JobStatusOpen:
+		_, err = tx.Exec(ctx, requeueTasks, pgx.NamedArgs{"job_id": job.ID})
+	}
+
+	if err != nil {
+		return fmt.Errorf("update processing tasks: %w", err)
+	}
+
+	const insertActivity = `
+		INSERT INTO hiring.t_activity_logs (team_id, actor_type, actor_id, action_id, target_id, details)
+		SELECT @team_id, 'user', @actor_id, id, @job_id, @details
+		FROM hiring.t_action_types
+		WHERE code = @action
+	`
+
+	details := map[string]any{"from": change.From, "to": change.To}
+	if change.Reason != "" {
+		details["reason"] = change.Reason
+	}
+
+	if _, err := tx.Exec(ctx, insertActivity, pgx.NamedArgs{
+		"team_id":  job.TeamID,
+		"actor_id": change.ActorID,
+		"job_id":   job.ID,
+		"details":  details,
+		"action":   domain.ActionJobStatusChanged,
+	}); err != nil {
+		return fmt.Errorf("insert activity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	*job = updated
+
+	return nil
+}
+
+// ListOpenJobs returns the jobs shown on the team's public careers page.
+func (r *jobRepo) ListOpenJobs(ctx context.Context, teamID string) ([]domain.CareersJob, error) {
+	const query = `
+		SELECT
+			id, title,
+			COALESCE(department, '') AS department,
+			COALESCE(work_format::text, '') AS work_format,
+			COALESCE(description, '') AS description,
+			COALESCE(published_at, created_at) AS published_at,
+			updated_at
+		FROM hiring.t_jobs
+		WHERE team_id = @team_id AND status = 'open'
+		ORDER BY published_at DESC NULLS LAST, id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{"team_id": teamID})
+	if err != nil {
+		return nil, fmt.Errorf("query open jobs: %w", err)
+	}
+
+	jobs, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.CareersJob])
+	if err != nil {
+		return nil, fmt.Errorf("scan open jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *jobRepo) GetJobSettings(ctx context.Context, teamID string) (*domain.JobSettings, error) {
+	const query = `SELECT job_approval_required FROM auth.t_teams WHERE id = @team_id`
+
+	var settings domain.JobSettings
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{"team_id": teamID}).Scan(&settings.ApprovalRequired); err != nil {
+		return nil, fmt.Errorf("query job settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *jobRepo) UpdateJobSettings(ctx context.Context, teamID string, settings *domain.JobSettings) error {
+	const query = `UPDATE auth.t_teams SET job_approval_required = @approval_required WHERE id = @team_id`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"team_id":           teamID,
+		"approval_required": settings.ApprovalRequired,
+	}); err != nil {
+		return fmt.Errorf("update job settings: %w", err)
+	}
+
+	return nil
+}
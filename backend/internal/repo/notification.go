@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationRepository interface {
+	ListNotifications(ctx context.Context, filter domain.NotificationFilter) ([]domain.Notification, int, error)
+	MarkRead(ctx context.Context, userID, notificationID string) error
+	MarkAllRead(ctx context.Context, userID string) (int, error)
+}
+
+type notificationRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewNotificationRepo(dbClient *db.PostgresClient) NotificationRepository {
+	return &notificationRepo{dbClient: dbClient}
+}
+
+// notify creates a notification like n for every user in userIDs within tx,
+// so that it commits together with the change it announces.
+func notify(ctx context.Context, tx pgx.Tx, teamID string, n *domain.Notification, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO hiring.t_notifications (team_id, user_id, type, actor_id, candidate_id, comment_id)
+		SELECT @team_id, u, @type, @actor_id, @candidate_id, @comment_id
+		FROM unnest(@users::uuid[]) AS u
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"team_id":      teamID,
+		"users":        userIDs,
+		"type":         n.Type,
+		"actor_id":     n.ActorID,
+		"candidate_id": n.CandidateID,
+		"comment_id":   n.CommentID,
+	}); err != nil {
+		return fmt.Errorf("insert notifications: %w", err)
+	}
+
+	return nil
+}
+
+// ListNotifications returns a page of the member's notifications, newest
+// first, and the total number of matches. Excerpt holds the start of the
+// comment.
+func (r *notificationRepo) ListNotifications(ctx context.Context, filter domain.NotificationFilter) ([]domain.Notification, int, error) {
+	const from = `
+		FROM hiring.t_notifications n
+		LEFT JOIN auth.t_users u ON u.id = n.actor_id
+		LEFT JOIN hiring.t_candidates cand ON cand.id = n.candidate_id
+		LEFT JOIN hiring.t_candidate_comments cm ON cm.id = n.comment_id
+	`
+
+	where := ` WHERE n.user_id = @user_id`
+	if filter.Unread {
+		where += ` AND n.read_at IS NULL`
+	}
+
+	args := pgx.NamedArgs{"user_id": filter.UserID}
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, `SELECT COUNT(*)`+from+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notifications: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := `
+		SELECT n.id, n.type, n.actor_id,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS actor_name,
+			n.candidate_id,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', cand.first_name, cand.last_name)), ''), cand.email, '') AS candidate_name,
+			cand.job_id, n.comment_id,
+			COALESCE(CASE WHEN cm.deleted_at IS NULL THEN LEFT(cm.body, 200) END, '') AS excerpt,
+			n.created_at, n.read_at
+	` + from + where + `
+		ORDER BY n.created_at DESC, n.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query notifications: %w", err)
+	}
+
+	notifications, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Notification])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+// MarkRead marks a notification of the member as read. Marking it again
+// keeps the first read time.
+func (r *notificationRepo) MarkRead(ctx context.Context, userID, notificationID string) error {
+	const query = `
+		UPDATE hiring.t_notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = @id AND user_id = @user_id
+	`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":      notificationID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("update notification: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the member as read and
+// returns how many there were.
+func (r *notificationRepo) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	const query = `UPDATE hiring.t_notifications SET read_at = NOW() WHERE user_id = @user_id AND read_at IS NULL`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("update notifications: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,853 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrPipelineNotFound = errors.New("pipeline template not found")
+	ErrPipelineExists   = errors.New("pipeline template already exists")
+	ErrPipelineConflict = errors.New("pipeline template was changed concurrently")
+	ErrPipelineInUse    = errors.New("pipeline template is in use")
+	ErrStageInUse       = errors.New("stage still has candidates")
+)
+
+// StageInUseError names a stage that cannot be removed because candidates
+// are still in it. It matches ErrStageInUse.
+type StageInUseError struct {
+	Stage string
+}
+
+func (e *StageInUseError) Error() string {
+	return fmt.Sprintf("stage %q still has candidates", e.Stage)
+}
+
+func (e *StageInUseError) Is(target error) bool {
+	return target == ErrStageInUse
+}
+
+type PipelineRepository interface {
+	ListTemplates(ctx context.Context, teamID string) ([]domain.PipelineTemplate, error)
+	GetTemplate(ctx context.Context, teamID, templateID string) (*domain.PipelineTemplate, error)
+	CreateTemplate(ctx context.Context, session domain.Session, template *domain.PipelineTemplate, defaultChange *domain.DefaultChange) error
+	UpdateTemplate(ctx context.Context, session domain.Session, template *domain.PipelineTemplate, version time.Time, migration domain.StageMigration, defaultChange *domain.DefaultChange) error
+	DeleteTemplate(ctx context.Context, session domain.Session, templateID string) error
+	GetJobPipeline(ctx context.Context, teamID, jobID string) (*domain.JobPipeline, error)
+	GetCandidatePipeline(ctx context.Context, teamID, candidateID string) (*domain.JobPipeline, error)
+	SetJobPipeline(ctx context.Context, session domain.Session, change domain.JobPipelineChange) error
+}
+
+type pipelineRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewPipelineRepo(dbClient *db.PostgresClient) PipelineRepository {
+	return &pipelineRepo{dbClient: dbClient}
+}
+
+type pipelineQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const templateColumns = `id, team_id, name, is_default, created_at, updated_at`
+
+const stageColumns = `id, name, type::text AS type, position, transitions`
+
+// templateJobs selects the jobs that follow a template: those assigned to it
+// and, for the default template, those assigned to none. Jobs with stages of
+// their own are left out.
+const templateJobs = `
+	SELECT j.id FROM hiring.t_jobs j
+	WHERE j.team_id = @team_id
+		AND NOT EXISTS (SELECT 1 FROM hiring.t_pipeline_stages s WHERE s.job_id = j.id)
+		AND (j.pipeline_template_id = @template_id OR (j.pipeline_template_id IS NULL AND @is_default::boolean))
+`
+
+// defaultJobs selects the jobs that follow the team default template.
+const defaultJobs = `
+	SELECT j.id FROM hiring.t_jobs j
+	WHERE j.team_id = @team_id AND j.pipeline_template_id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM hiring.t_pipeline_stages s WHERE s.job_id = j.id)
+`
+
+func (r *pipelineRepo) ListTemplates(ctx context.Context, teamID string) ([]domain.PipelineTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM hiring.t_pipeline_templates
+		WHERE team_id = @team_id
+		ORDER BY is_default DESC, name
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{"team_id": teamID})
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline templates: %w", err)
+	}
+
+	templates, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.PipelineTemplate])
+	if err != nil {
+		return nil, fmt.Errorf("scan pipeline templates: %w", err)
+	}
+
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	ids := make([]string, len(templates))
+	for i, t := range templates {
+		ids[i] = t.ID
+	}
+
+	type templateStage struct {
+		TemplateID string `db:"template_id"`
+		domain.Stage
+	}
+
+	rows, err = r.dbClient.Pool.Query(ctx, `
+		SELECT template_id, `+stageColumns+`
+		FROM hiring.t_pipeline_stages
+		WHERE template_id = ANY(@ids::uuid[])
+		ORDER BY template_id, position
+	`, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline stages: %w", err)
+	}
+
+	stages, err := pgx.CollectRows(rows, pgx.RowToStructByName[templateStage])
+	if err != nil {
+		return nil, fmt.Errorf("scan pipeline stages: %w", err)
+	}
+
+	byTemplate := make(map[string]domain.Stages, len(templates))
+	for _, s := range stages {
+		byTemplate[s.TemplateID] = append(byTemplate[s.TemplateID], s.Stage)
+	}
+
+	for i := range templates {
+		templates[i].Stages = byTemplate[templates[i].ID]
+	}
+
+	return templates, nil
+}
+
+func (r *pipelineRepo) GetTemplate(ctx context.Context, teamID, templateID string) (*domain.PipelineTemplate, error) {
+	return getTemplate(ctx, r.dbClient.Pool, teamID, templateID)
+}
+
+func getTemplate(ctx context.Context, q pipelineQuerier, teamID, templateID string) (*domain.PipelineTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM hiring.t_pipeline_templates
+		WHERE team_id = @team_id AND id = @id
+	`
+
+	rows, err := q.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      templateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline template: %w", err)
+	}
+
+	template, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.PipelineTemplate])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPipelineNotFound
+		}
+
+		return nil, fmt.Errorf("scan pipeline template: %w", err)
+	}
+
+	template.Stages, err = queryStages(ctx, q, "template_id", template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// queryStages returns the stages owned by a template or a job, depending on
+// owner, in pipeline order.
+func queryStages(ctx context.Context, q pipelineQuerier, owner, id string) (domain.Stages, error) {
+	query := `
+		SELECT ` + stageColumns + `
+		FROM hiring.t_pipeline_stages
+		WHERE ` + owner + ` = @id
+		ORDER BY position
+	`
+
+	rows, err := q.Query(ctx, query, pgx.NamedArgs{"id": id})
+	if err != nil {
+		return nil, fmt.Errorf("query pipeline stages: %w", err)
+	}
+
+	stages, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Stage])
+	if err != nil {
+		return nil, fmt.Errorf("scan pipeline stages: %w", err)
+	}
+
+	return stages, nil
+}
+
+// insertStages stores stages for a template or a job, depending on owner,
+// keeping the IDs of stages that have one.
+func insertStages(ctx context.Context, tx pgx.Tx, owner, id string, stages domain.Stages) error {
+	query := `
+		INSERT INTO hiring.t_pipeline_stages (id, ` + owner + `, name, type, position, transitions)
+		VALUES (COALESCE(NULLIF(@stage_id, '')::uuid, gen_random_uuid()), @id, @name, @type, @position, @transitions)
+		RETURNING id
+	`
+
+	for i := range stages {
+		stages[i].Position = i
+
+		if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+			"stage_id":    stages[i].ID,
+			"id":          id,
+			"name":        stages[i].Name,
+			"type":        stages[i].Type,
+			"position":    i,
+			"transitions": stages[i].Transitions,
+		}).Scan(&stages[i].ID); err != nil {
+			return fmt.Errorf("insert pipeline stage %q: %w", stages[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+// clearDefault unsets the team's default template so another can take its
+// place.
+func clearDefault(ctx context.Context, tx pgx.Tx, teamID string) error {
+	const query = `
+		UPDATE hiring.t_pipeline_templates
+		SET is_default = FALSE, updated_at = NOW()
+		WHERE team_id = @team_id AND is_default
+	`
+
+	if _, err := tx.Exec(ctx, query, pgx.NamedArgs{"team_id": teamID}); err != nil {
+		return fmt.Errorf("clear default template: %w", err)
+	}
+
+	return nil
+}
+
+// migrateDefault moves the candidates of the jobs following the team default
+// as change says. A default other than the one change was worked out from is
+// reported as ErrPipelineConflict. A nil change does nothing.
+func migrateDefault(ctx context.Context, tx pgx.Tx, session domain.Session, teamID string, change *domain.DefaultChange) error {
+	if change == nil {
+		return nil
+	}
+
+	// The advisory lock also covers a team without a default, where there
+	// is no row to lock.
+	const lockDefault = `
+		SELECT pg_advisory_xact_lock(hashtextextended('pipeline_default:' || @team_id::text, 0))
+	`
+
+	args := pgx.NamedArgs{"team_id": teamID}
+
+	if _, err := tx.Exec(ctx, lockDefault, args); err != nil {
+		return fmt.Errorf("lock default template: %w", err)
+	}
+
+	const selectDefault = `
+		SELECT id, updated_at
+		FROM hiring.t_pipeline_templates
+		WHERE team_id = @team_id AND is_default
+		FOR UPDATE
+	`
+
+	var (
+		id        string
+		updatedAt time.Time
+	)
+
+	err := tx.QueryRow(ctx, selectDefault, args).Scan(&id, &updatedAt)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if change.FromID != nil {
+			return ErrPipelineConflict
+		}
+	case err != nil:
+		return fmt.Errorf("select default template: %w", err)
+	case change.FromID == nil || *change.FromID != id || !updatedAt.Equal(change.Version):
+		return ErrPipelineConflict
+	}
+
+	return migrateCandidates(ctx, tx, session, defaultJobs, args, change.Migration)
+}
+
+// CreateTemplate stores the template. A new default template comes with the
+// migration of the jobs that followed the previous default.
+func (r *pipelineRepo) CreateTemplate(ctx context.Context, session domain.Session, template *domain.PipelineTemplate, defaultChange *domain.DefaultChange) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := migrateDefault(ctx, tx, session, template.TeamID, defaultChange); err != nil {
+		return err
+	}
+
+	if template.IsDefault {
+		if err := clearDefault(ctx, tx, template.TeamID); err != nil {
+			return err
+		}
+	}
+
+	const query = `
+		INSERT INTO hiring.t_pipeline_templates (team_id, name, is_default, created_by)
+		VALUES (@team_id, @name, @is_default, @created_by)
+		RETURNING id, created_at, updated_at
+	`
+
+	if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id":    template.TeamID,
+		"name":       template.Name,
+		"is_default": template.IsDefault,
+		"created_by": session.UserID,
+	}).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrPipelineExists
+		}
+
+		return fmt.Errorf("insert pipeline template: %w", err)
+	}
+
+	if err := insertStages(ctx, tx, "template_id", template.ID, template.Stages); err != nil {
+		return err
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    template.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionPipelineChanged,
+		TargetID:  &template.ID,
+		Details: map[string]any{
+			"template_id": template.ID,
+			"name":        template.Name,
+			"stages":      template.Stages.Names(),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTemplate replaces the template's name, default flag and stages and
+// moves the candidates of every job following it as migration says, in one
+// transaction. version is the updated_at the caller computed the migration
+// from; a template changed since then is reported as ErrPipelineConflict.
+// When the default flag changes, the jobs without a template move as
+// defaultChange says instead.
+func (r *pipelineRepo) UpdateTemplate(ctx context.Context, session domain.Session, template *domain.PipelineTemplate, version time.Time, migration domain.StageMigration, defaultChange *domain.DefaultChange) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const selectTemplate = `
+		SELECT updated_at, is_default
+		FROM hiring.t_pipeline_templates
+		WHERE team_id = @team_id AND id = @id
+		FOR UPDATE
+	`
+
+	var (
+		updatedAt  time.Time
+		wasDefault bool
+	)
+
+	if err := tx.QueryRow(ctx, selectTemplate, pgx.NamedArgs{
+		"team_id": template.TeamID,
+		"id":      template.ID,
+	}).Scan(&updatedAt, &wasDefault); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPipelineNotFound
+		}
+
+		return fmt.Errorf("lock pipeline template: %w", err)
+	}
+
+	if !updatedAt.Equal(version) {
+		return ErrPipelineConflict
+	}
+
+	if wasDefault != template.IsDefault && defaultChange == nil {
+		return ErrPipelineConflict
+	}
+
+	if err := migrateDefault(ctx, tx, session, template.TeamID, defaultChange); err != nil {
+		return err
+	}
+
+	if err := migrateCandidates(ctx, tx, session, templateJobs, pgx.NamedArgs{
+		"team_id":     template.TeamID,
+		"template_id": template.ID,
+		"is_default":  wasDefault && template.IsDefault,
+	}, migration); err != nil {
+		return err
+	}
+
+	if template.IsDefault && !wasDefault {
+		if err := clearDefault(ctx, tx, template.TeamID); err != nil {
+			return err
+		}
+	}
+
+	const update = `
+		UPDATE hiring.t_pipeline_templates
+		SET name = @name, is_default = @is_default, updated_at = NOW()
+		WHERE id = @id
+		RETURNING created_at, updated_at
+	`
+
+	if err := tx.QueryRow(ctx, update, pgx.NamedArgs{
+		"id":         template.ID,
+		"name":       template.Name,
+		"is_default": template.IsDefault,
+	}).Scan(&template.CreatedAt, &template.UpdatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrPipelineExists
+		}
+
+		return fmt.Errorf("update pipeline template: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM hiring.t_pipeline_stages WHERE template_id = @id`, pgx.NamedArgs{
+		"id": template.ID,
+	}); err != nil {
+		return fmt.Errorf("delete pipeline stages: %w", err)
+	}
+
+	if err := insertStages(ctx, tx, "template_id", template.ID, template.Stages); err != nil {
+		return err
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    template.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionPipelineChanged,
+		TargetID:  &template.ID,
+		Details:   migrationDetails(map[string]any{"template_id": template.ID}, template.Stages, migration),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTemplate removes a template no job is assigned to. The default
+// template cannot be removed while it is the default.
+func (r *pipelineRepo) DeleteTemplate(ctx context.Context, session domain.Session, templateID string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const query = `
+		DELETE FROM hiring.t_pipeline_templates
+		WHERE team_id = @team_id AND id = @id
+		RETURNING is_default, name
+	`
+
+	var (
+		isDefault bool
+		name      string
+	)
+
+	if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id": session.TeamID,
+		"id":      templateID,
+	}).Scan(&isDefault, &name); err != nil {
+		var pgErr *pgconn.PgError
+
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrPipelineNotFound
+		case errors.As(err, &pgErr) && pgErr.Code == "23503":
+			return ErrPipelineInUse
+		default:
+			return fmt.Errorf("delete pipeline template: %w", err)
+		}
+	}
+
+	// Jobs without a template follow the default one; the deletion is rolled
+	// back until another template becomes the default.
+	if isDefault {
+		return ErrPipelineInUse
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionPipelineChanged,
+		TargetID:  &templateID,
+		Details: map[string]any{
+			"template_id": templateID,
+			"name":        name,
+			"deleted":     true,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pipelineRepo) GetJobPipeline(ctx context.Context, teamID, jobID string) (*domain.JobPipeline, error) {
+	const query = `
+		SELECT j.id, j.pipeline_template_id,
+			EXISTS (SELECT 1 FROM hiring.t_pipeline_stages s WHERE s.job_id = j.id)
+		FROM hiring.t_jobs j
+		WHERE j.team_id = @team_id AND j.id = @id
+	`
+
+	pipeline, err := resolvePipeline(ctx, r.dbClient.Pool, teamID, query, jobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+func (r *pipelineRepo) GetCandidatePipeline(ctx context.Context, teamID, candidateID string) (*domain.JobPipeline, error) {
+	const query = `
+		SELECT j.id, j.pipeline_template_id,
+			EXISTS (SELECT 1 FROM hiring.t_pipeline_stages s WHERE s.job_id = j.id)
+		FROM hiring.t_candidates c
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		WHERE j.team_id = @team_id AND c.id = @id
+	`
+
+	pipeline, err := resolvePipeline(ctx, r.dbClient.Pool, teamID, query, candidateID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+// resolvePipeline finds the job selected by query and returns its stages:
+// its own, those of its template, those of the team default template or
+// domain.DefaultStages, whichever comes first. A missing job is returned as
+// pgx.ErrNoRows.
+func resolvePipeline(ctx context.Context, q pipelineQuerier, teamID, query, id string) (*domain.JobPipeline, error) {
+	var (
+		pipeline domain.JobPipeline
+		custom   bool
+	)
+
+	if err := q.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      id,
+	}).Scan(&pipeline.JobID, &pipeline.TemplateID, &custom); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("select job pipeline: %w", err)
+	}
+
+	if custom {
+		stages, err := queryStages(ctx, q, "job_id", pipeline.JobID)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline.Source = domain.PipelineSourceJob
+		pipeline.TemplateID = nil
+		pipeline.Stages = stages
+
+		return &pipeline, nil
+	}
+
+	if pipeline.TemplateID == nil {
+		const selectDefault = `
+			SELECT id FROM hiring.t_pipeline_templates
+			WHERE team_id = @team_id AND is_default
+		`
+
+		var templateID string
+		if err := q.QueryRow(ctx, selectDefault, pgx.NamedArgs{"team_id": teamID}).Scan(&templateID); err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("select default template: %w", err)
+			}
+
+			pipeline.Source = domain.PipelineSourceBuiltin
+			pipeline.Stages = domain.DefaultStages()
+
+			return &pipeline, nil
+		}
+
+		pipeline.TemplateID = &templateID
+	}
+
+	stages, err := queryStages(ctx, q, "template_id", *pipeline.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline.Source = domain.PipelineSourceTemplate
+	pipeline.Stages = stages
+
+	return &pipeline, nil
+}
+
+// SetJobPipeline assigns the job a template, its own stages or, when both
+// are empty, the team default, and moves its candidates as the migration
+// says, in one transaction.
+func (r *pipelineRepo) SetJobPipeline(ctx context.Context, session domain.Session, change domain.JobPipelineChange) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const lockJob = `
+		SELECT 1 FROM hiring.t_jobs
+		WHERE team_id = @team_id AND id = @id
+		FOR UPDATE
+	`
+
+	var found int
+	if err := tx.QueryRow(ctx, lockJob, pgx.NamedArgs{
+		"team_id": session.TeamID,
+		"id":      change.JobID,
+	}).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("lock job: %w", err)
+	}
+
+	if change.TemplateID != nil {
+		const selectTemplate = `
+			SELECT 1 FROM hiring.t_pipeline_templates
+			WHERE team_id = @team_id AND id = @id
+			FOR SHARE
+		`
+
+		if err := tx.QueryRow(ctx, selectTemplate, pgx.NamedArgs{
+			"team_id": session.TeamID,
+			"id":      *change.TemplateID,
+		}).Scan(&found); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPipelineNotFound
+			}
+
+			return fmt.Errorf("select pipeline template: %w", err)
+		}
+	}
+
+	if err := migrateCandidates(ctx, tx, session, `SELECT @job_id::uuid AS id`, pgx.NamedArgs{
+		"job_id": change.JobID,
+	}, change.Migration); err != nil {
+		return err
+	}
+
+	const updateJob = `
+		UPDATE hiring.t_jobs
+		SET pipeline_template_id = @template_id, updated_at = NOW()
+		WHERE id = @id
+	`
+
+	if _, err := tx.Exec(ctx, updateJob, pgx.NamedArgs{
+		"id":          change.JobID,
+		"template_id": change.TemplateID,
+	}); err != nil {
+		return fmt.Errorf("update job pipeline: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM hiring.t_pipeline_stages WHERE job_id = @id`, pgx.NamedArgs{
+		"id": change.JobID,
+	}); err != nil {
+		return fmt.Errorf("delete job stages: %w", err)
+	}
+
+	if change.Stages != nil {
+		if err := insertStages(ctx, tx, "job_id", change.JobID, change.Stages); err != nil {
+			return err
+		}
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionPipelineChanged,
+		TargetID:  &change.JobID,
+		Details: migrationDetails(map[string]any{
+			"job_id":      change.JobID,
+			"template_id": change.TemplateID,
+		}, change.Stages, change.Migration),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// migrateCandidates moves the candidates of the jobs selected by jobs to
+// their new stages and records each move in hiring.t_status_history. The
+// boards of those jobs are locked in job order first, as board moves do, so
+// a migration and a drag on the same board never interleave. Moved cards
+// keep their relative order at the end of the target stage.
+func migrateCandidates(ctx context.Context, tx pgx.Tx, session domain.Session, jobs string, args pgx.NamedArgs, migration domain.StageMigration) error {
+	moves := migration.Moves()
+	if len(moves) == 0 && len(migration.Removed) == 0 {
+		return nil
+	}
+
+	lock := `
+		SELECT pg_advisory_xact_lock(hashtextextended('board:' || id::text, 0))
+		FROM (SELECT id FROM (` + jobs + `) affected ORDER BY id) ordered
+	`
+
+	if _, err := tx.Exec(ctx, lock, args); err != nil {
+		return fmt.Errorf("lock boards: %w", err)
+	}
+
+	if len(migration.Removed) > 0 {
+		query := `
+			SELECT status FROM hiring.t_candidates
+			WHERE job_id IN (` + jobs + `) AND status = ANY(@removed::text[])
+			LIMIT 1
+		`
+
+		var stage string
+		err := tx.QueryRow(ctx, query, withArgs(args, pgx.NamedArgs{"removed": migration.Removed})).Scan(&stage)
+
+		switch {
+		case err == nil:
+			return &StageInUseError{Stage: stage}
+		case !errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("check removed stages: %w", err)
+		}
+	}
+
+	if err := migrateScorecards(ctx, tx, jobs, args, migration); err != nil {
+		return err
+	}
+
+	if len(moves) == 0 {
+		return nil
+	}
+
+	from := slices.Sorted(maps.Keys(moves))
+	to := make([]string, len(from))
+
+	for i, stage := range from {
+		to[i] = moves[stage]
+	}
+
+	query := `
+		WITH moved AS (
+			SELECT c.id, c.job_id, c.status AS old_status, m.new_status,
+				ROW_NUMBER() OVER (PARTITION BY c.job_id, m.new_status ORDER BY c.kanban_position, c.id) AS n
+			FROM hiring.t_candidates c
+			JOIN unnest(@from::text[], @to::text[]) AS m(old_status, new_status) ON m.old_status = c.status
+			WHERE c.job_id IN (` + jobs + `)
+		),
+		tails AS (
+			SELECT c.job_id, c.status, MAX(c.kanban_position) AS tail
+			FROM hiring.t_candidates c
+			WHERE c.job_id IN (` + jobs + `)
+				AND c.status = ANY(@to::text[]) AND NOT c.status = ANY(@from::text[])
+			GROUP BY c.job_id, c.status
+		),
+		updated AS (
+			UPDATE hiring.t_candidates c
+			SET status = moved.new_status,
+				kanban_position = COALESCE(t.tail, 0) + moved.n * @gap,
+				updated_at = NOW()
+			FROM moved
+			LEFT JOIN tails t ON t.job_id = moved.job_id AND t.status = moved.new_status
+			WHERE c.id = moved.id
+			RETURNING c.id, moved.old_status, moved.new_status
+		)
+		INSERT INTO hiring.t_status_history (candidate_id, old_status, new_status, changed_by)
+		SELECT id, old_status, new_status, @changed_by FROM updated
+	`
+
+	if _, err := tx.Exec(ctx, query, withArgs(args, pgx.NamedArgs{
+		"from":       from,
+		"to":         to,
+		"gap":        boardGap,
+		"changed_by": session.UserID,
+	})); err != nil {
+		return fmt.Errorf("migrate candidates: %w", err)
+	}
+
+	return nil
+}
+
+func withArgs(base, extra pgx.NamedArgs) pgx.NamedArgs {
+	args := maps.Clone(base)
+	maps.Copy(args, extra)
+
+	return args
+}
+
+func migrationDetails(details map[string]any, stages domain.Stages, migration domain.StageMigration) map[string]any {
+	if stages != nil {
+		details["stages"] = stages.Names()
+	}
+
+	if len(migration.Renames) > 0 {
+		details["renamed"] = migration.Renames
+	}
+
+	if len(migration.Reassign) > 0 {
+		details["reassigned"] = migration.Reassign
+	}
+
+	return details
+}
@@ -0,0 +1,274 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrProfileConflict     = errors.New("profile corrections were changed concurrently")
+	ErrNoResumeText        = errors.New("candidate has no resume to extract a profile from")
+	ErrExtractionQueued    = errors.New("profile extraction is already in progress")
+	ErrExtractionCancelled = errors.New("resume processing is stopped while the job is closed")
+)
+
+type ProfileRepository interface {
+	GetProfile(ctx context.Context, teamID, candidateID string) (*domain.CandidateProfile, error)
+	SaveCorrections(ctx context.Context, session domain.Session, correction domain.ProfileCorrection) (*domain.CandidateProfile, error)
+	RequestExtraction(ctx context.Context, session domain.Session, candidateID string) error
+}
+
+type profileRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewProfileRepo(dbClient *db.PostgresClient) ProfileRepository {
+	return &profileRepo{dbClient: dbClient}
+}
+
+const profileQuery = `
+	SELECT c.id AS candidate_id,
+		p.structured_data, p.extracted_data,
+		COALESCE(p.corrections, '{}'::jsonb) AS corrections,
+		p.schema_version,
+		COALESCE(p.provider, '') AS provider,
+		p.extracted_at, p.corrected_at, p.corrected_by::text AS corrected_by,
+		COALESCE(pt.status::text, '') AS processing_status,
+		COALESCE(pt.error_message, '') AS processing_error
+	FROM hiring.t_candidates c
+	JOIN hiring.t_jobs j ON j.id = c.job_id
+	LEFT JOIN hiring.t_candidate_profiles p ON p.candidate_id = c.id
+	LEFT JOIN ai_engine.t_processing_tasks pt ON pt.workflow_id = 'resume:' || c.id
+	WHERE j.team_id = @team_id AND c.id = @id
+`
+
+// GetProfile returns the profile of a candidate of the team. A candidate
+// whose resume has not been processed yet has an empty profile.
+func (r *profileRepo) GetProfile(ctx context.Context, teamID, candidateID string) (*domain.CandidateProfile, error) {
+	return queryProfile(ctx, r.dbClient.Pool, teamID, candidateID)
+}
+
+type profileQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func queryProfile(ctx context.Context, q profileQuerier, teamID, candidateID string) (*domain.CandidateProfile, error) {
+	rows, err := q.Query(ctx, profileQuery, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      candidateID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query profile: %w", err)
+	}
+
+	profile, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.CandidateProfile])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("scan profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// SaveCorrections replaces the corrections of a profile if they still equal
+// correction.Previous, so that concurrent edits are not lost; otherwise it
+// returns ErrProfileConflict. The database derives the corrected
+// structured_data. A candidate without a profile gets one that consists of
+// the corrections only.
+func (r *profileRepo) SaveCorrections(ctx context.Context, session domain.Session, correction domain.ProfileCorrection) (*domain.CandidateProfile, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	jobID, err := lockCandidate(ctx, tx, session.TeamID, correction.CandidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	const upsert = `
+		INSERT INTO hiring.t_candidate_profiles AS p (candidate_id, corrections, corrected_at, corrected_by, updated_at)
+		SELECT @id, @corrections, NOW(), @user_id, NOW()
+		WHERE @previous::jsonb = '{}'::jsonb
+		ON CONFLICT (candidate_id) DO UPDATE
+		SET corrections = EXCLUDED.corrections,
+			corrected_at = EXCLUDED.corrected_at,
+			corrected_by = EXCLUDED.corrected_by,
+			updated_at = EXCLUDED.updated_at
+		WHERE p.corrections = @previous::jsonb
+	`
+
+	const update = `
+		UPDATE hiring.t_candidate_profiles
+		SET corrections = @corrections,
+			corrected_at = NOW(),
+			corrected_by = @user_id,
+			updated_at = NOW()
+		WHERE candidate_id = @id AND corrections = @previous::jsonb
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":     session.UserID,
+		"id":          correction.CandidateID,
+		"previous":    string(correction.Previous),
+		"corrections": correction.Corrections,
+	}
+
+	// Without previous corrections the profile row may not exist yet.
+	query := update
+	if isEmptyPatch(correction.Previous) {
+		query = upsert
+	}
+
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("save corrections: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, ErrProfileConflict
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionProfileCorrected,
+		TargetID:  &correction.CandidateID,
+		Details: map[string]any{
+			"job_id": jobID,
+			"fields": correction.Fields,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	profile, err := queryProfile(ctx, tx, session.TeamID, correction.CandidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return profile, nil
+}
+
+// RequestExtraction queues the resume task of a candidate again. With
+// resume text the task goes straight to the profile stage, otherwise the text
+// is extracted first. Only finished tasks are queued; corrections are kept.
+func (r *profileRepo) RequestExtraction(ctx context.Context, session domain.Session, candidateID string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	jobID, err := lockCandidate(ctx, tx, session.TeamID, candidateID)
+	if err != nil {
+		return err
+	}
+
+	const queue = `
+		INSERT INTO ai_engine.t_processing_tasks AS t (workflow_id, entity_id, status, progress_percent, updated_at)
+		SELECT @workflow_id, c.id,
+			(CASE WHEN COALESCE(c.parsed_text, '') <> '' THEN 'analyzing' ELSE 'pending' END)::task_status,
+			CASE WHEN COALESCE(c.parsed_text, '') <> '' THEN 50 ELSE 0 END,
+			NOW()
+		FROM hiring.t_candidates c
+		WHERE c.id = @id AND (COALESCE(c.parsed_text, '') <> '' OR c.resume_file_key IS NOT NULL)
+		ON CONFLICT (workflow_id) DO UPDATE
+		SET status = EXCLUDED.status,
+			progress_percent = EXCLUDED.progress_percent,
+			attempts = 0,
+			locked_until = NULL,
+			error_message = NULL,
+			updated_at = NOW()
+		WHERE t.status IN ('completed', 'failed')
+	`
+
+	tag, err := tx.Exec(ctx, queue, pgx.NamedArgs{
+		"workflow_id": domain.ResumeWorkflowID(candidateID),
+		"id":          candidateID,
+	})
+	if err != nil {
+		return fmt.Errorf("queue task: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		profile, err := queryProfile(ctx, tx, session.TeamID, candidateID)
+		if err != nil {
+			return err
+		}
+
+		switch profile.ProcessingStatus {
+		case domain.TaskCancelled:
+			return ErrExtractionCancelled
+		case domain.TaskPending, domain.TaskExtracting, domain.TaskAnalyzing:
+			return ErrExtractionQueued
+		default:
+			return ErrNoResumeText
+		}
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionProfileExtractionRequested,
+		TargetID:  &candidateID,
+		Details:   map[string]any{"job_id": jobID},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// lockCandidate checks that the candidate belongs to the team, keeps it from
+// being deleted until the transaction ends and returns its job.
+func lockCandidate(ctx context.Context, tx pgx.Tx, teamID, candidateID string) (string, error) {
+	const query = `
+		SELECT c.job_id
+		FROM hiring.t_candidates c
+		JOIN hiring.t_jobs j ON j.id = c.job_id
+		WHERE j.team_id = @team_id AND c.id = @id
+		FOR SHARE OF c
+	`
+
+	var jobID string
+	if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      candidateID,
+	}).Scan(&jobID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrCandidateNotFound
+		}
+
+		return "", fmt.Errorf("select candidate: %w", err)
+	}
+
+	return jobID, nil
+}
+
+// isEmptyPatch reports whether a stored merge patch changes nothing.
+func isEmptyPatch(patch []byte) bool {
+	trimmed := strings.TrimSpace(string(patch))
+
+	return trimmed == "" || trimmed == "{}"
+}
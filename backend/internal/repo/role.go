@@ -0,0 +1,202 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrMemberNotFound = errors.New("team member not found")
+)
+
+type RoleRepository interface {
+	ListRoles(ctx context.Context, teamID string) ([]domain.Role, error)
+	GetRole(ctx context.Context, teamID, roleID string) (*domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role, apply func() error) error
+	UpdateRole(ctx context.Context, role *domain.Role, apply func() error) error
+	DeleteRole(ctx context.Context, teamID, roleID string) error
+	CheckMember(ctx context.Context, teamID, userID string) error
+}
+
+type roleRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewRoleRepo(dbClient *db.PostgresClient) RoleRepository {
+	return &roleRepo{dbClient: dbClient}
+}
+
+const roleColumns = `
+	id, team_id, name, COALESCE(description, '') AS description,
+	permissions, created_at, updated_at
+`
+
+func (r *roleRepo) ListRoles(ctx context.Context, teamID string) ([]domain.Role, error) {
+	query := `
+		SELECT ` + roleColumns + `
+		FROM auth.t_roles
+		WHERE team_id = @team_id
+		ORDER BY name
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{"team_id": teamID})
+	if err != nil {
+		return nil, fmt.Errorf("query roles: %w", err)
+	}
+
+	roles, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Role])
+	if err != nil {
+		return nil, fmt.Errorf("scan roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepo) GetRole(ctx context.Context, teamID, roleID string) (*domain.Role, error) {
+	query := `
+		SELECT ` + roleColumns + `
+		FROM auth.t_roles
+		WHERE team_id = @team_id AND id = @id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      roleID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query role: %w", err)
+	}
+
+	role, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Role])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoleNotFound
+		}
+
+		return nil, fmt.Errorf("scan role: %w", err)
+	}
+
+	return &role, nil
+}
+
+// CreateRole inserts a role and calls apply before committing, so that the
+// role is only stored once its policies are in place. An error from apply
+// rolls the insert back.
+func (r *roleRepo) CreateRole(ctx context.Context, role *domain.Role, apply func() error) error {
+	const query = `
+		INSERT INTO auth.t_roles (team_id, name, description, permissions)
+		VALUES (@team_id, @name, @description, @permissions)
+		RETURNING id, created_at, updated_at
+	`
+
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id":     role.TeamID,
+		"name":        role.Name,
+		"description": role.Description,
+		"permissions": role.Permissions,
+	}).Scan(&role.ID, &role.CreatedAt, &role.UpdatedAt); err != nil {
+		return fmt.Errorf("insert role: %w", err)
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRole updates a role and, like CreateRole, calls apply before
+// committing.
+func (r *roleRepo) UpdateRole(ctx context.Context, role *domain.Role, apply func() error) error {
+	const query = `
+		UPDATE auth.t_roles
+		SET name = @name,
+			description = @description,
+			permissions = @permissions,
+			updated_at = NOW()
+		WHERE team_id = @team_id AND id = @id
+		RETURNING created_at, updated_at
+	`
+
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := tx.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id":     role.TeamID,
+		"id":          role.ID,
+		"name":        role.Name,
+		"description": role.Description,
+		"permissions": role.Permissions,
+	}).Scan(&role.CreatedAt, &role.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRoleNotFound
+		}
+
+		return fmt.Errorf("update role: %w", err)
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepo) DeleteRole(ctx context.Context, teamID, roleID string) error {
+	const query = `DELETE FROM auth.t_roles WHERE team_id = @team_id AND id = @id`
+
+	tag, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      roleID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete role: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrRoleNotFound
+	}
+
+	return nil
+}
+
+func (r *roleRepo) CheckMember(ctx context.Context, teamID, userID string) error {
+	const query = `SELECT EXISTS (SELECT 1 FROM auth.t_users WHERE team_id = @team_id AND id = @id)`
+
+	var exists bool
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"id":      userID,
+	}).Scan(&exists); err != nil {
+		return fmt.Errorf("check member: %w", err)
+	}
+
+	if !exists {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,522 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrScorecardTemplateNotFound = errors.New("scorecard template not found")
+
+type ScorecardRepository interface {
+	ListTemplates(ctx context.Context, teamID, jobID string) ([]domain.ScorecardTemplate, error)
+	SaveTemplate(ctx context.Context, session domain.Session, template *domain.ScorecardTemplate) error
+	DeleteTemplate(ctx context.Context, session domain.Session, jobID, stage string) error
+	GetCandidateTemplate(ctx context.Context, scope domain.JobScope, candidateID, stage string) (*domain.ScorecardTemplate, error)
+	SubmitScorecard(ctx context.Context, scope domain.JobScope, card *domain.Scorecard) (*domain.Scorecard, error)
+	ListScorecards(ctx context.Context, scope domain.JobScope, candidateID, stage string) ([]domain.Scorecard, map[string]int, error)
+	CompareScorecards(ctx context.Context, scope domain.JobScope, jobID, stage string) ([]domain.ComparedCandidate, map[string]int, []domain.Scorecard, error)
+}
+
+type scorecardRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewScorecardRepo(dbClient *db.PostgresClient) ScorecardRepository {
+	return &scorecardRepo{dbClient: dbClient}
+}
+
+const scorecardTemplateColumns = `t.id, t.job_id, t.stage, t.blind, t.criteria, t.updated_by, t.created_at, t.updated_at`
+
+const scorecardColumns = `
+	s.id, s.candidate_id, c.job_id, s.stage, s.interviewer_id,
+	COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS interviewer_name,
+	s.criteria, s.ratings, s.recommendation, COALESCE(s.summary, '') AS summary, s.score,
+	s.submitted_at, s.updated_at
+`
+
+const scorecardFrom = `
+	FROM hiring.t_scorecards s
+	JOIN hiring.t_candidates c ON c.id = s.candidate_id
+	LEFT JOIN auth.t_users u ON u.id = s.interviewer_id
+`
+
+// scorecardVisible holds for the scorecards @user_id may read: their own,
+// those of stages without blind mode, those of stages in which they have
+// evaluated the candidate themselves, and all of them for decision makers of
+// the job.
+const scorecardVisible = `(
+	s.interviewer_id = @user_id
+	OR NOT COALESCE((
+		SELECT t.blind FROM hiring.t_scorecard_templates t WHERE t.job_id = c.job_id AND t.stage = s.stage
+	), FALSE)
+	OR EXISTS (
+		SELECT 1 FROM hiring.t_scorecards own
+		WHERE own.candidate_id = s.candidate_id AND own.stage = s.stage AND own.interviewer_id = @user_id
+	)
+	OR EXISTS (
+		SELECT 1 FROM hiring.t_job_access a
+		WHERE a.job_id = c.job_id AND a.user_id = @user_id AND 'decision_maker' = ANY(a.roles)
+	)
+)`
+
+// ListTemplates returns the scorecard templates of a job by stage name.
+func (r *scorecardRepo) ListTemplates(ctx context.Context, teamID, jobID string) ([]domain.ScorecardTemplate, error) {
+	query := `
+		SELECT ` + scorecardTemplateColumns + `
+		FROM hiring.t_scorecard_templates t
+		JOIN hiring.t_jobs j ON j.id = t.job_id
+		WHERE j.team_id = @team_id AND t.job_id = @job_id
+		ORDER BY t.stage
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"team_id": teamID,
+		"job_id":  jobID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query scorecard templates: %w", err)
+	}
+
+	templates, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.ScorecardTemplate])
+	if err != nil {
+		return nil, fmt.Errorf("scan scorecard templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// SaveTemplate creates or replaces the template of a stage of the job.
+// Scorecards already submitted keep the criteria they were rated on.
+func (r *scorecardRepo) SaveTemplate(ctx context.Context, session domain.Session, template *domain.ScorecardTemplate) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const selectJob = `
+		SELECT pg_advisory_xact_lock(hashtextextended('scorecards:' || id::text, 0))
+		FROM hiring.t_jobs
+		WHERE team_id = @team_id AND id = @id
+	`
+
+	tag, err := tx.Exec(ctx, selectJob, pgx.NamedArgs{"team_id": session.TeamID, "id": template.JobID})
+	if err != nil {
+		return fmt.Errorf("lock job: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrJobNotFound
+	}
+
+	args := pgx.NamedArgs{
+		"job_id":     template.JobID,
+		"stage":      template.Stage,
+		"blind":      template.Blind,
+		"criteria":   template.Criteria,
+		"updated_by": session.UserID,
+	}
+
+	const update = `
+		UPDATE hiring.t_scorecard_templates
+		SET blind = @blind, criteria = @criteria, updated_by = @updated_by, updated_at = NOW()
+		WHERE job_id = @job_id AND stage = @stage
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, update, args).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		const insert = `
+			INSERT INTO hiring.t_scorecard_templates (job_id, stage, blind, criteria, updated_by)
+			VALUES (@job_id, @stage, @blind, @criteria, @updated_by)
+			RETURNING id, created_at, updated_at
+		`
+
+		err = tx.QueryRow(ctx, insert, args).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	}
+
+	if err != nil {
+		return fmt.Errorf("save scorecard template: %w", err)
+	}
+
+	template.UpdatedBy = &session.UserID
+
+	keys := make([]string, len(template.Criteria))
+	for i, criterion := range template.Criteria {
+		keys[i] = criterion.Key
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionScorecardTemplateChanged,
+		TargetID:  &template.JobID,
+		Details: map[string]any{
+			"stage":    template.Stage,
+			"blind":    template.Blind,
+			"criteria": keys,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTemplate removes the template of a stage. Submitted scorecards stay;
+// without a template the stage is no longer blind.
+func (r *scorecardRepo) DeleteTemplate(ctx context.Context, session domain.Session, jobID, stage string) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const query = `
+		DELETE FROM hiring.t_scorecard_templates t
+		USING hiring.t_jobs j
+		WHERE j.id = t.job_id AND j.team_id = @team_id AND t.job_id = @job_id AND t.stage = @stage
+	`
+
+	tag, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"team_id": session.TeamID,
+		"job_id":  jobID,
+		"stage":   stage,
+	})
+	if err != nil {
+		return fmt.Errorf("delete scorecard template: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrScorecardTemplateNotFound
+	}
+
+	if err := logActivity(ctx, tx, &domain.Activity{
+		TeamID:    session.TeamID,
+		ActorType: domain.ActorUser,
+		ActorID:   &session.UserID,
+		Action:    domain.ActionScorecardTemplateChanged,
+		TargetID:  &jobID,
+		Details: map[string]any{
+			"stage":   stage,
+			"deleted": true,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// GetCandidateTemplate returns the template of a stage of the candidate's
+// job; an empty stage means the stage the candidate is in.
+func (r *scorecardRepo) GetCandidateTemplate(ctx context.Context, scope domain.JobScope, candidateID, stage string) (*domain.ScorecardTemplate, error) {
+	args := pgx.NamedArgs{"id": candidateID, "stage": stage}
+
+	selectCandidate := `SELECT 1 FROM hiring.t_candidates c WHERE c.id = @id AND ` + jobScopeCondition("c.job_id", scope, args)
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, selectCandidate, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("select candidate: %w", err)
+	}
+
+	query := `
+		SELECT ` + scorecardTemplateColumns + `
+		FROM hiring.t_candidates c
+		JOIN hiring.t_scorecard_templates t
+			ON t.job_id = c.job_id AND t.stage = COALESCE(NULLIF(@stage, ''), c.status)
+		WHERE c.id = @id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query scorecard template: %w", err)
+	}
+
+	template, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.ScorecardTemplate])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScorecardTemplateNotFound
+		}
+
+		return nil, fmt.Errorf("scan scorecard template: %w", err)
+	}
+
+	return &template, nil
+}
+
+// SubmitScorecard stores the caller's scorecard of the candidate in
+// card.Stage, replacing an earlier one, and returns it as stored.
+func (r *scorecardRepo) SubmitScorecard(ctx context.Context, scope domain.JobScope, card *domain.Scorecard) (*domain.Scorecard, error) {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	args := pgx.NamedArgs{
+		"candidate_id":   card.CandidateID,
+		"stage":          card.Stage,
+		"interviewer_id": scope.UserID,
+		"criteria":       card.Criteria,
+		"ratings":        card.Ratings,
+		"recommendation": card.Recommendation,
+		"summary":        card.Summary,
+		"score":          card.Score,
+	}
+
+	lock := `
+		SELECT pg_advisory_xact_lock(hashtextextended('scorecard:' || c.id::text || ':' || @interviewer_id, 0))
+		FROM hiring.t_candidates c
+		WHERE c.id = @candidate_id AND ` + jobScopeCondition("c.job_id", scope, args)
+
+	tag, err := tx.Exec(ctx, lock, args)
+	if err != nil {
+		return nil, fmt.Errorf("lock candidate: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, ErrCandidateNotFound
+	}
+
+	const update = `
+		UPDATE hiring.t_scorecards
+		SET criteria = @criteria, ratings = @ratings, recommendation = @recommendation,
+			summary = NULLIF(@summary, ''), score = @score, updated_at = NOW()
+		WHERE candidate_id = @candidate_id AND stage = @stage AND interviewer_id = @interviewer_id
+		RETURNING id
+	`
+
+	var id string
+
+	err = tx.QueryRow(ctx, update, args).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		const insert = `
+			INSERT INTO hiring.t_scorecards
+				(candidate_id, stage, interviewer_id, criteria, ratings, recommendation, summary, score)
+			VALUES (@candidate_id, @stage, @interviewer_id, @criteria, @ratings, @recommendation, NULLIF(@summary, ''), @score)
+			RETURNING id
+		`
+
+		err = tx.QueryRow(ctx, insert, args).Scan(&id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("save scorecard: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT `+scorecardColumns+scorecardFrom+` WHERE s.id = @id`, pgx.NamedArgs{"id": id})
+	if err != nil {
+		return nil, fmt.Errorf("query scorecard: %w", err)
+	}
+
+	saved, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Scorecard])
+	if err != nil {
+		return nil, fmt.Errorf("scan scorecard: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// ListScorecards returns the scorecards of a candidate the caller may read,
+// newest first, and the number of hidden ones per stage. An empty stage
+// lists every stage.
+func (r *scorecardRepo) ListScorecards(ctx context.Context, scope domain.JobScope, candidateID, stage string) ([]domain.Scorecard, map[string]int, error) {
+	args := pgx.NamedArgs{"candidate_id": candidateID, "user_id": scope.UserID}
+
+	where := ` WHERE s.candidate_id = @candidate_id AND ` + jobScopeCondition("c.job_id", scope, args)
+	if stage != "" {
+		where += ` AND s.stage = @stage`
+		args["stage"] = stage
+	}
+
+	query := `SELECT ` + scorecardColumns + scorecardFrom + where + ` AND ` + scorecardVisible + `
+		ORDER BY s.submitted_at DESC, s.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query scorecards: %w", err)
+	}
+
+	cards, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Scorecard])
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan scorecards: %w", err)
+	}
+
+	hiddenQuery := `SELECT s.stage, COUNT(*)` + scorecardFrom + where + ` AND NOT ` + scorecardVisible + `
+		GROUP BY s.stage
+	`
+
+	rows, err = r.dbClient.Pool.Query(ctx, hiddenQuery, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query hidden scorecards: %w", err)
+	}
+
+	hidden := make(map[string]int)
+
+	var (
+		hiddenStage string
+		count       int
+	)
+
+	if _, err := pgx.ForEachRow(rows, []any{&hiddenStage, &count}, func() error {
+		hidden[hiddenStage] = count
+		return nil
+	}); err != nil {
+		return nil, nil, fmt.Errorf("scan hidden scorecards: %w", err)
+	}
+
+	return cards, hidden, nil
+}
+
+// CompareScorecards returns the evaluated candidates of a job within scope,
+// the number of their scorecards hidden from the caller by candidate id, and
+// the scorecards the caller may read. A stage limits all of them to that
+// stage.
+func (r *scorecardRepo) CompareScorecards(ctx context.Context, scope domain.JobScope, jobID, stage string) ([]domain.ComparedCandidate, map[string]int, []domain.Scorecard, error) {
+	args := pgx.NamedArgs{"job_id": jobID, "user_id": scope.UserID}
+
+	where := ` WHERE c.job_id = @job_id AND ` + jobScopeCondition("c.job_id", scope, args)
+	if stage != "" {
+		where += ` AND s.stage = @stage`
+		args["stage"] = stage
+	}
+
+	candidatesQuery := `
+		SELECT c.id AS candidate_id,
+			COALESCE(c.first_name, '') AS first_name,
+			COALESCE(c.last_name, '') AS last_name,
+			COALESCE(c.status, '') AS status,
+			cs.match_score,
+			SUM(CASE WHEN ` + scorecardVisible + ` THEN 0 ELSE 1 END)::int AS hidden
+		FROM hiring.t_scorecards s
+		JOIN hiring.t_candidates c ON c.id = s.candidate_id
+		LEFT JOIN ai_engine.t_candidate_scores cs ON cs.candidate_id = c.id
+	` + where + `
+		GROUP BY c.id, cs.match_score
+		ORDER BY c.last_name, c.first_name, c.id
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, candidatesQuery, args)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("query compared candidates: %w", err)
+	}
+
+	type comparedRow struct {
+		domain.ComparedCandidate
+		Hidden int `db:"hidden"`
+	}
+
+	compared, err := pgx.CollectRows(rows, pgx.RowToStructByName[comparedRow])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("scan compared candidates: %w", err)
+	}
+
+	candidates := make([]domain.ComparedCandidate, len(compared))
+	hidden := make(map[string]int, len(compared))
+
+	for i, row := range compared {
+		candidates[i] = row.ComparedCandidate
+		hidden[row.CandidateID] = row.Hidden
+	}
+
+	query := `SELECT ` + scorecardColumns + scorecardFrom + where + ` AND ` + scorecardVisible + `
+		ORDER BY s.submitted_at, s.id
+	`
+
+	rows, err = r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("query scorecards: %w", err)
+	}
+
+	cards, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Scorecard])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("scan scorecards: %w", err)
+	}
+
+	return candidates, hidden, cards, nil
+}
+
+// migrateScorecards makes the scorecard templates and scorecards of the jobs
+// selected by jobs follow a pipeline change. Templates of removed stages are
+// deleted and renamed stages keep their templates and scorecards. Scorecards
+// of removed stages stay as they were. A renamed scorecard that would collide
+// with one an interviewer already has under the new name is left alone.
+func migrateScorecards(ctx context.Context, tx pgx.Tx, jobs string, args pgx.NamedArgs, migration domain.StageMigration) error {
+	removed := slices.Concat(migration.Removed, slices.Collect(maps.Keys(migration.Reassign)))
+
+	if len(removed) > 0 {
+		query := `DELETE FROM hiring.t_scorecard_templates WHERE job_id IN (` + jobs + `) AND stage = ANY(@removed::text[])`
+
+		if _, err := tx.Exec(ctx, query, withArgs(args, pgx.NamedArgs{"removed": removed})); err != nil {
+			return fmt.Errorf("delete scorecard templates: %w", err)
+		}
+	}
+
+	if len(migration.Renames) == 0 {
+		return nil
+	}
+
+	from := slices.Sorted(maps.Keys(migration.Renames))
+	to := make([]string, len(from))
+
+	for i, stage := range from {
+		to[i] = migration.Renames[stage]
+	}
+
+	renames := withArgs(args, pgx.NamedArgs{"from": from, "to": to})
+
+	templates := `
+		UPDATE hiring.t_scorecard_templates t
+		SET stage = m.new_stage, updated_at = NOW()
+		FROM unnest(@from::text[], @to::text[]) AS m(old_stage, new_stage)
+		WHERE t.stage = m.old_stage AND t.job_id IN (` + jobs + `)
+	`
+
+	if _, err := tx.Exec(ctx, templates, renames); err != nil {
+		return fmt.Errorf("rename scorecard templates: %w", err)
+	}
+
+	scorecards := `
+		UPDATE hiring.t_scorecards s
+		SET stage = m.new_stage
+		FROM unnest(@from::text[], @to::text[]) AS m(old_stage, new_stage), hiring.t_candidates c
+		WHERE c.id = s.candidate_id AND s.stage = m.old_stage AND c.job_id IN (` + jobs + `)
+			AND NOT EXISTS (
+				SELECT 1 FROM hiring.t_scorecards o
+				WHERE o.candidate_id = s.candidate_id AND o.interviewer_id = s.interviewer_id
+					AND o.stage = m.new_stage AND NOT o.stage = ANY(@from::text[])
+			)
+	`
+
+	if _, err := tx.Exec(ctx, scorecards, renames); err != nil {
+		return fmt.Errorf("rename scorecards: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SearchRepository interface {
+	SearchCandidates(ctx context.Context, search domain.CandidateSearch) ([]domain.SearchHit, error)
+	CandidateFacets(ctx context.Context, search domain.CandidateSearch) (map[string][]domain.FacetValue, error)
+}
+
+type searchRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewSearchRepo(dbClient *db.PostgresClient) SearchRepository {
+	return &searchRepo{dbClient: dbClient}
+}
+
+// maxLocationFacets caps the location facet to its most frequent values.
+const maxLocationFacets = 20
+
+const searchFrom = `
+	FROM hiring.t_candidate_search s
+	JOIN hiring.t_candidates c ON c.id = s.candidate_id
+	JOIN hiring.t_jobs j ON j.id = c.job_id
+	LEFT JOIN ai_engine.t_candidate_scores sc ON sc.candidate_id = c.id
+`
+
+// scoreBandColumn maps a score to one of domain.ScoreBands.
+const scoreBandColumn = `
+	CASE
+		WHEN sc.match_score IS NULL THEN 'unscored'
+		WHEN sc.match_score >= 80 THEN '80-100'
+		WHEN sc.match_score >= 60 THEN '60-79'
+		WHEN sc.match_score >= 40 THEN '40-59'
+		ELSE '0-39'
+	END
+`
+
+// headlineOptions configures ts_headline. The markers are HTML-escaped with
+// the rest of the excerpt by the use case and restored afterwards.
+const headlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=25, MinWords=8, FragmentDelimiter=" … "`
+
+// searchCondition matches the query within the scope of search.
+func searchCondition(search domain.CandidateSearch, args pgx.NamedArgs) string {
+	args["team_id"] = search.Scope.TeamID
+	args["query"] = search.Query
+
+	return ` WHERE j.team_id = @team_id AND ` + jobScopeCondition("c.job_id", search.Scope, args) + `
+		AND s.document @@ hiring.f_search_query(@query)`
+}
+
+// facetCondition applies the facet filters of search.
+func facetCondition(search domain.CandidateSearch, args pgx.NamedArgs) string {
+	var where string
+
+	if len(search.JobIDs) > 0 {
+		where += ` AND c.job_id = ANY(@job_ids::uuid[])`
+		args["job_ids"] = search.JobIDs
+	}
+
+	if len(search.Stages) > 0 {
+		where += ` AND c.status = ANY(@stages::text[])`
+		args["stages"] = search.Stages
+	}
+
+	if len(search.ScoreBands) > 0 {
+		where += ` AND ` + scoreBandColumn + ` = ANY(@score_bands::text[])`
+		args["score_bands"] = search.ScoreBands
+	}
+
+	if len(search.Locations) > 0 {
+		locations := make([]string, len(search.Locations))
+		for i, location := range search.Locations {
+			locations[i] = strings.ToLower(strings.TrimSpace(location))
+		}
+
+		where += ` AND LOWER(s.location) = ANY(@locations::text[])`
+		args["locations"] = locations
+	}
+
+	if len(search.WorkFormats) > 0 {
+		where += ` AND j.work_format::text = ANY(@work_formats::text[])`
+		args["work_formats"] = search.WorkFormats
+	}
+
+	return where
+}
+
+// SearchCandidates returns up to search.Limit+1 hits after search.Cursor,
+// best ranked first, with a headline of the resume text or, without one, of
+// the profile summary. The extra row tells the caller whether another page
+// exists.
+func (r *searchRepo) SearchCandidates(ctx context.Context, search domain.CandidateSearch) ([]domain.SearchHit, error) {
+	args := pgx.NamedArgs{}
+
+	where := searchCondition(search, args) + facetCondition(search, args)
+
+	var after string
+	if search.Cursor != nil {
+		after = ` WHERE (rank, id) < (@cursor_rank::real, @cursor_id::uuid)`
+		args["cursor_rank"] = search.Cursor.Rank
+		args["cursor_id"] = search.Cursor.ID
+	}
+
+	args["limit"] = search.Limit + 1
+	args["headline_options"] = headlineOptions
+
+	query := `
+		WITH hits AS (
+			SELECT ` + candidateColumns + `,
+				j.title AS job_title,
+				COALESCE(j.work_format::text, '') AS work_format,
+				COALESCE(s.location, '') AS location,
+				sc.match_score,
+				ts_rank_cd(s.document, hiring.f_search_query(@query)) AS rank,
+				s.config,
+				LEFT(COALESCE(c.parsed_text, p.structured_data ->> 'summary', ''), 50000) AS text
+			` + searchFrom + `
+			LEFT JOIN hiring.t_candidate_profiles p ON p.candidate_id = c.id
+			` + where + `
+		),
+		page AS (
+			SELECT * FROM hits` + after + `
+			ORDER BY rank DESC, id DESC
+			LIMIT @limit
+		)
+		SELECT id, job_id, first_name, last_name, email, phone, resume_file_key, resume_file_name,
+			status, kanban_position, source, created_at, updated_at,
+			job_title, work_format, location, match_score, rank,
+			ts_headline(config, text, hiring.f_search_query(@query), @headline_options) AS headline
+		FROM page
+		ORDER BY rank DESC, id DESC
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("search candidates: %w", err)
+	}
+
+	hits, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.SearchHit])
+	if err != nil {
+		return nil, fmt.Errorf("scan search hits: %w", err)
+	}
+
+	return hits, nil
+}
+
+type facetRow struct {
+	Facet string `db:"facet"`
+	domain.FacetValue
+}
+
+// CandidateFacets counts all matches of search.Query within scope by job,
+// stage, score band, location and work format. The facet filters are not
+// applied, so every value stays selectable.
+func (r *searchRepo) CandidateFacets(ctx context.Context, search domain.CandidateSearch) (map[string][]domain.FacetValue, error) {
+	args := pgx.NamedArgs{"max_locations": maxLocationFacets}
+
+	query := `
+		WITH matches AS (
+			SELECT c.job_id, j.title, c.status, s.location,
+				j.work_format::text AS work_format,
+				` + scoreBandColumn + ` AS score_band
+			` + searchFrom + searchCondition(search, args) + `
+		)
+		SELECT 'job' AS facet, job_id::text AS value, MIN(title) AS label, COUNT(*) AS count
+		FROM matches GROUP BY job_id
+		UNION ALL
+		SELECT 'stage', status, '', COUNT(*)
+		FROM matches WHERE status IS NOT NULL GROUP BY status
+		UNION ALL
+		SELECT 'score_band', score_band, '', COUNT(*)
+		FROM matches GROUP BY score_band
+		UNION ALL
+		(
+			SELECT 'location', MIN(location), '', COUNT(*)
+			FROM matches WHERE location IS NOT NULL GROUP BY LOWER(location)
+			ORDER BY COUNT(*) DESC LIMIT @max_locations
+		)
+		UNION ALL
+		SELECT 'work_format', work_format, '', COUNT(*)
+		FROM matches WHERE work_format IS NOT NULL GROUP BY work_format
+		ORDER BY facet, count DESC, value
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("query facets: %w", err)
+	}
+
+	facetRows, err := pgx.CollectRows(rows, pgx.RowToStructByName[facetRow])
+	if err != nil {
+		return nil, fmt.Errorf("scan facets: %w", err)
+	}
+
+	facets := map[string][]domain.FacetValue{
+		domain.FacetJob:        {},
+		domain.FacetStage:      {},
+		domain.FacetScoreBand:  {},
+		domain.FacetLocation:   {},
+		domain.FacetWorkFormat: {},
+	}
+
+	for _, row := range facetRows {
+		facets[row.Facet] = append(facets[row.Facet], row.FacetValue)
+	}
+
+	return facets, nil
+}
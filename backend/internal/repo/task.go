@@ -0,0 +1,334 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrNoTask = errors.New("no task to process")
+
+type TaskRepository interface {
+	ClaimResumeTask(ctx context.Context, lease time.Duration) (*domain.ResumeTask, error)
+	CompleteExtraction(ctx context.Context, task *domain.ResumeTask, result *domain.ResumeText) error
+	CompleteProfile(ctx context.Context, task *domain.ResumeTask, profile *domain.ExtractedProfile) error
+	FailTask(ctx context.Context, taskID, message string, retry bool) error
+	TouchTask(ctx context.Context, taskID string, progress int, lease time.Duration) error
+}
+
+type taskRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewTaskRepo(dbClient *db.PostgresClient) TaskRepository {
+	return &taskRepo{dbClient: dbClient}
+}
+
+// ClaimResumeTask leases the oldest resume task that waits for a stage: a
+// pending task moves to extracting, an analyzing one keeps its status. Tasks
+// whose lease expired belong to a worker that died and are claimed again.
+// SKIP LOCKED lets several workers poll the queue concurrently.
+func (r *taskRepo) ClaimResumeTask(ctx context.Context, lease time.Duration) (*domain.ResumeTask, error) {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks t
+		SET status = (CASE WHEN t.status = 'pending' THEN 'extracting' ELSE t.status::text END)::task_status,
+			attempts = t.attempts + 1,
+			progress_percent = CASE WHEN t.status = 'analyzing' THEN 60 ELSE 10 END,
+			locked_until = NOW() + make_interval(secs => @lease),
+			updated_at = NOW()
+		FROM hiring.t_candidates c
+		WHERE c.id = t.entity_id
+		  AND t.id = (
+			SELECT id FROM ai_engine.t_processing_tasks
+			WHERE workflow_id LIKE @prefix
+			  AND status IN ('pending', 'extracting', 'analyzing')
+			  AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY updated_at NULLS FIRST
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING t.id AS task_id, c.id AS candidate_id, t.status::text AS stage,
+			COALESCE(c.resume_file_key, '') AS file_key,
+			COALESCE(c.resume_file_name, '') AS file_name,
+			CASE WHEN t.status = 'analyzing' THEN COALESCE(c.parsed_text, '') ELSE '' END AS parsed_text,
+			t.attempts
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{
+		"prefix": domain.WorkflowResume + ":%",
+		"lease":  lease.Seconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim task: %w", err)
+	}
+
+	task, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.ResumeTask])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoTask
+		}
+
+		return nil, fmt.Errorf("scan task: %w", err)
+	}
+
+	return &task, nil
+}
+
+// CompleteExtraction stores the resume text on the candidate, replaces its
+// OCR page results and hands the task over to the profile stage with a fresh
+// attempt counter. A task cancelled meanwhile keeps its status.
+func (r *taskRepo) CompleteExtraction(ctx context.Context, task *domain.ResumeTask, result *domain.ResumeText) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const updateCandidate = `
+		UPDATE hiring.t_candidates
+		SET parsed_text = @text,
+			parsed_language = NULLIF(@language, ''),
+			parsed_at = NOW(),
+			parsed_by_ocr = @ocr,
+			ocr_confidence = @ocr_confidence,
+			updated_at = NOW()
+		WHERE id = @id
+	`
+
+	if _, err := tx.Exec(ctx, updateCandidate, pgx.NamedArgs{
+		"id":             task.CandidateID,
+		"text":           result.Text,
+		"language":       result.Language,
+		"ocr":            len(result.OCRPages) > 0,
+		"ocr_confidence": result.OCRConfidence(),
+	}); err != nil {
+		return fmt.Errorf("update candidate: %w", err)
+	}
+
+	const deletePages = `DELETE FROM ai_engine.t_ocr_pages WHERE candidate_id = @id`
+
+	if _, err := tx.Exec(ctx, deletePages, pgx.NamedArgs{"id": task.CandidateID}); err != nil {
+		return fmt.Errorf("delete ocr pages: %w", err)
+	}
+
+	if len(result.OCRPages) > 0 {
+		rows := make([][]any, len(result.OCRPages))
+		for i, p := range result.OCRPages {
+			rows[i] = []any{task.CandidateID, p.Page, p.Engine, p.Confidence, p.Chars}
+		}
+
+		_, err = tx.CopyFrom(
+			ctx,
+			pgx.Identifier{"ai_engine", "t_ocr_pages"},
+			[]string{"candidate_id", "page", "engine", "confidence", "chars"},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return fmt.Errorf("batch insert ocr pages: %w", err)
+		}
+	}
+
+	const updateTask = `
+		UPDATE ai_engine.t_processing_tasks
+		SET status = 'analyzing',
+			progress_percent = 50,
+			attempts = 0,
+			locked_until = NULL,
+			error_message = NULL,
+			updated_at = NOW()
+		WHERE id = @id AND status = 'extracting'
+	`
+
+	if _, err := tx.Exec(ctx, updateTask, pgx.NamedArgs{"id": task.TaskID}); err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteProfile stores the extracted profile and completes the task.
+// Recruiter corrections are kept: the database derives structured_data from
+// the new document and the stored corrections. A task cancelled meanwhile
+// keeps its status and the profile is not stored.
+func (r *taskRepo) CompleteProfile(ctx context.Context, task *domain.ResumeTask, profile *domain.ExtractedProfile) error {
+	tx, err := r.dbClient.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const updateTask = `
+		UPDATE ai_engine.t_processing_tasks
+		SET status = 'completed',
+			progress_percent = 100,
+			locked_until = NULL,
+			error_message = NULL,
+			updated_at = NOW()
+		WHERE id = @id AND status = 'analyzing'
+	`
+
+	tag, err := tx.Exec(ctx, updateTask, pgx.NamedArgs{"id": task.TaskID})
+	if err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	const upsertProfile = `
+		INSERT INTO hiring.t_candidate_profiles (
+			candidate_id, extracted_data, schema_version, provider, extracted_at, updated_at
+		)
+		VALUES (@candidate_id, @data, @schema_version, @provider, NOW(), NOW())
+		ON CONFLICT (candidate_id) DO UPDATE
+		SET extracted_data = EXCLUDED.extracted_data,
+			schema_version = EXCLUDED.schema_version,
+			provider = EXCLUDED.provider,
+			extracted_at = EXCLUDED.extracted_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := tx.Exec(ctx, upsertProfile, pgx.NamedArgs{
+		"candidate_id":   task.CandidateID,
+		"data":           profile.Data,
+		"schema_version": profile.SchemaVersion,
+		"provider":       profile.Provider,
+	}); err != nil {
+		return fmt.Errorf("upsert profile: %w", err)
+	}
+
+	if err := fillContacts(ctx, tx, task.CandidateID, profile.Contacts); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// fillContacts sets the blank contact fields of a candidate from its resume.
+// The name is only taken when both parts are blank, and an email only when no
+// other candidate of the job has it, under the lock CreateCandidate takes.
+func fillContacts(ctx context.Context, tx pgx.Tx, candidateID string, contacts domain.CandidateContacts) error {
+	if contacts == (domain.CandidateContacts{}) {
+		return nil
+	}
+
+	if contacts.Email != "" {
+		const lock = `
+			SELECT pg_advisory_xact_lock(hashtextextended(job_id::text || ':' || LOWER(@email::text), 0))
+			FROM hiring.t_candidates
+			WHERE id = @id
+		`
+
+		if _, err := tx.Exec(ctx, lock, pgx.NamedArgs{
+			"id":    candidateID,
+			"email": contacts.Email,
+		}); err != nil {
+			return fmt.Errorf("lock candidate: %w", err)
+		}
+	}
+
+	const query = `
+		UPDATE hiring.t_candidates c
+		SET first_name = CASE WHEN c.name_norm IS NULL THEN NULLIF(@first_name, '') ELSE c.first_name END,
+			last_name = CASE WHEN c.name_norm IS NULL THEN NULLIF(@last_name, '') ELSE c.last_name END,
+			email = CASE
+				WHEN c.email_norm IS NULL AND NOT EXISTS (
+					SELECT 1 FROM hiring.t_candidates o
+					WHERE o.job_id = c.job_id AND o.email_norm = LOWER(@email)
+				) THEN NULLIF(@email, '')
+				ELSE c.email
+			END,
+			phone = COALESCE(NULLIF(c.phone, ''), NULLIF(@phone, '')),
+			updated_at = NOW()
+		WHERE c.id = @id
+		  AND ((c.name_norm IS NULL AND @first_name <> '')
+			OR (c.email_norm IS NULL AND @email <> '')
+			OR (COALESCE(c.phone, '') = '' AND @phone <> ''))
+	`
+
+	tag, err := tx.Exec(ctx, query, pgx.NamedArgs{
+		"id":         candidateID,
+		"first_name": contacts.FirstName,
+		"last_name":  contacts.LastName,
+		"email":      contacts.Email,
+		"phone":      contacts.Phone,
+	})
+	if err != nil {
+		return fmt.Errorf("fill contacts: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	return detectDuplicates(ctx, tx, candidateID)
+}
+
+// FailTask records the error of a claimed task. With retry the task waits
+// for the same stage again at the end of the queue, otherwise it fails.
+func (r *taskRepo) FailTask(ctx context.Context, taskID, message string, retry bool) error {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks
+		SET status = (CASE
+				WHEN NOT @retry THEN 'failed'
+				WHEN status = 'analyzing' THEN 'analyzing'
+				ELSE 'pending'
+			END)::task_status,
+			progress_percent = CASE WHEN status = 'analyzing' THEN 50 ELSE 0 END,
+			locked_until = NULL,
+			error_message = @message,
+			updated_at = NOW()
+		WHERE id = @id AND status IN ('extracting', 'analyzing')
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":      taskID,
+		"message": message,
+		"retry":   retry,
+	}); err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	return nil
+}
+
+// TouchTask reports progress of a long-running task and renews its lease.
+func (r *taskRepo) TouchTask(ctx context.Context, taskID string, progress int, lease time.Duration) error {
+	const query = `
+		UPDATE ai_engine.t_processing_tasks
+		SET progress_percent = @progress,
+			locked_until = NOW() + make_interval(secs => @lease),
+			updated_at = NOW()
+		WHERE id = @id AND status IN ('extracting', 'analyzing')
+	`
+
+	if _, err := r.dbClient.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"id":       taskID,
+		"progress": progress,
+		"lease":    lease.Seconds(),
+	}); err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	return nil
+}
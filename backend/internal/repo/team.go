@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrTeamNotFound = errors.New("team not found")
+
+type TeamRepository interface {
+	GetTeam(ctx context.Context, teamID string) (*domain.Team, error)
+	GetTeamBySlug(ctx context.Context, slug string) (*domain.Team, error)
+	UpdateTeam(ctx context.Context, team *domain.Team) error
+}
+
+type teamRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewTeamRepo(dbClient *db.PostgresClient) TeamRepository {
+	return &teamRepo{dbClient: dbClient}
+}
+
+const teamColumns = `id, name, slug, locale, branding, created_at`
+
+func (r *teamRepo) GetTeam(ctx context.Context, teamID string) (*domain.Team, error) {
+	return r.getTeam(ctx, `SELECT `+teamColumns+` FROM auth.t_teams WHERE id = @value`, teamID)
+}
+
+func (r *teamRepo) GetTeamBySlug(ctx context.Context, slug string) (*domain.Team, error) {
+	return r.getTeam(ctx, `SELECT `+teamColumns+` FROM auth.t_teams WHERE slug = @value`, slug)
+}
+
+func (r *teamRepo) getTeam(ctx context.Context, query, value string) (*domain.Team, error) {
+	rows, err := r.dbClient.Pool.Query(ctx, query, pgx.NamedArgs{"value": value})
+	if err != nil {
+		return nil, fmt.Errorf("query team: %w", err)
+	}
+
+	team, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[domain.Team])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTeamNotFound
+		}
+
+		return nil, fmt.Errorf("scan team: %w", err)
+	}
+
+	return &team, nil
+}
+
+func (r *teamRepo) UpdateTeam(ctx context.Context, team *domain.Team) error {
+	const query = `
+		UPDATE auth.t_teams
+		SET name = @name, slug = @slug, locale = @locale, branding = @branding
+		WHERE id = @id
+		RETURNING created_at
+	`
+
+	if err := r.dbClient.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"id":       team.ID,
+		"name":     team.Name,
+		"slug":     team.Slug,
+		"locale":   team.Locale,
+		"branding": team.Branding,
+	}).Scan(&team.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTeamNotFound
+		}
+
+		return fmt.Errorf("update team: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"backend/internal/db"
+	"backend/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type TimelineRepository interface {
+	ListTimeline(ctx context.Context, filter domain.TimelineFilter) ([]domain.TimelineEntry, int, error)
+}
+
+type timelineRepo struct {
+	dbClient *db.PostgresClient
+}
+
+func NewTimelineRepo(dbClient *db.PostgresClient) TimelineRepository {
+	return &timelineRepo{dbClient: dbClient}
+}
+
+// timelineSources selects the entries of each kind for @candidate_id as
+// (kind, id, occurred_at, actor_type, actor_id, details). Times are cast to
+// TIMESTAMPTZ since interviews keep theirs with a time zone.
+var timelineSources = map[string]string{
+	domain.TimelineStatusChange: `
+		SELECT 'status_change', h.id, h.changed_at::timestamptz,
+			CASE WHEN h.changed_by IS NULL THEN 'system' ELSE 'user' END, h.changed_by,
+			jsonb_build_object('from', COALESCE(h.old_status, ''), 'to', COALESCE(h.new_status, ''))
+		FROM hiring.t_status_history h
+		WHERE h.candidate_id = @candidate_id
+	`,
+	// Communications are the generated emails and the interview invitations
+	// sent to the candidate.
+	domain.TimelineCommunication: `
+		SELECT 'communication', m.id, m.sent_at::timestamptz, 'user', m.generated_by_user_id,
+			jsonb_build_object('type', m.type::text, 'content', COALESCE(m.content, ''))
+		FROM ai_engine.t_communications m
+		WHERE m.candidate_id = @candidate_id AND m.sent_at IS NOT NULL
+		UNION ALL
+		SELECT 'communication', im.id, im.sent_at::timestamptz, 'system', NULL::uuid,
+			jsonb_build_object('type', 'interview_' || im.kind, 'interview_id', im.interview_id, 'email', im.email)
+		FROM hiring.t_interview_mails im
+		JOIN hiring.t_interviews i ON i.id = im.interview_id
+		WHERE i.candidate_id = @candidate_id AND im.recipient_type = 'candidate' AND im.sent_at IS NOT NULL
+	`,
+	domain.TimelineScore: `
+		SELECT 'score', h.id, h.analyzed_at::timestamptz, 'ai_agent', NULL::uuid,
+			jsonb_build_object('match_score', h.match_score,
+				'previous_score', LAG(h.match_score) OVER (ORDER BY h.analyzed_at, h.recorded_at))
+		FROM ai_engine.t_candidate_score_history h
+		WHERE h.candidate_id = @candidate_id
+	`,
+	domain.TimelineScorecard: `
+		SELECT 'scorecard', s.id, s.submitted_at::timestamptz, 'user', s.interviewer_id,
+			jsonb_build_object('stage', s.stage, 'score', s.score, 'recommendation', s.recommendation,
+				'updated_at', s.updated_at)
+		FROM hiring.t_scorecards s
+		JOIN hiring.t_candidates c ON c.id = s.candidate_id
+		WHERE s.candidate_id = @candidate_id AND ` + scorecardVisible + `
+	`,
+	domain.TimelineComment: `
+		SELECT 'comment', cm.id, cm.created_at::timestamptz, 'user', cm.author_id,
+			jsonb_build_object('parent_id', cm.parent_id, 'visibility', cm.visibility, 'body', cm.body,
+				'edited_at', cm.edited_at)
+		FROM hiring.t_candidate_comments cm
+		WHERE cm.candidate_id = @candidate_id AND cm.deleted_at IS NULL
+			AND (cm.visibility = 'team' OR cm.author_id = @user_id)
+	`,
+	// An interview is placed at its start; scheduling, rescheduling and
+	// cancelling it are in the activity log.
+	domain.TimelineInterview: `
+		SELECT 'interview', i.id, i.starts_at, 'user', i.created_by,
+			jsonb_build_object('title', i.title, 'stage', i.stage, 'status', i.status,
+				'ends_at', i.ends_at, 'location', COALESCE(i.location, ''), 'video_url', COALESCE(i.video_url, ''))
+		FROM hiring.t_interviews i
+		WHERE i.candidate_id = @candidate_id
+	`,
+	domain.TimelineActivity: `
+		SELECT 'activity', l.id, l.created_at::timestamptz, l.actor_type::text, l.actor_id,
+			jsonb_build_object('action', t.code, 'details', COALESCE(l.details, '{}'::jsonb))
+		FROM hiring.t_activity_logs l
+		JOIN hiring.t_action_types t ON t.id = l.action_id
+		WHERE l.team_id = @team_id AND l.target_id = @candidate_id
+	`,
+}
+
+// ListTimeline returns a page of the history of a candidate within scope,
+// newest first, and the total number of entries. Comments and scorecards are
+// limited to those the caller may read.
+func (r *timelineRepo) ListTimeline(ctx context.Context, filter domain.TimelineFilter) ([]domain.TimelineEntry, int, error) {
+	args := pgx.NamedArgs{
+		"candidate_id": filter.CandidateID,
+		"team_id":      filter.Scope.TeamID,
+		"user_id":      filter.Scope.UserID,
+	}
+
+	selectCandidate := `SELECT 1 FROM hiring.t_candidates c WHERE c.id = @candidate_id AND ` + jobScopeCondition("c.job_id", filter.Scope, args)
+
+	var found int
+	if err := r.dbClient.Pool.QueryRow(ctx, selectCandidate, args).Scan(&found); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, ErrCandidateNotFound
+		}
+
+		return nil, 0, fmt.Errorf("select candidate: %w", err)
+	}
+
+	var sources []string
+
+	for _, kind := range domain.TimelineKinds {
+		if len(filter.Kinds) == 0 || slices.Contains(filter.Kinds, kind) {
+			sources = append(sources, timelineSources[kind])
+		}
+	}
+
+	if len(sources) == 0 {
+		return []domain.TimelineEntry{}, 0, nil
+	}
+
+	timeline := `
+		WITH timeline (kind, id, occurred_at, actor_type, actor_id, details) AS (` +
+		strings.Join(sources, " UNION ALL ") + `
+		)
+	`
+
+	var total int
+	if err := r.dbClient.Pool.QueryRow(ctx, timeline+`SELECT COUNT(*) FROM timeline`, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count timeline: %w", err)
+	}
+
+	args["limit"] = filter.Limit
+	args["offset"] = filter.Offset
+
+	query := timeline + `
+		SELECT e.kind, e.id, e.occurred_at, e.actor_type, e.actor_id,
+			COALESCE(NULLIF(TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), ''), u.email, '') AS actor_name,
+			e.details
+		FROM timeline e
+		LEFT JOIN auth.t_users u ON e.actor_type = 'user' AND u.id = e.actor_id
+		ORDER BY e.occurred_at DESC, e.kind, e.id
+		LIMIT @limit OFFSET @offset
+	`
+
+	rows, err := r.dbClient.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query timeline: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.TimelineEntry])
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan timeline: %w", err)
+	}
+
+	return entries, total, nil
+}
@@ -2,8 +2,10 @@ package server
 
 import (
 	"backend/pkg/router"
+	"backend/pkg/svc"
 	"context"
 	"errors"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -84,3 +86,27 @@ func WithMiddleware(middlewares ...echo.MiddlewareFunc) func(*Api) {
 		s.api.Use(middlewares...)
 	}
 }
+
+// WithHealthCheck регистрирует эндпоинт, опрашивающий HealthCheck переданных
+// сервисов во время работы. Возвращает 503, если хотя бы один неисправен.
+func WithHealthCheck(path string, services ...svc.Service) func(*Api) {
+	return func(s *Api) {
+		s.api.GET(path, func(c echo.Context) error {
+			code := http.StatusOK
+			status := make(map[string]string, len(services))
+
+			for _, service := range services {
+				if err := service.HealthCheck(c.Request().Context()); err != nil {
+					code = http.StatusServiceUnavailable
+					status[service.Name()] = err.Error()
+
+					continue
+				}
+
+				status[service.Name()] = "ok"
+			}
+
+			return c.JSON(code, status)
+		})
+	}
+}
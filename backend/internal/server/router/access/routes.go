@@ -0,0 +1,53 @@
+package access
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AccessRoutes interface {
+	GetJobMembers() echo.HandlerFunc
+	GetMemberJobs() echo.HandlerFunc
+	PutGrant() echo.HandlerFunc
+	DeleteGrant() echo.HandlerFunc
+	PostDepartmentGrant() echo.HandlerFunc
+}
+
+type accessRouter struct {
+	routes    []router.Route
+	handler   AccessRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *accessRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*accessRouter)(nil)
+
+func NewRouter(h AccessRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &accessRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *accessRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "/jobs/:jobId", r.handler.GetJobMembers, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/jobs/:jobId/users/:userId", r.handler.PutGrant, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/jobs/:jobId/users/:userId", r.handler.DeleteGrant, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/users/:userId/jobs", r.handler.GetMemberJobs, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/users/:userId/departments", r.handler.PostDepartmentGrant, r.rateLimit, r.session, r.rbac),
+	}
+}
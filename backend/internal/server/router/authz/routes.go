@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuthzRoutes interface {
+	GetExplain() echo.HandlerFunc
+	GetDenials() echo.HandlerFunc
+}
+
+type authzRouter struct {
+	routes    []router.Route
+	handler   AuthzRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *authzRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*authzRouter)(nil)
+
+func NewRouter(h AuthzRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &authzRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *authzRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "/explain", r.handler.GetExplain, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/denials", r.handler.GetDenials, r.rateLimit, r.session, r.rbac),
+	}
+}
@@ -0,0 +1,54 @@
+package calendar
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarRoutes interface {
+	PostCalendarFeed() echo.HandlerFunc
+	DeleteCalendarFeed() echo.HandlerFunc
+	GetCalendarFeed() echo.HandlerFunc
+}
+
+type calendarRouter struct {
+	routes    []router.Route
+	handler   CalendarRoutes
+	rateLimit echo.MiddlewareFunc
+	feedLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+}
+
+func (r *calendarRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*calendarRouter)(nil)
+
+// NewRouter builds the calendar routes. feedLimit guards the public feed
+// route, which is fetched by calendar applications rather than users.
+func NewRouter(h CalendarRoutes, rateLimit echo.MiddlewareFunc, feedLimit echo.MiddlewareFunc, session echo.MiddlewareFunc) router.Router {
+	r := &calendarRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		feedLimit: feedLimit,
+		session:   session,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+// Every member manages the feed of their own interviews, so the feed routes
+// need a session but no permission. Calendar applications fetch the feed
+// with the token in its URL and no session at all.
+func (r *calendarRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodPost, "/feed", r.handler.PostCalendarFeed, r.rateLimit, r.session),
+		router.NewRoute(http.MethodDelete, "/feed", r.handler.DeleteCalendarFeed, r.rateLimit, r.session),
+		router.NewRoute(http.MethodGet, "/feeds/:token", r.handler.GetCalendarFeed, r.feedLimit),
+	}
+}
@@ -0,0 +1,126 @@
+package candidate
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CandidateRoutes interface {
+	GetCandidates() echo.HandlerFunc
+	GetCandidate() echo.HandlerFunc
+	PostCandidate() echo.HandlerFunc
+	PutCandidate() echo.HandlerFunc
+	DeleteCandidate() echo.HandlerFunc
+	GetResume() echo.HandlerFunc
+	GetResumeURL() echo.HandlerFunc
+}
+
+type ProfileRoutes interface {
+	GetProfileSchema() echo.HandlerFunc
+	GetProfile() echo.HandlerFunc
+	PatchProfile() echo.HandlerFunc
+	DeleteProfileCorrections() echo.HandlerFunc
+	PostProfileExtraction() echo.HandlerFunc
+}
+
+type BoardRoutes interface {
+	PostMove() echo.HandlerFunc
+	GetStatusHistory() echo.HandlerFunc
+}
+
+type DuplicateRoutes interface {
+	GetDuplicates() echo.HandlerFunc
+	PostDetect() echo.HandlerFunc
+	PostDismiss() echo.HandlerFunc
+	PostMerge() echo.HandlerFunc
+	GetMerges() echo.HandlerFunc
+}
+
+type SearchRoutes interface {
+	GetSearch() echo.HandlerFunc
+}
+
+type ImportRoutes interface {
+	PostImport() echo.HandlerFunc
+	GetImports() echo.HandlerFunc
+	GetImport() echo.HandlerFunc
+	GetImportRows() echo.HandlerFunc
+	GetImportReport() echo.HandlerFunc
+}
+
+type TimelineRoutes interface {
+	GetTimeline() echo.HandlerFunc
+}
+
+type candidateRouter struct {
+	routes    []router.Route
+	handler   CandidateRoutes
+	profile   ProfileRoutes
+	board     BoardRoutes
+	duplicate DuplicateRoutes
+	search    SearchRoutes
+	imports   ImportRoutes
+	timeline  TimelineRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *candidateRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*candidateRouter)(nil)
+
+func NewRouter(h CandidateRoutes, p ProfileRoutes, b BoardRoutes, d DuplicateRoutes, s SearchRoutes, i ImportRoutes, t TimelineRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &candidateRouter{
+		handler:   h,
+		profile:   p,
+		board:     b,
+		duplicate: d,
+		search:    s,
+		imports:   i,
+		timeline:  t,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *candidateRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetCandidates, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "", r.handler.PostCandidate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/profile-schema", r.profile.GetProfileSchema, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/search", r.search.GetSearch, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/imports", r.imports.PostImport, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/imports", r.imports.GetImports, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/imports/:importId", r.imports.GetImport, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/imports/:importId/rows", r.imports.GetImportRows, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/imports/:importId/report", r.imports.GetImportReport, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/duplicates", r.duplicate.GetDuplicates, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/duplicates/:duplicateId/dismiss", r.duplicate.PostDismiss, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId", r.handler.GetCandidate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:candidateId", r.handler.PutCandidate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:candidateId", r.handler.DeleteCandidate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/resume", r.handler.GetResume, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/resume/url", r.handler.GetResumeURL, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/profile", r.profile.GetProfile, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPatch, "/:candidateId/profile", r.profile.PatchProfile, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:candidateId/profile/corrections", r.profile.DeleteProfileCorrections, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/:candidateId/profile/extract", r.profile.PostProfileExtraction, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/:candidateId/move", r.board.PostMove, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/status-history", r.board.GetStatusHistory, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/timeline", r.timeline.GetTimeline, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/duplicates", r.duplicate.GetDuplicates, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/:candidateId/duplicates/detect", r.duplicate.PostDetect, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/:candidateId/merge", r.duplicate.PostMerge, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:candidateId/merges", r.duplicate.GetMerges, r.rateLimit, r.session, r.rbac),
+	}
+}
@@ -0,0 +1,61 @@
+package careers
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CareersRoutes interface {
+	GetBoard() echo.HandlerFunc
+	GetJob() echo.HandlerFunc
+	GetJobPosting() echo.HandlerFunc
+	GetRSS() echo.HandlerFunc
+	GetAtom() echo.HandlerFunc
+}
+
+type ApplyRoutes interface {
+	PostApply() echo.HandlerFunc
+}
+
+type careersRouter struct {
+	routes    []router.Route
+	handler   CareersRoutes
+	apply     ApplyRoutes
+	rateLimit echo.MiddlewareFunc
+	applyMws  []echo.MiddlewareFunc
+}
+
+func (r *careersRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*careersRouter)(nil)
+
+// NewRouter builds the public careers routes. They are unauthenticated and
+// only rate limited; applyMws are appended to the application route, which
+// needs its own body size and submission limits.
+func NewRouter(h CareersRoutes, a ApplyRoutes, rateLimit echo.MiddlewareFunc, applyMws ...echo.MiddlewareFunc) router.Router {
+	r := &careersRouter{
+		handler:   h,
+		apply:     a,
+		rateLimit: rateLimit,
+		applyMws:  applyMws,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *careersRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "/:slug", r.handler.GetBoard, r.rateLimit),
+		router.NewRoute(http.MethodGet, "/:slug/rss", r.handler.GetRSS, r.rateLimit),
+		router.NewRoute(http.MethodGet, "/:slug/atom", r.handler.GetAtom, r.rateLimit),
+		router.NewRoute(http.MethodGet, "/:slug/jobs/:jobId", r.handler.GetJob, r.rateLimit),
+		router.NewRoute(http.MethodGet, "/:slug/jobs/:jobId/jsonld", r.handler.GetJobPosting, r.rateLimit),
+		router.NewRoute(http.MethodPost, "/:slug/jobs/:jobId/apply", r.apply.PostApply, append([]echo.MiddlewareFunc{r.rateLimit}, r.applyMws...)...),
+	}
+}
@@ -0,0 +1,55 @@
+package comment
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CommentRoutes interface {
+	GetComments() echo.HandlerFunc
+	PostComment() echo.HandlerFunc
+	PutComment() echo.HandlerFunc
+	DeleteComment() echo.HandlerFunc
+	GetRevisions() echo.HandlerFunc
+}
+
+type commentRouter struct {
+	routes    []router.Route
+	handler   CommentRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *commentRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*commentRouter)(nil)
+
+func NewRouter(h CommentRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &commentRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+// Comments have a route group of their own so that roles can discuss
+// candidates without the permission to change them.
+func (r *commentRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "/candidates/:candidateId", r.handler.GetComments, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/candidates/:candidateId", r.handler.PostComment, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:commentId", r.handler.PutComment, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:commentId", r.handler.DeleteComment, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:commentId/revisions", r.handler.GetRevisions, r.rateLimit, r.session, r.rbac),
+	}
+}
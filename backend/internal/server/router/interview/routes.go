@@ -0,0 +1,63 @@
+package interview
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InterviewRoutes interface {
+	GetInterviews() echo.HandlerFunc
+	PostInterview() echo.HandlerFunc
+	GetInterviewConflicts() echo.HandlerFunc
+	GetInterview() echo.HandlerFunc
+	PutInterview() echo.HandlerFunc
+	PostInterviewCancel() echo.HandlerFunc
+	GetInterviewCalendar() echo.HandlerFunc
+	GetAvailability() echo.HandlerFunc
+	PostAvailability() echo.HandlerFunc
+	DeleteAvailability() echo.HandlerFunc
+}
+
+type interviewRouter struct {
+	routes    []router.Route
+	handler   InterviewRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *interviewRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*interviewRouter)(nil)
+
+func NewRouter(h InterviewRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &interviewRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *interviewRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetInterviews, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "", r.handler.PostInterview, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/conflicts", r.handler.GetInterviewConflicts, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/availability", r.handler.GetAvailability, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/availability", r.handler.PostAvailability, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/availability/:windowId", r.handler.DeleteAvailability, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:interviewId", r.handler.GetInterview, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:interviewId", r.handler.PutInterview, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/:interviewId/cancel", r.handler.PostInterviewCancel, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:interviewId/ics", r.handler.GetInterviewCalendar, r.rateLimit, r.session, r.rbac),
+	}
+}
@@ -0,0 +1,93 @@
+package job
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type JobRoutes interface {
+	GetJobs() echo.HandlerFunc
+	GetJob() echo.HandlerFunc
+	PostJob() echo.HandlerFunc
+	PutJob() echo.HandlerFunc
+	DeleteJob() echo.HandlerFunc
+	PostStatus() echo.HandlerFunc
+	GetSettings() echo.HandlerFunc
+	PutSettings() echo.HandlerFunc
+}
+
+type BoardRoutes interface {
+	GetBoard() echo.HandlerFunc
+}
+
+type PipelineRoutes interface {
+	GetJobPipeline() echo.HandlerFunc
+	PutJobPipeline() echo.HandlerFunc
+}
+
+type ScorecardTemplateRoutes interface {
+	GetScorecardTemplates() echo.HandlerFunc
+	PutScorecardTemplate() echo.HandlerFunc
+	DeleteScorecardTemplate() echo.HandlerFunc
+}
+
+type EventsRoutes interface {
+	GetEvents() echo.HandlerFunc
+}
+
+type jobRouter struct {
+	routes    []router.Route
+	handler   JobRoutes
+	board     BoardRoutes
+	pipeline  PipelineRoutes
+	events    EventsRoutes
+	scorecard ScorecardTemplateRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *jobRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*jobRouter)(nil)
+
+func NewRouter(h JobRoutes, b BoardRoutes, p PipelineRoutes, e EventsRoutes, s ScorecardTemplateRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &jobRouter{
+		handler:   h,
+		board:     b,
+		pipeline:  p,
+		events:    e,
+		scorecard: s,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *jobRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetJobs, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "", r.handler.PostJob, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/settings", r.handler.GetSettings, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/settings", r.handler.PutSettings, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:jobId", r.handler.GetJob, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:jobId", r.handler.PutJob, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:jobId", r.handler.DeleteJob, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "/:jobId/status", r.handler.PostStatus, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:jobId/board", r.board.GetBoard, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:jobId/events", r.events.GetEvents, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:jobId/pipeline", r.pipeline.GetJobPipeline, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:jobId/pipeline", r.pipeline.PutJobPipeline, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:jobId/scorecard-templates", r.scorecard.GetScorecardTemplates, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:jobId/scorecard-templates", r.scorecard.PutScorecardTemplate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:jobId/scorecard-templates", r.scorecard.DeleteScorecardTemplate, r.rateLimit, r.session, r.rbac),
+	}
+}
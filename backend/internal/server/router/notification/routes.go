@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationRoutes interface {
+	GetNotifications() echo.HandlerFunc
+	PostRead() echo.HandlerFunc
+	PostReadAll() echo.HandlerFunc
+}
+
+type notificationRouter struct {
+	routes    []router.Route
+	handler   NotificationRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+}
+
+func (r *notificationRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*notificationRouter)(nil)
+
+// NewRouter has no RBAC middleware: members only ever reach their own
+// notifications.
+func NewRouter(h NotificationRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc) router.Router {
+	r := &notificationRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *notificationRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetNotifications, r.rateLimit, r.session),
+		router.NewRoute(http.MethodPost, "/read", r.handler.PostReadAll, r.rateLimit, r.session),
+		router.NewRoute(http.MethodPost, "/:notificationId/read", r.handler.PostRead, r.rateLimit, r.session),
+	}
+}
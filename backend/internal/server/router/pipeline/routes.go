@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PipelineRoutes interface {
+	GetTemplates() echo.HandlerFunc
+	GetTemplate() echo.HandlerFunc
+	PostTemplate() echo.HandlerFunc
+	PutTemplate() echo.HandlerFunc
+	DeleteTemplate() echo.HandlerFunc
+}
+
+type pipelineRouter struct {
+	routes    []router.Route
+	handler   PipelineRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *pipelineRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*pipelineRouter)(nil)
+
+func NewRouter(h PipelineRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &pipelineRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *pipelineRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetTemplates, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "", r.handler.PostTemplate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/:pipelineId", r.handler.GetTemplate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:pipelineId", r.handler.PutTemplate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:pipelineId", r.handler.DeleteTemplate, r.rateLimit, r.session, r.rbac),
+	}
+}
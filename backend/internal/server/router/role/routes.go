@@ -0,0 +1,57 @@
+package role
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleRoutes interface {
+	GetPermissions() echo.HandlerFunc
+	GetRoles() echo.HandlerFunc
+	PostRole() echo.HandlerFunc
+	PutRole() echo.HandlerFunc
+	DeleteRole() echo.HandlerFunc
+	PutMember() echo.HandlerFunc
+	DeleteMember() echo.HandlerFunc
+}
+
+type roleRouter struct {
+	routes    []router.Route
+	handler   RoleRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *roleRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*roleRouter)(nil)
+
+func NewRouter(h RoleRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &roleRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *roleRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetRoles, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/permissions", r.handler.GetPermissions, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPost, "", r.handler.PostRole, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:roleId", r.handler.PutRole, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:roleId", r.handler.DeleteRole, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/:roleId/members/:userId", r.handler.PutMember, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodDelete, "/:roleId/members/:userId", r.handler.DeleteMember, r.rateLimit, r.session, r.rbac),
+	}
+}
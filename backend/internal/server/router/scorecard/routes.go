@@ -0,0 +1,53 @@
+package scorecard
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ScorecardRoutes interface {
+	GetScorecards() echo.HandlerFunc
+	PutScorecard() echo.HandlerFunc
+	GetCandidateTemplate() echo.HandlerFunc
+	GetComparison() echo.HandlerFunc
+}
+
+type scorecardRouter struct {
+	routes    []router.Route
+	handler   ScorecardRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *scorecardRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*scorecardRouter)(nil)
+
+func NewRouter(h ScorecardRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &scorecardRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+// Scorecards have a route group of their own so that interviewers can
+// evaluate candidates without the permission to change them.
+func (r *scorecardRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "/candidates/:candidateId", r.handler.GetScorecards, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "/candidates/:candidateId", r.handler.PutScorecard, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/candidates/:candidateId/template", r.handler.GetCandidateTemplate, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodGet, "/jobs/:jobId/comparison", r.handler.GetComparison, r.rateLimit, r.session, r.rbac),
+	}
+}
@@ -0,0 +1,47 @@
+package team
+
+import (
+	"backend/pkg/router"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TeamRoutes interface {
+	GetTeam() echo.HandlerFunc
+	PutTeam() echo.HandlerFunc
+}
+
+type teamRouter struct {
+	routes    []router.Route
+	handler   TeamRoutes
+	rateLimit echo.MiddlewareFunc
+	session   echo.MiddlewareFunc
+	rbac      echo.MiddlewareFunc
+}
+
+func (r *teamRouter) Routes() []router.Route {
+	return r.routes
+}
+
+var _ router.Router = (*teamRouter)(nil)
+
+func NewRouter(h TeamRoutes, rateLimit echo.MiddlewareFunc, session echo.MiddlewareFunc, rbac echo.MiddlewareFunc) router.Router {
+	r := &teamRouter{
+		handler:   h,
+		rateLimit: rateLimit,
+		session:   session,
+		rbac:      rbac,
+	}
+
+	r.initRoutes()
+
+	return r
+}
+
+func (r *teamRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewRoute(http.MethodGet, "", r.handler.GetTeam, r.rateLimit, r.session, r.rbac),
+		router.NewRoute(http.MethodPut, "", r.handler.PutTeam, r.rateLimit, r.session, r.rbac),
+	}
+}
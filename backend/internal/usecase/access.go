@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrGrantNotFound  = errors.New("job access grant not found")
+	ErrUnknownJobRole = errors.New("unknown job role")
+)
+
+type AccessUseCase interface {
+	ListJobMembers(ctx context.Context, teamID, jobID string) (*domain.JobMembers, error)
+	ListMemberJobs(ctx context.Context, scope domain.JobScope, userID string) (*domain.MemberJobs, error)
+	GrantAccess(ctx context.Context, session domain.Session, jobID, userID string, roles []string) error
+	RevokeAccess(ctx context.Context, teamID, jobID, userID string) error
+	GrantDepartment(ctx context.Context, scope domain.JobScope, userID, department string, roles []string) (int, error)
+}
+
+var _ AccessUseCase = (*accessUseCase)(nil)
+
+type accessUseCase struct {
+	repo     repo.AccessRepository
+	roleRepo repo.RoleRepository
+	enforcer *rbac.CasbinClient
+}
+
+func NewAccessUseCase(repo repo.AccessRepository, roleRepo repo.RoleRepository, enforcer *rbac.CasbinClient) AccessUseCase {
+	return &accessUseCase{
+		repo:     repo,
+		roleRepo: roleRepo,
+		enforcer: enforcer,
+	}
+}
+
+// ListJobMembers returns the explicit grants of a job together with the
+// members who see it through the jobs:all permission.
+func (a *accessUseCase) ListJobMembers(ctx context.Context, teamID, jobID string) (*domain.JobMembers, error) {
+	grants, err := a.repo.ListJobGrants(ctx, teamID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("list job grants: %w", err)
+	}
+
+	members, err := a.repo.ListMembers(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+
+	allJobs := make([]domain.Member, 0)
+
+	for _, m := range members {
+		ok, err := a.enforcer.HasPermissionInDomain(m.ID, domain.PermJobsAll, teamID)
+		if err != nil {
+			return nil, fmt.Errorf("check jobs:all: %w", err)
+		}
+
+		if ok {
+			allJobs = append(allJobs, m)
+		}
+	}
+
+	return &domain.JobMembers{
+		Grants:  grants,
+		AllJobs: allJobs,
+	}, nil
+}
+
+// ListMemberJobs returns the jobs a member can see. Grants are limited to
+// the jobs the caller can see as well.
+func (a *accessUseCase) ListMemberJobs(ctx context.Context, scope domain.JobScope, userID string) (*domain.MemberJobs, error) {
+	if err := a.checkMember(ctx, scope.TeamID, userID); err != nil {
+		return nil, err
+	}
+
+	allJobs, err := a.enforcer.HasPermissionInDomain(userID, domain.PermJobsAll, scope.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("check jobs:all: %w", err)
+	}
+
+	grants, err := a.repo.ListMemberGrants(ctx, scope, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list member grants: %w", err)
+	}
+
+	return &domain.MemberJobs{
+		AllJobs: allJobs,
+		Grants:  grants,
+	}, nil
+}
+
+func (a *accessUseCase) GrantAccess(ctx context.Context, session domain.Session, jobID, userID string, roles []string) error {
+	roles, err := validateJobRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	if err := a.checkMember(ctx, session.TeamID, userID); err != nil {
+		return err
+	}
+
+	if err := a.repo.GrantJobAccess(ctx, jobID, domain.GrantParams{
+		TeamID:    session.TeamID,
+		UserID:    userID,
+		GrantedBy: session.UserID,
+		Roles:     roles,
+	}); err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("grant access: %w", err)
+	}
+
+	return nil
+}
+
+func (a *accessUseCase) RevokeAccess(ctx context.Context, teamID, jobID, userID string) error {
+	if err := a.repo.RevokeJobAccess(ctx, teamID, jobID, userID); err != nil {
+		if errors.Is(err, repo.ErrGrantNotFound) {
+			return ErrGrantNotFound
+		}
+
+		return fmt.Errorf("revoke access: %w", err)
+	}
+
+	return nil
+}
+
+// GrantDepartment grants the member the jobs of the department that the
+// caller can see.
+func (a *accessUseCase) GrantDepartment(ctx context.Context, scope domain.JobScope, userID, department string, roles []string) (int, error) {
+	roles, err := validateJobRoles(roles)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := a.checkMember(ctx, scope.TeamID, userID); err != nil {
+		return 0, err
+	}
+
+	n, err := a.repo.GrantDepartmentAccess(ctx, scope, department, domain.GrantParams{
+		TeamID:    scope.TeamID,
+		UserID:    userID,
+		GrantedBy: scope.UserID,
+		Roles:     roles,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("grant department: %w", err)
+	}
+
+	return n, nil
+}
+
+func (a *accessUseCase) checkMember(ctx context.Context, teamID, userID string) error {
+	if err := a.roleRepo.CheckMember(ctx, teamID, userID); err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+
+		return fmt.Errorf("check member: %w", err)
+	}
+
+	return nil
+}
+
+func validateJobRoles(roles []string) ([]string, error) {
+	for _, r := range roles {
+		if !slices.Contains(domain.JobRoles, r) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownJobRole, r)
+		}
+	}
+
+	return compactStrings(roles), nil
+}
+
+// withAllJobs returns those of userIDs whose roles grant jobs:all in the
+// team.
+func withAllJobs(enforcer *rbac.CasbinClient, teamID string, userIDs []string) ([]string, error) {
+	allJobs := make([]string, 0)
+
+	for _, userID := range userIDs {
+		ok, err := enforcer.HasPermissionInDomain(userID, domain.PermJobsAll, teamID)
+		if err != nil {
+			return nil, fmt.Errorf("check jobs:all: %w", err)
+		}
+
+		if ok {
+			allJobs = append(allJobs, userID)
+		}
+	}
+
+	return allJobs, nil
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrJobClosed       = errors.New("job is not accepting applications")
+	ErrAlreadyApplied  = errors.New("you have already applied to this job")
+	ErrUnsupportedFile = errors.New("unsupported resume format")
+	ErrFileTooLarge    = errors.New("resume file is too large")
+)
+
+// resumeType is an accepted resume format: the content type it is stored
+// with and the sniffed types its content may have.
+type resumeType struct {
+	contentType string
+	sniffed     []string
+}
+
+// resumeTypes maps accepted resume extensions to their formats. DOCX and ODT
+// are zip archives and old Word files are OLE containers; sniffing may only
+// recognise the container. Photos of a resume are accepted for OCR.
+var resumeTypes = map[string]resumeType{
+	".pdf":  {"application/pdf", []string{"application/pdf"}},
+	".doc":  {"application/msword", []string{"application/msword", "application/x-ole-storage"}},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", []string{"application/zip"}},
+	".rtf":  {"application/rtf", []string{"text/rtf"}},
+	".odt":  {"application/vnd.oasis.opendocument.text", []string{"application/zip"}},
+	".txt":  {"text/plain", []string{"text/plain"}},
+	".jpg":  {"image/jpeg", []string{"image/jpeg"}},
+	".jpeg": {"image/jpeg", []string{"image/jpeg"}},
+	".png":  {"image/png", []string{"image/png"}},
+	".tif":  {"image/tiff", []string{"image/tiff"}},
+	".tiff": {"image/tiff", []string{"image/tiff"}},
+}
+
+type ApplicationUseCase interface {
+	Apply(ctx context.Context, app domain.Application, resume io.Reader, size int64) error
+}
+
+var _ ApplicationUseCase = (*applicationUseCase)(nil)
+
+type applicationUseCase struct {
+	repo      repo.CandidateRepository
+	pipelines repo.PipelineRepository
+	careers   CareersUseCase
+	storage   storage.Storage
+}
+
+func NewApplicationUseCase(repo repo.CandidateRepository, pipelines repo.PipelineRepository, careers CareersUseCase, storage storage.Storage) ApplicationUseCase {
+	return &applicationUseCase{
+		repo:      repo,
+		pipelines: pipelines,
+		careers:   careers,
+		storage:   storage,
+	}
+}
+
+// Apply stores the resume under the team's key prefix and creates the
+// candidate in the first stage of the job. The file content must match its
+// extension. Cheap checks against the cached careers board and existing
+// candidates run before the upload; the repo repeats them in its transaction.
+// The file is removed again if the candidate cannot be created.
+func (a *applicationUseCase) Apply(ctx context.Context, app domain.Application, resume io.Reader, size int64) error {
+	ext := strings.ToLower(path.Ext(app.FileName))
+
+	format, ok := resumeTypes[ext]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFile, ext)
+	}
+
+	sniffed, resume, err := storage.Sniff(resume)
+	if err != nil {
+		return fmt.Errorf("read resume: %w", err)
+	}
+
+	if !storage.SniffMatches(sniffed, format.sniffed...) {
+		return fmt.Errorf("%w: %s content in a %s file", ErrUnsupportedFile, sniffed.String(), ext)
+	}
+
+	if _, _, err := a.careers.GetJob(ctx, app.Slug, app.JobID); err != nil {
+		return err
+	}
+
+	teamID, applied, err := a.repo.CheckApplication(ctx, app.JobID, app.Email)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("check application: %w", err)
+	}
+
+	if applied {
+		return ErrAlreadyApplied
+	}
+
+	pipeline, err := a.pipelines.GetJobPipeline(ctx, teamID, app.JobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("get job pipeline: %w", err)
+	}
+
+	key := storage.TeamKey(teamID, "resumes", app.JobID, uuid.NewString()+ext)
+
+	if err := a.storage.Put(ctx, key, resume, size, format.contentType); err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return ErrFileTooLarge
+		}
+
+		return fmt.Errorf("store resume: %w", err)
+	}
+
+	candidate := &domain.Candidate{
+		FirstName:      app.FirstName,
+		LastName:       app.LastName,
+		Email:          app.Email,
+		Phone:          app.Phone,
+		ResumeFileKey:  key,
+		ResumeFileName: resumeFileName(app.FileName),
+		Status:         pipeline.Stages.First(),
+		Source:         domain.CandidateSourceCareers,
+	}
+
+	if err := a.repo.CreateApplication(ctx, app, candidate); err != nil {
+		_ = a.storage.Delete(context.WithoutCancel(ctx), key)
+
+		switch {
+		case errors.Is(err, repo.ErrJobNotFound):
+			return ErrJobNotFound
+		case errors.Is(err, repo.ErrJobClosed):
+			return ErrJobClosed
+		case errors.Is(err, repo.ErrAlreadyApplied):
+			return ErrAlreadyApplied
+		default:
+			return fmt.Errorf("create application: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resumeFileName keeps the base name of an uploaded file, also for paths sent
+// by Windows browsers, cut to the column size.
+func resumeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	if r := []rune(name); len(r) > 255 {
+		name = string(r[len(r)-255:])
+	}
+
+	return name
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+)
+
+type AuthzUseCase interface {
+	Explain(ctx context.Context, check domain.AccessCheck) (*rbac.Explanation, error)
+	ListDenials(ctx context.Context, teamID string, limit, offset int) ([]domain.Activity, error)
+}
+
+var _ AuthzUseCase = (*authzUseCase)(nil)
+
+type authzUseCase struct {
+	roleRepo     repo.RoleRepository
+	accessRepo   repo.AccessRepository
+	activityRepo repo.ActivityRepository
+	enforcer     *rbac.CasbinClient
+}
+
+func NewAuthzUseCase(roleRepo repo.RoleRepository, accessRepo repo.AccessRepository, activityRepo repo.ActivityRepository, enforcer *rbac.CasbinClient) AuthzUseCase {
+	return &authzUseCase{
+		roleRepo:     roleRepo,
+		accessRepo:   accessRepo,
+		activityRepo: activityRepo,
+		enforcer:     enforcer,
+	}
+}
+
+// Explain evaluates the request the same way middleware.RBAC does, including
+// the job attributes when a job is given, and reports which policy decided it.
+func (a *authzUseCase) Explain(ctx context.Context, check domain.AccessCheck) (*rbac.Explanation, error) {
+	if err := a.roleRepo.CheckMember(ctx, check.TeamID, check.UserID); err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return nil, ErrMemberNotFound
+		}
+
+		return nil, fmt.Errorf("check member: %w", err)
+	}
+
+	var res rbac.Resource
+
+	if check.JobID != "" {
+		access, err := a.accessRepo.GetJobAccess(ctx, check.UserID, check.JobID)
+		if err != nil {
+			if errors.Is(err, repo.ErrJobNotFound) {
+				return nil, ErrJobNotFound
+			}
+
+			return nil, fmt.Errorf("get job access: %w", err)
+		}
+
+		res = rbac.Resource{
+			TeamID:  access.TeamID,
+			JobID:   access.JobID,
+			Granted: access.Granted,
+		}
+	}
+
+	explanation, err := a.enforcer.Explain(check.UserID, check.TeamID, check.Path, check.Method, res)
+	if err != nil {
+		return nil, fmt.Errorf("explain: %w", err)
+	}
+
+	return explanation, nil
+}
+
+func (a *authzUseCase) ListDenials(ctx context.Context, teamID string, limit, offset int) ([]domain.Activity, error) {
+	activities, err := a.activityRepo.ListActivities(ctx, domain.ActivityFilter{
+		TeamID: teamID,
+		Action: domain.ActionAccessDenied,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list denials: %w", err)
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrMoveAnchorNotFound = errors.New("neighbour candidate is not in the target stage")
+
+// defaultStageLimit is the number of cards returned per stage when the
+// caller does not ask for another.
+const defaultStageLimit = 100
+
+type BoardUseCase interface {
+	GetBoard(ctx context.Context, teamID, jobID string, stageLimit int) (*domain.Board, error)
+	MoveCandidate(ctx context.Context, session domain.Session, move domain.CandidateMove) (*domain.Candidate, error)
+	ListStatusHistory(ctx context.Context, teamID, candidateID string) ([]domain.StatusChange, error)
+}
+
+var _ BoardUseCase = (*boardUseCase)(nil)
+
+type boardUseCase struct {
+	repo      repo.BoardRepository
+	pipelines repo.PipelineRepository
+}
+
+func NewBoardUseCase(repo repo.BoardRepository, pipelines repo.PipelineRepository) BoardUseCase {
+	return &boardUseCase{
+		repo:      repo,
+		pipelines: pipelines,
+	}
+}
+
+// GetBoard groups the job's candidates into stage columns: the stages of the
+// job pipeline first, in order, then any other stage in use by name.
+func (u *boardUseCase) GetBoard(ctx context.Context, teamID, jobID string, stageLimit int) (*domain.Board, error) {
+	if stageLimit <= 0 {
+		stageLimit = defaultStageLimit
+	}
+
+	pipeline, err := u.pipelines.GetJobPipeline(ctx, teamID, jobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("get job pipeline: %w", err)
+	}
+
+	cards, totals, err := u.repo.GetBoard(ctx, teamID, jobID, stageLimit)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("get board: %w", err)
+	}
+
+	board := &domain.Board{JobID: jobID}
+
+	for _, stage := range pipeline.Stages {
+		board.Stages = append(board.Stages, domain.BoardStage{Stage: stage.Name, Type: stage.Type})
+	}
+
+	var extra []string
+	for stage := range totals {
+		if _, ok := pipeline.Stages.Find(stage); !ok {
+			extra = append(extra, stage)
+		}
+	}
+
+	slices.Sort(extra)
+
+	for _, stage := range extra {
+		board.Stages = append(board.Stages, domain.BoardStage{Stage: stage})
+	}
+
+	index := make(map[string]int, len(board.Stages))
+	for i := range board.Stages {
+		column := &board.Stages[i]
+		column.Total = totals[column.Stage]
+		column.Candidates = []domain.BoardCard{}
+		index[column.Stage] = i
+	}
+
+	for _, card := range cards {
+		column := &board.Stages[index[card.Status]]
+		column.Candidates = append(column.Candidates, card)
+	}
+
+	return board, nil
+}
+
+// MoveCandidate moves the candidate to a stage of its job pipeline if the
+// pipeline allows the move from the candidate's current stage.
+func (u *boardUseCase) MoveCandidate(ctx context.Context, session domain.Session, move domain.CandidateMove) (*domain.Candidate, error) {
+	pipeline, err := u.pipelines.GetCandidatePipeline(ctx, session.TeamID, move.CandidateID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("get job pipeline: %w", err)
+	}
+
+	if _, ok := pipeline.Stages.Find(move.Status); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStage, move.Status)
+	}
+
+	move.Blocked = pipeline.Stages.BlockedSources(move.Status)
+
+	candidate, err := u.repo.MoveCandidate(ctx, session, move)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrCandidateNotFound):
+			return nil, ErrCandidateNotFound
+		case errors.Is(err, repo.ErrMoveAnchorNotFound):
+			return nil, ErrMoveAnchorNotFound
+		case errors.Is(err, repo.ErrTransitionNotAllowed):
+			return nil, fmt.Errorf("%w: cannot move to %q", ErrTransitionNotAllowed, move.Status)
+		default:
+			return nil, fmt.Errorf("move candidate: %w", err)
+		}
+	}
+
+	return candidate, nil
+}
+
+func (u *boardUseCase) ListStatusHistory(ctx context.Context, teamID, candidateID string) ([]domain.StatusChange, error) {
+	history, err := u.repo.ListStatusHistory(ctx, teamID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("list status history: %w", err)
+	}
+
+	return history, nil
+}
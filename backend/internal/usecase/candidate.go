@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/config"
+	"backend/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrCandidateNotFound  = errors.New("candidate not found")
+	ErrCandidateDuplicate = errors.New("candidate with this email already exists for the job")
+	ErrResumeNotFound     = errors.New("candidate has no resume")
+	ErrPresignUnsupported = errors.New("storage does not support direct download links")
+)
+
+const defaultPresignTTL = 15 * time.Minute
+
+type CandidateUseCase interface {
+	ListCandidates(ctx context.Context, filter domain.CandidateFilter) (*domain.CursorPage[domain.CandidateDetails], error)
+	GetCandidate(ctx context.Context, teamID, candidateID string) (*domain.CandidateDetails, error)
+	CreateCandidate(ctx context.Context, scope domain.JobScope, req domain.CandidateParams) (*domain.Candidate, error)
+	UpdateCandidate(ctx context.Context, session domain.Session, candidateID string, req domain.CandidateParams) (*domain.Candidate, error)
+	DeleteCandidate(ctx context.Context, session domain.Session, candidateID string) error
+	OpenResume(ctx context.Context, teamID, candidateID string) (*domain.Download, error)
+	ResumeURL(ctx context.Context, teamID, candidateID string) (*domain.SignedURL, error)
+}
+
+var _ CandidateUseCase = (*candidateUseCase)(nil)
+
+type candidateUseCase struct {
+	repo       repo.CandidateRepository
+	pipelines  repo.PipelineRepository
+	storage    storage.Storage
+	presignTTL time.Duration
+}
+
+func NewCandidateUseCase(cfg *config.Storage, repo repo.CandidateRepository, pipelines repo.PipelineRepository, storage storage.Storage) CandidateUseCase {
+	presignTTL := cfg.PresignTTL
+	if presignTTL == 0 {
+		presignTTL = defaultPresignTTL
+	}
+
+	return &candidateUseCase{
+		repo:       repo,
+		pipelines:  pipelines,
+		storage:    storage,
+		presignTTL: presignTTL,
+	}
+}
+
+func (u *candidateUseCase) ListCandidates(ctx context.Context, filter domain.CandidateFilter) (*domain.CursorPage[domain.CandidateDetails], error) {
+	candidates, err := u.repo.ListCandidates(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list candidates: %w", err)
+	}
+
+	page := &domain.CursorPage[domain.CandidateDetails]{
+		Items: candidates,
+		Limit: filter.Limit,
+	}
+
+	if len(candidates) > filter.Limit {
+		page.Items = candidates[:filter.Limit]
+
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	return page, nil
+}
+
+func (u *candidateUseCase) GetCandidate(ctx context.Context, teamID, candidateID string) (*domain.CandidateDetails, error) {
+	candidate, err := u.repo.GetCandidate(ctx, teamID, candidateID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("get candidate: %w", err)
+	}
+
+	return candidate, nil
+}
+
+// CreateCandidate adds a candidate to a stage of the job pipeline, the first
+// one unless req names another.
+func (u *candidateUseCase) CreateCandidate(ctx context.Context, scope domain.JobScope, req domain.CandidateParams) (*domain.Candidate, error) {
+	pipeline, err := u.pipelines.GetJobPipeline(ctx, scope.TeamID, req.JobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("get job pipeline: %w", err)
+	}
+
+	status := req.Status
+	if status == "" {
+		status = pipeline.Stages.First()
+	}
+
+	if _, ok := pipeline.Stages.Find(status); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStage, status)
+	}
+
+	candidate := &domain.Candidate{
+		JobID:     req.JobID,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Status:    status,
+		Source:    domain.CandidateSourceManual,
+	}
+
+	if err := u.repo.CreateCandidate(ctx, scope, candidate); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrJobNotFound):
+			return nil, ErrJobNotFound
+		case errors.Is(err, repo.ErrCandidateDuplicate):
+			return nil, ErrCandidateDuplicate
+		default:
+			return nil, fmt.Errorf("create candidate: %w", err)
+		}
+	}
+
+	return candidate, nil
+}
+
+func (u *candidateUseCase) UpdateCandidate(ctx context.Context, session domain.Session, candidateID string, req domain.CandidateParams) (*domain.Candidate, error) {
+	candidate := &domain.Candidate{
+		ID:        candidateID,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Phone:     req.Phone,
+	}
+
+	if err := u.repo.UpdateCandidate(ctx, session, candidate); err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("update candidate: %w", err)
+	}
+
+	return candidate, nil
+}
+
+// DeleteCandidate removes the candidate and then its resume file. The file is
+// removed on a best-effort basis: the candidate is already gone.
+func (u *candidateUseCase) DeleteCandidate(ctx context.Context, session domain.Session, candidateID string) error {
+	candidate, err := u.repo.DeleteCandidate(ctx, session, candidateID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return ErrCandidateNotFound
+		}
+
+		return fmt.Errorf("delete candidate: %w", err)
+	}
+
+	if candidate.ResumeFileKey != "" {
+		_ = u.storage.Delete(context.WithoutCancel(ctx), candidate.ResumeFileKey)
+	}
+
+	return nil
+}
+
+// OpenResume opens the candidate's resume for streaming. Keys outside the
+// team's prefix are treated as missing.
+func (u *candidateUseCase) OpenResume(ctx context.Context, teamID, candidateID string) (*domain.Download, error) {
+	candidate, err := u.resume(ctx, teamID, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, object, err := u.storage.Get(ctx, candidate.ResumeFileKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrResumeNotFound
+		}
+
+		return nil, fmt.Errorf("open resume: %w", err)
+	}
+
+	return &domain.Download{
+		Name:        candidate.ResumeFileName,
+		ContentType: object.ContentType,
+		Size:        object.Size,
+		Body:        body,
+	}, nil
+}
+
+// ResumeURL returns a presigned link to the candidate's resume for storage
+// drivers that support it.
+func (u *candidateUseCase) ResumeURL(ctx context.Context, teamID, candidateID string) (*domain.SignedURL, error) {
+	candidate, err := u.resume(ctx, teamID, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(u.presignTTL)
+
+	url, err := u.storage.PresignGet(ctx, candidate.ResumeFileKey, u.presignTTL)
+	if err != nil {
+		if errors.Is(err, storage.ErrUnsupported) {
+			return nil, ErrPresignUnsupported
+		}
+
+		return nil, fmt.Errorf("presign resume: %w", err)
+	}
+
+	return &domain.SignedURL{
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (u *candidateUseCase) resume(ctx context.Context, teamID, candidateID string) (*domain.CandidateDetails, error) {
+	candidate, err := u.GetCandidate(ctx, teamID, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate.ResumeFileKey == "" || !storage.InTeam(candidate.ResumeFileKey, teamID) {
+		return nil, ErrResumeNotFound
+	}
+
+	return candidate, nil
+}
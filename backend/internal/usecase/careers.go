@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"backend/internal/cache"
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/config"
+	"backend/pkg/feed"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+var ErrTeamNotFound = errors.New("team not found")
+
+const defaultCareersTTL = 5 * time.Minute
+
+type CareersUseCase interface {
+	GetBoard(ctx context.Context, slug string) (*domain.CareersBoard, error)
+	GetJob(ctx context.Context, slug, jobID string) (*domain.CareersBoard, *domain.CareersJob, error)
+	Feed(ctx context.Context, slug string) (*feed.Feed, error)
+	JobPosting(ctx context.Context, slug, jobID string) (*feed.JobPosting, error)
+	CacheTTL() time.Duration
+}
+
+var _ CareersUseCase = (*careersUseCase)(nil)
+
+type careersUseCase struct {
+	cfg          *config.Careers
+	teamRepo     repo.TeamRepository
+	jobRepo      repo.JobRepository
+	cacheManager *cache.Manager
+}
+
+func NewCareersUseCase(cfg *config.Careers, teamRepo repo.TeamRepository, jobRepo repo.JobRepository, cacheManager *cache.Manager) CareersUseCase {
+	return &careersUseCase{
+		cfg:          cfg,
+		teamRepo:     teamRepo,
+		jobRepo:      jobRepo,
+		cacheManager: cacheManager,
+	}
+}
+
+// CacheTTL is how long boards are cached, both here and by clients.
+func (c *careersUseCase) CacheTTL() time.Duration {
+	if c.cfg.CacheTTL == 0 {
+		return defaultCareersTTL
+	}
+
+	return c.cfg.CacheTTL
+}
+
+// GetBoard returns the open jobs of the team with the given slug. Boards are
+// cached by team ID so that job and team changes can drop them without
+// knowing the slug; cache failures fall back to the database.
+func (c *careersUseCase) GetBoard(ctx context.Context, slug string) (*domain.CareersBoard, error) {
+	ttl := c.CacheTTL()
+
+	teamID, err := cache.Get(ctx, c.cacheManager, cache.CareersSlugKey, slug)
+	if err == nil {
+		if board, err := cache.Get(ctx, c.cacheManager, cache.CareersKey, teamID); err == nil {
+			return &board, nil
+		}
+	}
+
+	team, err := c.teamRepo.GetTeamBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return nil, ErrTeamNotFound
+		}
+
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	jobs, err := c.jobRepo.ListOpenJobs(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list open jobs: %w", err)
+	}
+
+	board := domain.CareersBoard{
+		Team: domain.CareersTeam{
+			Name:     team.Name,
+			Slug:     team.Slug,
+			Locale:   team.Locale,
+			Branding: team.Branding,
+		},
+		URL:  c.boardURL(team.Slug),
+		Jobs: jobs,
+	}
+
+	for i := range board.Jobs {
+		board.Jobs[i].URL = c.jobURL(team.Slug, board.Jobs[i].ID)
+	}
+
+	_ = cache.SetWithTTL(ctx, c.cacheManager, cache.CareersSlugKey, slug, team.ID, ttl)
+	_ = cache.SetWithTTL(ctx, c.cacheManager, cache.CareersKey, team.ID, board, ttl)
+
+	return &board, nil
+}
+
+func (c *careersUseCase) GetJob(ctx context.Context, slug, jobID string) (*domain.CareersBoard, *domain.CareersJob, error) {
+	board, err := c.GetBoard(ctx, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range board.Jobs {
+		if board.Jobs[i].ID == jobID {
+			return board, &board.Jobs[i], nil
+		}
+	}
+
+	return nil, nil, ErrJobNotFound
+}
+
+func (c *careersUseCase) Feed(ctx context.Context, slug string) (*feed.Feed, error) {
+	board, err := c.GetBoard(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &feed.Feed{
+		Title:       board.Team.Name,
+		Link:        board.URL,
+		Description: board.Team.Branding.Tagline,
+		Language:    board.Team.Locale,
+		ImageURL:    board.Team.Branding.LogoURL,
+		Items:       make([]feed.Item, 0, len(board.Jobs)),
+	}
+
+	for _, j := range board.Jobs {
+		f.Items = append(f.Items, feed.Item{
+			ID:          "urn:uuid:" + j.ID,
+			Title:       j.Title,
+			Link:        j.URL,
+			Description: j.Description,
+			Category:    j.Department,
+			Published:   j.PublishedAt,
+			Updated:     j.UpdatedAt,
+		})
+
+		f.Updated = maxTime(f.Updated, j.UpdatedAt)
+	}
+
+	if f.Updated.IsZero() {
+		f.Updated = time.Now()
+	}
+
+	return f, nil
+}
+
+func (c *careersUseCase) JobPosting(ctx context.Context, slug, jobID string) (*feed.JobPosting, error) {
+	board, job, err := c.GetJob(ctx, slug, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	posting := feed.NewJobPosting(feed.JobPostingParams{
+		ID:          job.ID,
+		Title:       job.Title,
+		Description: job.Description,
+		Category:    job.Department,
+		URL:         job.URL,
+		Remote:      job.WorkFormat == domain.WorkFormatRemote,
+		DirectApply: true,
+		Published:   job.PublishedAt,
+		Org: feed.Organization{
+			Name:   board.Team.Name,
+			SameAs: board.Team.Branding.Website,
+			Logo:   board.Team.Branding.LogoURL,
+		},
+	})
+
+	return &posting, nil
+}
+
+func (c *careersUseCase) boardURL(slug string) string {
+	return c.cfg.BaseURL + "/" + url.PathEscape(slug)
+}
+
+func (c *careersUseCase) jobURL(slug, jobID string) string {
+	return c.boardURL(slug) + "/jobs/" + jobID
+}
+
+// invalidateCareers drops the cached careers board of a team. It is called
+// after any change that can alter the public job list.
+func invalidateCareers(ctx context.Context, cacheManager *cache.Manager, teamID string) {
+	_ = cache.Delete(ctx, cacheManager, cache.CareersKey, teamID)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("only the author can change a comment")
+	ErrMentionNotMember = errors.New("mentioned user is not a member of the team")
+	ErrMentionNoAccess  = errors.New("mentioned user cannot see the candidate")
+	ErrPrivateMention   = errors.New("private notes cannot mention members")
+)
+
+type CommentUseCase interface {
+	ListComments(ctx context.Context, filter domain.CommentFilter) (*domain.Page[domain.Comment], error)
+	CreateComment(ctx context.Context, scope domain.JobScope, params domain.CommentParams) (*domain.Comment, error)
+	UpdateComment(ctx context.Context, scope domain.JobScope, commentID string, update domain.CommentUpdate) (*domain.Comment, error)
+	DeleteComment(ctx context.Context, scope domain.JobScope, commentID string) error
+	ListRevisions(ctx context.Context, scope domain.JobScope, commentID string) ([]domain.CommentRevision, error)
+}
+
+var _ CommentUseCase = (*commentUseCase)(nil)
+
+type commentUseCase struct {
+	repo     repo.CommentRepository
+	enforcer *rbac.CasbinClient
+}
+
+func NewCommentUseCase(repo repo.CommentRepository, enforcer *rbac.CasbinClient) CommentUseCase {
+	return &commentUseCase{
+		repo:     repo,
+		enforcer: enforcer,
+	}
+}
+
+func (u *commentUseCase) ListComments(ctx context.Context, filter domain.CommentFilter) (*domain.Page[domain.Comment], error) {
+	comments, total, err := u.repo.ListComments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+
+	return &domain.Page[domain.Comment]{
+		Items:  comments,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// CreateComment adds a comment. Mentioned members must see the candidate,
+// through a grant on its job or the jobs:all permission; the author of the
+// comment replied to is only notified while they still do.
+func (u *commentUseCase) CreateComment(ctx context.Context, scope domain.JobScope, params domain.CommentParams) (*domain.Comment, error) {
+	params.Mentions = uniqueIDs(params.Mentions)
+
+	notified := params.Mentions
+
+	if params.ParentID != "" {
+		parent, err := u.repo.GetComment(ctx, scope, params.ParentID)
+		if err != nil {
+			return nil, commentError("get parent comment", err)
+		}
+
+		if parent.AuthorID != nil {
+			notified = append(slices.Clone(notified), *parent.AuthorID)
+		}
+	}
+
+	allJobs, err := withAllJobs(u.enforcer, scope.TeamID, notified)
+	if err != nil {
+		return nil, err
+	}
+
+	commentID, err := u.repo.CreateComment(ctx, scope, params, allJobs)
+	if err != nil {
+		return nil, commentError("create comment", err)
+	}
+
+	comment, err := u.repo.GetComment(ctx, scope, commentID)
+	if err != nil {
+		return nil, commentError("get comment", err)
+	}
+
+	return comment, nil
+}
+
+func (u *commentUseCase) UpdateComment(ctx context.Context, scope domain.JobScope, commentID string, update domain.CommentUpdate) (*domain.Comment, error) {
+	update.Mentions = uniqueIDs(update.Mentions)
+
+	allJobs, err := withAllJobs(u.enforcer, scope.TeamID, update.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.UpdateComment(ctx, scope, commentID, update, allJobs); err != nil {
+		return nil, commentError("update comment", err)
+	}
+
+	comment, err := u.repo.GetComment(ctx, scope, commentID)
+	if err != nil {
+		return nil, commentError("get comment", err)
+	}
+
+	return comment, nil
+}
+
+func (u *commentUseCase) DeleteComment(ctx context.Context, scope domain.JobScope, commentID string) error {
+	if err := u.repo.DeleteComment(ctx, scope, commentID); err != nil {
+		return commentError("delete comment", err)
+	}
+
+	return nil
+}
+
+func (u *commentUseCase) ListRevisions(ctx context.Context, scope domain.JobScope, commentID string) ([]domain.CommentRevision, error) {
+	revisions, err := u.repo.ListRevisions(ctx, scope, commentID)
+	if err != nil {
+		return nil, commentError("list revisions", err)
+	}
+
+	return revisions, nil
+}
+
+// commentError maps repository errors of the comment routes to use case
+// errors.
+func commentError(op string, err error) error {
+	switch {
+	case errors.Is(err, repo.ErrCandidateNotFound):
+		return ErrCandidateNotFound
+	case errors.Is(err, repo.ErrCommentNotFound):
+		return ErrCommentNotFound
+	case errors.Is(err, repo.ErrCommentForbidden):
+		return ErrCommentForbidden
+	case errors.Is(err, repo.ErrMentionNotMember):
+		return ErrMentionNotMember
+	case errors.Is(err, repo.ErrMentionNoAccess):
+		return ErrMentionNoAccess
+	case errors.Is(err, repo.ErrPrivateMention):
+		return ErrPrivateMention
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// uniqueIDs returns ids sorted and without repetitions.
+func uniqueIDs(ids []string) []string {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+
+	return slices.Compact(ids)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDuplicateNotFound = errors.New("duplicate pair not found")
+	ErrMergeSelf         = errors.New("candidate cannot be merged into itself")
+)
+
+// candidateDuplicatesLimit caps the pairs returned for a single candidate.
+const candidateDuplicatesLimit = 100
+
+type DuplicateUseCase interface {
+	ListDuplicates(ctx context.Context, filter domain.DuplicateFilter) (*domain.Page[domain.Duplicate], error)
+	DetectDuplicates(ctx context.Context, scope domain.JobScope, candidateID string) ([]domain.Duplicate, error)
+	DismissDuplicate(ctx context.Context, scope domain.JobScope, duplicateID string) error
+	MergeCandidates(ctx context.Context, scope domain.JobScope, targetID, sourceID string) (*domain.CandidateDetails, error)
+	ListMerges(ctx context.Context, teamID, candidateID string) ([]domain.CandidateMerge, error)
+}
+
+var _ DuplicateUseCase = (*duplicateUseCase)(nil)
+
+type duplicateUseCase struct {
+	repo       repo.DuplicateRepository
+	candidates repo.CandidateRepository
+	storage    storage.Storage
+}
+
+func NewDuplicateUseCase(repo repo.DuplicateRepository, candidates repo.CandidateRepository, storage storage.Storage) DuplicateUseCase {
+	return &duplicateUseCase{
+		repo:       repo,
+		candidates: candidates,
+		storage:    storage,
+	}
+}
+
+func (u *duplicateUseCase) ListDuplicates(ctx context.Context, filter domain.DuplicateFilter) (*domain.Page[domain.Duplicate], error) {
+	duplicates, total, err := u.repo.ListDuplicates(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list duplicates: %w", err)
+	}
+
+	return &domain.Page[domain.Duplicate]{
+		Items:  duplicates,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// DetectDuplicates runs detection for the candidate again and returns its
+// pending pairs within scope.
+func (u *duplicateUseCase) DetectDuplicates(ctx context.Context, scope domain.JobScope, candidateID string) ([]domain.Duplicate, error) {
+	if _, err := u.repo.DetectDuplicates(ctx, scope.TeamID, candidateID); err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("detect duplicates: %w", err)
+	}
+
+	duplicates, _, err := u.repo.ListDuplicates(ctx, domain.DuplicateFilter{
+		Scope:       scope,
+		Status:      domain.DuplicatePending,
+		CandidateID: candidateID,
+		Limit:       candidateDuplicatesLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list duplicates: %w", err)
+	}
+
+	return duplicates, nil
+}
+
+func (u *duplicateUseCase) DismissDuplicate(ctx context.Context, scope domain.JobScope, duplicateID string) error {
+	if err := u.repo.DismissDuplicate(ctx, scope, duplicateID); err != nil {
+		if errors.Is(err, repo.ErrDuplicateNotFound) {
+			return ErrDuplicateNotFound
+		}
+
+		return fmt.Errorf("dismiss duplicate: %w", err)
+	}
+
+	return nil
+}
+
+// MergeCandidates folds the source candidate into the target and returns the
+// target as it is after the merge. The source resume file is removed on a
+// best-effort basis unless the target took it over.
+func (u *duplicateUseCase) MergeCandidates(ctx context.Context, scope domain.JobScope, targetID, sourceID string) (*domain.CandidateDetails, error) {
+	if targetID == sourceID {
+		return nil, ErrMergeSelf
+	}
+
+	source, err := u.repo.MergeCandidates(ctx, scope, targetID, sourceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("merge candidates: %w", err)
+	}
+
+	target, err := u.candidates.GetCandidate(ctx, scope.TeamID, targetID)
+	if err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("get candidate: %w", err)
+	}
+
+	if source.ResumeFileKey != "" && source.ResumeFileKey != target.ResumeFileKey {
+		_ = u.storage.Delete(context.WithoutCancel(ctx), source.ResumeFileKey)
+	}
+
+	return target, nil
+}
+
+func (u *duplicateUseCase) ListMerges(ctx context.Context, teamID, candidateID string) ([]domain.CandidateMerge, error) {
+	merges, err := u.repo.ListMerges(ctx, teamID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("list merges: %w", err)
+	}
+
+	return merges, nil
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/realtime"
+	"backend/internal/repo"
+	"backend/pkg/config"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultAccessCheck = time.Minute
+	defaultMaxLifetime = time.Hour
+)
+
+type EventsUseCase interface {
+	Subscribe(ctx context.Context, check domain.AccessCheck) (<-chan domain.BoardEvent, error)
+}
+
+var _ EventsUseCase = (*eventsUseCase)(nil)
+
+type eventsUseCase struct {
+	hub         *realtime.Hub
+	accessRepo  repo.AccessRepository
+	enforcer    *rbac.CasbinClient
+	accessCheck time.Duration
+	maxLifetime time.Duration
+}
+
+func NewEventsUseCase(cfg *config.Realtime, hub *realtime.Hub, accessRepo repo.AccessRepository, enforcer *rbac.CasbinClient) EventsUseCase {
+	u := &eventsUseCase{
+		hub:         hub,
+		accessRepo:  accessRepo,
+		enforcer:    enforcer,
+		accessCheck: cfg.AccessCheck,
+		maxLifetime: cfg.MaxLifetime,
+	}
+
+	if u.accessCheck <= 0 {
+		u.accessCheck = defaultAccessCheck
+	}
+
+	if u.maxLifetime <= 0 {
+		u.maxLifetime = defaultMaxLifetime
+	}
+
+	return u
+}
+
+// Subscribe streams the events of check.JobID for as long as the user may
+// make the request described by check. Access is evaluated like
+// middleware.RBAC does, again whenever one of the job's grants changes and
+// periodically for role changes. The channel is closed when ctx ends, when
+// access is lost, when the client falls behind and after the configured
+// lifetime; clients reconnect and reload the board.
+func (u *eventsUseCase) Subscribe(ctx context.Context, check domain.AccessCheck) (<-chan domain.BoardEvent, error) {
+	ok, err := u.authorize(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	sub := u.hub.Subscribe(check.JobID)
+	events := make(chan domain.BoardEvent)
+
+	go func() {
+		defer close(events)
+		defer sub.Close()
+
+		ticker := time.NewTicker(u.accessCheck)
+		defer ticker.Stop()
+
+		lifetime := time.NewTimer(u.maxLifetime)
+		defer lifetime.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Done():
+				return
+			case <-lifetime.C:
+				return
+			case <-ticker.C:
+				if !u.stillAllowed(ctx, check) {
+					return
+				}
+			case event := <-sub.Events():
+				if event.Type == domain.EventAccessChanged {
+					if event.UserID == check.UserID && !u.stillAllowed(ctx, check) {
+						return
+					}
+
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// stillAllowed re-checks access. A failed check keeps the stream open; the
+// next one may succeed.
+func (u *eventsUseCase) stillAllowed(ctx context.Context, check domain.AccessCheck) bool {
+	ok, err := u.authorize(ctx, check)
+
+	return ok || err != nil
+}
+
+func (u *eventsUseCase) authorize(ctx context.Context, check domain.AccessCheck) (bool, error) {
+	access, err := u.accessRepo.GetJobAccess(ctx, check.UserID, check.JobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("get job access: %w", err)
+	}
+
+	ok, err := u.enforcer.Enforce(check.UserID, check.TeamID, check.Path, check.Method, rbac.Resource{
+		TeamID:  access.TeamID,
+		JobID:   access.JobID,
+		Granted: access.Granted,
+	})
+	if err != nil {
+		return false, fmt.Errorf("enforce: %w", err)
+	}
+
+	return ok, nil
+}
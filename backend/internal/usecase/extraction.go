@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/config"
+	"backend/pkg/extract"
+	"backend/pkg/ocr"
+	"backend/pkg/profile"
+	"backend/pkg/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	errNoResumeFile = errors.New("candidate has no resume file")
+	errNoText       = errors.New("no text found in the document")
+	errOCRDisabled  = errors.New("document is a scan and OCR is disabled")
+)
+
+const (
+	defaultTaskLease   = 5 * time.Minute
+	defaultMaxAttempts = 3
+	// defaultMaxResumeSize bounds what the worker reads when the storage
+	// has no upload limit configured.
+	defaultMaxResumeSize = 32 << 20
+	defaultOCRMaxPages   = 10
+)
+
+// ExtractionUseCase turns uploaded resumes into hiring.t_candidates.parsed_text
+// and the text into a structured profile in hiring.t_candidate_profiles.
+type ExtractionUseCase interface {
+	// ProcessNext runs one stage of a waiting resume task. It reports false
+	// when the queue is empty. Failures of the document itself are recorded
+	// on the task and are not returned.
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+var _ ExtractionUseCase = (*extractionUseCase)(nil)
+
+type extractionUseCase struct {
+	repo        repo.TaskRepository
+	storage     storage.Storage
+	ocr         ocr.Engine
+	extractor   profile.Extractor
+	lease       time.Duration
+	maxAttempts int
+	maxFileSize int64
+	ocrMaxPages int
+}
+
+// NewExtractionUseCase creates the use case; a nil engine disables OCR of
+// scanned documents.
+func NewExtractionUseCase(
+	cfg *config.Config,
+	repo repo.TaskRepository,
+	storage storage.Storage,
+	engine ocr.Engine,
+	extractor profile.Extractor,
+) ExtractionUseCase {
+	u := &extractionUseCase{
+		repo:        repo,
+		storage:     storage,
+		ocr:         engine,
+		extractor:   extractor,
+		lease:       cfg.Worker.Lease,
+		maxAttempts: cfg.Worker.MaxAttempts,
+		maxFileSize: cfg.Storage.MaxFileSize,
+		ocrMaxPages: cfg.OCR.MaxPages,
+	}
+
+	if u.lease <= 0 {
+		u.lease = defaultTaskLease
+	}
+
+	if u.maxAttempts <= 0 {
+		u.maxAttempts = defaultMaxAttempts
+	}
+
+	if u.maxFileSize <= 0 {
+		u.maxFileSize = defaultMaxResumeSize
+	}
+
+	if u.ocrMaxPages <= 0 {
+		u.ocrMaxPages = defaultOCRMaxPages
+	}
+
+	return u
+}
+
+func (u *extractionUseCase) ProcessNext(ctx context.Context) (bool, error) {
+	task, err := u.repo.ClaimResumeTask(ctx, u.lease)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoTask) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("claim task: %w", err)
+	}
+
+	// A task reclaimed after its lease expired may have crashed the worker
+	// on every attempt.
+	if task.Attempts > u.maxAttempts {
+		return true, u.fail(ctx, task, errors.New("too many attempts"), false)
+	}
+
+	if task.Stage == domain.TaskAnalyzing {
+		return true, u.analyze(ctx, task)
+	}
+
+	result, err := u.extract(ctx, task)
+	if err != nil {
+		return true, u.fail(ctx, task, err, !permanentExtractionError(err) && task.Attempts < u.maxAttempts)
+	}
+
+	if err := u.repo.CompleteExtraction(context.WithoutCancel(ctx), task, result); err != nil {
+		return true, fmt.Errorf("complete task: %w", err)
+	}
+
+	return true, nil
+}
+
+// analyze runs the profile stage: the extractor output is checked against
+// the profile schema before it is stored. A model may return an invalid
+// document once and a valid one on the next attempt, so schema violations
+// are retried.
+func (u *extractionUseCase) analyze(ctx context.Context, task *domain.ResumeTask) error {
+	if task.Text == "" {
+		return u.fail(ctx, task, errNoText, false)
+	}
+
+	data, err := u.extractor.Extract(ctx, task.Text)
+	if err != nil {
+		return u.fail(ctx, task, fmt.Errorf("extract profile: %w", err),
+			!errors.Is(err, profile.ErrRejected) && task.Attempts < u.maxAttempts)
+	}
+
+	p, err := profile.Parse(data)
+	if err != nil {
+		return u.fail(ctx, task, err, task.Attempts < u.maxAttempts)
+	}
+
+	data, err = json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal profile: %w", err)
+	}
+
+	if err := u.repo.CompleteProfile(context.WithoutCancel(ctx), task, &domain.ExtractedProfile{
+		Data:          data,
+		SchemaVersion: p.SchemaVersion,
+		Provider:      u.extractor.Name(),
+		Contacts:      contacts(p.Contacts),
+	}); err != nil {
+		return fmt.Errorf("complete task: %w", err)
+	}
+
+	return nil
+}
+
+// contacts splits the full name of a resume into the first word and the
+// rest.
+func contacts(c profile.Contacts) domain.CandidateContacts {
+	var first, last string
+	if name := strings.Fields(c.FullName); len(name) > 0 {
+		first = name[0]
+		last = strings.Join(name[1:], " ")
+	}
+
+	return domain.CandidateContacts{
+		FirstName: first,
+		LastName:  last,
+		Email:     strings.ToLower(c.Email),
+		Phone:     c.Phone,
+	}
+}
+
+func (u *extractionUseCase) extract(ctx context.Context, task *domain.ResumeTask) (*domain.ResumeText, error) {
+	if task.FileKey == "" {
+		return nil, errNoResumeFile
+	}
+
+	body, _, err := u.storage.Get(ctx, task.FileKey)
+	if err != nil {
+		return nil, fmt.Errorf("open resume: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, u.maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read resume: %w", err)
+	}
+
+	if int64(len(data)) > u.maxFileSize {
+		return nil, extract.ErrTooLarge
+	}
+
+	result, err := extract.Extract(data, task.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// A scan may still carry a little text, e.g. a page number or a name
+	// added by the scanner software; it is used when OCR gives nothing.
+	if result.ImageOnly {
+		recognized, err := u.recognize(ctx, task, data)
+		if err == nil || result.Text == "" || !(errors.Is(err, errNoText) || errors.Is(err, errOCRDisabled)) {
+			return recognized, err
+		}
+	}
+
+	if result.Text == "" {
+		return nil, errNoText
+	}
+
+	return &domain.ResumeText{
+		Text:     result.Text,
+		Language: result.Language,
+	}, nil
+}
+
+// recognize runs OCR over the page images of a scanned document. Each page
+// renews the task lease, so the lease only has to cover a single page.
+func (u *extractionUseCase) recognize(ctx context.Context, task *domain.ResumeTask, data []byte) (*domain.ResumeText, error) {
+	if u.ocr == nil {
+		return nil, errOCRDisabled
+	}
+
+	images, err := extract.PageImages(data, u.ocrMaxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(images) == 0 {
+		return nil, errNoText
+	}
+
+	var (
+		texts []string
+		pages []domain.OCRPage
+	)
+
+	for i, img := range images {
+		page, err := u.ocr.Recognize(ctx, img.Data)
+		if err != nil {
+			return nil, fmt.Errorf("recognize page %d: %w", img.Page, err)
+		}
+
+		text := extract.Normalize(page.Text)
+		if text != "" {
+			texts = append(texts, text)
+		}
+
+		pages = append(pages, domain.OCRPage{
+			Page:       img.Page,
+			Engine:     u.ocr.Name(),
+			Confidence: page.Confidence,
+			Chars:      utf8.RuneCountInString(text),
+		})
+
+		if err := u.repo.TouchTask(ctx, task.TaskID, 10+40*(i+1)/len(images), u.lease); err != nil {
+			return nil, fmt.Errorf("renew task lease: %w", err)
+		}
+	}
+
+	text := strings.Join(texts, "\n\n")
+	if text == "" {
+		return nil, errNoText
+	}
+
+	return &domain.ResumeText{
+		Text:     text,
+		Language: extract.DetectLanguage(text),
+		OCRPages: pages,
+	}, nil
+}
+
+// fail records err on the task. The error is stored even if ctx is already
+// cancelled so that a shutdown does not leave the reason unrecorded.
+func (u *extractionUseCase) fail(ctx context.Context, task *domain.ResumeTask, cause error, retry bool) error {
+	if err := u.repo.FailTask(context.WithoutCancel(ctx), task.TaskID, cause.Error(), retry); err != nil {
+		return fmt.Errorf("fail task: %w", err)
+	}
+
+	return nil
+}
+
+// permanentExtractionError reports whether retrying cannot help: the
+// document is missing, unreadable or contains no text.
+func permanentExtractionError(err error) bool {
+	for _, target := range []error{
+		errNoResumeFile,
+		errNoText,
+		errOCRDisabled,
+		ocr.ErrUnavailable,
+		storage.ErrNotFound,
+		storage.ErrInvalidKey,
+		extract.ErrUnsupportedFormat,
+		extract.ErrEncrypted,
+		extract.ErrCorrupted,
+		extract.ErrTooLarge,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,831 @@
+package usecase
+
+import (
+	"archive/zip"
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/config"
+	"backend/pkg/storage"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrImportNotFound    = errors.New("import not found")
+	ErrImportNoFiles     = errors.New("a CSV file or a ZIP archive of resumes is required")
+	ErrUnsupportedImport = errors.New("unsupported import file")
+	ErrImportTooLarge    = errors.New("import file is too large")
+
+	errImportJobRemoved  = errors.New("job of the import no longer exists")
+	errImportTooManyRows = errors.New("import has too many rows")
+	errImportCSVHeader   = errors.New("CSV header names none of the columns first_name, last_name, name, email, phone, resume")
+	errImportCSV         = errors.New("CSV file cannot be read")
+	errImportArchive     = errors.New("ZIP archive cannot be read")
+)
+
+const (
+	defaultImportMaxRows        = 5000
+	defaultImportMaxArchiveSize = 512 << 20
+	// maxImportCSVSize bounds the CSV file, which the worker reads into
+	// memory.
+	maxImportCSVSize = 16 << 20
+	// importTouchEvery is the number of rows between progress reports.
+	importTouchEvery = 20
+)
+
+// importColumns maps normalized CSV header names to the fields of a row.
+var importColumns = map[string]string{
+	"first_name":   "first_name",
+	"firstname":    "first_name",
+	"given_name":   "first_name",
+	"last_name":    "last_name",
+	"lastname":     "last_name",
+	"surname":      "last_name",
+	"family_name":  "last_name",
+	"name":         "name",
+	"full_name":    "name",
+	"email":        "email",
+	"e_mail":       "email",
+	"phone":        "phone",
+	"phone_number": "phone",
+	"mobile":       "phone",
+	"resume":       "resume",
+	"resume_file":  "resume",
+	"cv":           "resume",
+	"file":         "resume",
+	"stage":        "stage",
+	"status":       "stage",
+}
+
+var importPhonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ().\-]{5,30}$`)
+
+// ImportUseCase imports candidates in bulk from a CSV of candidate fields
+// and/or a ZIP archive of resumes. Imports run in the background.
+type ImportUseCase interface {
+	StartImport(ctx context.Context, scope domain.JobScope, params domain.ImportParams, csvFile, archive *domain.Upload) (*domain.CandidateImport, error)
+	ListImports(ctx context.Context, filter domain.ImportFilter) (*domain.Page[domain.CandidateImport], error)
+	GetImport(ctx context.Context, scope domain.JobScope, importID string) (*domain.CandidateImport, error)
+	ListImportRows(ctx context.Context, filter domain.ImportRowFilter) (*domain.Page[domain.ImportRow], error)
+	ImportReport(ctx context.Context, scope domain.JobScope, importID string) (*domain.Download, error)
+	// ProcessNext runs a waiting import to the end. It reports false when
+	// the queue is empty. Failures of the files themselves are recorded on
+	// the task and are not returned.
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+var _ ImportUseCase = (*importUseCase)(nil)
+
+type importUseCase struct {
+	repo           repo.ImportRepository
+	pipelines      repo.PipelineRepository
+	storage        storage.Storage
+	archives       storage.Storage
+	lease          time.Duration
+	maxAttempts    int
+	maxRows        int
+	maxArchiveSize int64
+	maxFileSize    int64
+}
+
+func NewImportUseCase(cfg *config.Config, repo repo.ImportRepository, pipelines repo.PipelineRepository, files storage.Storage) ImportUseCase {
+	u := &importUseCase{
+		repo:           repo,
+		pipelines:      pipelines,
+		storage:        files,
+		lease:          cfg.Worker.Lease,
+		maxAttempts:    cfg.Worker.MaxAttempts,
+		maxRows:        cfg.Import.MaxRows,
+		maxArchiveSize: cfg.Import.MaxArchiveSize,
+		maxFileSize:    cfg.Storage.MaxFileSize,
+	}
+
+	if u.lease <= 0 {
+		u.lease = defaultTaskLease
+	}
+
+	if u.maxAttempts <= 0 {
+		u.maxAttempts = defaultMaxAttempts
+	}
+
+	if u.maxRows <= 0 {
+		u.maxRows = defaultImportMaxRows
+	}
+
+	if u.maxArchiveSize <= 0 {
+		u.maxArchiveSize = defaultImportMaxArchiveSize
+	}
+
+	if u.maxFileSize <= 0 {
+		u.maxFileSize = defaultMaxResumeSize
+	}
+
+	// Archives hold many resumes and have a limit of their own.
+	u.archives = storage.WithLimit(files, u.maxArchiveSize)
+
+	return u
+}
+
+// StartImport stores the files under the team's key prefix and queues the
+// import of the job. The files are removed again if the import cannot be
+// created.
+func (u *importUseCase) StartImport(ctx context.Context, scope domain.JobScope, params domain.ImportParams, csvFile, archive *domain.Upload) (*domain.CandidateImport, error) {
+	if csvFile == nil && archive == nil {
+		return nil, ErrImportNoFiles
+	}
+
+	pipeline, err := u.pipelines.GetJobPipeline(ctx, scope.TeamID, params.JobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("get job pipeline: %w", err)
+	}
+
+	if params.Stage != "" {
+		if _, ok := pipeline.Stages.Find(params.Stage); !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownStage, params.Stage)
+		}
+	}
+
+	imp := &domain.CandidateImport{
+		ID:              uuid.NewString(),
+		JobID:           params.JobID,
+		DuplicatePolicy: params.DuplicatePolicy,
+		Stage:           params.Stage,
+		CreatedBy:       &scope.UserID,
+	}
+
+	if csvFile != nil {
+		imp.CSVFileName = resumeFileName(csvFile.Name)
+		imp.CSVFileKey = storage.TeamKey(scope.TeamID, "imports", imp.ID, "candidates.csv")
+
+		if err := u.upload(ctx, u.storage, imp.CSVFileKey, csvFile, ".csv", "text/csv", "text/plain", "text/csv"); err != nil {
+			return nil, err
+		}
+	}
+
+	if archive != nil {
+		imp.ArchiveFileName = resumeFileName(archive.Name)
+		imp.ArchiveFileKey = storage.TeamKey(scope.TeamID, "imports", imp.ID, "resumes.zip")
+
+		if err := u.upload(ctx, u.archives, imp.ArchiveFileKey, archive, ".zip", "application/zip", "application/zip"); err != nil {
+			u.removeFiles(ctx, imp)
+			return nil, err
+		}
+	}
+
+	if err := u.repo.CreateImport(ctx, scope, imp); err != nil {
+		u.removeFiles(ctx, imp)
+
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("create import: %w", err)
+	}
+
+	return imp, nil
+}
+
+// upload stores an import file after checking its extension and content.
+func (u *importUseCase) upload(ctx context.Context, files storage.Storage, key string, file *domain.Upload, ext, contentType string, sniffed ...string) error {
+	if strings.ToLower(path.Ext(file.Name)) != ext {
+		return fmt.Errorf("%w: %q is not a %s file", ErrUnsupportedImport, file.Name, ext)
+	}
+
+	mtype, body, err := storage.Sniff(file.Body)
+	if err != nil {
+		return fmt.Errorf("read import file: %w", err)
+	}
+
+	if !storage.SniffMatches(mtype, sniffed...) {
+		return fmt.Errorf("%w: %s content in a %s file", ErrUnsupportedImport, mtype.String(), ext)
+	}
+
+	if err := files.Put(ctx, key, body, file.Size, contentType); err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return ErrImportTooLarge
+		}
+
+		return fmt.Errorf("store import file: %w", err)
+	}
+
+	return nil
+}
+
+func (u *importUseCase) removeFiles(ctx context.Context, imp *domain.CandidateImport) {
+	for _, key := range []string{imp.CSVFileKey, imp.ArchiveFileKey} {
+		if key != "" {
+			_ = u.storage.Delete(context.WithoutCancel(ctx), key)
+		}
+	}
+}
+
+func (u *importUseCase) ListImports(ctx context.Context, filter domain.ImportFilter) (*domain.Page[domain.CandidateImport], error) {
+	imports, total, err := u.repo.ListImports(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list imports: %w", err)
+	}
+
+	return &domain.Page[domain.CandidateImport]{
+		Items:  imports,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+func (u *importUseCase) GetImport(ctx context.Context, scope domain.JobScope, importID string) (*domain.CandidateImport, error) {
+	imp, err := u.repo.GetImport(ctx, scope, importID)
+	if err != nil {
+		if errors.Is(err, repo.ErrImportNotFound) {
+			return nil, ErrImportNotFound
+		}
+
+		return nil, fmt.Errorf("get import: %w", err)
+	}
+
+	return imp, nil
+}
+
+func (u *importUseCase) ListImportRows(ctx context.Context, filter domain.ImportRowFilter) (*domain.Page[domain.ImportRow], error) {
+	rows, total, err := u.repo.ListImportRows(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repo.ErrImportNotFound) {
+			return nil, ErrImportNotFound
+		}
+
+		return nil, fmt.Errorf("list import rows: %w", err)
+	}
+
+	return &domain.Page[domain.ImportRow]{
+		Items:  rows,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// ImportReport renders the outcome of every row processed so far as CSV.
+// The file starts with a byte order mark so that spreadsheet applications
+// read it as UTF-8.
+func (u *importUseCase) ImportReport(ctx context.Context, scope domain.JobScope, importID string) (*domain.Download, error) {
+	imp, err := u.GetImport(ctx, scope, importID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.ListImportRows(ctx, domain.ImportRowFilter{Scope: scope, ImportID: importID})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\uFEFF")
+
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"row", "file", "first_name", "last_name", "email", "phone", "outcome", "candidate_id", "message"})
+
+	for _, row := range rows.Items {
+		var candidateID string
+		if row.CandidateID != nil {
+			candidateID = *row.CandidateID
+		}
+
+		_ = w.Write([]string{
+			strconv.Itoa(row.Row), row.FileName, row.FirstName, row.LastName, row.Email, row.Phone,
+			row.Outcome, candidateID, row.Message,
+		})
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("write report: %w", err)
+	}
+
+	return &domain.Download{
+		Name:        fmt.Sprintf("import-%s-%s.csv", imp.CreatedAt.Format("2006-01-02"), imp.ID[:8]),
+		ContentType: "text/csv; charset=utf-8",
+		Size:        int64(buf.Len()),
+		Body:        io.NopCloser(&buf),
+	}, nil
+}
+
+func (u *importUseCase) ProcessNext(ctx context.Context) (bool, error) {
+	task, err := u.repo.ClaimImportTask(ctx, u.lease)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoTask) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("claim task: %w", err)
+	}
+
+	if task.Attempts > u.maxAttempts {
+		return true, u.fail(ctx, task, errors.New("too many attempts"), false)
+	}
+
+	if err := u.run(ctx, task); err != nil {
+		return true, u.fail(ctx, task, err, !permanentImportError(err) && task.Attempts < u.maxAttempts)
+	}
+
+	if err := u.repo.CompleteImport(context.WithoutCancel(ctx), task); err != nil {
+		return true, fmt.Errorf("complete task: %w", err)
+	}
+
+	return true, nil
+}
+
+// run imports the rows after the last one recorded by an earlier attempt.
+func (u *importUseCase) run(ctx context.Context, task *domain.ImportTask) error {
+	pipeline, err := u.pipelines.GetJobPipeline(ctx, task.TeamID, task.JobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return errImportJobRemoved
+		}
+
+		return fmt.Errorf("get job pipeline: %w", err)
+	}
+
+	source, err := u.open(ctx, task)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	if len(source.entries) > u.maxRows {
+		return fmt.Errorf("%w: %d, at most %d are allowed", errImportTooManyRows, len(source.entries), u.maxRows)
+	}
+
+	if task.TotalRows == nil {
+		if err := u.repo.SetImportTotal(ctx, task.ImportID, len(source.entries)); err != nil {
+			return fmt.Errorf("set import total: %w", err)
+		}
+	}
+
+	for i := range source.entries {
+		entry := &source.entries[i]
+		if entry.Row <= task.Done {
+			continue
+		}
+
+		if err := u.importRow(ctx, task, pipeline.Stages, source, entry); err != nil {
+			return err
+		}
+
+		if (i+1)%importTouchEvery == 0 {
+			if err := u.repo.TouchImport(ctx, task.TaskID, 100*(i+1)/len(source.entries), u.lease); err != nil {
+				return fmt.Errorf("renew task lease: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importRow validates a row, stores its resume and hands it to the repo.
+// Problems of the row are recorded as its outcome; only errors that should
+// stop the import are returned.
+func (u *importUseCase) importRow(ctx context.Context, task *domain.ImportTask, stages domain.Stages, source *importSource, entry *domain.ImportEntry) error {
+	if message := validateImportEntry(entry); message != "" {
+		return u.failRow(ctx, task, entry, message)
+	}
+
+	stage := entry.Stage
+	if stage == "" {
+		stage = task.Stage
+	}
+
+	if stage == "" {
+		entry.Stage = stages.First()
+	} else {
+		i := slices.IndexFunc(stages, func(s domain.Stage) bool { return strings.EqualFold(s.Name, stage) })
+		if i < 0 {
+			return u.failRow(ctx, task, entry, fmt.Sprintf("stage %q is not part of the job pipeline", stage))
+		}
+
+		entry.Stage = stages[i].Name
+	}
+
+	if entry.FileName != "" {
+		file := source.file(entry.FileName)
+		if file == nil {
+			return u.failRow(ctx, task, entry, fmt.Sprintf("resume %q is not in the archive", entry.FileName))
+		}
+
+		if err := u.storeResume(ctx, task, file, entry); err != nil {
+			if errors.Is(err, ErrUnsupportedFile) || errors.Is(err, ErrFileTooLarge) || errors.Is(err, errImportArchive) {
+				return u.failRow(ctx, task, entry, err.Error())
+			}
+
+			return err
+		}
+	}
+
+	attached, err := u.repo.ImportCandidate(ctx, task, entry)
+	if err != nil {
+		u.removeResume(ctx, entry)
+		return fmt.Errorf("import row %d: %w", entry.Row, err)
+	}
+
+	if !attached {
+		u.removeResume(ctx, entry)
+	}
+
+	return nil
+}
+
+func (u *importUseCase) failRow(ctx context.Context, task *domain.ImportTask, entry *domain.ImportEntry, message string) error {
+	row := entry.ImportRow
+	row.Outcome = domain.ImportRowFailed
+	row.Message = message
+
+	// The columns are sized for valid values; the report only needs enough
+	// to find the row.
+	row.FirstName = clip(row.FirstName, 100)
+	row.LastName = clip(row.LastName, 100)
+	row.Email = clip(row.Email, 254)
+	row.Phone = clip(row.Phone, 32)
+	row.FileName = clip(row.FileName, 255)
+
+	if err := u.repo.RecordImportRow(ctx, task.ImportID, &row); err != nil {
+		return fmt.Errorf("record row %d: %w", entry.Row, err)
+	}
+
+	return nil
+}
+
+// storeResume stores a resume from the archive under the job in the same
+// way as a public application.
+func (u *importUseCase) storeResume(ctx context.Context, task *domain.ImportTask, file *zip.File, entry *domain.ImportEntry) error {
+	name := resumeFileName(file.Name)
+	ext := strings.ToLower(path.Ext(name))
+
+	format, ok := resumeTypes[ext]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFile, ext)
+	}
+
+	if file.UncompressedSize64 > uint64(u.maxFileSize) {
+		return ErrFileTooLarge
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errImportArchive, err)
+	}
+	defer r.Close()
+
+	// The size in the archive header is not trusted.
+	data, err := io.ReadAll(io.LimitReader(r, u.maxFileSize+1))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errImportArchive, err)
+	}
+
+	if int64(len(data)) > u.maxFileSize {
+		return ErrFileTooLarge
+	}
+
+	sniffed, _, err := storage.Sniff(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("read resume: %w", err)
+	}
+
+	if !storage.SniffMatches(sniffed, format.sniffed...) {
+		return fmt.Errorf("%w: %s content in a %s file", ErrUnsupportedFile, sniffed.String(), ext)
+	}
+
+	key := storage.TeamKey(task.TeamID, "resumes", task.JobID, uuid.NewString()+ext)
+
+	if err := u.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), format.contentType); err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return ErrFileTooLarge
+		}
+
+		return fmt.Errorf("store resume: %w", err)
+	}
+
+	entry.ResumeFileKey = key
+	entry.ResumeFileName = name
+
+	return nil
+}
+
+func (u *importUseCase) removeResume(ctx context.Context, entry *domain.ImportEntry) {
+	if entry.ResumeFileKey != "" {
+		_ = u.storage.Delete(context.WithoutCancel(ctx), entry.ResumeFileKey)
+		entry.ResumeFileKey = ""
+	}
+}
+
+// fail records err on the task, also when ctx is already cancelled.
+func (u *importUseCase) fail(ctx context.Context, task *domain.ImportTask, cause error, retry bool) error {
+	if err := u.repo.FailImport(context.WithoutCancel(ctx), task.TaskID, cause.Error(), retry); err != nil {
+		return fmt.Errorf("fail task: %w", err)
+	}
+
+	return nil
+}
+
+// importSource is the parsed content of the import files. The archive is
+// kept in a temporary file while the import runs.
+type importSource struct {
+	entries []domain.ImportEntry
+	files   map[string]*zip.File
+	tmp     *os.File
+}
+
+// file returns the archive entry with the base name of name, ignoring case.
+func (s *importSource) file(name string) *zip.File {
+	return s.files[strings.ToLower(path.Base(strings.ReplaceAll(name, "\\", "/")))]
+}
+
+func (s *importSource) Close() {
+	if s.tmp != nil {
+		_ = s.tmp.Close()
+		_ = os.Remove(s.tmp.Name())
+	}
+}
+
+// open reads the import files. Rows come from the CSV when there is one;
+// otherwise every resume in the archive, sorted by name, is a row. With both,
+// the resume column of the CSV names files of the archive.
+func (u *importUseCase) open(ctx context.Context, task *domain.ImportTask) (*importSource, error) {
+	source := &importSource{}
+
+	if task.ArchiveFileKey != "" {
+		names, err := u.openArchive(ctx, task.ArchiveFileKey, source)
+		if err != nil {
+			source.Close()
+			return nil, err
+		}
+
+		if task.CSVFileKey == "" {
+			source.entries = make([]domain.ImportEntry, len(names))
+			for i, name := range names {
+				source.entries[i].Row = i + 1
+				source.entries[i].FileName = name
+			}
+		}
+	}
+
+	if task.CSVFileKey != "" {
+		entries, err := u.readCSV(ctx, task.CSVFileKey)
+		if err != nil {
+			source.Close()
+			return nil, err
+		}
+
+		source.entries = entries
+	}
+
+	return source, nil
+}
+
+// openArchive copies the archive to a temporary file, indexes its files by
+// base name and returns those names sorted. Folders and files added by
+// archivers, such as __MACOSX or .DS_Store, are left out.
+func (u *importUseCase) openArchive(ctx context.Context, key string, source *importSource) ([]string, error) {
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer body.Close()
+
+	source.tmp, err = os.CreateTemp("", "import-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+
+	size, err := io.Copy(source.tmp, io.LimitReader(body, u.maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("copy archive: %w", err)
+	}
+
+	if size > u.maxArchiveSize {
+		return nil, ErrImportTooLarge
+	}
+
+	archive, err := zip.NewReader(source.tmp, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errImportArchive, err)
+	}
+
+	source.files = make(map[string]*zip.File, len(archive.File))
+
+	var names []string
+
+	for _, f := range archive.File {
+		name := path.Base(strings.ReplaceAll(f.Name, "\\", "/"))
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		lower := strings.ToLower(name)
+		if _, ok := source.files[lower]; ok {
+			continue
+		}
+
+		source.files[lower] = f
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// readCSV parses the candidate rows of a CSV file. The delimiter is a comma,
+// a semicolon as written by spreadsheet applications in many locales, or a
+// tab, whichever the header line has most of. Header names are matched
+// without regard to case, spaces and dashes; unknown columns are ignored.
+// Row is the line the record starts on.
+func (u *importUseCase) readCSV(ctx context.Context, key string) ([]domain.ImportEntry, error) {
+	body, _, err := u.storage.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("open csv: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxImportCSVSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+
+	if len(data) > maxImportCSVSize {
+		return nil, ErrImportTooLarge
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: the file is not UTF-8 encoded", errImportCSV)
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = csvDelimiter(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errImportCSV, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+		if field, ok := importColumns[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+
+	if _, stage := columns["stage"]; len(columns) == 0 || (stage && len(columns) == 1) {
+		return nil, errImportCSVHeader
+	}
+
+	var entries []domain.ImportEntry
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errImportCSV, err)
+		}
+
+		value := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[i])
+		}
+
+		line, _ := r.FieldPos(0)
+
+		entry := domain.ImportEntry{
+			ImportRow: domain.ImportRow{
+				Row:       line,
+				FileName:  value("resume"),
+				FirstName: value("first_name"),
+				LastName:  value("last_name"),
+				Email:     strings.ToLower(value("email")),
+				Phone:     value("phone"),
+			},
+			Stage: value("stage"),
+		}
+
+		if entry.FirstName == "" && entry.LastName == "" {
+			if name := strings.Fields(value("name")); len(name) > 0 {
+				entry.FirstName = name[0]
+				entry.LastName = strings.Join(name[1:], " ")
+			}
+		}
+
+		if entry.FileName == "" && entry.FirstName == "" && entry.LastName == "" &&
+			entry.Email == "" && entry.Phone == "" && entry.Stage == "" {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// csvDelimiter picks the delimiter the first line has most of.
+func csvDelimiter(data []byte) rune {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+
+	delimiter, most := ',', bytes.Count(line, []byte(","))
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(line, []byte(string(d))); n > most {
+			delimiter, most = d, n
+		}
+	}
+
+	return delimiter
+}
+
+// validateImportEntry returns what is wrong with a row, or "" if nothing is.
+// Phone numbers are accepted in any common notation, not only E.164 as on
+// the form, since they come from other systems.
+func validateImportEntry(entry *domain.ImportEntry) string {
+	if entry.FileName == "" && entry.FirstName == "" && entry.LastName == "" && entry.Email == "" && entry.Phone == "" {
+		return "row has no name, contacts or resume"
+	}
+
+	if utf8.RuneCountInString(entry.FirstName) > 100 || utf8.RuneCountInString(entry.LastName) > 100 {
+		return "name is longer than 100 characters"
+	}
+
+	if entry.Email != "" {
+		addr, err := mail.ParseAddress(entry.Email)
+		if err != nil || addr.Address != entry.Email || len(entry.Email) > 254 {
+			return fmt.Sprintf("email %q is not valid", entry.Email)
+		}
+	}
+
+	if entry.Phone != "" && !importPhonePattern.MatchString(entry.Phone) {
+		return fmt.Sprintf("phone %q is not valid", entry.Phone)
+	}
+
+	if utf8.RuneCountInString(entry.Stage) > 32 {
+		return "stage is longer than 32 characters"
+	}
+
+	return ""
+}
+
+// permanentImportError reports whether retrying cannot help: the job is
+// gone or the files are missing or unusable.
+func permanentImportError(err error) bool {
+	for _, target := range []error{
+		errImportJobRemoved,
+		errImportTooManyRows,
+		errImportCSVHeader,
+		errImportCSV,
+		errImportArchive,
+		ErrImportTooLarge,
+		storage.ErrNotFound,
+		storage.ErrInvalidKey,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clip cuts s to at most n runes.
+func clip(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+
+	return s
+}
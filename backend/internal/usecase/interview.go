@@ -0,0 +1,602 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/config"
+	"backend/pkg/ical"
+	"backend/pkg/mailer"
+	"backend/pkg/rbac"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInterviewNotFound    = errors.New("interview not found")
+	ErrInterviewCancelled   = errors.New("interview is cancelled")
+	ErrInterviewerNotMember = errors.New("interviewer is not a member of the team")
+	ErrInterviewerNoAccess  = errors.New("interviewer cannot see the candidate")
+	ErrInterviewConflict    = errors.New("interview conflicts with other appointments")
+	ErrInvalidInterview     = errors.New("invalid interview")
+	ErrAvailabilityNotFound = errors.New("availability window not found")
+	ErrInvalidAvailability  = errors.New("invalid availability window")
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	errMailerDisabled = errors.New("email is not configured")
+)
+
+const (
+	// maxAvailabilityWindow bounds a single availability window.
+	maxAvailabilityWindow = 31 * 24 * time.Hour
+	// The calendar feed covers interviews from feedPast ago to feedAhead
+	// from now.
+	feedPast  = 90 * 24 * time.Hour
+	feedAhead = 365 * 24 * time.Hour
+	// feedTokenBytes is the entropy of a calendar feed token.
+	feedTokenBytes = 32
+	// feedPath is the feed route used when no public feed URL is configured.
+	feedPath = "/api/v1/calendar/feeds"
+)
+
+// InterviewConflictError lists why a slot cannot be booked. It matches
+// ErrInterviewConflict.
+type InterviewConflictError struct {
+	Conflicts []domain.InterviewConflict
+}
+
+func (e *InterviewConflictError) Error() string {
+	return ErrInterviewConflict.Error()
+}
+
+func (e *InterviewConflictError) Is(target error) bool {
+	return target == ErrInterviewConflict
+}
+
+// InterviewUseCase schedules interviews with candidates, keeps the
+// availability of interviewers and sends iCalendar invitations. Invitations
+// are emailed in the background.
+type InterviewUseCase interface {
+	ListInterviews(ctx context.Context, filter domain.InterviewFilter) (*domain.Page[domain.Interview], error)
+	GetInterview(ctx context.Context, scope domain.JobScope, interviewID string) (*domain.Interview, error)
+	// CheckConflicts reports what keeps the slot from being booked without
+	// booking it. interviewID, when set, is the interview being moved.
+	CheckConflicts(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams) ([]domain.InterviewConflict, error)
+	ScheduleInterview(ctx context.Context, scope domain.JobScope, params domain.InterviewParams) (*domain.Interview, error)
+	RescheduleInterview(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams) (*domain.Interview, error)
+	CancelInterview(ctx context.Context, scope domain.JobScope, interviewID, reason string, notify bool) (*domain.Interview, error)
+	// InterviewCalendar returns the interview as an .ics file.
+	InterviewCalendar(ctx context.Context, scope domain.JobScope, interviewID string) ([]byte, error)
+
+	ListAvailability(ctx context.Context, teamID, userID string, from, to time.Time) ([]domain.AvailabilityWindow, error)
+	AddAvailability(ctx context.Context, userID string, startsAt, endsAt time.Time) (*domain.AvailabilityWindow, error)
+	DeleteAvailability(ctx context.Context, userID, windowID string) error
+
+	// CreateCalendarFeed issues a new subscription URL for the member's
+	// interviews. An earlier URL stops working.
+	CreateCalendarFeed(ctx context.Context, userID string) (*domain.CalendarFeed, error)
+	DeleteCalendarFeed(ctx context.Context, userID string) error
+	// CalendarFeed returns the calendar of the feed with the given token.
+	CalendarFeed(ctx context.Context, token string) ([]byte, error)
+
+	// ProcessNext sends a waiting invitation email. It reports false when
+	// the queue is empty. Delivery failures are recorded on the task and
+	// are not returned.
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+var _ InterviewUseCase = (*interviewUseCase)(nil)
+
+type interviewUseCase struct {
+	repo        repo.InterviewRepository
+	enforcer    *rbac.CasbinClient
+	mailer      mailer.Mailer
+	organizer   *ical.Person
+	domain      string
+	feedURL     string
+	lease       time.Duration
+	maxAttempts int
+}
+
+// NewInterviewUseCase creates the use case. A nil mailer disables
+// invitation emails.
+func NewInterviewUseCase(cfg *config.Config, repo repo.InterviewRepository, enforcer *rbac.CasbinClient, sender mailer.Mailer) InterviewUseCase {
+	u := &interviewUseCase{
+		repo:        repo,
+		enforcer:    enforcer,
+		mailer:      sender,
+		domain:      cfg.Calendar.Domain,
+		feedURL:     strings.TrimSuffix(cfg.Calendar.FeedURL, "/"),
+		lease:       cfg.Worker.Lease,
+		maxAttempts: cfg.Worker.MaxAttempts,
+	}
+
+	if u.domain == "" {
+		u.domain = "ai-hr-platform"
+	}
+
+	if u.feedURL == "" {
+		u.feedURL = feedPath
+	}
+
+	if from, err := mail.ParseAddress(cfg.Mailer.From); err == nil {
+		name := cfg.Mailer.FromName
+		if name == "" {
+			name = from.Name
+		}
+
+		u.organizer = &ical.Person{Name: name, Email: from.Address}
+	}
+
+	if u.lease <= 0 {
+		u.lease = defaultTaskLease
+	}
+
+	if u.maxAttempts <= 0 {
+		u.maxAttempts = defaultMaxAttempts
+	}
+
+	return u
+}
+
+func (u *interviewUseCase) ListInterviews(ctx context.Context, filter domain.InterviewFilter) (*domain.Page[domain.Interview], error) {
+	interviews, total, err := u.repo.ListInterviews(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list interviews: %w", err)
+	}
+
+	return &domain.Page[domain.Interview]{
+		Items:  interviews,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+func (u *interviewUseCase) GetInterview(ctx context.Context, scope domain.JobScope, interviewID string) (*domain.Interview, error) {
+	interview, err := u.repo.GetInterview(ctx, scope, interviewID)
+	if err != nil {
+		return nil, interviewError("get interview", err)
+	}
+
+	return interview, nil
+}
+
+func (u *interviewUseCase) CheckConflicts(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams) ([]domain.InterviewConflict, error) {
+	if err := validateInterview(&params, false); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := u.repo.CheckConflicts(ctx, scope, interviewID, params)
+	if err != nil {
+		return nil, interviewError("check conflicts", err)
+	}
+
+	return conflicts, nil
+}
+
+// ScheduleInterview books an interview. Unless params.Force is set, a slot
+// taken by another interview of the candidate or an interviewer, or outside
+// an interviewer's availability, fails with an InterviewConflictError.
+// Interviewers need a grant on the candidate's job or the jobs:all
+// permission.
+func (u *interviewUseCase) ScheduleInterview(ctx context.Context, scope domain.JobScope, params domain.InterviewParams) (*domain.Interview, error) {
+	if err := validateInterview(&params, true); err != nil {
+		return nil, err
+	}
+
+	params.Notify = params.Notify && u.mailer != nil
+
+	allJobs, err := withAllJobs(u.enforcer, scope.TeamID, params.Interviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := u.repo.CreateInterview(ctx, scope, params, allJobs)
+	if err != nil {
+		return nil, interviewError("create interview", err)
+	}
+
+	return u.GetInterview(ctx, scope, id)
+}
+
+// RescheduleInterview replaces the time, place and interviewers of a
+// scheduled interview. Invitations already sent are updated by raising the
+// interview's sequence.
+func (u *interviewUseCase) RescheduleInterview(ctx context.Context, scope domain.JobScope, interviewID string, params domain.InterviewParams) (*domain.Interview, error) {
+	if err := validateInterview(&params, true); err != nil {
+		return nil, err
+	}
+
+	params.Notify = params.Notify && u.mailer != nil
+
+	allJobs, err := withAllJobs(u.enforcer, scope.TeamID, params.Interviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.UpdateInterview(ctx, scope, interviewID, params, allJobs); err != nil {
+		return nil, interviewError("update interview", err)
+	}
+
+	return u.GetInterview(ctx, scope, interviewID)
+}
+
+func (u *interviewUseCase) CancelInterview(ctx context.Context, scope domain.JobScope, interviewID, reason string, notify bool) (*domain.Interview, error) {
+	if err := u.repo.CancelInterview(ctx, scope, interviewID, strings.TrimSpace(reason), notify && u.mailer != nil); err != nil {
+		return nil, interviewError("cancel interview", err)
+	}
+
+	return u.GetInterview(ctx, scope, interviewID)
+}
+
+func (u *interviewUseCase) InterviewCalendar(ctx context.Context, scope domain.JobScope, interviewID string) ([]byte, error) {
+	interview, err := u.GetInterview(ctx, scope, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := ical.Calendar{
+		ProdID: u.prodID(),
+		Method: ical.MethodPublish,
+		Events: []ical.Event{u.event(interview, true, time.Now())},
+	}
+
+	return calendar.Encode(), nil
+}
+
+// validateInterview checks the slot and the interviewers of params and
+// normalizes them. future requires the interview to start later than now.
+func validateInterview(params *domain.InterviewParams, future bool) error {
+	if params.Duration < domain.MinInterviewDuration || params.Duration > domain.MaxInterviewDuration {
+		return fmt.Errorf("%w: an interview lasts %s to %s", ErrInvalidInterview, domain.MinInterviewDuration, domain.MaxInterviewDuration)
+	}
+
+	if future && !params.StartsAt.After(time.Now()) {
+		return fmt.Errorf("%w: the interview must start in the future", ErrInvalidInterview)
+	}
+
+	if params.Timezone == "" {
+		params.Timezone = "UTC"
+	}
+
+	if _, err := time.LoadLocation(params.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidInterview, params.Timezone)
+	}
+
+	slices.Sort(params.Interviewers)
+	params.Interviewers = slices.Compact(params.Interviewers)
+
+	if len(params.Interviewers) == 0 || len(params.Interviewers) > domain.MaxInterviewers {
+		return fmt.Errorf("%w: an interview has 1 to %d interviewers", ErrInvalidInterview, domain.MaxInterviewers)
+	}
+
+	params.StartsAt = params.StartsAt.UTC().Truncate(time.Minute)
+	params.Title = strings.TrimSpace(params.Title)
+	params.Location = strings.TrimSpace(params.Location)
+	params.Notes = strings.TrimSpace(params.Notes)
+
+	return nil
+}
+
+func (u *interviewUseCase) ListAvailability(ctx context.Context, teamID, userID string, from, to time.Time) ([]domain.AvailabilityWindow, error) {
+	windows, err := u.repo.ListAvailability(ctx, teamID, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list availability: %w", err)
+	}
+
+	return windows, nil
+}
+
+// AddAvailability records a period in which the member is free for
+// interviews. Once a member has windows, slots outside them conflict.
+func (u *interviewUseCase) AddAvailability(ctx context.Context, userID string, startsAt, endsAt time.Time) (*domain.AvailabilityWindow, error) {
+	if !endsAt.After(startsAt) || endsAt.Sub(startsAt) > maxAvailabilityWindow {
+		return nil, fmt.Errorf("%w: a window ends after it starts and lasts at most %s", ErrInvalidAvailability, maxAvailabilityWindow)
+	}
+
+	if !endsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the window is over", ErrInvalidAvailability)
+	}
+
+	window := &domain.AvailabilityWindow{
+		UserID:   userID,
+		StartsAt: startsAt.UTC(),
+		EndsAt:   endsAt.UTC(),
+	}
+
+	if err := u.repo.AddAvailability(ctx, window); err != nil {
+		return nil, fmt.Errorf("add availability: %w", err)
+	}
+
+	return window, nil
+}
+
+func (u *interviewUseCase) DeleteAvailability(ctx context.Context, userID, windowID string) error {
+	if err := u.repo.DeleteAvailability(ctx, userID, windowID); err != nil {
+		return interviewError("delete availability", err)
+	}
+
+	return nil
+}
+
+// CreateCalendarFeed stores only a hash of the token; the URL cannot be
+// shown again.
+func (u *interviewUseCase) CreateCalendarFeed(ctx context.Context, userID string) (*domain.CalendarFeed, error) {
+	random := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("generate feed token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(random)
+
+	createdAt, err := u.repo.SaveCalendarFeed(ctx, userID, hashFeedToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("save calendar feed: %w", err)
+	}
+
+	return &domain.CalendarFeed{
+		Token:     token,
+		URL:       u.feedURL + "/" + token + ".ics",
+		CreatedAt: createdAt,
+	}, nil
+}
+
+func (u *interviewUseCase) DeleteCalendarFeed(ctx context.Context, userID string) error {
+	if err := u.repo.DeleteCalendarFeed(ctx, userID); err != nil {
+		return interviewError("delete calendar feed", err)
+	}
+
+	return nil
+}
+
+func (u *interviewUseCase) CalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	now := time.Now()
+
+	interviews, err := u.repo.FeedInterviews(ctx, hashFeedToken(token), now.Add(-feedPast), now.Add(feedAhead))
+	if err != nil {
+		return nil, interviewError("feed interviews", err)
+	}
+
+	calendar := ical.Calendar{
+		ProdID: u.prodID(),
+		Method: ical.MethodPublish,
+		Name:   "Interviews",
+		Events: make([]ical.Event, len(interviews)),
+	}
+
+	for i := range interviews {
+		calendar.Events[i] = u.event(&interviews[i], true, now)
+	}
+
+	return calendar.Encode(), nil
+}
+
+func hashFeedToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+func (u *interviewUseCase) ProcessNext(ctx context.Context) (bool, error) {
+	task, err := u.repo.ClaimInterviewMailTask(ctx, u.lease)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoTask) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("claim task: %w", err)
+	}
+
+	if task.Attempts > u.maxAttempts {
+		return true, u.fail(ctx, task, errors.New("too many attempts"), false)
+	}
+
+	if err := u.send(ctx, task); err != nil {
+		permanent := mailer.IsPermanent(err) || errors.Is(err, errMailerDisabled) || errors.Is(err, repo.ErrInterviewNotFound)
+		return true, u.fail(ctx, task, err, !permanent && task.Attempts < u.maxAttempts)
+	}
+
+	if err := u.repo.CompleteInterviewMail(context.WithoutCancel(ctx), task.TaskID); err != nil {
+		return true, fmt.Errorf("complete task: %w", err)
+	}
+
+	return true, nil
+}
+
+func (u *interviewUseCase) fail(ctx context.Context, task *domain.InterviewMailTask, cause error, retry bool) error {
+	if err := u.repo.FailInterviewMail(context.WithoutCancel(ctx), task.TaskID, cause.Error(), retry); err != nil {
+		return fmt.Errorf("fail task: %w", err)
+	}
+
+	return nil
+}
+
+// send emails the invitation of a task. It is built from the interview as it
+// is now, so a queued invitation never announces a superseded time; an
+// interview cancelled meanwhile is announced as cancelled.
+func (u *interviewUseCase) send(ctx context.Context, task *domain.InterviewMailTask) error {
+	if u.mailer == nil {
+		return errMailerDisabled
+	}
+
+	interview, err := u.repo.FindInterview(ctx, task.InterviewID)
+	if err != nil {
+		return err
+	}
+
+	cancelled := task.Kind == domain.InterviewMailCancel || interview.Status == domain.InterviewCancelled
+	interviewer := task.RecipientType == domain.RecipientInterviewer
+
+	event := u.event(interview, interviewer, time.Now())
+	method := ical.MethodRequest
+	subject := "Invitation: "
+
+	switch {
+	case cancelled:
+		method = ical.MethodCancel
+		subject = "Cancelled: "
+		event.Status = ical.StatusCancelled
+	case task.Kind == domain.InterviewMailUpdate:
+		subject = "Updated invitation: "
+	}
+
+	// A member removed from the interview is no longer among its attendees
+	// but must be named in the cancellation to drop the event.
+	if !slices.ContainsFunc(event.Attendees, func(p ical.Person) bool { return strings.EqualFold(p.Email, task.Email) }) {
+		event.Attendees = append(event.Attendees, ical.Person{Name: task.Name, Email: task.Email})
+	}
+
+	calendar := ical.Calendar{
+		ProdID: u.prodID(),
+		Method: method,
+		Events: []ical.Event{event},
+	}
+
+	return u.mailer.Send(ctx, &mailer.Message{
+		To:      []mailer.Address{{Name: task.Name, Email: task.Email}},
+		Subject: subject + interview.Title,
+		Text:    interviewText(interview, interviewer, cancelled),
+		Calendar: &mailer.Calendar{
+			Method: method,
+			Data:   calendar.Encode(),
+		},
+	})
+}
+
+// event returns the interview as an iCalendar event. Notes are only
+// included for interviewers.
+func (u *interviewUseCase) event(interview *domain.Interview, interviewer bool, now time.Time) ical.Event {
+	location := interview.Location
+	if location == "" {
+		location = interview.VideoURL
+	}
+
+	event := ical.Event{
+		UID:         interview.ID + "@" + u.domain,
+		Sequence:    interview.Sequence,
+		Stamp:       now,
+		Start:       interview.StartsAt,
+		End:         interview.EndsAt,
+		Summary:     interview.Title,
+		Description: interviewText(interview, interviewer, false),
+		Location:    location,
+		URL:         interview.VideoURL,
+		Status:      ical.StatusConfirmed,
+		Organizer:   u.organizer,
+	}
+
+	if interview.Status == domain.InterviewCancelled {
+		event.Status = ical.StatusCancelled
+	}
+
+	if interview.CandidateEmail != "" {
+		event.Attendees = append(event.Attendees, ical.Person{
+			Name:  interview.CandidateName,
+			Email: interview.CandidateEmail,
+			RSVP:  true,
+		})
+	}
+
+	for _, member := range interview.Interviewers {
+		event.Attendees = append(event.Attendees, ical.Person{
+			Name:  member.Name,
+			Email: member.Email,
+			RSVP:  true,
+		})
+	}
+
+	return event
+}
+
+// interviewText describes the interview in plain text, with the time in the
+// interview's time zone.
+func interviewText(interview *domain.Interview, interviewer, cancelled bool) string {
+	loc, err := time.LoadLocation(interview.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var b strings.Builder
+
+	if cancelled {
+		b.WriteString("This interview has been cancelled.\n")
+
+		if interview.CancelReason != "" {
+			b.WriteString("Reason: " + interview.CancelReason + "\n")
+		}
+
+		b.WriteString("\n")
+	}
+
+	b.WriteString(interview.Title + "\n")
+	fmt.Fprintf(&b, "When: %s - %s (%s)\n",
+		interview.StartsAt.In(loc).Format("Mon, 02 Jan 2006 15:04"),
+		interview.EndsAt.In(loc).Format("15:04"),
+		loc,
+	)
+
+	if interview.Location != "" {
+		b.WriteString("Where: " + interview.Location + "\n")
+	}
+
+	if interview.VideoURL != "" {
+		b.WriteString("Video link: " + interview.VideoURL + "\n")
+	}
+
+	if len(interview.Interviewers) > 0 {
+		names := make([]string, len(interview.Interviewers))
+		for i, member := range interview.Interviewers {
+			names[i] = member.Name
+		}
+
+		b.WriteString("Interviewers: " + strings.Join(names, ", ") + "\n")
+	}
+
+	if interviewer {
+		b.WriteString("Candidate: " + interview.CandidateName + " (" + interview.JobTitle + ", " + interview.Stage + ")\n")
+
+		if interview.Notes != "" {
+			b.WriteString("\n" + interview.Notes + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (u *interviewUseCase) prodID() string {
+	return "-//" + u.domain + "//Interviews//EN"
+}
+
+// interviewError maps repository errors of the interview routes to use case
+// errors.
+func interviewError(op string, err error) error {
+	var conflict *repo.InterviewConflictError
+
+	switch {
+	case errors.Is(err, repo.ErrInterviewNotFound):
+		return ErrInterviewNotFound
+	case errors.Is(err, repo.ErrCandidateNotFound):
+		return ErrCandidateNotFound
+	case errors.Is(err, repo.ErrInterviewCancelled):
+		return ErrInterviewCancelled
+	case errors.Is(err, repo.ErrInterviewerNotMember):
+		return ErrInterviewerNotMember
+	case errors.Is(err, repo.ErrInterviewerNoAccess):
+		return ErrInterviewerNoAccess
+	case errors.As(err, &conflict):
+		return &InterviewConflictError{Conflicts: conflict.Conflicts}
+	case errors.Is(err, repo.ErrAvailabilityNotFound):
+		return ErrAvailabilityNotFound
+	case errors.Is(err, repo.ErrCalendarFeedNotFound):
+		return ErrCalendarFeedNotFound
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
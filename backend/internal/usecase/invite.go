@@ -11,6 +11,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,7 @@ import (
 var (
 	ErrInviteNotFound = errors.New("invite not found")
 	ErrInviteExpired  = errors.New("invite expired")
+	ErrJobNotFound    = errors.New("job not found")
 )
 
 const inviteTTL = 48 * time.Hour
@@ -85,7 +87,11 @@ func (i *inviteUseCase) InviteUser(ctx context.Context, tokenStr string, req dom
 		jobIDs = *req.JobIDs
 	}
 
-	if err := i.repo.CreateInvite(ctx, invite, jobIDs); err != nil {
+	if err := i.repo.CreateInvite(ctx, invite, slices.Compact(slices.Sorted(slices.Values(jobIDs)))); err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return ErrJobNotFound
+		}
+
 		return fmt.Errorf("create invite: %w", err)
 	}
 
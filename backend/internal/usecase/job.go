@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"backend/internal/cache"
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidJobTransition = errors.New("job status transition is not allowed")
+	ErrJobApprovalRequired  = errors.New("job must be approved before it opens")
+	ErrNotJobApprover       = errors.New("job approval requires the jobs:approve permission")
+	ErrCloseReasonRequired  = errors.New("close reason is required")
+	ErrJobStatusConflict    = errors.New("job status was changed by another request")
+)
+
+type JobUseCase interface {
+	ListJobs(ctx context.Context, filter domain.JobFilter) (*domain.Page[domain.Job], error)
+	GetJob(ctx context.Context, teamID, jobID string) (*domain.Job, error)
+	CreateJob(ctx context.Context, scope domain.JobScope, req domain.JobParams) (*domain.Job, error)
+	UpdateJob(ctx context.Context, teamID, jobID string, req domain.JobParams) (*domain.Job, error)
+	DeleteJob(ctx context.Context, teamID, jobID string) error
+	ChangeStatus(ctx context.Context, session domain.Session, jobID, status, reason string) (*domain.Job, error)
+	GetSettings(ctx context.Context, teamID string) (*domain.JobSettings, error)
+	UpdateSettings(ctx context.Context, session domain.Session, settings domain.JobSettings) (*domain.JobSettings, error)
+}
+
+var _ JobUseCase = (*jobUseCase)(nil)
+
+type jobUseCase struct {
+	repo         repo.JobRepository
+	enforcer     *rbac.CasbinClient
+	cacheManager *cache.Manager
+}
+
+func NewJobUseCase(repo repo.JobRepository, enforcer *rbac.CasbinClient, cacheManager *cache.Manager) JobUseCase {
+	return &jobUseCase{
+		repo:         repo,
+		enforcer:     enforcer,
+		cacheManager: cacheManager,
+	}
+}
+
+func (j *jobUseCase) ListJobs(ctx context.Context, filter domain.JobFilter) (*domain.Page[domain.Job], error) {
+	jobs, total, err := j.repo.ListJobs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	return &domain.Page[domain.Job]{
+		Items:  jobs,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+func (j *jobUseCase) GetJob(ctx context.Context, teamID, jobID string) (*domain.Job, error) {
+	job, err := j.repo.GetJob(ctx, teamID, jobID)
+	if err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (j *jobUseCase) CreateJob(ctx context.Context, scope domain.JobScope, req domain.JobParams) (*domain.Job, error) {
+	job := &domain.Job{
+		TeamID:      scope.TeamID,
+		Title:       req.Title,
+		Department:  req.Department,
+		WorkFormat:  req.WorkFormat,
+		Description: req.Description,
+		CreatedBy:   &scope.UserID,
+	}
+
+	if err := j.repo.CreateJob(ctx, job, !scope.AllJobs); err != nil {
+		return nil, fmt.Errorf("create job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (j *jobUseCase) UpdateJob(ctx context.Context, teamID, jobID string, req domain.JobParams) (*domain.Job, error) {
+	job := &domain.Job{
+		ID:          jobID,
+		TeamID:      teamID,
+		Title:       req.Title,
+		Department:  req.Department,
+		WorkFormat:  req.WorkFormat,
+		Description: req.Description,
+	}
+
+	if err := j.repo.UpdateJob(ctx, job); err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+
+		return nil, fmt.Errorf("update job: %w", err)
+	}
+
+	invalidateCareers(ctx, j.cacheManager, teamID)
+
+	return job, nil
+}
+
+func (j *jobUseCase) DeleteJob(ctx context.Context, teamID, jobID string) error {
+	if err := j.repo.DeleteJob(ctx, teamID, jobID); err != nil {
+		if errors.Is(err, repo.ErrJobNotFound) {
+			return ErrJobNotFound
+		}
+
+		return fmt.Errorf("delete job: %w", err)
+	}
+
+	invalidateCareers(ctx, j.cacheManager, teamID)
+
+	return nil
+}
+
+// ChangeStatus validates a lifecycle transition. Opening a job needs approval
+// when the team requires it: members without jobs:approve submit it for
+// approval instead, and only approvers may open a pending job. Moving it back
+// to draft withdraws or rejects the request.
+func (j *jobUseCase) ChangeStatus(ctx context.Context, session domain.Session, jobID, status, reason string) (*domain.Job, error) {
+	job, err := j.GetJob(ctx, session.TeamID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !domain.CanTransitionJob(job.Status, status) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidJobTransition, job.Status, status)
+	}
+
+	if status == domain.JobStatusClosed && reason == "" {
+		return nil, ErrCloseReasonRequired
+	}
+
+	approver, err := j.enforcer.HasPermissionInDomain(session.UserID, domain.PermJobsApprove, session.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("check approver: %w", err)
+	}
+
+	change := domain.JobStatusChange{
+		From:    job.Status,
+		To:      status,
+		Reason:  reason,
+		ActorID: session.UserID,
+	}
+
+	switch {
+	case job.Status == domain.JobStatusPendingApproval && status == domain.JobStatusOpen:
+		if !approver {
+			return nil, ErrNotJobApprover
+		}
+
+		change.Approved = true
+	case job.Status == domain.JobStatusDraft && status == domain.JobStatusOpen:
+		settings, err := j.GetSettings(ctx, session.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		if settings.ApprovalRequired && !approver {
+			return nil, ErrJobApprovalRequired
+		}
+
+		change.Approved = approver
+	}
+
+	if err := j.repo.ChangeJobStatus(ctx, job, change); err != nil {
+		if errors.Is(err, repo.ErrJobStatusConflict) {
+			return nil, ErrJobStatusConflict
+		}
+
+		return nil, fmt.Errorf("change job status: %w", err)
+	}
+
+	invalidateCareers(ctx, j.cacheManager, session.TeamID)
+
+	return job, nil
+}
+
+func (j *jobUseCase) GetSettings(ctx context.Context, teamID string) (*domain.JobSettings, error) {
+	settings, err := j.repo.GetJobSettings(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get job settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (j *jobUseCase) UpdateSettings(ctx context.Context, session domain.Session, settings domain.JobSettings) (*domain.JobSettings, error) {
+	approver, err := j.enforcer.HasPermissionInDomain(session.UserID, domain.PermJobsApprove, session.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("check approver: %w", err)
+	}
+
+	if !approver {
+		return nil, ErrNotJobApprover
+	}
+
+	if err := j.repo.UpdateJobSettings(ctx, session.TeamID, &settings); err != nil {
+		return nil, fmt.Errorf("update job settings: %w", err)
+	}
+
+	return &settings, nil
+}
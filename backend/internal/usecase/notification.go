@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationUseCase interface {
+	ListNotifications(ctx context.Context, filter domain.NotificationFilter) (*domain.Page[domain.Notification], error)
+	MarkRead(ctx context.Context, userID, notificationID string) error
+	MarkAllRead(ctx context.Context, userID string) (int, error)
+}
+
+var _ NotificationUseCase = (*notificationUseCase)(nil)
+
+type notificationUseCase struct {
+	repo repo.NotificationRepository
+}
+
+func NewNotificationUseCase(repo repo.NotificationRepository) NotificationUseCase {
+	return &notificationUseCase{repo: repo}
+}
+
+func (u *notificationUseCase) ListNotifications(ctx context.Context, filter domain.NotificationFilter) (*domain.Page[domain.Notification], error) {
+	notifications, total, err := u.repo.ListNotifications(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+
+	return &domain.Page[domain.Notification]{
+		Items:  notifications,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+func (u *notificationUseCase) MarkRead(ctx context.Context, userID, notificationID string) error {
+	if err := u.repo.MarkRead(ctx, userID, notificationID); err != nil {
+		if errors.Is(err, repo.ErrNotificationNotFound) {
+			return ErrNotificationNotFound
+		}
+
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+
+	return nil
+}
+
+func (u *notificationUseCase) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	count, err := u.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("mark notifications read: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,461 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrPipelineNotFound     = errors.New("pipeline template not found")
+	ErrPipelineExists       = errors.New("pipeline template already exists")
+	ErrPipelineConflict     = errors.New("pipeline template was changed concurrently")
+	ErrPipelineInUse        = errors.New("pipeline template is in use")
+	ErrInvalidPipeline      = errors.New("invalid pipeline")
+	ErrStageInUse           = errors.New("stage still has candidates")
+	ErrUnknownStage         = errors.New("stage is not part of the job pipeline")
+	ErrTransitionNotAllowed = errors.New("stage transition is not allowed")
+	ErrNotTemplateEditor    = errors.New("pipeline templates require the jobs:all permission")
+)
+
+type PipelineUseCase interface {
+	ListTemplates(ctx context.Context, teamID string) ([]domain.PipelineTemplate, error)
+	GetTemplate(ctx context.Context, teamID, templateID string) (*domain.PipelineTemplate, error)
+	CreateTemplate(ctx context.Context, session domain.Session, req domain.PipelineParams) (*domain.PipelineTemplate, error)
+	UpdateTemplate(ctx context.Context, session domain.Session, templateID string, req domain.PipelineParams) (*domain.PipelineTemplate, error)
+	DeleteTemplate(ctx context.Context, session domain.Session, templateID string) error
+	GetJobPipeline(ctx context.Context, teamID, jobID string) (*domain.JobPipeline, error)
+	SetJobPipeline(ctx context.Context, session domain.Session, jobID string, req domain.JobPipelineParams) (*domain.JobPipeline, error)
+}
+
+var _ PipelineUseCase = (*pipelineUseCase)(nil)
+
+type pipelineUseCase struct {
+	repo     repo.PipelineRepository
+	enforcer *rbac.CasbinClient
+}
+
+func NewPipelineUseCase(repo repo.PipelineRepository, enforcer *rbac.CasbinClient) PipelineUseCase {
+	return &pipelineUseCase{
+		repo:     repo,
+		enforcer: enforcer,
+	}
+}
+
+func (u *pipelineUseCase) ListTemplates(ctx context.Context, teamID string) ([]domain.PipelineTemplate, error) {
+	templates, err := u.repo.ListTemplates(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("list pipeline templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (u *pipelineUseCase) GetTemplate(ctx context.Context, teamID, templateID string) (*domain.PipelineTemplate, error) {
+	template, err := u.repo.GetTemplate(ctx, teamID, templateID)
+	if err != nil {
+		return nil, pipelineRepoError("get pipeline template", err)
+	}
+
+	return template, nil
+}
+
+func (u *pipelineUseCase) CreateTemplate(ctx context.Context, session domain.Session, req domain.PipelineParams) (*domain.PipelineTemplate, error) {
+	if err := u.checkTemplateEditor(session); err != nil {
+		return nil, err
+	}
+
+	stages, err := validateStages(req.Stages)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stages {
+		stages[i].ID = ""
+	}
+
+	var change *domain.DefaultChange
+
+	if req.IsDefault {
+		from, err := u.defaultTemplate(ctx, session.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		if change, err = defaultChange(from, stages, req.Reassign); err != nil {
+			return nil, err
+		}
+	}
+
+	template := &domain.PipelineTemplate{
+		TeamID:    session.TeamID,
+		Name:      strings.TrimSpace(req.Name),
+		IsDefault: req.IsDefault,
+		Stages:    stages,
+	}
+
+	if err := u.repo.CreateTemplate(ctx, session, template, change); err != nil {
+		return nil, pipelineRepoError("create pipeline template", err)
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate replaces the template's stages. Stages are matched to the
+// current ones by ID: a stage whose name changed is renamed on every job
+// following the template, and the candidates of a removed stage move to the
+// stage named in req.Reassign. Removing a stage that still has candidates
+// without naming a target fails with ErrStageInUse. When the template becomes
+// or stops being the default, the jobs without a template move from the
+// previous default to the new one by stage name, with the same reassign.
+func (u *pipelineUseCase) UpdateTemplate(ctx context.Context, session domain.Session, templateID string, req domain.PipelineParams) (*domain.PipelineTemplate, error) {
+	if err := u.checkTemplateEditor(session); err != nil {
+		return nil, err
+	}
+
+	current, err := u.GetTemplate(ctx, session.TeamID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, err := validateStages(req.Stages)
+	if err != nil {
+		return nil, err
+	}
+
+	migration, err := stageMigration(current.Stages, stages, req.Reassign)
+	if err != nil {
+		return nil, err
+	}
+
+	var change *domain.DefaultChange
+
+	switch {
+	case req.IsDefault && !current.IsDefault:
+		from, err := u.defaultTemplate(ctx, session.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		if change, err = defaultChange(from, stages, req.Reassign); err != nil {
+			return nil, err
+		}
+	case !req.IsDefault && current.IsDefault:
+		if change, err = defaultChange(current, domain.DefaultStages(), req.Reassign); err != nil {
+			return nil, err
+		}
+	}
+
+	template := &domain.PipelineTemplate{
+		ID:        templateID,
+		TeamID:    session.TeamID,
+		Name:      strings.TrimSpace(req.Name),
+		IsDefault: req.IsDefault,
+		Stages:    stages,
+	}
+
+	if err := u.repo.UpdateTemplate(ctx, session, template, current.UpdatedAt, migration, change); err != nil {
+		return nil, pipelineRepoError("update pipeline template", err)
+	}
+
+	return template, nil
+}
+
+func (u *pipelineUseCase) DeleteTemplate(ctx context.Context, session domain.Session, templateID string) error {
+	if err := u.checkTemplateEditor(session); err != nil {
+		return err
+	}
+
+	if err := u.repo.DeleteTemplate(ctx, session, templateID); err != nil {
+		return pipelineRepoError("delete pipeline template", err)
+	}
+
+	return nil
+}
+
+func (u *pipelineUseCase) GetJobPipeline(ctx context.Context, teamID, jobID string) (*domain.JobPipeline, error) {
+	pipeline, err := u.repo.GetJobPipeline(ctx, teamID, jobID)
+	if err != nil {
+		return nil, pipelineRepoError("get job pipeline", err)
+	}
+
+	return pipeline, nil
+}
+
+// SetJobPipeline gives the job its own stages, assigns it a template or, when
+// req names neither, returns it to the team default. Own stages are matched
+// to the current ones by ID, so editing them renames stages like a template
+// edit does; switching to a template matches stages by name. Candidates in
+// stages the new pipeline lacks move as req.Reassign says.
+func (u *pipelineUseCase) SetJobPipeline(ctx context.Context, session domain.Session, jobID string, req domain.JobPipelineParams) (*domain.JobPipeline, error) {
+	current, err := u.GetJobPipeline(ctx, session.TeamID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := domain.JobPipelineChange{JobID: jobID}
+
+	var target domain.Stages
+
+	switch {
+	case req.Stages != nil:
+		if target, err = validateStages(req.Stages); err != nil {
+			return nil, err
+		}
+
+		change.Stages = target
+	case req.TemplateID != "":
+		template, err := u.GetTemplate(ctx, session.TeamID, req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+
+		change.TemplateID = &template.ID
+		target = template.Stages
+	default:
+		if target, err = u.defaultStages(ctx, session.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	if change.Stages == nil {
+		target = stagesByName(target)
+	}
+
+	if change.Migration, err = stageMigration(current.Stages, target, req.Reassign); err != nil {
+		return nil, err
+	}
+
+	// Stage IDs only identify stages of the current pipeline; the job gets
+	// rows of its own.
+	if current.Source != domain.PipelineSourceJob {
+		for i := range change.Stages {
+			change.Stages[i].ID = ""
+		}
+	}
+
+	if err := u.repo.SetJobPipeline(ctx, session, change); err != nil {
+		return nil, pipelineRepoError("set job pipeline", err)
+	}
+
+	return u.GetJobPipeline(ctx, session.TeamID, jobID)
+}
+
+// defaultStages returns the stages jobs without a template follow.
+// checkTemplateEditor fails with ErrNotTemplateEditor unless the caller may
+// act on every job of the team. A template change renames and reassigns
+// stages on all jobs following it, including those the caller cannot see;
+// per-job pipelines are set through SetJobPipeline instead.
+func (u *pipelineUseCase) checkTemplateEditor(session domain.Session) error {
+	ok, err := u.enforcer.HasPermissionInDomain(session.UserID, domain.PermJobsAll, session.TeamID)
+	if err != nil {
+		return fmt.Errorf("check jobs:all: %w", err)
+	}
+
+	if !ok {
+		return ErrNotTemplateEditor
+	}
+
+	return nil
+}
+
+func (u *pipelineUseCase) defaultStages(ctx context.Context, teamID string) (domain.Stages, error) {
+	template, err := u.defaultTemplate(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return domain.DefaultStages(), nil
+	}
+
+	return template.Stages, nil
+}
+
+// defaultTemplate returns the team default template, or nil when jobs without
+// a template follow domain.DefaultStages.
+func (u *pipelineUseCase) defaultTemplate(ctx context.Context, teamID string) (*domain.PipelineTemplate, error) {
+	templates, err := u.ListTemplates(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		if templates[i].IsDefault {
+			return &templates[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// defaultChange works out how the jobs without a template follow a change of
+// the default from the template from (nil for domain.DefaultStages) to
+// stages. Stages are matched by name, as when a job switches templates.
+// Reassign targets missing from stages were meant for the template's own
+// jobs and are left out, so those stages must be empty.
+func defaultChange(from *domain.PipelineTemplate, stages domain.Stages, reassign map[string]string) (*domain.DefaultChange, error) {
+	change := &domain.DefaultChange{}
+	current := domain.DefaultStages()
+
+	if from != nil {
+		change.FromID = &from.ID
+		change.Version = from.UpdatedAt
+		current = from.Stages
+	}
+
+	targets := maps.Clone(reassign)
+	maps.DeleteFunc(targets, func(_, target string) bool {
+		_, ok := stages.Find(target)
+		return !ok
+	})
+
+	migration, err := stageMigration(current, stagesByName(stages), targets)
+	if err != nil {
+		return nil, err
+	}
+
+	change.Migration = migration
+
+	return change, nil
+}
+
+// stagesByName drops stage IDs so the stages are matched by name only.
+func stagesByName(stages domain.Stages) domain.Stages {
+	stages = slices.Clone(stages)
+	for i := range stages {
+		stages[i].ID = ""
+	}
+
+	return stages
+}
+
+// validateStages trims and checks a pipeline: 1 to domain.MaxPipelineStages
+// stages with unique names and known types, a first stage candidates can
+// work through, and transitions to other stages of the pipeline only.
+func validateStages(stages domain.Stages) (domain.Stages, error) {
+	if len(stages) == 0 || len(stages) > domain.MaxPipelineStages {
+		return nil, fmt.Errorf("%w: a pipeline has 1 to %d stages", ErrInvalidPipeline, domain.MaxPipelineStages)
+	}
+
+	stages = slices.Clone(stages)
+	names := make(map[string]bool, len(stages))
+
+	for i := range stages {
+		stage := &stages[i]
+		stage.Name = strings.TrimSpace(stage.Name)
+
+		switch {
+		case stage.Name == "" || len(stage.Name) > 32:
+			return nil, fmt.Errorf("%w: stage names are 1 to 32 characters", ErrInvalidPipeline)
+		case names[strings.ToLower(stage.Name)]:
+			return nil, fmt.Errorf("%w: duplicate stage %q", ErrInvalidPipeline, stage.Name)
+		case !domain.IsStageType(stage.Type):
+			return nil, fmt.Errorf("%w: stage %q has unknown type %q", ErrInvalidPipeline, stage.Name, stage.Type)
+		}
+
+		names[strings.ToLower(stage.Name)] = true
+	}
+
+	if t := stages[0].Type; t == domain.StageTypeHired || t == domain.StageTypeRejected {
+		return nil, fmt.Errorf("%w: the first stage cannot be of type %q", ErrInvalidPipeline, t)
+	}
+
+	for i := range stages {
+		stage := &stages[i]
+		if stage.Transitions == nil {
+			continue
+		}
+
+		transitions := make([]string, 0, len(stage.Transitions))
+		for _, name := range stage.Transitions {
+			name = strings.TrimSpace(name)
+
+			if _, ok := stages.Find(name); !ok || name == stage.Name {
+				return nil, fmt.Errorf("%w: stage %q cannot move to %q", ErrInvalidPipeline, stage.Name, name)
+			}
+
+			if !slices.Contains(transitions, name) {
+				transitions = append(transitions, name)
+			}
+		}
+
+		stage.Transitions = transitions
+	}
+
+	return stages, nil
+}
+
+// stageMigration works out how candidates follow a change from stages to
+// next. A stage keeps its candidates when next has a stage with its ID or,
+// failing that, its name; a stage found by ID under another name is renamed.
+// The remaining stages are reassigned to the stage named in reassign or
+// reported as removed.
+func stageMigration(stages, next domain.Stages, reassign map[string]string) (domain.StageMigration, error) {
+	migration := domain.StageMigration{
+		Renames:  make(map[string]string),
+		Reassign: make(map[string]string),
+	}
+
+	for _, stage := range next {
+		if stage.ID != "" && !slices.ContainsFunc(stages, func(s domain.Stage) bool { return s.ID == stage.ID }) {
+			return migration, fmt.Errorf("%w: unknown stage id %q", ErrInvalidPipeline, stage.ID)
+		}
+	}
+
+	for _, stage := range stages {
+		i := slices.IndexFunc(next, func(s domain.Stage) bool { return s.ID != "" && s.ID == stage.ID })
+		if i >= 0 {
+			if next[i].Name != stage.Name {
+				migration.Renames[stage.Name] = next[i].Name
+			}
+
+			continue
+		}
+
+		if _, ok := next.Find(stage.Name); ok {
+			continue
+		}
+
+		target, ok := reassign[stage.Name]
+		if !ok {
+			migration.Removed = append(migration.Removed, stage.Name)
+			continue
+		}
+
+		if _, ok := next.Find(target); !ok {
+			return migration, fmt.Errorf("%w: stage %q cannot be reassigned to %q", ErrInvalidPipeline, stage.Name, target)
+		}
+
+		migration.Reassign[stage.Name] = target
+	}
+
+	return migration, nil
+}
+
+func pipelineRepoError(op string, err error) error {
+	var inUse *repo.StageInUseError
+
+	switch {
+	case errors.Is(err, repo.ErrPipelineNotFound):
+		return ErrPipelineNotFound
+	case errors.Is(err, repo.ErrJobNotFound):
+		return ErrJobNotFound
+	case errors.Is(err, repo.ErrPipelineExists):
+		return ErrPipelineExists
+	case errors.Is(err, repo.ErrPipelineConflict):
+		return ErrPipelineConflict
+	case errors.Is(err, repo.ErrPipelineInUse):
+		return ErrPipelineInUse
+	case errors.As(err, &inUse):
+		return fmt.Errorf("%w: %q", ErrStageInUse, inUse.Stage)
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
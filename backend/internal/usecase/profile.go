@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/profile"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrProfileInvalid      = errors.New("invalid profile")
+	ErrProfileConflict     = errors.New("profile was changed concurrently, retry the request")
+	ErrNoResumeText        = errors.New("candidate has no resume to extract a profile from")
+	ErrExtractionQueued    = errors.New("profile extraction is already in progress")
+	ErrExtractionCancelled = errors.New("resume processing is stopped while the job is closed")
+)
+
+// profileSaveAttempts bounds the retries of a correction that raced with
+// another one.
+const profileSaveAttempts = 3
+
+// ProfileUseCase serves the structured profiles of candidates. Recruiters
+// correct a profile with JSON merge patches (RFC 7396); the patches are
+// accumulated apart from the extracted document, so a re-extraction keeps
+// them.
+type ProfileUseCase interface {
+	GetProfile(ctx context.Context, teamID, candidateID string) (*domain.CandidateProfile, error)
+	CorrectProfile(ctx context.Context, session domain.Session, candidateID string, patch json.RawMessage) (*domain.CandidateProfile, error)
+	ResetCorrections(ctx context.Context, session domain.Session, candidateID string) (*domain.CandidateProfile, error)
+	RequestExtraction(ctx context.Context, session domain.Session, candidateID string) error
+}
+
+var _ ProfileUseCase = (*profileUseCase)(nil)
+
+type profileUseCase struct {
+	repo repo.ProfileRepository
+}
+
+func NewProfileUseCase(repo repo.ProfileRepository) ProfileUseCase {
+	return &profileUseCase{repo: repo}
+}
+
+func (u *profileUseCase) GetProfile(ctx context.Context, teamID, candidateID string) (*domain.CandidateProfile, error) {
+	p, err := u.repo.GetProfile(ctx, teamID, candidateID)
+	if err != nil {
+		return nil, profileRepoError(err, "get profile")
+	}
+
+	return p, nil
+}
+
+// CorrectProfile adds patch to the corrections of the profile. The corrected
+// profile must satisfy the schema; a candidate without an extracted profile
+// is corrected on top of an empty one.
+func (u *profileUseCase) CorrectProfile(ctx context.Context, session domain.Session, candidateID string, patch json.RawMessage) (*domain.CandidateProfile, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: the patch must be a JSON object", ErrProfileInvalid)
+	}
+
+	if _, ok := fields["schema_version"]; ok {
+		return nil, fmt.Errorf("%w: schema_version cannot be corrected", ErrProfileInvalid)
+	}
+
+	return u.save(ctx, session, candidateID, func(current *domain.CandidateProfile) (json.RawMessage, []string, error) {
+		corrections, err := profile.ComposePatch(current.Corrections, patch)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrProfileInvalid, err)
+		}
+
+		base := []byte(current.Extracted)
+		if len(base) == 0 {
+			base, err = json.Marshal(profile.New())
+			if err != nil {
+				return nil, nil, fmt.Errorf("marshal profile: %w", err)
+			}
+		}
+
+		merged, err := profile.MergePatch(base, corrections)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrProfileInvalid, err)
+		}
+
+		if _, err := profile.Parse(merged); err != nil {
+			var verr *profile.ValidationError
+			if errors.As(err, &verr) {
+				return nil, nil, fmt.Errorf("%w: %s", ErrProfileInvalid, strings.Join(verr.Problems, "; "))
+			}
+
+			return nil, nil, fmt.Errorf("%w: %v", ErrProfileInvalid, err)
+		}
+
+		return corrections, sortedKeys(fields), nil
+	})
+}
+
+// ResetCorrections drops all corrections, so the profile equals the
+// extracted document again.
+func (u *profileUseCase) ResetCorrections(ctx context.Context, session domain.Session, candidateID string) (*domain.CandidateProfile, error) {
+	return u.save(ctx, session, candidateID, func(current *domain.CandidateProfile) (json.RawMessage, []string, error) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(current.Corrections, &fields); err != nil {
+			return nil, nil, fmt.Errorf("decode corrections: %w", err)
+		}
+
+		return json.RawMessage(`{}`), sortedKeys(fields), nil
+	})
+}
+
+func (u *profileUseCase) RequestExtraction(ctx context.Context, session domain.Session, candidateID string) error {
+	if err := u.repo.RequestExtraction(ctx, session, candidateID); err != nil {
+		return profileRepoError(err, "request extraction")
+	}
+
+	return nil
+}
+
+// save computes new corrections from the current profile and stores them.
+// When another correction wins the race, the profile is read again and the
+// change is recomputed on top of it.
+func (u *profileUseCase) save(
+	ctx context.Context,
+	session domain.Session,
+	candidateID string,
+	change func(current *domain.CandidateProfile) (json.RawMessage, []string, error),
+) (*domain.CandidateProfile, error) {
+	for range profileSaveAttempts {
+		current, err := u.repo.GetProfile(ctx, session.TeamID, candidateID)
+		if err != nil {
+			return nil, profileRepoError(err, "get profile")
+		}
+
+		corrections, fields, err := change(current)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) == 0 {
+			return current, nil
+		}
+
+		saved, err := u.repo.SaveCorrections(ctx, session, domain.ProfileCorrection{
+			CandidateID: candidateID,
+			Previous:    current.Corrections,
+			Corrections: corrections,
+			Fields:      fields,
+		})
+		if err == nil {
+			return saved, nil
+		}
+
+		if !errors.Is(err, repo.ErrProfileConflict) {
+			return nil, profileRepoError(err, "save corrections")
+		}
+	}
+
+	return nil, ErrProfileConflict
+}
+
+func profileRepoError(err error, op string) error {
+	switch {
+	case errors.Is(err, repo.ErrCandidateNotFound):
+		return ErrCandidateNotFound
+	case errors.Is(err, repo.ErrNoResumeText):
+		return ErrNoResumeText
+	case errors.Is(err, repo.ErrExtractionQueued):
+		return ErrExtractionQueued
+	case errors.Is(err, repo.ErrExtractionCancelled):
+		return ErrExtractionCancelled
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"backend/pkg/rbac"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleAlreadyExists = errors.New("role already exists")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrMemberNotFound    = errors.New("team member not found")
+)
+
+type RoleUseCase interface {
+	ListPermissions() []string
+	ListRoles(ctx context.Context, teamID string) ([]domain.Role, error)
+	CreateRole(ctx context.Context, teamID string, req domain.RoleParams) (*domain.Role, error)
+	UpdateRole(ctx context.Context, teamID, roleID string, req domain.RoleParams) (*domain.Role, error)
+	DeleteRole(ctx context.Context, teamID, roleID string) error
+	AssignRole(ctx context.Context, teamID, roleID, userID string) error
+	UnassignRole(ctx context.Context, teamID, roleID, userID string) error
+}
+
+var _ RoleUseCase = (*roleUseCase)(nil)
+
+type roleUseCase struct {
+	repo     repo.RoleRepository
+	enforcer *rbac.CasbinClient
+}
+
+func NewRoleUseCase(repo repo.RoleRepository, enforcer *rbac.CasbinClient) RoleUseCase {
+	return &roleUseCase{
+		repo:     repo,
+		enforcer: enforcer,
+	}
+}
+
+// customRoleSubject is the Casbin subject of a team-defined role. Prefixing
+// with the ID keeps custom roles from colliding with built-in role names.
+func customRoleSubject(roleID string) string {
+	return "role:" + roleID
+}
+
+func (r *roleUseCase) ListPermissions() []string {
+	return domain.Permissions
+}
+
+func (r *roleUseCase) ListRoles(ctx context.Context, teamID string) ([]domain.Role, error) {
+	roles := make([]domain.Role, 0, len(domain.BuiltinRoles))
+
+	for _, name := range domain.BuiltinRoles {
+		permissions, err := r.enforcer.GetPermissionsForRoleInDomain(name, rbac.AnyDomain)
+		if err != nil {
+			return nil, fmt.Errorf("get builtin permissions: %w", err)
+		}
+
+		roles = append(roles, domain.Role{
+			Name:        name,
+			Permissions: permissions,
+			Builtin:     true,
+		})
+	}
+
+	custom, err := r.repo.ListRoles(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+
+	return append(roles, custom...), nil
+}
+
+func (r *roleUseCase) CreateRole(ctx context.Context, teamID string, req domain.RoleParams) (*domain.Role, error) {
+	if err := validateRoleParams(req); err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		TeamID:      teamID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: compactStrings(req.Permissions),
+	}
+
+	var (
+		subject string
+		applied bool
+	)
+
+	err := r.repo.CreateRole(ctx, role, func() error {
+		subject = customRoleSubject(role.ID)
+		if err := r.replacePermissions(subject, teamID, nil, role.Permissions); err != nil {
+			return err
+		}
+
+		applied = true
+
+		return nil
+	})
+	if err != nil {
+		// The row was not committed: drop the policies written for it.
+		if applied {
+			_ = r.enforcer.DeleteRoleInDomain(subject, teamID)
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrRoleAlreadyExists
+		}
+
+		return nil, fmt.Errorf("create role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *roleUseCase) UpdateRole(ctx context.Context, teamID, roleID string, req domain.RoleParams) (*domain.Role, error) {
+	if err := validateRoleParams(req); err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		ID:          roleID,
+		TeamID:      teamID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: compactStrings(req.Permissions),
+	}
+
+	subject := customRoleSubject(roleID)
+
+	previous, err := r.enforcer.GetPermissionsForRoleInDomain(subject, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get role permissions: %w", err)
+	}
+
+	applied := false
+
+	err = r.repo.UpdateRole(ctx, role, func() error {
+		if err := r.replacePermissions(subject, teamID, previous, role.Permissions); err != nil {
+			return err
+		}
+
+		applied = true
+
+		return nil
+	})
+	if err != nil {
+		// The row keeps its old permissions: so do the policies.
+		if applied {
+			_ = r.enforcer.SetPermissionsForRoleInDomain(subject, teamID, previous)
+		}
+
+		var pgErr *pgconn.PgError
+
+		switch {
+		case errors.Is(err, repo.ErrRoleNotFound):
+			return nil, ErrRoleNotFound
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			return nil, ErrRoleAlreadyExists
+		default:
+			return nil, fmt.Errorf("update role: %w", err)
+		}
+	}
+
+	return role, nil
+}
+
+// replacePermissions sets the Casbin permissions of a custom role. If that
+// fails halfway the previous permissions are put back, so the policies never
+// disagree with the stored role.
+func (r *roleUseCase) replacePermissions(subject, teamID string, previous, permissions []string) error {
+	if err := r.enforcer.SetPermissionsForRoleInDomain(subject, teamID, permissions); err != nil {
+		_ = r.enforcer.SetPermissionsForRoleInDomain(subject, teamID, previous)
+		return fmt.Errorf("set role permissions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleUseCase) DeleteRole(ctx context.Context, teamID, roleID string) error {
+	if err := r.repo.DeleteRole(ctx, teamID, roleID); err != nil {
+		if errors.Is(err, repo.ErrRoleNotFound) {
+			return ErrRoleNotFound
+		}
+
+		return fmt.Errorf("delete role: %w", err)
+	}
+
+	if err := r.enforcer.DeleteRoleInDomain(customRoleSubject(roleID), teamID); err != nil {
+		return fmt.Errorf("delete role policies: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleUseCase) AssignRole(ctx context.Context, teamID, roleID, userID string) error {
+	if err := r.checkAssignment(ctx, teamID, roleID, userID); err != nil {
+		return err
+	}
+
+	if _, err := r.enforcer.AddRoleForUserInDomain(userID, customRoleSubject(roleID), teamID); err != nil {
+		return fmt.Errorf("assign role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleUseCase) UnassignRole(ctx context.Context, teamID, roleID, userID string) error {
+	if err := r.checkAssignment(ctx, teamID, roleID, userID); err != nil {
+		return err
+	}
+
+	if _, err := r.enforcer.DeleteRoleForUserInDomain(userID, customRoleSubject(roleID), teamID); err != nil {
+		return fmt.Errorf("unassign role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleUseCase) checkAssignment(ctx context.Context, teamID, roleID, userID string) error {
+	if _, err := r.repo.GetRole(ctx, teamID, roleID); err != nil {
+		if errors.Is(err, repo.ErrRoleNotFound) {
+			return ErrRoleNotFound
+		}
+
+		return fmt.Errorf("get role: %w", err)
+	}
+
+	if err := r.repo.CheckMember(ctx, teamID, userID); err != nil {
+		if errors.Is(err, repo.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+
+		return fmt.Errorf("check member: %w", err)
+	}
+
+	return nil
+}
+
+func validateRoleParams(req domain.RoleParams) error {
+	if domain.IsBuiltinRole(req.Name) {
+		return ErrRoleAlreadyExists
+	}
+
+	for _, p := range req.Permissions {
+		if !domain.IsPermission(p) {
+			return fmt.Errorf("%w: %q", ErrUnknownPermission, p)
+		}
+	}
+
+	return nil
+}
+
+// compactStrings returns a sorted copy of values without duplicates. The
+// result is never nil so it can be stored in NOT NULL array columns.
+func compactStrings(values []string) []string {
+	out := append([]string{}, values...)
+	slices.Sort(out)
+
+	return slices.Compact(out)
+}
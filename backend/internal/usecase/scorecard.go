@@ -0,0 +1,413 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrScorecardTemplateNotFound = errors.New("scorecard template not found")
+	ErrInvalidScorecardTemplate  = errors.New("invalid scorecard template")
+	ErrInvalidScorecard          = errors.New("invalid scorecard")
+)
+
+// criterionKey is the form of criterion keys; ratings refer to criteria by
+// key, so keys stay stable while names are reworded.
+var criterionKey = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+type ScorecardUseCase interface {
+	ListTemplates(ctx context.Context, teamID, jobID string) ([]domain.ScorecardTemplate, error)
+	SaveTemplate(ctx context.Context, session domain.Session, params domain.ScorecardTemplateParams) (*domain.ScorecardTemplate, error)
+	DeleteTemplate(ctx context.Context, session domain.Session, jobID, stage string) error
+	GetCandidateTemplate(ctx context.Context, scope domain.JobScope, candidateID, stage string) (*domain.ScorecardTemplate, error)
+	SubmitScorecard(ctx context.Context, scope domain.JobScope, params domain.ScorecardParams) (*domain.Scorecard, error)
+	ListScorecards(ctx context.Context, scope domain.JobScope, candidateID, stage string) (*domain.CandidateScorecards, error)
+	CompareScorecards(ctx context.Context, scope domain.JobScope, jobID, stage string) (*domain.ScorecardComparison, error)
+}
+
+var _ ScorecardUseCase = (*scorecardUseCase)(nil)
+
+type scorecardUseCase struct {
+	repo      repo.ScorecardRepository
+	pipelines repo.PipelineRepository
+}
+
+func NewScorecardUseCase(repo repo.ScorecardRepository, pipelines repo.PipelineRepository) ScorecardUseCase {
+	return &scorecardUseCase{repo: repo, pipelines: pipelines}
+}
+
+func (u *scorecardUseCase) ListTemplates(ctx context.Context, teamID, jobID string) ([]domain.ScorecardTemplate, error) {
+	templates, err := u.repo.ListTemplates(ctx, teamID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("list scorecard templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// SaveTemplate sets the template of a stage of the job's pipeline.
+func (u *scorecardUseCase) SaveTemplate(ctx context.Context, session domain.Session, params domain.ScorecardTemplateParams) (*domain.ScorecardTemplate, error) {
+	if err := validateCriteria(params.Criteria); err != nil {
+		return nil, err
+	}
+
+	pipeline, err := u.pipelines.GetJobPipeline(ctx, session.TeamID, params.JobID)
+	if err != nil {
+		return nil, scorecardError("get job pipeline", err)
+	}
+
+	if _, ok := pipeline.Stages.Find(params.Stage); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStage, params.Stage)
+	}
+
+	template := &domain.ScorecardTemplate{
+		JobID:    params.JobID,
+		Stage:    params.Stage,
+		Blind:    params.Blind,
+		Criteria: params.Criteria,
+	}
+
+	if err := u.repo.SaveTemplate(ctx, session, template); err != nil {
+		return nil, scorecardError("save scorecard template", err)
+	}
+
+	return template, nil
+}
+
+func (u *scorecardUseCase) DeleteTemplate(ctx context.Context, session domain.Session, jobID, stage string) error {
+	if err := u.repo.DeleteTemplate(ctx, session, jobID, stage); err != nil {
+		return scorecardError("delete scorecard template", err)
+	}
+
+	return nil
+}
+
+func (u *scorecardUseCase) GetCandidateTemplate(ctx context.Context, scope domain.JobScope, candidateID, stage string) (*domain.ScorecardTemplate, error) {
+	template, err := u.repo.GetCandidateTemplate(ctx, scope, candidateID, stage)
+	if err != nil {
+		return nil, scorecardError("get scorecard template", err)
+	}
+
+	return template, nil
+}
+
+// SubmitScorecard rates the candidate on the template of params.Stage, or of
+// the candidate's current stage when it is empty. Every criterion is rated
+// exactly once; the scorecard keeps a copy of the criteria so that later
+// template changes do not alter its meaning.
+func (u *scorecardUseCase) SubmitScorecard(ctx context.Context, scope domain.JobScope, params domain.ScorecardParams) (*domain.Scorecard, error) {
+	template, err := u.repo.GetCandidateTemplate(ctx, scope, params.CandidateID, params.Stage)
+	if err != nil {
+		return nil, scorecardError("get scorecard template", err)
+	}
+
+	ratings, err := orderRatings(template.Criteria, params.Ratings)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := u.repo.SubmitScorecard(ctx, scope, &domain.Scorecard{
+		CandidateID:    params.CandidateID,
+		Stage:          template.Stage,
+		Criteria:       template.Criteria,
+		Ratings:        ratings,
+		Recommendation: params.Recommendation,
+		Summary:        strings.TrimSpace(params.Summary),
+		Score:          scorecardScore(template.Criteria, ratings),
+	})
+	if err != nil {
+		return nil, scorecardError("submit scorecard", err)
+	}
+
+	return card, nil
+}
+
+// ListScorecards returns the candidate's scorecards the caller may read with
+// a summary per stage, in the order the stages were first evaluated, and one
+// over all of them.
+func (u *scorecardUseCase) ListScorecards(ctx context.Context, scope domain.JobScope, candidateID, stage string) (*domain.CandidateScorecards, error) {
+	cards, hidden, err := u.repo.ListScorecards(ctx, scope, candidateID, stage)
+	if err != nil {
+		return nil, scorecardError("list scorecards", err)
+	}
+
+	var stages []string
+
+	for _, card := range slices.Backward(cards) {
+		if !slices.Contains(stages, card.Stage) {
+			stages = append(stages, card.Stage)
+		}
+	}
+
+	var hiddenOnly []string
+
+	for name := range hidden {
+		if !slices.Contains(stages, name) {
+			hiddenOnly = append(hiddenOnly, name)
+		}
+	}
+
+	slices.Sort(hiddenOnly)
+	stages = append(stages, hiddenOnly...)
+
+	result := &domain.CandidateScorecards{
+		Scorecards: cards,
+		Stages:     make([]domain.ScorecardSummary, len(stages)),
+	}
+
+	totalHidden := 0
+
+	for i, name := range stages {
+		stageCards := slices.DeleteFunc(slices.Clone(cards), func(card domain.Scorecard) bool {
+			return card.Stage != name
+		})
+
+		result.Stages[i] = summarizeScorecards(name, stageCards, hidden[name], true)
+		totalHidden += hidden[name]
+	}
+
+	if len(cards) > 0 || totalHidden > 0 {
+		overall := summarizeScorecards("", cards, totalHidden, false)
+		result.Overall = &overall
+	}
+
+	return result, nil
+}
+
+// CompareScorecards sets the evaluated candidates of a job side by side,
+// best score first. Within a stage candidates are also compared per
+// criterion of the stage's template.
+func (u *scorecardUseCase) CompareScorecards(ctx context.Context, scope domain.JobScope, jobID, stage string) (*domain.ScorecardComparison, error) {
+	candidates, hidden, cards, err := u.repo.CompareScorecards(ctx, scope, jobID, stage)
+	if err != nil {
+		return nil, scorecardError("compare scorecards", err)
+	}
+
+	comparison := &domain.ScorecardComparison{
+		JobID:      jobID,
+		Stage:      stage,
+		Candidates: candidates,
+	}
+
+	if stage != "" {
+		templates, err := u.repo.ListTemplates(ctx, scope.TeamID, jobID)
+		if err != nil {
+			return nil, fmt.Errorf("list scorecard templates: %w", err)
+		}
+
+		if i := slices.IndexFunc(templates, func(t domain.ScorecardTemplate) bool { return t.Stage == stage }); i >= 0 {
+			comparison.Criteria = templates[i].Criteria
+		}
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+
+		candidateCards := slices.DeleteFunc(slices.Clone(cards), func(card domain.Scorecard) bool {
+			return card.CandidateID != candidate.CandidateID
+		})
+
+		candidate.Summary = summarizeScorecards(stage, candidateCards, hidden[candidate.CandidateID], stage != "")
+	}
+
+	slices.SortStableFunc(candidates, func(a, b domain.ComparedCandidate) int {
+		switch {
+		case a.Summary.Score == nil && b.Summary.Score == nil:
+			return 0
+		case a.Summary.Score == nil:
+			return 1
+		case b.Summary.Score == nil:
+			return -1
+		default:
+			return cmp.Compare(*b.Summary.Score, *a.Summary.Score)
+		}
+	})
+
+	return comparison, nil
+}
+
+// validateCriteria checks a template: between one and MaxScorecardSize
+// criteria with distinct keys, scales within bounds and a label, if any, for
+// every point of the scale.
+func validateCriteria(criteria []domain.Criterion) error {
+	if len(criteria) == 0 || len(criteria) > domain.MaxScorecardSize {
+		return fmt.Errorf("%w: between 1 and %d criteria are required", ErrInvalidScorecardTemplate, domain.MaxScorecardSize)
+	}
+
+	keys := make(map[string]struct{}, len(criteria))
+
+	for _, criterion := range criteria {
+		if !criterionKey.MatchString(criterion.Key) {
+			return fmt.Errorf("%w: key %q must be 1-32 lowercase letters, digits or underscores", ErrInvalidScorecardTemplate, criterion.Key)
+		}
+
+		if _, ok := keys[criterion.Key]; ok {
+			return fmt.Errorf("%w: duplicate key %q", ErrInvalidScorecardTemplate, criterion.Key)
+		}
+
+		keys[criterion.Key] = struct{}{}
+
+		if strings.TrimSpace(criterion.Name) == "" {
+			return fmt.Errorf("%w: criterion %q has no name", ErrInvalidScorecardTemplate, criterion.Key)
+		}
+
+		if criterion.Scale < domain.MinRatingScale || criterion.Scale > domain.MaxRatingScale {
+			return fmt.Errorf("%w: scale of %q must be between %d and %d",
+				ErrInvalidScorecardTemplate, criterion.Key, domain.MinRatingScale, domain.MaxRatingScale)
+		}
+
+		if len(criterion.Labels) > 0 && len(criterion.Labels) != criterion.Scale {
+			return fmt.Errorf("%w: %q needs %d labels, one per rating", ErrInvalidScorecardTemplate, criterion.Key, criterion.Scale)
+		}
+	}
+
+	return nil
+}
+
+// orderRatings checks that ratings rate every criterion exactly once within
+// its scale, with a comment where one is required, and returns them in
+// criteria order.
+func orderRatings(criteria []domain.Criterion, ratings []domain.Rating) ([]domain.Rating, error) {
+	byKey := make(map[string]domain.Rating, len(ratings))
+
+	for _, rating := range ratings {
+		if _, ok := byKey[rating.Key]; ok {
+			return nil, fmt.Errorf("%w: %q is rated more than once", ErrInvalidScorecard, rating.Key)
+		}
+
+		rating.Comment = strings.TrimSpace(rating.Comment)
+		byKey[rating.Key] = rating
+	}
+
+	ordered := make([]domain.Rating, 0, len(criteria))
+
+	for _, criterion := range criteria {
+		rating, ok := byKey[criterion.Key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not rated", ErrInvalidScorecard, criterion.Key)
+		}
+
+		if rating.Rating < 1 || rating.Rating > criterion.Scale {
+			return nil, fmt.Errorf("%w: rating of %q must be between 1 and %d", ErrInvalidScorecard, criterion.Key, criterion.Scale)
+		}
+
+		if criterion.CommentRequired && rating.Comment == "" {
+			return nil, fmt.Errorf("%w: %q needs a comment", ErrInvalidScorecard, criterion.Key)
+		}
+
+		ordered = append(ordered, rating)
+		delete(byKey, criterion.Key)
+	}
+
+	for key := range byKey {
+		return nil, fmt.Errorf("%w: %q is not a criterion of the stage", ErrInvalidScorecard, key)
+	}
+
+	return ordered, nil
+}
+
+// normalizedRating maps a rating on a 1..scale scale onto 0..1.
+func normalizedRating(rating, scale int) float64 {
+	return float64(rating-1) / float64(scale-1)
+}
+
+// scorecardScore is the mean of the normalized ratings on a 0-100 scale.
+func scorecardScore(criteria []domain.Criterion, ratings []domain.Rating) int {
+	total := 0.0
+	for i, rating := range ratings {
+		total += normalizedRating(rating.Rating, criteria[i].Scale)
+	}
+
+	return int(math.Round(total / float64(len(ratings)) * 100))
+}
+
+// summarizeScorecards aggregates cards: the mean score, the recommendations
+// given and, with perCriterion, the mean rating of every criterion of the
+// most recently updated scorecard. Criteria are matched by key, each rating
+// on the scale it was given on.
+func summarizeScorecards(stage string, cards []domain.Scorecard, hidden int, perCriterion bool) domain.ScorecardSummary {
+	summary := domain.ScorecardSummary{
+		Stage:           stage,
+		Count:           len(cards),
+		Hidden:          hidden,
+		Recommendations: make(map[string]int, len(domain.Recommendations)),
+	}
+
+	for _, recommendation := range domain.Recommendations {
+		summary.Recommendations[recommendation] = 0
+	}
+
+	if len(cards) == 0 {
+		return summary
+	}
+
+	total := 0
+	for _, card := range cards {
+		total += card.Score
+		summary.Recommendations[card.Recommendation]++
+	}
+
+	score := roundScore(float64(total) / float64(len(cards)))
+	summary.Score = &score
+
+	if !perCriterion {
+		return summary
+	}
+
+	latest := slices.MaxFunc(cards, func(a, b domain.Scorecard) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+
+	for _, criterion := range latest.Criteria {
+		criterionSummary := domain.CriterionSummary{Key: criterion.Key, Name: criterion.Name}
+		sum := 0.0
+
+		for _, card := range cards {
+			i := slices.IndexFunc(card.Criteria, func(c domain.Criterion) bool { return c.Key == criterion.Key })
+			j := slices.IndexFunc(card.Ratings, func(r domain.Rating) bool { return r.Key == criterion.Key })
+
+			if i < 0 || j < 0 {
+				continue
+			}
+
+			sum += normalizedRating(card.Ratings[j].Rating, card.Criteria[i].Scale) * 100
+			criterionSummary.Count++
+		}
+
+		if criterionSummary.Count > 0 {
+			criterionSummary.Score = roundScore(sum / float64(criterionSummary.Count))
+		}
+
+		summary.Criteria = append(summary.Criteria, criterionSummary)
+	}
+
+	return summary
+}
+
+// roundScore rounds a 0-100 score to one decimal place.
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
+
+// scorecardError maps repository errors of the scorecard routes to use case
+// errors.
+func scorecardError(op string, err error) error {
+	switch {
+	case errors.Is(err, repo.ErrJobNotFound):
+		return ErrJobNotFound
+	case errors.Is(err, repo.ErrCandidateNotFound):
+		return ErrCandidateNotFound
+	case errors.Is(err, repo.ErrScorecardTemplateNotFound):
+		return ErrScorecardTemplateNotFound
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"context"
+	"fmt"
+	"html"
+	"strings"
+)
+
+type SearchUseCase interface {
+	SearchCandidates(ctx context.Context, search domain.CandidateSearch) (*domain.SearchPage, error)
+}
+
+var _ SearchUseCase = (*searchUseCase)(nil)
+
+type searchUseCase struct {
+	repo repo.SearchRepository
+}
+
+func NewSearchUseCase(repo repo.SearchRepository) SearchUseCase {
+	return &searchUseCase{repo: repo}
+}
+
+// SearchCandidates returns a page of hits and, for the first page, the
+// facets of the whole result.
+func (u *searchUseCase) SearchCandidates(ctx context.Context, search domain.CandidateSearch) (*domain.SearchPage, error) {
+	hits, err := u.repo.SearchCandidates(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("search candidates: %w", err)
+	}
+
+	page := &domain.SearchPage{
+		CursorPage: domain.CursorPage[domain.SearchHit]{
+			Items: hits,
+			Limit: search.Limit,
+		},
+	}
+
+	if len(hits) > search.Limit {
+		page.Items = hits[:search.Limit]
+
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = domain.SearchCursor{Rank: last.Rank, ID: last.ID}.Encode()
+	}
+
+	for i := range page.Items {
+		page.Items[i].Headline = headline(page.Items[i].Headline)
+	}
+
+	if search.Cursor == nil {
+		facets, err := u.repo.CandidateFacets(ctx, search)
+		if err != nil {
+			return nil, fmt.Errorf("candidate facets: %w", err)
+		}
+
+		page.Facets = facets
+	}
+
+	return page, nil
+}
+
+// headline escapes a ts_headline excerpt of untrusted resume text for HTML
+// while keeping the <mark> tags around the matched words.
+func headline(excerpt string) string {
+	escaped := html.EscapeString(excerpt)
+	escaped = strings.ReplaceAll(escaped, "&lt;mark&gt;", "<mark>")
+
+	return strings.ReplaceAll(escaped, "&lt;/mark&gt;", "</mark>")
+}
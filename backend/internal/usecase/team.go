@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"backend/internal/cache"
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrInvalidSlug = errors.New("slug must be 2-64 lowercase letters, digits or dashes")
+	ErrSlugTaken   = errors.New("slug is already taken")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}[a-z0-9]$`)
+
+type TeamUseCase interface {
+	GetTeam(ctx context.Context, teamID string) (*domain.Team, error)
+	UpdateTeam(ctx context.Context, teamID string, req domain.TeamParams) (*domain.Team, error)
+}
+
+var _ TeamUseCase = (*teamUseCase)(nil)
+
+type teamUseCase struct {
+	repo         repo.TeamRepository
+	cacheManager *cache.Manager
+}
+
+func NewTeamUseCase(repo repo.TeamRepository, cacheManager *cache.Manager) TeamUseCase {
+	return &teamUseCase{
+		repo:         repo,
+		cacheManager: cacheManager,
+	}
+}
+
+func (t *teamUseCase) GetTeam(ctx context.Context, teamID string) (*domain.Team, error) {
+	team, err := t.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return nil, ErrTeamNotFound
+		}
+
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+
+	return team, nil
+}
+
+func (t *teamUseCase) UpdateTeam(ctx context.Context, teamID string, req domain.TeamParams) (*domain.Team, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	team, err := t.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldSlug := team.Slug
+
+	team.Name = req.Name
+	team.Slug = req.Slug
+	team.Locale = req.Locale
+	team.Branding = req.Branding
+
+	if err := t.repo.UpdateTeam(ctx, team); err != nil {
+		var pgErr *pgconn.PgError
+
+		switch {
+		case errors.Is(err, repo.ErrTeamNotFound):
+			return nil, ErrTeamNotFound
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			return nil, ErrSlugTaken
+		default:
+			return nil, fmt.Errorf("update team: %w", err)
+		}
+	}
+
+	_ = cache.Delete(ctx, t.cacheManager, cache.CareersSlugKey, oldSlug)
+	invalidateCareers(ctx, t.cacheManager, teamID)
+
+	return team, nil
+}
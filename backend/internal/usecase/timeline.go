@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"backend/internal/domain"
+	"backend/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TimelineUseCase merges the history of a candidate into a single stream.
+type TimelineUseCase interface {
+	ListTimeline(ctx context.Context, filter domain.TimelineFilter) (*domain.Page[domain.TimelineEntry], error)
+}
+
+var _ TimelineUseCase = (*timelineUseCase)(nil)
+
+type timelineUseCase struct {
+	repo repo.TimelineRepository
+}
+
+func NewTimelineUseCase(repo repo.TimelineRepository) TimelineUseCase {
+	return &timelineUseCase{repo: repo}
+}
+
+// ListTimeline returns a page of stage changes, communications, scores,
+// scorecards, comments, interviews and logged actions concerning the
+// candidate, newest first.
+func (u *timelineUseCase) ListTimeline(ctx context.Context, filter domain.TimelineFilter) (*domain.Page[domain.TimelineEntry], error) {
+	entries, total, err := u.repo.ListTimeline(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repo.ErrCandidateNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+
+		return nil, fmt.Errorf("list timeline: %w", err)
+	}
+
+	return &domain.Page[domain.TimelineEntry]{
+		Items:  entries,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
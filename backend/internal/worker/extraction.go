@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"backend/internal/db"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+
+	"go.uber.org/zap"
+)
+
+// NewExtraction creates the queue that turns resumes into text and profiles.
+func NewExtraction(log *zap.Logger, cfg *config.Worker, dbClient *db.PostgresClient, usecase usecase.ExtractionUseCase) *Queue {
+	return newQueue("extraction", log, cfg, dbClient, usecase)
+}
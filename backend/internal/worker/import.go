@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"backend/internal/db"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+
+	"go.uber.org/zap"
+)
+
+// NewImport creates the queue that runs bulk candidate imports.
+func NewImport(log *zap.Logger, cfg *config.Worker, dbClient *db.PostgresClient, usecase usecase.ImportUseCase) *Queue {
+	return newQueue("import", log, cfg, dbClient, usecase)
+}
@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"backend/internal/db"
+	"backend/internal/usecase"
+	"backend/pkg/config"
+
+	"go.uber.org/zap"
+)
+
+// NewInterviewMail creates the queue that emails interview invitations.
+func NewInterviewMail(log *zap.Logger, cfg *config.Worker, dbClient *db.PostgresClient, usecase usecase.InterviewUseCase) *Queue {
+	return newQueue("interview-mail", log, cfg, dbClient, usecase)
+}
@@ -0,0 +1,223 @@
+package worker
+
+import (
+	"backend/internal/db"
+	"backend/pkg/config"
+	"backend/pkg/svc"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// tasksChannel is notified by ai_engine.f_processing_tasks_notify when a
+	// task starts waiting for a stage (pending or analyzing).
+	tasksChannel = "processing_tasks"
+
+	defaultConcurrency  = 2
+	defaultPollInterval = 30 * time.Second
+
+	listenMinBackoff = time.Second
+	listenMaxBackoff = 30 * time.Second
+)
+
+// Processor runs the tasks of one workflow.
+type Processor interface {
+	// ProcessNext runs one waiting task and reports false when there is
+	// none.
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+// Queue processes tasks of ai_engine.t_processing_tasks in the background.
+// Workers are woken by Postgres notifications and also poll the queue, so
+// tasks are picked up when a notification is lost or a lease expires.
+type Queue struct {
+	name         string
+	log          *zap.Logger
+	dbClient     *db.PostgresClient
+	processor    Processor
+	concurrency  int
+	pollInterval time.Duration
+
+	wake chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newQueue(name string, log *zap.Logger, cfg *config.Worker, dbClient *db.PostgresClient, processor Processor) *Queue {
+	w := &Queue{
+		name:         name,
+		log:          log,
+		dbClient:     dbClient,
+		processor:    processor,
+		concurrency:  cfg.Concurrency,
+		pollInterval: cfg.PollInterval,
+	}
+
+	if w.concurrency <= 0 {
+		w.concurrency = defaultConcurrency
+	}
+
+	if w.pollInterval <= 0 {
+		w.pollInterval = defaultPollInterval
+	}
+
+	w.wake = make(chan struct{}, w.concurrency)
+
+	return w
+}
+
+func (w *Queue) Name() string {
+	return w.name
+}
+
+func (w *Queue) DependsOn() []string {
+	return []string{"logger", "db"}
+}
+
+func (w *Queue) Init(ctx context.Context) error {
+	return nil
+}
+
+func (w *Queue) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (w *Queue) Run(ctx context.Context) error {
+	for i := 0; i < w.concurrency; i++ {
+		w.wg.Add(1)
+
+		go func() {
+			defer w.wg.Done()
+			w.loop(ctx)
+		}()
+	}
+
+	w.listen(ctx)
+	w.wg.Wait()
+
+	return nil
+}
+
+// Stop waits for the tasks in progress; Run's context is already cancelled
+// by then.
+func (w *Queue) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for %s workers: %w", w.name, ctx.Err())
+	}
+}
+
+// loop drains the queue, then sleeps until a notification or the poll
+// interval.
+func (w *Queue) loop(ctx context.Context) {
+	for {
+		for ctx.Err() == nil {
+			processed, err := w.processNext(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					w.log.Error("task processing failed", zap.String("queue", w.name), zap.Error(err))
+				}
+
+				break
+			}
+
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// processNext turns a panic of the processor into an error, so one broken
+// task does not take the whole service down. The task is then handled as
+// after a crash: its lease expires.
+func (w *Queue) processNext(ctx context.Context) (processed bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return w.processor.ProcessNext(ctx)
+}
+
+// listen wakes a worker on every notification until ctx is cancelled,
+// reconnecting with exponential backoff.
+func (w *Queue) listen(ctx context.Context) {
+	backoff := listenMinBackoff
+
+	for ctx.Err() == nil {
+		err := w.listenOnce(ctx, func() { backoff = listenMinBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.log.Error("processing tasks listener disconnected", zap.Error(err), zap.Duration("retry_in", backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (w *Queue) listenOnce(ctx context.Context, onConnect func()) error {
+	conn, err := pgx.ConnectConfig(ctx, w.dbClient.ConnConfig())
+	if err != nil {
+		return fmt.Errorf("connect listener: %w", err)
+	}
+
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{tasksChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen %q: %w", tasksChannel, err)
+	}
+
+	onConnect()
+
+	// Tasks queued while the listener was down are not announced again.
+	w.notify()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+
+		w.notify()
+	}
+}
+
+func (w *Queue) notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+var _ svc.Service = (*Queue)(nil)
@@ -0,0 +1,9 @@
+-- =============================================================================
+-- Migration: 000005_custom_roles (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS auth.t_roles;
+
+COMMIT;
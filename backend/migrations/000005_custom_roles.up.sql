@@ -0,0 +1,22 @@
+-- =============================================================================
+-- Migration: 000005_custom_roles (UP)
+-- Description: Team-defined roles composed of named permissions. The actual
+--              enforcement lives in Casbin grouping policies; this table keeps
+--              the role metadata shown in the UI.
+-- =============================================================================
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS auth.t_roles (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id     UUID        NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    name        VARCHAR     NOT NULL,
+    description TEXT,
+    permissions TEXT[]      NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_roles_team_name UNIQUE (team_id, name)
+);
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- =============================================================================
+-- Migration: 000006_activity_details (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code = 'access_denied');
+
+DELETE FROM hiring.t_action_types WHERE code = 'access_denied';
+
+DROP INDEX IF EXISTS hiring.idx_activity_logs_team_action;
+
+ALTER TABLE hiring.t_activity_logs DROP COLUMN IF EXISTS details;
+
+COMMIT;
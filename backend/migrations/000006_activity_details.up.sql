@@ -0,0 +1,18 @@
+-- =============================================================================
+-- Migration: 000006_activity_details (UP)
+-- Description: Free-form details for activity log entries and the
+--              access_denied action recorded by the RBAC middleware.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_activity_logs ADD COLUMN IF NOT EXISTS details JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_activity_logs_team_action
+    ON hiring.t_activity_logs (team_id, action_id, created_at DESC);
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('access_denied', 'Request rejected by the authorization policy')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- =============================================================================
+-- Migration: 000007_jobs (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP INDEX IF EXISTS hiring.idx_job_access_job;
+DROP INDEX IF EXISTS hiring.idx_jobs_team_department;
+DROP INDEX IF EXISTS hiring.idx_jobs_team_status;
+
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS created_by;
+
+COMMIT;
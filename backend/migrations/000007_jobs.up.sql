@@ -0,0 +1,16 @@
+-- =============================================================================
+-- Migration: 000007_jobs (UP)
+-- Description: Columns and indexes used by the jobs API: modification time,
+--              author and the filters of the job list.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES auth.t_users (id) ON DELETE SET NULL;
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_jobs_team_status     ON hiring.t_jobs (team_id, status);
+CREATE INDEX IF NOT EXISTS idx_jobs_team_department ON hiring.t_jobs (team_id, department);
+CREATE INDEX IF NOT EXISTS idx_job_access_job       ON hiring.t_job_access (job_id);
+
+COMMIT;
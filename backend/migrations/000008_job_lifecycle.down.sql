@@ -0,0 +1,28 @@
+-- =============================================================================
+-- Migration: 000008_job_lifecycle (DOWN)
+-- The 'cancelled' task_status value is left in place: PostgreSQL cannot drop
+-- enum values. Cancelled tasks are returned to 'failed'.
+-- =============================================================================
+
+BEGIN;
+
+UPDATE ai_engine.t_processing_tasks SET status = 'failed' WHERE status = 'cancelled';
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code = 'job_status_changed');
+
+DELETE FROM hiring.t_action_types WHERE code = 'job_status_changed';
+
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS approved_by;
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS close_reason;
+
+ALTER TABLE hiring.t_jobs
+    DROP CONSTRAINT IF EXISTS chk_jobs_status,
+    ALTER COLUMN status DROP NOT NULL,
+    ALTER COLUMN status DROP DEFAULT;
+
+ALTER TABLE auth.t_teams DROP COLUMN IF EXISTS job_approval_required;
+
+COMMIT;
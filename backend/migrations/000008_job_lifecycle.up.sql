@@ -0,0 +1,35 @@
+-- =============================================================================
+-- Migration: 000008_job_lifecycle (UP)
+-- Description: Defined job statuses (draft → pending_approval → open → paused
+--              → closed/filled), the per-team approval switch, close reasons
+--              and the cancelled state for AI tasks of paused or closed jobs.
+-- =============================================================================
+
+-- ALTER TYPE ... ADD VALUE cannot be used in the same transaction that adds it.
+ALTER TYPE task_status ADD VALUE IF NOT EXISTS 'cancelled';
+
+BEGIN;
+
+ALTER TABLE auth.t_teams ADD COLUMN IF NOT EXISTS job_approval_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE hiring.t_jobs
+SET status = 'draft'
+WHERE status IS NULL
+   OR status NOT IN ('draft', 'pending_approval', 'open', 'paused', 'closed', 'filled');
+
+ALTER TABLE hiring.t_jobs
+    ALTER COLUMN status SET DEFAULT 'draft',
+    ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT chk_jobs_status
+        CHECK (status IN ('draft', 'pending_approval', 'open', 'paused', 'closed', 'filled'));
+
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS close_reason TEXT;
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS closed_at    TIMESTAMP;
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS approved_by  UUID REFERENCES auth.t_users (id) ON DELETE SET NULL;
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS approved_at  TIMESTAMP;
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('job_status_changed', 'Job moved to another lifecycle status')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
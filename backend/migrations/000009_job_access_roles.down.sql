@@ -0,0 +1,13 @@
+-- =============================================================================
+-- Migration: 000009_job_access_roles (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_job_access DROP CONSTRAINT IF EXISTS chk_job_access_roles;
+
+ALTER TABLE hiring.t_job_access DROP COLUMN IF EXISTS granted_at;
+ALTER TABLE hiring.t_job_access DROP COLUMN IF EXISTS granted_by;
+ALTER TABLE hiring.t_job_access DROP COLUMN IF EXISTS roles;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- =============================================================================
+-- Migration: 000009_job_access_roles (UP)
+-- Description: Per-job roles and grant metadata for hiring.t_job_access.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_job_access ADD COLUMN IF NOT EXISTS roles      TEXT[]    NOT NULL DEFAULT '{}';
+ALTER TABLE hiring.t_job_access ADD COLUMN IF NOT EXISTS granted_by UUID      REFERENCES auth.t_users (id) ON DELETE SET NULL;
+ALTER TABLE hiring.t_job_access ADD COLUMN IF NOT EXISTS granted_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+ALTER TABLE hiring.t_job_access
+    ADD CONSTRAINT chk_job_access_roles
+        CHECK (roles <@ ARRAY['interviewer', 'coordinator', 'decision_maker']::TEXT[]);
+
+COMMIT;
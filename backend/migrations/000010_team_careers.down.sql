@@ -0,0 +1,18 @@
+-- =============================================================================
+-- Migration: 000010_team_careers (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS published_at;
+
+DROP TRIGGER IF EXISTS tg_teams_set_slug ON auth.t_teams;
+DROP FUNCTION IF EXISTS auth.f_teams_set_slug();
+
+ALTER TABLE auth.t_teams DROP COLUMN IF EXISTS branding;
+ALTER TABLE auth.t_teams DROP COLUMN IF EXISTS locale;
+ALTER TABLE auth.t_teams DROP COLUMN IF EXISTS slug;
+
+DROP FUNCTION IF EXISTS auth.f_team_slug(TEXT);
+
+COMMIT;
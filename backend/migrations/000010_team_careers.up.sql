@@ -0,0 +1,50 @@
+-- =============================================================================
+-- Migration: 000010_team_careers (UP)
+-- Description: Public careers page settings per team (slug, locale, branding)
+--              and the first publication time of jobs.
+-- =============================================================================
+
+BEGIN;
+
+-- auth.f_team_slug derives an ASCII slug from a team name with a random
+-- suffix; names without latin letters or digits fall back to "team".
+CREATE OR REPLACE FUNCTION auth.f_team_slug(name TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(
+               NULLIF(LEFT(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g')), 48), ''),
+               'team'
+           ) || '-' || SUBSTR(MD5(RANDOM()::TEXT), 1, 6);
+$$ LANGUAGE SQL VOLATILE;
+
+ALTER TABLE auth.t_teams ADD COLUMN IF NOT EXISTS slug     VARCHAR(64);
+ALTER TABLE auth.t_teams ADD COLUMN IF NOT EXISTS locale   VARCHAR(16) NOT NULL DEFAULT 'en';
+ALTER TABLE auth.t_teams ADD COLUMN IF NOT EXISTS branding JSONB       NOT NULL DEFAULT '{}';
+
+UPDATE auth.t_teams SET slug = auth.f_team_slug(name) WHERE slug IS NULL;
+
+ALTER TABLE auth.t_teams
+    ALTER COLUMN slug SET NOT NULL,
+    ADD CONSTRAINT uq_teams_slug UNIQUE (slug),
+    ADD CONSTRAINT chk_teams_slug CHECK (slug ~ '^[a-z0-9]([a-z0-9-]*[a-z0-9])?$');
+
+-- Teams created without an explicit slug get a generated one.
+CREATE OR REPLACE FUNCTION auth.f_teams_set_slug() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.slug IS NULL THEN
+        NEW.slug := auth.f_team_slug(NEW.name);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_teams_set_slug
+    BEFORE INSERT ON auth.t_teams
+    FOR EACH ROW EXECUTE FUNCTION auth.f_teams_set_slug();
+
+ALTER TABLE hiring.t_jobs ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+
+UPDATE hiring.t_jobs
+SET published_at = created_at
+WHERE status IN ('open', 'paused', 'closed', 'filled');
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- =============================================================================
+-- Migration: 000011_candidate_applications (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code = 'candidate_applied');
+
+DELETE FROM hiring.t_action_types WHERE code = 'candidate_applied';
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_entity;
+DROP INDEX IF EXISTS hiring.idx_candidates_job_email;
+
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS resume_file_name;
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS source;
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS phone;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- =============================================================================
+-- Migration: 000011_candidate_applications (UP)
+-- Description: Contact and origin fields for candidates who apply through the
+--              public careers page.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS phone            VARCHAR(32);
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS source           VARCHAR(32) NOT NULL DEFAULT 'manual';
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS resume_file_name VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_candidates_job_email ON hiring.t_candidates (job_id, LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_processing_tasks_entity ON ai_engine.t_processing_tasks (entity_id);
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('candidate_applied', 'Candidate applied through the public careers page')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
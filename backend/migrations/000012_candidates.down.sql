@@ -0,0 +1,18 @@
+-- =============================================================================
+-- Migration: 000012_candidates (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (
+    SELECT id FROM hiring.t_action_types
+    WHERE code IN ('candidate_created', 'candidate_updated', 'candidate_deleted')
+);
+
+DELETE FROM hiring.t_action_types
+WHERE code IN ('candidate_created', 'candidate_updated', 'candidate_deleted');
+
+DROP INDEX IF EXISTS hiring.idx_candidates_job_created;
+
+COMMIT;
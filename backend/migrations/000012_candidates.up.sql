@@ -0,0 +1,18 @@
+-- =============================================================================
+-- Migration: 000012_candidates (UP)
+-- Description: Keyset pagination index and audit actions for the candidate
+--              management API.
+-- =============================================================================
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_candidates_job_created
+    ON hiring.t_candidates (job_id, created_at DESC, id DESC);
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('candidate_created', 'Candidate added by a team member'),
+       ('candidate_updated', 'Candidate contact details edited'),
+       ('candidate_deleted', 'Candidate removed')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
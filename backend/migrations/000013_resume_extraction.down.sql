@@ -0,0 +1,17 @@
+-- =============================================================================
+-- Migration: 000013_resume_extraction (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tg_processing_tasks_notify ON ai_engine.t_processing_tasks;
+DROP FUNCTION IF EXISTS ai_engine.f_processing_tasks_notify();
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+ALTER TABLE ai_engine.t_processing_tasks DROP COLUMN IF EXISTS attempts;
+
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS parsed_at;
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS parsed_language;
+
+COMMIT;
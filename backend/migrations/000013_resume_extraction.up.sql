@@ -0,0 +1,32 @@
+-- =============================================================================
+-- Migration: 000013_resume_extraction (UP)
+-- Description: Extraction results on candidates, retry counter on processing
+--              tasks and a notification that wakes the extraction worker when
+--              a task becomes pending.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS parsed_language VARCHAR(8);
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS parsed_at       TIMESTAMP;
+
+ALTER TABLE ai_engine.t_processing_tasks ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting');
+
+-- Workers LISTEN on "processing_tasks"; the payload is the workflow_id of the
+-- task that became pending.
+CREATE OR REPLACE FUNCTION ai_engine.f_processing_tasks_notify() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('processing_tasks', NEW.workflow_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_processing_tasks_notify
+    AFTER INSERT OR UPDATE OF status ON ai_engine.t_processing_tasks
+    FOR EACH ROW WHEN (NEW.status = 'pending') EXECUTE FUNCTION ai_engine.f_processing_tasks_notify();
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- =============================================================================
+-- Migration: 000014_resume_ocr (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS ai_engine.t_ocr_pages;
+
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS ocr_confidence;
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS parsed_by_ocr;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- =============================================================================
+-- Migration: 000014_resume_ocr (UP)
+-- Description: OCR fallback for scanned resumes: a flag and mean confidence
+--              on candidates whose text was recognized from page images, and
+--              per-page recognition confidence.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS parsed_by_ocr  BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS ocr_confidence NUMERIC(5, 2);
+
+-- Recognition results of the last OCR run, one row per page.
+CREATE TABLE IF NOT EXISTS ai_engine.t_ocr_pages (
+    candidate_id UUID          NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    page         INT           NOT NULL,
+    engine       VARCHAR(32)   NOT NULL,
+    confidence   NUMERIC(5, 2) NOT NULL,
+    chars        INT           NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP     NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (candidate_id, page)
+);
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- =============================================================================
+-- Migration: 000015_candidate_profiles (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (
+    SELECT id FROM hiring.t_action_types
+    WHERE code IN ('profile_corrected', 'profile_extraction_requested')
+);
+
+DELETE FROM hiring.t_action_types WHERE code IN ('profile_corrected', 'profile_extraction_requested');
+
+DROP TRIGGER IF EXISTS tg_processing_tasks_notify ON ai_engine.t_processing_tasks;
+
+CREATE TRIGGER tg_processing_tasks_notify
+    AFTER INSERT OR UPDATE OF status ON ai_engine.t_processing_tasks
+    FOR EACH ROW WHEN (NEW.status = 'pending') EXECUTE FUNCTION ai_engine.f_processing_tasks_notify();
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+CREATE INDEX idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting');
+
+-- Tasks waiting for the profile stage have their text already.
+UPDATE ai_engine.t_processing_tasks SET status = 'completed' WHERE status = 'analyzing';
+
+ALTER TABLE ai_engine.t_processing_tasks DROP COLUMN IF EXISTS locked_until;
+
+-- Keep the corrected profiles as plain data.
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN structured_data_plain JSONB;
+UPDATE hiring.t_candidate_profiles SET structured_data_plain = structured_data;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN structured_data;
+ALTER TABLE hiring.t_candidate_profiles RENAME COLUMN structured_data_plain TO structured_data;
+
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS corrected_by;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS corrected_at;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS extracted_at;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS provider;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS schema_version;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS corrections;
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS extracted_data;
+
+DROP FUNCTION IF EXISTS hiring.f_jsonb_merge_patch(JSONB, JSONB);
+
+COMMIT;
@@ -0,0 +1,90 @@
+-- =============================================================================
+-- Migration: 000015_candidate_profiles (UP)
+-- Description: Structured profiles extracted from resume text. The extracted
+--              document and the recruiters' corrections are stored apart and
+--              structured_data is derived from both, so corrections survive a
+--              re-extraction. Resume tasks gain a profile stage ('analyzing')
+--              and an explicit lease.
+-- =============================================================================
+
+BEGIN;
+
+-- JSON Merge Patch (RFC 7396), the same rules as profile.MergePatch in Go:
+-- objects merge recursively, null removes a field, anything else replaces it.
+CREATE OR REPLACE FUNCTION hiring.f_jsonb_merge_patch(target JSONB, patch JSONB) RETURNS JSONB AS $$
+DECLARE
+    result JSONB;
+    item   RECORD;
+BEGIN
+    IF patch IS NULL THEN
+        RETURN target;
+    END IF;
+
+    IF jsonb_typeof(patch) <> 'object' THEN
+        RETURN patch;
+    END IF;
+
+    IF target IS NULL OR jsonb_typeof(target) <> 'object' THEN
+        result := '{}'::jsonb;
+    ELSE
+        result := target;
+    END IF;
+
+    FOR item IN SELECT key, value FROM jsonb_each(patch) LOOP
+        IF jsonb_typeof(item.value) = 'null' THEN
+            result := result - item.key;
+        ELSE
+            result := jsonb_set(result, ARRAY[item.key], hiring.f_jsonb_merge_patch(result -> item.key, item.value));
+        END IF;
+    END LOOP;
+
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Nothing has filled structured_data so far; it becomes derived.
+ALTER TABLE hiring.t_candidate_profiles DROP COLUMN IF EXISTS structured_data;
+
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS extracted_data JSONB;
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS corrections    JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS schema_version INT;
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS provider       VARCHAR(64);
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS extracted_at   TIMESTAMP;
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS corrected_at   TIMESTAMP;
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN IF NOT EXISTS corrected_by   UUID REFERENCES auth.t_users (id) ON DELETE SET NULL;
+
+ALTER TABLE hiring.t_candidate_profiles ADD COLUMN structured_data JSONB GENERATED ALWAYS AS (
+    CASE
+        WHEN extracted_data IS NULL AND corrections = '{}'::jsonb THEN NULL
+        ELSE hiring.f_jsonb_merge_patch(extracted_data, corrections)
+    END
+) STORED;
+
+-- A task is claimed while locked_until is in the future. A task in
+-- 'analyzing' with no lease waits for the profile stage.
+ALTER TABLE ai_engine.t_processing_tasks ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+UPDATE ai_engine.t_processing_tasks
+SET locked_until = updated_at + INTERVAL '5 minutes'
+WHERE status = 'extracting';
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+CREATE INDEX idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting', 'analyzing');
+
+-- Wake the workers when a task enters either stage unclaimed.
+DROP TRIGGER IF EXISTS tg_processing_tasks_notify ON ai_engine.t_processing_tasks;
+
+CREATE TRIGGER tg_processing_tasks_notify
+    AFTER INSERT OR UPDATE OF status, locked_until ON ai_engine.t_processing_tasks
+    FOR EACH ROW WHEN (NEW.status IN ('pending', 'analyzing') AND NEW.locked_until IS NULL)
+    EXECUTE FUNCTION ai_engine.f_processing_tasks_notify();
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('profile_corrected', 'Recruiter corrected the structured profile of a candidate'),
+       ('profile_extraction_requested', 'Recruiter requested a new extraction of the candidate profile')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- =============================================================================
+-- Migration: 000016_kanban_board (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP INDEX IF EXISTS hiring.idx_status_history_candidate;
+
+ALTER TABLE hiring.t_status_history DROP COLUMN IF EXISTS changed_by;
+
+DROP INDEX IF EXISTS hiring.idx_candidates_board;
+
+COMMIT;
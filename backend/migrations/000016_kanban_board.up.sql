@@ -0,0 +1,18 @@
+-- =============================================================================
+-- Migration: 000016_kanban_board (UP)
+-- Description: Board ordering index, the author of stage changes in the status
+--              history and its per-candidate index.
+-- =============================================================================
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_candidates_board
+    ON hiring.t_candidates (job_id, status, kanban_position, id);
+
+ALTER TABLE hiring.t_status_history
+    ADD COLUMN IF NOT EXISTS changed_by UUID REFERENCES auth.t_users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_status_history_candidate
+    ON hiring.t_status_history (candidate_id, changed_at DESC);
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- =============================================================================
+-- Migration: 000017_pipeline_stages (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code = 'pipeline_changed');
+
+DELETE FROM hiring.t_action_types WHERE code = 'pipeline_changed';
+
+DROP INDEX IF EXISTS hiring.idx_jobs_pipeline_template;
+
+ALTER TABLE hiring.t_jobs DROP COLUMN IF EXISTS pipeline_template_id;
+
+DROP TABLE IF EXISTS hiring.t_pipeline_stages;
+DROP TABLE IF EXISTS hiring.t_pipeline_templates;
+
+DROP TYPE IF EXISTS stage_type;
+
+COMMIT;
@@ -0,0 +1,61 @@
+-- =============================================================================
+-- Migration: 000017_pipeline_stages (UP)
+-- Description: Team pipeline templates, per-job stage overrides and the stage
+--              types and transitions the board enforces.
+-- =============================================================================
+
+BEGIN;
+
+CREATE TYPE stage_type AS ENUM ('new', 'screening', 'interview', 'offer', 'hired', 'rejected');
+
+CREATE TABLE IF NOT EXISTS hiring.t_pipeline_templates (
+    id          UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id     UUID         NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    is_default  BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_by  UUID         REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_pipeline_templates_team_name UNIQUE (team_id, name)
+);
+
+-- At most one default template per team.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pipeline_templates_default
+    ON hiring.t_pipeline_templates (team_id) WHERE is_default;
+
+-- A stage belongs either to a template or to a job that overrides its
+-- template. name is the value stored in hiring.t_candidates.status;
+-- transitions holds the names of the stages a candidate may move to, NULL
+-- meaning the default rules.
+CREATE TABLE IF NOT EXISTS hiring.t_pipeline_stages (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID        REFERENCES hiring.t_pipeline_templates (id) ON DELETE CASCADE,
+    job_id      UUID        REFERENCES hiring.t_jobs (id) ON DELETE CASCADE,
+    name        VARCHAR(32) NOT NULL,
+    type        stage_type  NOT NULL,
+    position    INT         NOT NULL,
+    transitions TEXT[],
+
+    CONSTRAINT chk_pipeline_stages_owner CHECK ((template_id IS NULL) <> (job_id IS NULL)),
+    CONSTRAINT uq_pipeline_stages_template_name UNIQUE (template_id, name),
+    CONSTRAINT uq_pipeline_stages_job_name UNIQUE (job_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_template
+    ON hiring.t_pipeline_stages (template_id, position) WHERE template_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_job
+    ON hiring.t_pipeline_stages (job_id, position) WHERE job_id IS NOT NULL;
+
+ALTER TABLE hiring.t_jobs
+    ADD COLUMN IF NOT EXISTS pipeline_template_id UUID
+        REFERENCES hiring.t_pipeline_templates (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_pipeline_template
+    ON hiring.t_jobs (pipeline_template_id) WHERE pipeline_template_id IS NOT NULL;
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('pipeline_changed', 'Pipeline template or job stages changed')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- =============================================================================
+-- Migration: 000018_board_events (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tg_job_access_notify ON hiring.t_job_access;
+DROP FUNCTION IF EXISTS hiring.f_job_access_notify();
+
+DROP TRIGGER IF EXISTS tg_candidate_scores_notify ON ai_engine.t_candidate_scores;
+DROP FUNCTION IF EXISTS ai_engine.f_candidate_scores_notify();
+
+DROP TRIGGER IF EXISTS tg_candidates_board_move ON hiring.t_candidates;
+DROP TRIGGER IF EXISTS tg_candidates_board_notify ON hiring.t_candidates;
+DROP FUNCTION IF EXISTS hiring.f_candidates_board_notify();
+
+COMMIT;
@@ -0,0 +1,94 @@
+-- =============================================================================
+-- Migration: 000018_board_events (UP)
+-- Description: Notifications on the board_events channel for candidate
+--              changes, new scores and job access changes, fanned out to
+--              board streams by every API replica.
+-- =============================================================================
+
+BEGIN;
+
+CREATE OR REPLACE FUNCTION hiring.f_candidates_board_notify() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('board_events', json_build_object(
+            'type', 'candidate.deleted',
+            'job_id', OLD.job_id,
+            'candidate_id', OLD.id,
+            'old_status', OLD.status
+        )::text);
+
+        RETURN NULL;
+    END IF;
+
+    PERFORM pg_notify('board_events', json_build_object(
+        'type', CASE TG_OP WHEN 'INSERT' THEN 'candidate.created' ELSE 'candidate.moved' END,
+        'job_id', NEW.job_id,
+        'candidate_id', NEW.id,
+        'status', NEW.status,
+        'old_status', CASE TG_OP WHEN 'UPDATE' THEN OLD.status END,
+        'kanban_position', NEW.kanban_position,
+        'first_name', NEW.first_name,
+        'last_name', NEW.last_name
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_candidates_board_notify
+    AFTER INSERT OR DELETE ON hiring.t_candidates
+    FOR EACH ROW EXECUTE FUNCTION hiring.f_candidates_board_notify();
+
+CREATE TRIGGER tg_candidates_board_move
+    AFTER UPDATE OF status, kanban_position ON hiring.t_candidates
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status OR OLD.kanban_position IS DISTINCT FROM NEW.kanban_position)
+    EXECUTE FUNCTION hiring.f_candidates_board_notify();
+
+-- Scores are written by the AI engine, so they are announced from the
+-- database rather than by the API.
+CREATE OR REPLACE FUNCTION ai_engine.f_candidate_scores_notify() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('board_events', json_build_object(
+        'type', 'candidate.scored',
+        'job_id', c.job_id,
+        'candidate_id', NEW.candidate_id,
+        'match_score', NEW.match_score
+    )::text)
+    FROM hiring.t_candidates c
+    WHERE c.id = NEW.candidate_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_candidate_scores_notify
+    AFTER INSERT OR UPDATE OF match_score ON ai_engine.t_candidate_scores
+    FOR EACH ROW EXECUTE FUNCTION ai_engine.f_candidate_scores_notify();
+
+-- Grant changes make open streams of the job re-check the user's access.
+CREATE OR REPLACE FUNCTION hiring.f_job_access_notify() RETURNS TRIGGER AS $$
+DECLARE
+    target hiring.t_job_access%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        target := OLD;
+    ELSE
+        target := NEW;
+    END IF;
+
+    PERFORM pg_notify('board_events', json_build_object(
+        'type', 'access.changed',
+        'job_id', target.job_id,
+        'user_id', target.user_id
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_job_access_notify
+    AFTER INSERT OR UPDATE OR DELETE ON hiring.t_job_access
+    FOR EACH ROW EXECUTE FUNCTION hiring.f_job_access_notify();
+
+COMMIT;
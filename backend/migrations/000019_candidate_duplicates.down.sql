@@ -0,0 +1,28 @@
+-- =============================================================================
+-- Migration: 000019_candidate_duplicates (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code IN ('candidate_merged', 'duplicate_dismissed'));
+
+DELETE FROM hiring.t_action_types WHERE code IN ('candidate_merged', 'duplicate_dismissed');
+
+DROP TRIGGER IF EXISTS tg_resume_embeddings_detect ON ai_engine.t_resume_embeddings;
+DROP FUNCTION IF EXISTS ai_engine.f_resume_embeddings_detect();
+DROP FUNCTION IF EXISTS hiring.f_detect_duplicates(UUID);
+
+DROP TABLE IF EXISTS hiring.t_candidate_merges;
+DROP TABLE IF EXISTS hiring.t_candidate_duplicates;
+
+DROP INDEX IF EXISTS ai_engine.idx_resume_embeddings_team_candidate;
+DROP INDEX IF EXISTS hiring.idx_candidates_name_trgm;
+DROP INDEX IF EXISTS hiring.idx_candidates_phone_norm;
+DROP INDEX IF EXISTS hiring.idx_candidates_email_norm;
+
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS name_norm;
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS phone_norm;
+ALTER TABLE hiring.t_candidates DROP COLUMN IF EXISTS email_norm;
+
+COMMIT;
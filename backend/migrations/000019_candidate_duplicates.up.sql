@@ -0,0 +1,188 @@
+-- =============================================================================
+-- Migration: 000019_candidate_duplicates (UP)
+-- Description: Normalized contact columns, the queue of suspected duplicate
+--              candidates within a team and the audit record of merges.
+--              Detection lives in hiring.f_detect_duplicates so that the API
+--              and resume embeddings written by the AI engine share it.
+-- =============================================================================
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS email_norm VARCHAR
+    GENERATED ALWAYS AS (NULLIF(LOWER(BTRIM(email)), '')) STORED;
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS phone_norm VARCHAR
+    GENERATED ALWAYS AS (NULLIF(REGEXP_REPLACE(phone, '[^0-9]', '', 'g'), '')) STORED;
+ALTER TABLE hiring.t_candidates ADD COLUMN IF NOT EXISTS name_norm VARCHAR
+    GENERATED ALWAYS AS (NULLIF(LOWER(BTRIM(COALESCE(first_name, '') || ' ' || COALESCE(last_name, ''))), '')) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_candidates_email_norm ON hiring.t_candidates (email_norm) WHERE email_norm IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_candidates_phone_norm ON hiring.t_candidates (phone_norm) WHERE phone_norm IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_candidates_name_trgm  ON hiring.t_candidates USING GIN (name_norm gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_resume_embeddings_team_candidate
+    ON ai_engine.t_resume_embeddings (team_id, candidate_id);
+
+-- A suspected pair is stored once, with the smaller id first. Merged pairs
+-- disappear with the removed candidate; dismissed pairs are kept so that
+-- detection does not raise them again.
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_duplicates (
+    id                UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id           UUID        NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    candidate_id      UUID        NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    duplicate_id      UUID        NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    score             REAL        NOT NULL,
+    reasons           TEXT[]      NOT NULL,
+    name_similarity   REAL,
+    resume_similarity REAL,
+    status            VARCHAR(16) NOT NULL DEFAULT 'pending',
+    detected_at       TIMESTAMP   NOT NULL DEFAULT NOW(),
+    resolved_at       TIMESTAMP,
+    resolved_by       UUID        REFERENCES auth.t_users (id) ON DELETE SET NULL,
+
+    CONSTRAINT chk_candidate_duplicates_order CHECK (candidate_id < duplicate_id),
+    CONSTRAINT chk_candidate_duplicates_status CHECK (status IN ('pending', 'dismissed')),
+    CONSTRAINT uq_candidate_duplicates_pair UNIQUE (candidate_id, duplicate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_duplicates_queue
+    ON hiring.t_candidate_duplicates (team_id, status, score DESC, detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_candidate_duplicates_duplicate
+    ON hiring.t_candidate_duplicates (duplicate_id);
+
+-- candidate_id is the candidate that was kept; merged_id no longer exists and
+-- snapshot holds its row, profile and score as they were before the merge.
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_merges (
+    id            UUID      PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id       UUID      NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    candidate_id  UUID      REFERENCES hiring.t_candidates (id) ON DELETE SET NULL,
+    merged_id     UUID      NOT NULL,
+    merged_job_id UUID      REFERENCES hiring.t_jobs (id) ON DELETE SET NULL,
+    snapshot      JSONB     NOT NULL,
+    merged_by     UUID      REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    merged_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_merges_candidate
+    ON hiring.t_candidate_merges (candidate_id, merged_at DESC);
+
+-- f_detect_duplicates compares a candidate with the rest of its team and
+-- records every pair that matches on the normalized email or phone, on a
+-- name trigram similarity of at least 0.8 or on a cosine similarity of the
+-- averaged resume embeddings of at least 0.9. Pending pairs of the candidate
+-- that no longer match are removed. Returns the number of pairs recorded.
+CREATE OR REPLACE FUNCTION hiring.f_detect_duplicates(p_candidate_id UUID) RETURNS INT AS $$
+DECLARE
+    v_team_id   UUID;
+    v_email     VARCHAR;
+    v_phone     VARCHAR;
+    v_name      VARCHAR;
+    v_embedding vector;
+    v_found     INT;
+BEGIN
+    SELECT j.team_id, c.email_norm, c.phone_norm, c.name_norm
+    INTO v_team_id, v_email, v_phone, v_name
+    FROM hiring.t_candidates c
+    JOIN hiring.t_jobs j ON j.id = c.job_id
+    WHERE c.id = p_candidate_id;
+
+    IF NOT FOUND THEN
+        RETURN 0;
+    END IF;
+
+    SELECT AVG(embedding) INTO v_embedding
+    FROM ai_engine.t_resume_embeddings
+    WHERE candidate_id = p_candidate_id;
+
+    WITH resume AS (
+        SELECT e.candidate_id, 1 - (AVG(e.embedding) <=> v_embedding) AS similarity
+        FROM ai_engine.t_resume_embeddings e
+        WHERE v_embedding IS NOT NULL AND e.team_id = v_team_id AND e.candidate_id <> p_candidate_id
+        GROUP BY e.candidate_id
+    ),
+    suspects AS (
+        SELECT id FROM hiring.t_candidates WHERE v_email IS NOT NULL AND email_norm = v_email
+        UNION
+        SELECT id FROM hiring.t_candidates WHERE v_phone IS NOT NULL AND phone_norm = v_phone
+        UNION
+        SELECT id FROM hiring.t_candidates WHERE v_name IS NOT NULL AND name_norm % v_name
+        UNION
+        SELECT candidate_id FROM resume WHERE similarity >= 0.9
+    ),
+    signals AS (
+        SELECT o.id,
+               v_email IS NOT NULL AND o.email_norm = v_email AS email_match,
+               v_phone IS NOT NULL AND o.phone_norm = v_phone AS phone_match,
+               COALESCE(similarity(o.name_norm, v_name), 0)::real AS name_similarity,
+               r.similarity::real AS resume_similarity
+        FROM suspects s
+        JOIN hiring.t_candidates o ON o.id = s.id
+        JOIN hiring.t_jobs j ON j.id = o.job_id
+        LEFT JOIN resume r ON r.candidate_id = o.id
+        WHERE j.team_id = v_team_id AND o.id <> p_candidate_id
+    ),
+    matched AS (
+        SELECT id, name_similarity, resume_similarity,
+               GREATEST(
+                   CASE WHEN email_match THEN 1.0::real END,
+                   CASE WHEN phone_match THEN 0.9::real END,
+                   CASE WHEN resume_similarity >= 0.9 THEN resume_similarity END,
+                   CASE WHEN name_similarity >= 0.8 THEN 0.7::real * name_similarity END
+               ) AS score,
+               ARRAY_REMOVE(ARRAY[
+                   CASE WHEN email_match THEN 'email' END,
+                   CASE WHEN phone_match THEN 'phone' END,
+                   CASE WHEN name_similarity >= 0.8 THEN 'name' END,
+                   CASE WHEN resume_similarity >= 0.9 THEN 'resume' END
+               ], NULL) AS reasons
+        FROM signals
+        WHERE email_match OR phone_match OR name_similarity >= 0.8 OR resume_similarity >= 0.9
+    ),
+    stale AS (
+        DELETE FROM hiring.t_candidate_duplicates d
+        WHERE d.status = 'pending'
+          AND p_candidate_id IN (d.candidate_id, d.duplicate_id)
+          AND NOT EXISTS (SELECT 1 FROM matched m WHERE m.id IN (d.candidate_id, d.duplicate_id))
+    )
+    INSERT INTO hiring.t_candidate_duplicates (
+        team_id, candidate_id, duplicate_id, score, reasons, name_similarity, resume_similarity
+    )
+    SELECT v_team_id, LEAST(p_candidate_id, id), GREATEST(p_candidate_id, id),
+           score, reasons, name_similarity, resume_similarity
+    FROM matched
+    ON CONFLICT (candidate_id, duplicate_id) DO UPDATE
+    SET score = EXCLUDED.score,
+        reasons = EXCLUDED.reasons,
+        name_similarity = EXCLUDED.name_similarity,
+        resume_similarity = EXCLUDED.resume_similarity,
+        detected_at = NOW();
+
+    GET DIAGNOSTICS v_found = ROW_COUNT;
+
+    RETURN v_found;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Embeddings are written by the AI engine, so the resume comparison runs
+-- from the database once they arrive.
+CREATE OR REPLACE FUNCTION ai_engine.f_resume_embeddings_detect() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM hiring.f_detect_duplicates(candidate_id)
+    FROM (SELECT DISTINCT candidate_id FROM inserted) AS c;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_resume_embeddings_detect
+    AFTER INSERT ON ai_engine.t_resume_embeddings
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION ai_engine.f_resume_embeddings_detect();
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('candidate_merged', 'Duplicate candidate merged into another candidate'),
+       ('duplicate_dismissed', 'Suspected duplicate candidates marked as different people')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- =============================================================================
+-- Migration: 000020_candidate_search (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tg_candidate_profiles_search ON hiring.t_candidate_profiles;
+DROP TRIGGER IF EXISTS tg_candidates_search ON hiring.t_candidates;
+
+DROP FUNCTION IF EXISTS hiring.f_candidate_profiles_search();
+DROP FUNCTION IF EXISTS hiring.f_candidates_search();
+DROP FUNCTION IF EXISTS hiring.f_candidate_search_refresh(UUID);
+
+DROP TABLE IF EXISTS hiring.t_candidate_search;
+
+DROP FUNCTION IF EXISTS hiring.f_search_query(TEXT);
+DROP FUNCTION IF EXISTS hiring.f_search_config(VARCHAR);
+
+COMMIT;
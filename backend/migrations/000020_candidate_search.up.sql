@@ -0,0 +1,95 @@
+-- =============================================================================
+-- Migration: 000020_candidate_search (UP)
+-- Description: Full-text search documents of candidates built from the name
+--              and email, the structured profile and the resume text, with
+--              the text search configuration of the resume language, kept
+--              current by triggers on candidates and profiles.
+-- =============================================================================
+
+BEGIN;
+
+-- Resume languages with a stemming configuration; the others are indexed
+-- without stemming.
+CREATE OR REPLACE FUNCTION hiring.f_search_config(language VARCHAR) RETURNS regconfig AS $$
+    SELECT CASE language
+        WHEN 'ru' THEN 'russian'::regconfig
+        WHEN 'en' THEN 'english'::regconfig
+        WHEN 'es' THEN 'spanish'::regconfig
+        ELSE 'simple'::regconfig
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- A search string matches a document of any configuration, so a query is
+-- stemmed with each of them.
+CREATE OR REPLACE FUNCTION hiring.f_search_query(query TEXT) RETURNS tsquery AS $$
+    SELECT websearch_to_tsquery('simple', query)
+        || websearch_to_tsquery('russian', query)
+        || websearch_to_tsquery('english', query)
+        || websearch_to_tsquery('spanish', query);
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_search (
+    candidate_id UUID      PRIMARY KEY REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    config       regconfig NOT NULL,
+    document     tsvector  NOT NULL,
+    location     VARCHAR,
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_search_document
+    ON hiring.t_candidate_search USING GIN (document);
+CREATE INDEX IF NOT EXISTS idx_candidate_search_location
+    ON hiring.t_candidate_search (LOWER(location)) WHERE location IS NOT NULL;
+
+-- Weights: A for the name and email, B for skills, companies and titles, C
+-- for the rest of the profile and D for the resume text, of which only the
+-- first 100 000 characters are indexed.
+CREATE OR REPLACE FUNCTION hiring.f_candidate_search_refresh(p_candidate_id UUID) RETURNS VOID AS $$
+    INSERT INTO hiring.t_candidate_search (candidate_id, config, document, location, updated_at)
+    SELECT c.id, cfg.config,
+           setweight(to_tsvector('simple', COALESCE(c.first_name, '') || ' ' || COALESCE(c.last_name, '') || ' ' || COALESCE(c.email, '')), 'A')
+           || setweight(jsonb_to_tsvector(cfg.config, COALESCE(jsonb_build_object(
+                  'skills', p.structured_data -> 'skills',
+                  'experience', jsonb_path_query_array(p.structured_data, '$.experience[*].company ? (@ != "")')
+                      || jsonb_path_query_array(p.structured_data, '$.experience[*].title ? (@ != "")')
+              ), '{}'), '["string"]'), 'B')
+           || setweight(jsonb_to_tsvector(cfg.config, COALESCE(p.structured_data - 'skills' - 'experience', '{}'), '["string"]'), 'C')
+           || setweight(to_tsvector(cfg.config, LEFT(COALESCE(c.parsed_text, ''), 100000)), 'D'),
+           NULLIF(BTRIM(p.structured_data #>> '{contacts,location}'), ''),
+           NOW()
+    FROM hiring.t_candidates c
+    LEFT JOIN hiring.t_candidate_profiles p ON p.candidate_id = c.id
+    CROSS JOIN LATERAL (SELECT hiring.f_search_config(c.parsed_language) AS config) cfg
+    WHERE c.id = p_candidate_id
+    ON CONFLICT (candidate_id) DO UPDATE
+    SET config = EXCLUDED.config,
+        document = EXCLUDED.document,
+        location = EXCLUDED.location,
+        updated_at = EXCLUDED.updated_at;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION hiring.f_candidates_search() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM hiring.f_candidate_search_refresh(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_candidates_search
+    AFTER INSERT OR UPDATE OF first_name, last_name, email, parsed_text, parsed_language ON hiring.t_candidates
+    FOR EACH ROW EXECUTE FUNCTION hiring.f_candidates_search();
+
+CREATE OR REPLACE FUNCTION hiring.f_candidate_profiles_search() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM hiring.f_candidate_search_refresh(NEW.candidate_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_candidate_profiles_search
+    AFTER INSERT OR UPDATE ON hiring.t_candidate_profiles
+    FOR EACH ROW EXECUTE FUNCTION hiring.f_candidate_profiles_search();
+
+SELECT hiring.f_candidate_search_refresh(id) FROM hiring.t_candidates;
+
+COMMIT;
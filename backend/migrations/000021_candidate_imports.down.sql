@@ -0,0 +1,24 @@
+-- =============================================================================
+-- Migration: 000021_candidate_imports (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code IN ('candidate_import_started', 'candidate_imported'));
+
+DELETE FROM hiring.t_action_types WHERE code IN ('candidate_import_started', 'candidate_imported');
+
+-- The enum value itself cannot be dropped; no task may keep it.
+DELETE FROM ai_engine.t_processing_tasks WHERE workflow_id LIKE 'import:%';
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+CREATE INDEX idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting', 'analyzing');
+
+DROP TABLE IF EXISTS hiring.t_candidate_import_rows;
+DROP TABLE IF EXISTS hiring.t_candidate_imports;
+
+COMMIT;
@@ -0,0 +1,67 @@
+-- =============================================================================
+-- Migration: 000021_candidate_imports (UP)
+-- Description: Bulk candidate imports from a CSV of candidate fields and/or a
+--              ZIP of resumes. An import runs as a processing task with
+--              workflow_id "import:<import id>" in the new 'importing' status;
+--              every source row records its outcome for the summary report.
+-- =============================================================================
+
+-- ALTER TYPE ... ADD VALUE cannot be used in the same transaction that adds it.
+ALTER TYPE task_status ADD VALUE IF NOT EXISTS 'importing';
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_imports (
+    id                UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id           UUID         NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    job_id            UUID         NOT NULL REFERENCES hiring.t_jobs (id) ON DELETE CASCADE,
+    duplicate_policy  VARCHAR(16)  NOT NULL,
+    stage             VARCHAR(32),
+    csv_file_key      VARCHAR,
+    csv_file_name     VARCHAR(255),
+    archive_file_key  VARCHAR,
+    archive_file_name VARCHAR(255),
+    total_rows        INT,
+    created_by        UUID         REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    created_at        TIMESTAMP    NOT NULL DEFAULT NOW(),
+    finished_at       TIMESTAMP,
+
+    CONSTRAINT chk_candidate_imports_policy CHECK (duplicate_policy IN ('skip', 'merge', 'create')),
+    CONSTRAINT chk_candidate_imports_source CHECK (csv_file_key IS NOT NULL OR archive_file_key IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_imports_team
+    ON hiring.t_candidate_imports (team_id, created_at DESC);
+
+-- row_no is the line of the CSV, header included, or the position of the
+-- resume in the archive sorted by name. Rows are processed in order, so an
+-- import resumed after a crash continues after its last recorded row.
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_import_rows (
+    import_id    UUID         NOT NULL REFERENCES hiring.t_candidate_imports (id) ON DELETE CASCADE,
+    row_no       INT          NOT NULL,
+    file_name    VARCHAR(255),
+    first_name   VARCHAR(100),
+    last_name    VARCHAR(100),
+    email        VARCHAR(254),
+    phone        VARCHAR(32),
+    outcome      VARCHAR(16)  NOT NULL,
+    candidate_id UUID         REFERENCES hiring.t_candidates (id) ON DELETE SET NULL,
+    message      TEXT,
+    processed_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (import_id, row_no),
+    CONSTRAINT chk_candidate_import_rows_outcome CHECK (outcome IN ('created', 'merged', 'skipped', 'failed'))
+);
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+CREATE INDEX idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting', 'analyzing', 'importing');
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('candidate_import_started', 'Recruiter started a bulk candidate import'),
+       ('candidate_imported', 'Candidate created or updated by a bulk import')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- =============================================================================
+-- Migration: 000022_candidate_comments (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS hiring.t_notifications;
+DROP TABLE IF EXISTS hiring.t_candidate_comment_mentions;
+DROP TABLE IF EXISTS hiring.t_candidate_comment_revisions;
+DROP TABLE IF EXISTS hiring.t_candidate_comments;
+
+COMMIT;
@@ -0,0 +1,75 @@
+-- =============================================================================
+-- Migration: 000022_candidate_comments (UP)
+-- Description: Threaded comments on candidates with @mentions, the history
+--              of edits and deletions, and in-app notifications of the
+--              mentioned members and of replies.
+-- =============================================================================
+
+BEGIN;
+
+-- Replies point at the top-level comment of their thread and share its
+-- visibility: 'private' comments are seen by their author only, 'team'
+-- comments by everyone who can see the candidate. Deleted comments keep their
+-- row so that threads stay intact.
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_comments (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    candidate_id UUID        NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    parent_id    UUID        REFERENCES hiring.t_candidate_comments (id) ON DELETE CASCADE,
+    author_id    UUID        REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    visibility   VARCHAR(16) NOT NULL,
+    body         TEXT        NOT NULL,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    edited_at    TIMESTAMP,
+    deleted_at   TIMESTAMP,
+
+    CONSTRAINT chk_candidate_comments_visibility CHECK (visibility IN ('private', 'team'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_comments_candidate
+    ON hiring.t_candidate_comments (candidate_id, created_at DESC) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_candidate_comments_parent
+    ON hiring.t_candidate_comments (parent_id, created_at) WHERE parent_id IS NOT NULL;
+
+-- Every edit and the deletion store the body the comment had before.
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_comment_revisions (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID        NOT NULL REFERENCES hiring.t_candidate_comments (id) ON DELETE CASCADE,
+    action     VARCHAR(16) NOT NULL,
+    body       TEXT        NOT NULL,
+    changed_by UUID        REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    changed_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_candidate_comment_revisions_action CHECK (action IN ('edited', 'deleted'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_comment_revisions_comment
+    ON hiring.t_candidate_comment_revisions (comment_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS hiring.t_candidate_comment_mentions (
+    comment_id UUID NOT NULL REFERENCES hiring.t_candidate_comments (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES auth.t_users (id) ON DELETE CASCADE,
+
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_comment_mentions_user
+    ON hiring.t_candidate_comment_mentions (user_id);
+
+CREATE TABLE IF NOT EXISTS hiring.t_notifications (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id      UUID        NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    user_id      UUID        NOT NULL REFERENCES auth.t_users (id) ON DELETE CASCADE,
+    type         VARCHAR(32) NOT NULL,
+    actor_id     UUID        REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    candidate_id UUID        REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    comment_id   UUID        REFERENCES hiring.t_candidate_comments (id) ON DELETE CASCADE,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    read_at      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user
+    ON hiring.t_notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread
+    ON hiring.t_notifications (user_id) WHERE read_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- =============================================================================
+-- Migration: 000023_scorecards (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types WHERE code = 'scorecard_template_changed');
+
+DELETE FROM hiring.t_action_types WHERE code = 'scorecard_template_changed';
+
+DROP TABLE IF EXISTS hiring.t_scorecards;
+DROP TABLE IF EXISTS hiring.t_scorecard_templates;
+
+COMMIT;
@@ -0,0 +1,53 @@
+-- =============================================================================
+-- Migration: 000023_scorecards (UP)
+-- Description: Scorecard templates per job stage and the scorecards that
+--              interviewers submit for candidates.
+-- =============================================================================
+
+BEGIN;
+
+-- Stages are referenced by name, as in hiring.t_candidates.status; renamed
+-- stages are followed by the pipeline migration. The unique constraints are
+-- deferrable so that stages can swap names within one statement.
+CREATE TABLE IF NOT EXISTS hiring.t_scorecard_templates (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id     UUID        NOT NULL REFERENCES hiring.t_jobs (id) ON DELETE CASCADE,
+    stage      VARCHAR(32) NOT NULL,
+    blind      BOOLEAN     NOT NULL DEFAULT TRUE,
+    criteria   JSONB       NOT NULL,
+    updated_by UUID        REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_scorecard_templates_stage UNIQUE (job_id, stage) DEFERRABLE
+);
+
+-- criteria is the template as submitted against, so that a later change of
+-- the template does not reinterpret the ratings. score is the ratings on a
+-- 0-100 scale.
+CREATE TABLE IF NOT EXISTS hiring.t_scorecards (
+    id             UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    candidate_id   UUID        NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    stage          VARCHAR(32) NOT NULL,
+    interviewer_id UUID        REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    criteria       JSONB       NOT NULL,
+    ratings        JSONB       NOT NULL,
+    recommendation VARCHAR(16) NOT NULL,
+    summary        TEXT,
+    score          INT         NOT NULL,
+    submitted_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_scorecards_recommendation CHECK (recommendation IN ('strong_no', 'no', 'yes', 'strong_yes')),
+    CONSTRAINT chk_scorecards_score CHECK (score BETWEEN 0 AND 100),
+    CONSTRAINT uq_scorecards_interviewer UNIQUE (candidate_id, stage, interviewer_id) DEFERRABLE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scorecards_interviewer
+    ON hiring.t_scorecards (interviewer_id);
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('scorecard_template_changed', 'Scorecard template of a job stage set or removed')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
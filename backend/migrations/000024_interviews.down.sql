@@ -0,0 +1,29 @@
+-- =============================================================================
+-- Migration: 000024_interviews (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DELETE FROM hiring.t_activity_logs
+WHERE action_id IN (SELECT id FROM hiring.t_action_types
+                    WHERE code IN ('interview_scheduled', 'interview_rescheduled', 'interview_cancelled'));
+
+DELETE FROM hiring.t_action_types
+WHERE code IN ('interview_scheduled', 'interview_rescheduled', 'interview_cancelled');
+
+-- The enum value itself cannot be dropped; no task may keep it.
+DELETE FROM ai_engine.t_processing_tasks WHERE workflow_id LIKE 'interview-mail:%';
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+CREATE INDEX idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting', 'analyzing', 'importing');
+
+DROP TABLE IF EXISTS hiring.t_interview_mails;
+DROP TABLE IF EXISTS hiring.t_calendar_feeds;
+DROP TABLE IF EXISTS hiring.t_availability_windows;
+DROP TABLE IF EXISTS hiring.t_interview_interviewers;
+DROP TABLE IF EXISTS hiring.t_interviews;
+
+COMMIT;
@@ -0,0 +1,113 @@
+-- =============================================================================
+-- Migration: 000024_interviews (UP)
+-- Description: Scheduled interviews with their interviewers, availability
+--              windows of team members, calendar feed tokens and the queue of
+--              invitation emails. An email is sent by a processing task with
+--              workflow_id "interview-mail:<mail id>" in the new 'sending'
+--              status.
+-- =============================================================================
+
+-- ALTER TYPE ... ADD VALUE cannot be used in the same transaction that adds it.
+ALTER TYPE task_status ADD VALUE IF NOT EXISTS 'sending';
+
+BEGIN;
+
+-- Times are TIMESTAMPTZ: they are shared with calendars in other time zones.
+-- timezone is the IANA zone the interview was planned in and is used to
+-- present its time in emails. sequence is the iCalendar SEQUENCE, raised by
+-- every change so that calendars replace the earlier invitation.
+CREATE TABLE IF NOT EXISTS hiring.t_interviews (
+    id            UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id       UUID         NOT NULL REFERENCES auth.t_teams (id) ON DELETE CASCADE,
+    candidate_id  UUID         NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    stage         VARCHAR(32)  NOT NULL,
+    title         VARCHAR(200) NOT NULL,
+    starts_at     TIMESTAMPTZ  NOT NULL,
+    ends_at       TIMESTAMPTZ  NOT NULL,
+    timezone      VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    location      VARCHAR(500),
+    video_url     VARCHAR(2048),
+    notes         TEXT,
+    status        VARCHAR(16)  NOT NULL DEFAULT 'scheduled',
+    sequence      INT          NOT NULL DEFAULT 0,
+    cancel_reason TEXT,
+    created_by    UUID         REFERENCES auth.t_users (id) ON DELETE SET NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP    NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_interviews_period CHECK (ends_at > starts_at),
+    CONSTRAINT chk_interviews_status CHECK (status IN ('scheduled', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_interviews_candidate
+    ON hiring.t_interviews (candidate_id, starts_at);
+
+CREATE INDEX IF NOT EXISTS idx_interviews_team
+    ON hiring.t_interviews (team_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS hiring.t_interview_interviewers (
+    interview_id UUID NOT NULL REFERENCES hiring.t_interviews (id) ON DELETE CASCADE,
+    user_id      UUID NOT NULL REFERENCES auth.t_users (id) ON DELETE CASCADE,
+
+    PRIMARY KEY (interview_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interview_interviewers_user
+    ON hiring.t_interview_interviewers (user_id);
+
+-- A member with availability windows is only free for interviews inside one
+-- of them; a member without any is free at any time.
+CREATE TABLE IF NOT EXISTS hiring.t_availability_windows (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES auth.t_users (id) ON DELETE CASCADE,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_availability_windows_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_windows_user
+    ON hiring.t_availability_windows (user_id, starts_at);
+
+-- Calendar clients cannot log in, so a feed is addressed by a secret token.
+-- Only its SHA-256 is stored; rotating the token invalidates the old URL.
+CREATE TABLE IF NOT EXISTS hiring.t_calendar_feeds (
+    user_id    UUID      PRIMARY KEY REFERENCES auth.t_users (id) ON DELETE CASCADE,
+    token_hash BYTEA     NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- One invitation email per recipient. The email is composed when it is sent,
+-- from the interview as it is then; kind only selects the wording and
+-- whether the recipient is invited or uninvited.
+CREATE TABLE IF NOT EXISTS hiring.t_interview_mails (
+    id             UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+    interview_id   UUID         NOT NULL REFERENCES hiring.t_interviews (id) ON DELETE CASCADE,
+    kind           VARCHAR(16)  NOT NULL,
+    recipient_type VARCHAR(16)  NOT NULL,
+    email          VARCHAR(254) NOT NULL,
+    name           VARCHAR(200) NOT NULL DEFAULT '',
+    created_at     TIMESTAMP    NOT NULL DEFAULT NOW(),
+    sent_at        TIMESTAMP,
+
+    CONSTRAINT chk_interview_mails_kind CHECK (kind IN ('invite', 'update', 'cancel')),
+    CONSTRAINT chk_interview_mails_recipient CHECK (recipient_type IN ('candidate', 'interviewer'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_interview_mails_interview
+    ON hiring.t_interview_mails (interview_id);
+
+DROP INDEX IF EXISTS ai_engine.idx_processing_tasks_queue;
+
+CREATE INDEX idx_processing_tasks_queue
+    ON ai_engine.t_processing_tasks (updated_at)
+    WHERE status IN ('pending', 'extracting', 'analyzing', 'importing', 'sending');
+
+INSERT INTO hiring.t_action_types (code, description)
+VALUES ('interview_scheduled', 'Interview with a candidate scheduled'),
+       ('interview_rescheduled', 'Interview changed or rescheduled'),
+       ('interview_cancelled', 'Interview cancelled')
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
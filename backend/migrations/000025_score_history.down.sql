@@ -0,0 +1,12 @@
+-- =============================================================================
+-- Migration: 000025_score_history (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tg_candidate_scores_history ON ai_engine.t_candidate_scores;
+DROP FUNCTION IF EXISTS ai_engine.f_candidate_scores_history();
+
+DROP TABLE IF EXISTS ai_engine.t_candidate_score_history;
+
+COMMIT;
@@ -0,0 +1,53 @@
+-- =============================================================================
+-- Migration: 000025_score_history (UP)
+-- Description: History of AI match scores. t_candidate_scores keeps only the
+--              latest score of a candidate; every analysis is also recorded
+--              here so that the candidate timeline shows each of them.
+-- =============================================================================
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS ai_engine.t_candidate_score_history (
+    id           UUID      PRIMARY KEY DEFAULT gen_random_uuid(),
+    candidate_id UUID      NOT NULL REFERENCES hiring.t_candidates (id) ON DELETE CASCADE,
+    match_score  INT,
+    analyzed_at  TIMESTAMP NOT NULL,
+    recorded_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_score_history_candidate
+    ON ai_engine.t_candidate_score_history (candidate_id, analyzed_at);
+
+-- Scores are written by the AI engine, so their history is recorded by the
+-- database rather than by the API. A score without analyzed_at is not an
+-- analysis yet.
+CREATE OR REPLACE FUNCTION ai_engine.f_candidate_scores_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.analyzed_at IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP = 'UPDATE'
+        AND OLD.match_score IS NOT DISTINCT FROM NEW.match_score
+        AND OLD.analyzed_at IS NOT DISTINCT FROM NEW.analyzed_at THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO ai_engine.t_candidate_score_history (candidate_id, match_score, analyzed_at)
+    VALUES (NEW.candidate_id, NEW.match_score, NEW.analyzed_at);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tg_candidate_scores_history
+    AFTER INSERT OR UPDATE OF match_score, analyzed_at ON ai_engine.t_candidate_scores
+    FOR EACH ROW EXECUTE FUNCTION ai_engine.f_candidate_scores_history();
+
+-- Earlier analyses are lost; the current scores start the history.
+INSERT INTO ai_engine.t_candidate_score_history (candidate_id, match_score, analyzed_at)
+SELECT candidate_id, match_score, analyzed_at
+FROM ai_engine.t_candidate_scores
+WHERE analyzed_at IS NOT NULL;
+
+COMMIT;
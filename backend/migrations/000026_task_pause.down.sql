@@ -0,0 +1,9 @@
+-- =============================================================================
+-- Migration: 000026_task_pause (DOWN)
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE ai_engine.t_processing_tasks DROP COLUMN IF EXISTS cancelled_by_pause;
+
+COMMIT;
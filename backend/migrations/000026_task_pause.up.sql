@@ -0,0 +1,25 @@
+-- =============================================================================
+-- Migration: 000026_task_pause (UP)
+-- Description: Marks the AI tasks cancelled by pausing a job, so that
+--              reopening the job re-queues only those and leaves tasks
+--              cancelled for other reasons alone.
+-- =============================================================================
+
+BEGIN;
+
+ALTER TABLE ai_engine.t_processing_tasks
+    ADD COLUMN IF NOT EXISTS cancelled_by_pause BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Cancelled tasks of jobs paused now were most likely cancelled by the pause
+-- and were re-queued on reopening so far.
+UPDATE ai_engine.t_processing_tasks
+SET cancelled_by_pause = TRUE
+WHERE status = 'cancelled'
+  AND entity_id IN (
+      SELECT c.id
+      FROM hiring.t_candidates c
+      JOIN hiring.t_jobs j ON j.id = c.job_id
+      WHERE j.status = 'paused'
+  );
+
+COMMIT;
@@ -18,6 +18,146 @@ type Config struct {
 	Token     Token                `yaml:"token"`
 	RateLimit map[string]RateLimit `yaml:"rate-limit"`
 	Invite    Invite               `yaml:"invite"`
+	Casbin    Casbin               `yaml:"casbin"`
+	Careers   Careers              `yaml:"careers"`
+	Storage   Storage              `yaml:"storage"`
+	Worker    Worker               `yaml:"worker"`
+	Import    Import               `yaml:"import"`
+	OCR       OCR                  `yaml:"ocr"`
+	LLM       LLM                  `yaml:"llm"`
+	Realtime  Realtime             `yaml:"realtime"`
+	Mailer    Mailer               `yaml:"mailer"`
+	Calendar  Calendar             `yaml:"calendar"`
+}
+
+// Mailer configures outgoing email over SMTP.
+type Mailer struct {
+	// Host of the SMTP server; empty disables email.
+	Host string `yaml:"host"`
+	// Port defaults to 465 with TLS set to tls and to 587 otherwise.
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	// Password falls back to SMTP_PASSWORD when empty.
+	Password string `yaml:"password"`
+	// From is the sender address, FromName its display name.
+	From     string `yaml:"from"`
+	FromName string `yaml:"from-name"`
+	// TLS: starttls (default, used when the server offers it), tls for
+	// implicit TLS, or none.
+	TLS     string        `yaml:"tls"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Calendar configures interview invites and calendar feeds.
+type Calendar struct {
+	// Domain is the right-hand side of event UIDs, e.g. "hr.example.com".
+	Domain string `yaml:"domain"`
+	// FeedURL is the public base URL of the calendar feeds; a feed is
+	// <feed-url>/<token>.ics. Defaults to the path of the feed route.
+	FeedURL string `yaml:"feed-url"`
+}
+
+// Realtime configures the board event streams.
+type Realtime struct {
+	// Buffer is how many events a slow client may fall behind before its
+	// stream is closed.
+	Buffer int `yaml:"buffer"`
+	// Heartbeat is how often an idle stream sends a keep-alive comment.
+	Heartbeat time.Duration `yaml:"heartbeat"`
+	// AccessCheck is how often a stream re-checks that its user may still
+	// see the job.
+	AccessCheck time.Duration `yaml:"access-check"`
+	// MaxLifetime closes streams after this long so clients reconnect with a
+	// fresh session.
+	MaxLifetime time.Duration `yaml:"max-lifetime"`
+}
+
+// LLM configures the model that turns resume text into structured profiles.
+type LLM struct {
+	// Provider: stub (default, deterministic local rules) or openai (any
+	// OpenAI-compatible chat completions API, e.g. a local Ollama or vLLM).
+	Provider string `yaml:"provider"`
+	BaseURL  string `yaml:"base-url"`
+	// APIKey falls back to OPENAI_API_KEY when empty.
+	APIKey  string        `yaml:"api-key"`
+	Model   string        `yaml:"model"`
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxInputChars truncates the resume text sent to the model.
+	MaxInputChars int `yaml:"max-input-chars"`
+}
+
+// OCR configures text recognition for scanned resumes.
+type OCR struct {
+	// Engine: tesseract, or empty to disable OCR.
+	Engine string `yaml:"engine"`
+	// Command is the tesseract binary; defaults to "tesseract" from PATH.
+	Command string `yaml:"command"`
+	// Languages are tesseract language codes joined with "+", e.g. "eng+rus".
+	Languages string `yaml:"languages"`
+	// Timeout limits recognition of a single page.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxPages limits how many pages of a document are recognized.
+	MaxPages int `yaml:"max-pages"`
+}
+
+// Import configures bulk candidate imports.
+type Import struct {
+	// MaxArchiveSize limits an uploaded ZIP of resumes, in bytes.
+	MaxArchiveSize int64 `yaml:"max-archive-size"`
+	// MaxRows limits the candidates of one import.
+	MaxRows int `yaml:"max-rows"`
+}
+
+// Worker configures background processing of ai_engine.t_processing_tasks.
+type Worker struct {
+	// Concurrency is the number of tasks processed in parallel.
+	Concurrency int `yaml:"concurrency"`
+	// PollInterval is how often the queue is checked without notifications.
+	PollInterval time.Duration `yaml:"poll-interval"`
+	// Lease is how long a claimed task may stay in progress before another
+	// worker may take it over.
+	Lease time.Duration `yaml:"lease"`
+	// MaxAttempts limits retries of transient failures.
+	MaxAttempts int `yaml:"max-attempts"`
+}
+
+type Storage struct {
+	// Driver: local (default) or s3.
+	Driver string       `yaml:"driver"`
+	Local  LocalStorage `yaml:"local"`
+	S3     S3Storage    `yaml:"s3"`
+	// MaxFileSize limits uploaded files, in bytes.
+	MaxFileSize int64 `yaml:"max-file-size"`
+	// PresignTTL is the lifetime of presigned download links.
+	PresignTTL time.Duration `yaml:"presign-ttl"`
+}
+
+type LocalStorage struct {
+	Root string `yaml:"root"`
+}
+
+// S3Storage configures an S3-compatible bucket, e.g. AWS S3 or MinIO.
+// Empty credentials fall back to AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+type S3Storage struct {
+	Endpoint        string        `yaml:"endpoint"`
+	Region          string        `yaml:"region"`
+	Bucket          string        `yaml:"bucket"`
+	AccessKeyID     string        `yaml:"access-key-id"`
+	SecretAccessKey string        `yaml:"secret-access-key"`
+	PathStyle       bool          `yaml:"path-style"`
+	Timeout         time.Duration `yaml:"timeout"`
+}
+
+type Careers struct {
+	// BaseURL is the public careers site; job links are
+	// <base-url>/<team slug>/jobs/<job id>.
+	BaseURL  string        `yaml:"base-url"`
+	CacheTTL time.Duration `yaml:"cache-ttl"`
+}
+
+type Casbin struct {
+	// PolicySync: add (default), prune or none.
+	PolicySync string `yaml:"policy-sync"`
 }
 
 type Invite struct {
@@ -0,0 +1,130 @@
+// Package extract извлекает текст из резюме без внешних программ: PDF, DOCX,
+// ODT, RTF и простой текст. Текст нормализуется (NFC, пробелы, пустые
+// строки), язык определяется эвристикой по частым словам. Для сканов и
+// фотографий текста нет: Result.ImageOnly сообщает, что нужно
+// распознавание, а PageImages возвращает изображения страниц.
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Форматы документов.
+const (
+	FormatPDF   = "pdf"
+	FormatDOCX  = "docx"
+	FormatODT   = "odt"
+	FormatRTF   = "rtf"
+	FormatText  = "txt"
+	FormatImage = "image"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("extract: unsupported document format")
+	ErrEncrypted         = errors.New("extract: document is encrypted")
+	ErrCorrupted         = errors.New("extract: document is corrupted")
+	ErrTooLarge          = errors.New("extract: document content is too large")
+)
+
+// maxTextSize ограничивает распакованное содержимое, чтобы архив-бомба
+// не исчерпала память.
+const maxTextSize = 64 << 20
+
+// Result — результат извлечения. ImageOnly — документ состоит из
+// изображений страниц (скан или фотография), и текста в нём нет.
+type Result struct {
+	Format    string
+	Text      string
+	Language  string
+	Pages     int
+	ImageOnly bool
+}
+
+// Extract определяет формат по содержимому (расширение fileName — только
+// подсказка для простого текста) и извлекает нормализованный текст.
+func Extract(data []byte, fileName string) (_ *Result, err error) {
+	defer recoverCorrupted(&err)
+
+	format, err := DetectFormat(data, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		text      string
+		pages     int
+		imageOnly bool
+	)
+
+	switch format {
+	case FormatPDF:
+		doc, err := parsePDF(data)
+		if err != nil {
+			return nil, err
+		}
+
+		texts := doc.pageTexts()
+		text, pages, imageOnly = joinPages(texts), len(texts), pdfImageOnly(texts)
+	case FormatImage:
+		pages, imageOnly = 1, true
+	case FormatDOCX:
+		text, err = extractDOCX(data)
+	case FormatODT:
+		text, err = extractODT(data)
+	case FormatRTF:
+		text, err = extractRTF(data)
+	case FormatText:
+		text, err = decodeText(data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	text = Normalize(text)
+
+	return &Result{
+		Format:    format,
+		Text:      text,
+		Language:  DetectLanguage(text),
+		Pages:     pages,
+		ImageOnly: imageOnly,
+	}, nil
+}
+
+// recoverCorrupted превращает панику разбора в ErrCorrupted: разборщик
+// работает с недоверенными файлами, и ошибка в нём не должна ронять
+// процесс.
+func recoverCorrupted(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v", ErrCorrupted, r)
+	}
+}
+
+// DetectFormat распознаёт формат по сигнатуре. Zip-архивы различаются по
+// содержимому: word/document.xml у DOCX и mimetype у ODT.
+func DetectFormat(data []byte, fileName string) (string, error) {
+	switch {
+	case bytes.HasPrefix(bytes.TrimLeft(data[:min(len(data), 1024)], "\x00\t\r\n "), []byte("%PDF-")):
+		return FormatPDF, nil
+	case bytes.HasPrefix(data, []byte("{\\rtf")):
+		return FormatRTF, nil
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return detectZip(data)
+	case imageFormat(data) != "":
+		return FormatImage, nil
+	case bytes.HasPrefix(data, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")):
+		return "", fmt.Errorf("%w: legacy binary office document", ErrUnsupportedFormat)
+	}
+
+	ext := strings.ToLower(path.Ext(fileName))
+	if (ext == ".txt" || ext == "") && looksLikeText(data) {
+		return FormatText, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
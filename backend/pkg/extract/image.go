@@ -0,0 +1,527 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"sort"
+)
+
+// Форматы изображений, которые возвращает PageImages.
+const (
+	ImageJPEG = "jpeg"
+	ImagePNG  = "png"
+	ImageTIFF = "tiff"
+	ImageJP2  = "jp2"
+)
+
+// maxImagePixels ограничивает размер растра, собираемого из PDF.
+const maxImagePixels = 100 << 20
+
+// Image — изображение страницы для распознавания. Page нумеруется с 1.
+type Image struct {
+	Page   int
+	Format string
+	Data   []byte
+}
+
+// imageFormat распознаёт файл-изображение по сигнатуре.
+func imageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xFF\xD8\xFF")):
+		return ImageJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1A\n")):
+		return ImagePNG
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return ImageTIFF
+	case bytes.HasPrefix(data, []byte("\x00\x00\x00\x0CjP  \r\n\x87\n")):
+		return ImageJP2
+	}
+
+	return ""
+}
+
+// PageImages возвращает изображения страниц для распознавания: файл-
+// изображение целиком, а для PDF — самое крупное изображение каждой
+// страницы, которое удалось извлечь. maxPages > 0 ограничивает число страниц.
+func PageImages(data []byte, maxPages int) (_ []Image, err error) {
+	defer recoverCorrupted(&err)
+
+	if format := imageFormat(data); format != "" {
+		return []Image{{Page: 1, Format: format, Data: data}}, nil
+	}
+
+	if format, err := DetectFormat(data, ""); err != nil || format != FormatPDF {
+		return nil, ErrUnsupportedFormat
+	}
+
+	doc, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []Image
+
+	for i, page := range doc.pageTexts() {
+		if maxPages > 0 && len(images) >= maxPages {
+			break
+		}
+
+		streams := append([]*pdfStream(nil), page.images...)
+		sort.SliceStable(streams, func(a, b int) bool {
+			return doc.imageArea(streams[a]) > doc.imageArea(streams[b])
+		})
+
+		for _, s := range streams {
+			img, format, err := doc.imageData(s)
+			if err != nil {
+				continue
+			}
+
+			images = append(images, Image{Page: i + 1, Format: format, Data: img})
+
+			break
+		}
+	}
+
+	return images, nil
+}
+
+func (d *pdfDocument) imageArea(s *pdfStream) int64 {
+	w, _ := d.resolve(s.dict["Width"]).(int64)
+	h, _ := d.resolve(s.dict["Height"]).(int64)
+
+	return w * h
+}
+
+// decodeParms возвращает параметры i-го фильтра потока.
+func (d *pdfDocument) decodeParms(s *pdfStream, i int) pdfDict {
+	switch p := d.resolve(s.dict["DecodeParms"]).(type) {
+	case pdfDict:
+		return p
+	case pdfArray:
+		if i < len(p) {
+			return d.dict(p[i])
+		}
+	}
+
+	return nil
+}
+
+// imageData переводит изображение PDF в файл, понятный движку
+// распознавания: JPEG и JPEG 2000 возвращаются как есть, CCITT G3/G4
+// упаковываются в TIFF, несжатые растры кодируются в PNG.
+func (d *pdfDocument) imageData(s *pdfStream) ([]byte, string, error) {
+	filters := d.filters(s)
+
+	last := pdfName("")
+	if len(filters) > 0 {
+		last = filters[len(filters)-1]
+	}
+
+	switch last {
+	case "DCTDecode", "DCT":
+		data, err := d.applyFilters(s.raw, filters[:len(filters)-1])
+		return data, ImageJPEG, err
+	case "JPXDecode":
+		data, err := d.applyFilters(s.raw, filters[:len(filters)-1])
+		return data, ImageJP2, err
+	case "CCITTFaxDecode", "CCF":
+		data, err := d.applyFilters(s.raw, filters[:len(filters)-1])
+		if err != nil {
+			return nil, "", err
+		}
+
+		return d.ccittTIFF(s, d.decodeParms(s, len(filters)-1), data), ImageTIFF, nil
+	}
+
+	data, err := d.decode(s)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(filters) > 0 {
+		if data, err = undoPredictor(data, d.decodeParms(s, len(filters)-1)); err != nil {
+			return nil, "", err
+		}
+	}
+
+	img, err := d.raster(s, data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	return buf.Bytes(), ImagePNG, nil
+}
+
+func intParam(dict pdfDict, key pdfName, def int) int {
+	switch v := dict[key].(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+
+	return def
+}
+
+// undoPredictor снимает PNG-предикторы (Predictor >= 10), которыми
+// FlateDecode сжимает растры.
+func undoPredictor(data []byte, parms pdfDict) ([]byte, error) {
+	predictor := intParam(parms, "Predictor", 1)
+
+	switch {
+	case predictor == 1:
+		return data, nil
+	case predictor < 10:
+		return nil, fmt.Errorf("%w: TIFF predictor", ErrUnsupportedFormat)
+	}
+
+	colors := intParam(parms, "Colors", 1)
+	bpc := intParam(parms, "BitsPerComponent", 8)
+	columns := intParam(parms, "Columns", 1)
+
+	bpp := max(1, colors*bpc/8)
+	rowLen := (colors*bpc*columns + 7) / 8
+
+	if rowLen <= 0 || colors <= 0 || bpc <= 0 {
+		return nil, ErrCorrupted
+	}
+
+	out := make([]byte, 0, len(data)/(rowLen+1)*rowLen)
+	prev := make([]byte, rowLen)
+
+	for off := 0; off+rowLen+1 <= len(data); off += rowLen + 1 {
+		kind, row := data[off], append([]byte(nil), data[off+1:off+1+rowLen]...)
+
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+
+			up := prev[i]
+
+			switch kind {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+
+		out = append(out, row...)
+		prev = row
+	}
+
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// raster собирает изображение из распакованных отсчётов. Поддерживаются
+// DeviceGray, DeviceRGB, DeviceCMYK, ICCBased и Indexed с 1, 2, 4 и 8 битами
+// на компонент, а также маски ImageMask.
+func (d *pdfDocument) raster(s *pdfStream, data []byte) (image.Image, error) {
+	width := intParam(s.dict, "Width", 0)
+	height := intParam(s.dict, "Height", 0)
+
+	if width <= 0 || height <= 0 || width*height > maxImagePixels {
+		return nil, ErrCorrupted
+	}
+
+	bpc := intParam(s.dict, "BitsPerComponent", 8)
+	mask, _ := d.resolve(s.dict["ImageMask"]).(bool)
+
+	var (
+		comps   = 1
+		palette color.Palette
+	)
+
+	if mask {
+		bpc = 1
+	} else {
+		var err error
+
+		comps, palette, err = d.colorSpace(s.dict["ColorSpace"], 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if bpc != 1 && bpc != 2 && bpc != 4 && bpc != 8 {
+		return nil, fmt.Errorf("%w: %d bits per component", ErrUnsupportedFormat, bpc)
+	}
+
+	if palette != nil {
+		comps = 1
+	}
+
+	rowLen := (width*comps*bpc + 7) / 8
+	if len(data) < rowLen*height {
+		return nil, ErrCorrupted
+	}
+
+	maxValue := (1 << bpc) - 1
+
+	// Decode [1 0] у масок и серых изображений означает инверсию.
+	invert := false
+	if decode, ok := d.resolve(s.dict["Decode"]).(pdfArray); ok && len(decode) >= 2 {
+		invert = number(decode[0]) > number(decode[1])
+	}
+
+	if mask {
+		// По умолчанию у маски 0 — закрашенная (чёрная) точка.
+		invert = !invert
+	}
+
+	sample := func(row []byte, i int) int {
+		bit := i * bpc
+
+		return int(row[bit/8]>>(8-bpc-bit%8)) & maxValue
+	}
+
+	scale := func(v int) uint8 {
+		return uint8(v * 255 / maxValue)
+	}
+
+	rect := image.Rect(0, 0, width, height)
+
+	switch {
+	case palette != nil:
+		img := image.NewPaletted(rect, palette)
+
+		for y := 0; y < height; y++ {
+			row := data[y*rowLen : (y+1)*rowLen]
+			for x := 0; x < width; x++ {
+				img.Pix[y*img.Stride+x] = uint8(min(sample(row, x), len(palette)-1))
+			}
+		}
+
+		return img, nil
+	case comps == 1:
+		img := image.NewGray(rect)
+
+		for y := 0; y < height; y++ {
+			row := data[y*rowLen : (y+1)*rowLen]
+			for x := 0; x < width; x++ {
+				v := scale(sample(row, x))
+				if invert {
+					v = 255 - v
+				}
+
+				img.Pix[y*img.Stride+x] = v
+			}
+		}
+
+		return img, nil
+	case comps == 3 || comps == 4:
+		img := image.NewRGBA(rect)
+
+		for y := 0; y < height; y++ {
+			row := data[y*rowLen : (y+1)*rowLen]
+			for x := 0; x < width; x++ {
+				var c [4]uint8
+				for k := 0; k < comps; k++ {
+					c[k] = scale(sample(row, x*comps+k))
+				}
+
+				if comps == 4 {
+					c[0], c[1], c[2] = color.CMYKToRGB(c[0], c[1], c[2], c[3])
+				}
+
+				i := y*img.Stride + x*4
+				img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c[0], c[1], c[2], 255
+			}
+		}
+
+		return img, nil
+	}
+
+	return nil, fmt.Errorf("%w: %d color components", ErrUnsupportedFormat, comps)
+}
+
+// colorSpace возвращает число компонентов цветового пространства или
+// палитру для Indexed.
+func (d *pdfDocument) colorSpace(v any, depth int) (int, color.Palette, error) {
+	if depth > 4 {
+		return 0, nil, ErrCorrupted
+	}
+
+	switch cs := d.resolve(v).(type) {
+	case pdfName:
+		switch cs {
+		case "DeviceGray", "CalGray", "G":
+			return 1, nil, nil
+		case "DeviceRGB", "CalRGB", "RGB":
+			return 3, nil, nil
+		case "DeviceCMYK", "CMYK":
+			return 4, nil, nil
+		}
+	case pdfArray:
+		if len(cs) == 0 {
+			break
+		}
+
+		switch d.resolve(cs[0]) {
+		case pdfName("ICCBased"):
+			if len(cs) > 1 {
+				if n := intParam(d.dict(cs[1]), "N", 0); n > 0 {
+					return n, nil, nil
+				}
+			}
+		case pdfName("CalGray"):
+			return 1, nil, nil
+		case pdfName("CalRGB"), pdfName("Lab"):
+			return 3, nil, nil
+		case pdfName("Indexed"), pdfName("I"):
+			if len(cs) < 4 {
+				break
+			}
+
+			base, _, err := d.colorSpace(cs[1], depth+1)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			return 1, d.palette(base, cs[3]), nil
+		}
+	}
+
+	return 0, nil, fmt.Errorf("%w: color space", ErrUnsupportedFormat)
+}
+
+func (d *pdfDocument) palette(base int, lookup any) color.Palette {
+	var table []byte
+
+	switch t := d.resolve(lookup).(type) {
+	case pdfString:
+		table = t
+	case *pdfStream:
+		table, _ = d.decode(t)
+	}
+
+	var palette color.Palette
+
+	for i := 0; i+base <= len(table) && len(palette) < 256; i += base {
+		c := table[i : i+base]
+
+		switch base {
+		case 1:
+			palette = append(palette, color.Gray{Y: c[0]})
+		case 3:
+			palette = append(palette, color.RGBA{R: c[0], G: c[1], B: c[2], A: 255})
+		case 4:
+			r, g, b := color.CMYKToRGB(c[0], c[1], c[2], c[3])
+			palette = append(palette, color.RGBA{R: r, G: g, B: b, A: 255})
+		default:
+			return nil
+		}
+	}
+
+	if len(palette) == 0 {
+		return nil
+	}
+
+	return palette
+}
+
+// ccittTIFF упаковывает поток CCITTFaxDecode в одностраничный TIFF.
+// Фотометрия WhiteIsZero соответствует порядку серий в коде CCITT;
+// BlackIs1 влияет только на то, как PDF отображает биты, и не нужен.
+func (d *pdfDocument) ccittTIFF(s *pdfStream, parms pdfDict, data []byte) []byte {
+	k := intParam(parms, "K", 0)
+	width := intParam(parms, "Columns", 1728)
+	height := intParam(parms, "Rows", intParam(s.dict, "Height", 0))
+
+	compression, options := 4, -1
+	if k >= 0 {
+		compression, options = 3, 0
+		if k > 0 {
+			options = 1
+		}
+	}
+
+	type entry struct {
+		tag, typ uint16
+		value    uint32
+	}
+
+	const (
+		typeShort = 3
+		typeLong  = 4
+	)
+
+	entries := []entry{
+		{256, typeLong, uint32(width)},
+		{257, typeLong, uint32(height)},
+		{258, typeShort, 1},
+		{259, typeShort, uint32(compression)},
+		{262, typeShort, 0},
+		{273, typeLong, 0},
+		{277, typeShort, 1},
+		{278, typeLong, uint32(height)},
+		{279, typeLong, uint32(len(data))},
+	}
+
+	if options >= 0 {
+		entries = append(entries, entry{292, typeLong, uint32(options)})
+	}
+
+	dataOffset := 8 + 2 + len(entries)*12 + 4
+	entries[5].value = uint32(dataOffset)
+
+	buf := make([]byte, 0, dataOffset+len(data))
+	buf = append(buf, 'I', 'I', 42, 0)
+	buf = binary.LittleEndian.AppendUint32(buf, 8)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(entries)))
+
+	for _, e := range entries {
+		buf = binary.LittleEndian.AppendUint16(buf, e.tag)
+		buf = binary.LittleEndian.AppendUint16(buf, e.typ)
+		buf = binary.LittleEndian.AppendUint32(buf, 1)
+
+		if e.typ == typeShort {
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(e.value))
+			buf = binary.LittleEndian.AppendUint16(buf, 0)
+		} else {
+			buf = binary.LittleEndian.AppendUint32(buf, e.value)
+		}
+	}
+
+	buf = binary.LittleEndian.AppendUint32(buf, 0)
+
+	return append(buf, data...)
+}
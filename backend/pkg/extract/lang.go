@@ -0,0 +1,116 @@
+package extract
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Языки, которые распознаёт DetectLanguage (ISO 639-1).
+const (
+	LangEnglish    = "en"
+	LangRussian    = "ru"
+	LangUkrainian  = "uk"
+	LangSpanish    = "es"
+	LangGerman     = "de"
+	LangFrench     = "fr"
+	LangPortuguese = "pt"
+	LangItalian    = "it"
+)
+
+// minLanguageWords — меньше слов недостаточно для уверенного ответа.
+const minLanguageWords = 5
+
+// stopWords — самые частые служебные слова латинских языков.
+var stopWords = map[string][]string{
+	LangEnglish:    {"the", "and", "of", "to", "in", "for", "with", "on", "at", "is", "as", "by", "from", "my", "i", "experience", "skills", "work", "years"},
+	LangSpanish:    {"de", "la", "el", "en", "y", "los", "las", "del", "con", "por", "para", "una", "un", "se", "que", "experiencia", "años", "trabajo"},
+	LangGerman:     {"der", "die", "das", "und", "mit", "von", "zu", "im", "den", "für", "ist", "ein", "eine", "auf", "bei", "erfahrung", "jahre"},
+	LangFrench:     {"le", "la", "les", "et", "des", "du", "de", "en", "pour", "avec", "dans", "une", "un", "sur", "au", "expérience", "ans"},
+	LangPortuguese: {"de", "da", "do", "em", "e", "os", "as", "dos", "das", "com", "para", "uma", "um", "no", "na", "experiência", "anos"},
+	LangItalian:    {"di", "il", "la", "e", "in", "per", "con", "del", "della", "che", "un", "una", "nel", "dei", "gli", "esperienza", "anni"},
+}
+
+var stopWordSets = func() map[string]map[string]struct{} {
+	sets := make(map[string]map[string]struct{}, len(stopWords))
+
+	for lang, words := range stopWords {
+		set := make(map[string]struct{}, len(words))
+		for _, w := range words {
+			set[w] = struct{}{}
+		}
+
+		sets[lang] = set
+	}
+
+	return sets
+}()
+
+// DetectLanguage возвращает код языка текста или пустую строку, если язык
+// определить не удалось. Кириллица отличается от латиницы по алфавиту,
+// украинский от русского — по характерным буквам, латинские языки — по
+// доле служебных слов.
+func DetectLanguage(text string) string {
+	var (
+		latin, cyrillic int
+		ukrainian       int
+		russian         int
+	)
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+
+			switch unicode.ToLower(r) {
+			case 'і', 'ї', 'є', 'ґ':
+				ukrainian++
+			case 'ы', 'э', 'ъ', 'ё':
+				russian++
+			}
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	if len(words) < minLanguageWords {
+		return ""
+	}
+
+	if cyrillic > latin {
+		if ukrainian > russian {
+			return LangUkrainian
+		}
+
+		return LangRussian
+	}
+
+	if latin == 0 {
+		return ""
+	}
+
+	best, bestScore := "", 0
+
+	for _, lang := range []string{LangEnglish, LangSpanish, LangGerman, LangFrench, LangPortuguese, LangItalian} {
+		score := 0
+
+		for _, w := range words {
+			if _, ok := stopWordSets[lang][w]; ok {
+				score++
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore = lang, score
+		}
+	}
+
+	if bestScore*50 < len(words) {
+		return ""
+	}
+
+	return best
+}
@@ -0,0 +1,211 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	nsWord       = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	nsODFText    = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	nsODFTable   = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	nsODFOffice  = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odtMediaType = "application/vnd.oasis.opendocument.text"
+)
+
+// detectZip различает DOCX и ODT по файлам внутри архива.
+func detectZip(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", ErrCorrupted
+	}
+
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return FormatDOCX, nil
+		case "mimetype":
+			mimetype, err := readZipFile(f, 256)
+			if err == nil && strings.TrimSpace(string(mimetype)) == odtMediaType {
+				return FormatODT, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: unknown zip document", ErrUnsupportedFormat)
+}
+
+// openZipXML открывает XML-файл архива с ограничением на распакованный размер.
+func openZipXML(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+
+	for _, f := range zr.File {
+		if f.Name == name {
+			return readZipFile(f, maxTextSize)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s is missing", ErrCorrupted, name)
+}
+
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	defer rc.Close()
+
+	body, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+
+	if int64(len(body)) > limit {
+		return nil, ErrTooLarge
+	}
+
+	return body, nil
+}
+
+// extractDOCX собирает текст из word/document.xml: содержимое w:t,
+// табуляции, переносы строк; абзацы и строки таблиц дают перевод строки,
+// ячейки — табуляцию.
+func extractDOCX(data []byte) (string, error) {
+	body, err := openZipXML(data, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		out    strings.Builder
+		inText bool
+	)
+
+	dec := xml.NewDecoder(bytes.NewReader(body))
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != nsWord {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				out.WriteByte('\t')
+			case "br", "cr":
+				out.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if t.Name.Space != nsWord {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p", "tr":
+				out.WriteByte('\n')
+			case "tc":
+				out.WriteByte('\t')
+			}
+		case xml.CharData:
+			if inText {
+				out.Write(t)
+			}
+		}
+	}
+
+	return out.String(), nil
+}
+
+// extractODT собирает текст из content.xml: всё содержимое office:body,
+// где text:p и text:h — абзацы, text:s — пробелы, text:tab — табуляция.
+func extractODT(data []byte) (string, error) {
+	body, err := openZipXML(data, "content.xml")
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		out    strings.Builder
+		inBody bool
+	)
+
+	dec := xml.NewDecoder(bytes.NewReader(body))
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrCorrupted, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == nsODFOffice && t.Name.Local == "body" {
+				inBody = true
+			}
+
+			if t.Name.Space != nsODFText {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "s":
+				n := 1
+				for _, a := range t.Attr {
+					if a.Name.Local == "c" {
+						if c, err := strconv.Atoi(a.Value); err == nil && c > 0 {
+							n = min(c, 100)
+						}
+					}
+				}
+
+				out.WriteString(strings.Repeat(" ", n))
+			case "tab":
+				out.WriteByte('\t')
+			case "line-break":
+				out.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == nsODFOffice && t.Name.Local == "body":
+				inBody = false
+			case t.Name.Space == nsODFText && (t.Name.Local == "p" || t.Name.Local == "h"):
+				out.WriteByte('\n')
+			case t.Name.Space == nsODFTable && t.Name.Local == "table-cell":
+				out.WriteByte('\t')
+			}
+		case xml.CharData:
+			if inBody {
+				out.WriteString(strings.ReplaceAll(string(t), "\n", " "))
+			}
+		}
+	}
+
+	return out.String(), nil
+}
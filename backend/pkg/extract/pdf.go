@@ -0,0 +1,426 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxPDFPages ограничивает обход дерева страниц.
+const maxPDFPages = 2000
+
+// minPageText — сколько непробельных символов в среднем на страницу
+// должно быть у документа, чтобы он не считался сканом.
+const minPageText = 20
+
+var pdfObjHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// pdfDocument — объекты PDF, собранные сканированием файла. Таблица xref не
+// читается: смещения в повреждённых файлах часто неверны, а заголовки
+// "N G obj" находятся надёжно. Более поздние определения объекта заменяют
+// ранние, как при инкрементальных обновлениях.
+type pdfDocument struct {
+	objects map[int64]any
+	trailer pdfDict
+	pages   []pdfPage
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+	content   []byte
+}
+
+// pdfPageText — текст страницы и изображения на ней.
+type pdfPageText struct {
+	text   string
+	images []*pdfStream
+}
+
+func parsePDF(data []byte) (*pdfDocument, error) {
+	doc := &pdfDocument{objects: map[int64]any{}, trailer: pdfDict{}}
+
+	// end — конец последнего разобранного объекта: заголовки внутри его
+	// данных (например, в двоичном потоке) пропускаются.
+	end := 0
+
+	for _, m := range pdfObjHeader.FindAllSubmatchIndex(data, -1) {
+		if m[0] < end {
+			continue
+		}
+
+		var num int64
+		if _, err := fmt.Sscan(string(data[m[2]:m[3]]), &num); err != nil {
+			continue
+		}
+
+		l := &pdfLexer{data: data, pos: m[1]}
+
+		obj, ok := l.object(0)
+		if !ok {
+			continue
+		}
+
+		end = l.pos
+		doc.objects[num] = obj
+
+		if s, ok := obj.(*pdfStream); ok && s.dict["Type"] == pdfName("XRef") {
+			doc.mergeTrailer(s.dict)
+		}
+	}
+
+	for _, idx := range allIndexes(data, []byte("trailer")) {
+		l := &pdfLexer{data: data, pos: idx + len("trailer")}
+		if obj, ok := l.object(0); ok {
+			if dict, ok := obj.(pdfDict); ok {
+				doc.mergeTrailer(dict)
+			}
+		}
+	}
+
+	if doc.trailer["Encrypt"] != nil {
+		return nil, ErrEncrypted
+	}
+
+	doc.loadObjectStreams()
+
+	if len(doc.objects) == 0 {
+		return nil, ErrCorrupted
+	}
+
+	doc.loadPages()
+
+	return doc, nil
+}
+
+func (d *pdfDocument) mergeTrailer(dict pdfDict) {
+	for k, v := range dict {
+		d.trailer[k] = v
+	}
+}
+
+// loadObjectStreams распаковывает объекты из потоков /ObjStm (PDF 1.5+).
+// Объекты, уже найденные напрямую, не заменяются.
+func (d *pdfDocument) loadObjectStreams() {
+	nums := make([]int64, 0)
+	for num, obj := range d.objects {
+		if s, ok := obj.(*pdfStream); ok && s.dict["Type"] == pdfName("ObjStm") {
+			nums = append(nums, num)
+		}
+	}
+
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+
+	for _, num := range nums {
+		s := d.objects[num].(*pdfStream)
+
+		data, err := d.decode(s)
+		if err != nil {
+			continue
+		}
+
+		n, _ := d.resolve(s.dict["N"]).(int64)
+		first, _ := d.resolve(s.dict["First"]).(int64)
+
+		if first <= 0 || int(first) > len(data) {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:first]}
+
+		for i := int64(0); i < n; i++ {
+			objNum, ok1 := header.token()
+			offset, ok2 := header.token()
+
+			on, isNum := objNum.(int64)
+			off, isOff := offset.(int64)
+
+			if !ok1 || !ok2 || !isNum || !isOff {
+				break
+			}
+
+			if _, exists := d.objects[on]; exists {
+				continue
+			}
+
+			l := &pdfLexer{data: data, pos: int(first + off)}
+			if l.pos >= len(data) {
+				continue
+			}
+
+			if obj, ok := l.object(0); ok {
+				d.objects[on] = obj
+			}
+		}
+	}
+}
+
+// resolve раскрывает ссылку; цепочки ссылок ограничены по глубине.
+func (d *pdfDocument) resolve(v any) any {
+	for i := 0; i < 16; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+
+		v = d.objects[ref.num]
+	}
+
+	return nil
+}
+
+func (d *pdfDocument) dict(v any) pdfDict {
+	switch t := d.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case *pdfStream:
+		return t.dict
+	}
+
+	return nil
+}
+
+// loadPages обходит дерево страниц от /Root /Pages, наследуя /Resources.
+// Если каталог не найден, берутся все объекты /Type /Page по порядку.
+func (d *pdfDocument) loadPages() {
+	visited := map[int64]bool{}
+	root := d.dict(d.trailer["Root"])
+
+	var walk func(node any, resources pdfDict, depth int)
+
+	walk = func(node any, resources pdfDict, depth int) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref.num] {
+				return
+			}
+
+			visited[ref.num] = true
+		}
+
+		dict := d.dict(node)
+		if dict == nil || depth > 32 || len(d.pages) >= maxPDFPages {
+			return
+		}
+
+		if r := d.dict(dict["Resources"]); r != nil {
+			resources = r
+		}
+
+		kids, isTree := d.resolve(dict["Kids"]).(pdfArray)
+		if !isTree || dict["Type"] == pdfName("Page") {
+			d.addPage(dict, resources)
+			return
+		}
+
+		for _, kid := range kids {
+			walk(kid, resources, depth+1)
+		}
+	}
+
+	if root != nil {
+		walk(root["Pages"], nil, 0)
+	}
+
+	if len(d.pages) > 0 {
+		return
+	}
+
+	nums := make([]int64, 0)
+	for num, obj := range d.objects {
+		if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+
+	for _, num := range nums {
+		if len(d.pages) >= maxPDFPages {
+			break
+		}
+
+		dict := d.objects[num].(pdfDict)
+		d.addPage(dict, d.dict(dict["Resources"]))
+	}
+}
+
+func (d *pdfDocument) addPage(dict, resources pdfDict) {
+	var content bytes.Buffer
+
+	var streams []any
+	switch c := d.resolve(dict["Contents"]).(type) {
+	case *pdfStream:
+		streams = []any{c}
+	case pdfArray:
+		streams = c
+	}
+
+	for _, s := range streams {
+		if stream, ok := d.resolve(s).(*pdfStream); ok {
+			if data, err := d.decode(stream); err == nil {
+				content.Write(data)
+				content.WriteByte('\n')
+			}
+		}
+	}
+
+	d.pages = append(d.pages, pdfPage{
+		dict:      dict,
+		resources: resources,
+		content:   content.Bytes(),
+	})
+}
+
+// decode применяет фильтры потока. Поддерживаются FlateDecode,
+// ASCIIHexDecode и ASCII85Decode; для остальных (изображения) — ошибка.
+func (d *pdfDocument) decode(s *pdfStream) ([]byte, error) {
+	return d.applyFilters(s.raw, d.filters(s))
+}
+
+// filters возвращает имена фильтров потока по порядку применения.
+func (d *pdfDocument) filters(s *pdfStream) []pdfName {
+	var list []any
+
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		list = []any{f}
+	case pdfArray:
+		list = f
+	}
+
+	names := make([]pdfName, 0, len(list))
+	for _, f := range list {
+		name, _ := d.resolve(f).(pdfName)
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (d *pdfDocument) applyFilters(data []byte, filters []pdfName) ([]byte, error) {
+	for _, name := range filters {
+		var err error
+
+		switch name {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data)
+		case "ASCIIHexDecode", "AHx":
+			data = (&pdfLexer{data: append(append([]byte{'<'}, data...), '>')}).hexString()
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("%w: filter %s", ErrUnsupportedFormat, name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) > maxTextSize {
+			return nil, ErrTooLarge
+		}
+	}
+
+	return data, nil
+}
+
+// inflate распаковывает zlib-поток. Обрезанные потоки встречаются часто,
+// поэтому возвращается всё, что удалось прочитать.
+func inflate(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(io.LimitReader(zr, maxTextSize+1))
+	if err != nil && len(out) == 0 {
+		return nil, ErrCorrupted
+	}
+
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+
+	out := make([]byte, len(data))
+
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+
+	return out[:n], nil
+}
+
+func allIndexes(data, sep []byte) []int {
+	var idx []int
+
+	for off := 0; ; {
+		i := bytes.Index(data[off:], sep)
+		if i < 0 {
+			return idx
+		}
+
+		idx = append(idx, off+i)
+		off += i + len(sep)
+	}
+}
+
+// pageTexts извлекает текст каждой страницы.
+func (d *pdfDocument) pageTexts() []pdfPageText {
+	texts := make([]pdfPageText, len(d.pages))
+
+	for i, p := range d.pages {
+		in := newPDFInterpreter(d)
+		in.run(p.content, p.resources, 0)
+
+		texts[i] = pdfPageText{
+			text:   in.out.String(),
+			images: in.images,
+		}
+	}
+
+	return texts
+}
+
+// joinPages возвращает текст всех страниц, разделённых пустой строкой.
+func joinPages(pages []pdfPageText) string {
+	parts := make([]string, len(pages))
+
+	for i, p := range pages {
+		parts[i] = p.text
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// pdfImageOnly сообщает, что документ — скан: на страницах есть изображения,
+// а текста почти нет (колонтитулы, номера страниц).
+func pdfImageOnly(pages []pdfPageText) bool {
+	var images, chars int
+
+	for _, p := range pages {
+		images += len(p.images)
+
+		for _, r := range p.text {
+			if !unicode.IsSpace(r) {
+				chars++
+			}
+		}
+	}
+
+	return images > 0 && chars < minPageText*len(pages)
+}
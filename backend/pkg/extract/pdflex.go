@@ -0,0 +1,420 @@
+package extract
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Объекты PDF после разбора: nil, bool, int64, float64, pdfName, pdfString,
+// pdfArray, pdfDict, pdfRef, *pdfStream и pdfKeyword (операторы потоков
+// содержимого).
+type (
+	pdfName    string
+	pdfString  []byte
+	pdfArray   []any
+	pdfDict    map[pdfName]any
+	pdfKeyword string
+)
+
+type pdfRef struct {
+	num, gen int64
+}
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+// pdfLexer читает объекты PDF из среза байт.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// token возвращает следующий простой токен: числа, имена, строки, ключевые
+// слова и разделители "<<", ">>", "[", "]". ok=false — конец данных.
+func (l *pdfLexer) token() (tok any, ok bool) {
+	l.skipSpace()
+
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	c := l.data[l.pos]
+
+	switch {
+	case c == '/':
+		return l.name(), true
+	case c == '(':
+		return l.literalString(), true
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<"), true
+		}
+
+		return l.hexString(), true
+	case c == '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+			return pdfKeyword(">>"), true
+		}
+
+		return pdfKeyword(">"), true
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return pdfKeyword(string(c)), true
+	case c == ')':
+		l.pos++
+		return pdfKeyword(")"), true
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+
+	word := string(l.data[start:l.pos])
+
+	if n, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return n, true
+	}
+
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, true
+	}
+
+	switch word {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+
+	return pdfKeyword(word), true
+}
+
+func (l *pdfLexer) name() pdfName {
+	l.pos++
+
+	var b []byte
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFSpace(c) || isPDFDelimiter(c) {
+			break
+		}
+
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				l.pos += 3
+
+				continue
+			}
+		}
+
+		b = append(b, c)
+		l.pos++
+	}
+
+	return pdfName(b)
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++
+
+	var (
+		b     []byte
+		depth = 1
+	)
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+
+			e := l.data[l.pos]
+			l.pos++
+
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+
+					for k := 0; k < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; k++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+
+		b = append(b, c)
+	}
+
+	return b
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++
+
+	var (
+		b    []byte
+		half = -1
+	)
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		if c == '>' {
+			break
+		}
+
+		v := hexValue(c)
+		if v < 0 {
+			continue
+		}
+
+		if half < 0 {
+			half = v
+		} else {
+			b = append(b, byte(half<<4|v))
+			half = -1
+		}
+	}
+
+	if half >= 0 {
+		b = append(b, byte(half<<4))
+	}
+
+	return b
+}
+
+func hexValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+
+	return -1
+}
+
+// object читает полный объект: массивы, словари, ссылки "N G R" и потоки.
+func (l *pdfLexer) object(depth int) (any, bool) {
+	if depth > 64 {
+		return nil, false
+	}
+
+	tok, ok := l.token()
+	if !ok {
+		return nil, false
+	}
+
+	switch t := tok.(type) {
+	case pdfKeyword:
+		switch t {
+		case "[":
+			var arr pdfArray
+
+			for {
+				l.skipSpace()
+
+				if l.pos < len(l.data) && l.data[l.pos] == ']' {
+					l.pos++
+					return arr, true
+				}
+
+				v, ok := l.object(depth + 1)
+				if !ok {
+					return arr, false
+				}
+
+				if kw, isKw := v.(pdfKeyword); isKw && (kw == "]" || kw == ">>") {
+					return arr, true
+				}
+
+				arr = append(arr, v)
+			}
+		case "<<":
+			dict := pdfDict{}
+
+			for {
+				l.skipSpace()
+
+				if bytes.HasPrefix(l.data[l.pos:], []byte(">>")) {
+					l.pos += 2
+					return l.maybeStream(dict), true
+				}
+
+				key, ok := l.token()
+				if !ok {
+					return dict, false
+				}
+
+				name, isName := key.(pdfName)
+				if !isName {
+					if kw, isKw := key.(pdfKeyword); isKw && kw == ">>" {
+						return l.maybeStream(dict), true
+					}
+
+					continue
+				}
+
+				v, ok := l.object(depth + 1)
+				if !ok {
+					return dict, false
+				}
+
+				dict[name] = v
+			}
+		}
+
+		return t, true
+	case int64:
+		// Проверяем, не ссылка ли это "N G R".
+		save := l.pos
+
+		gen, ok := l.token()
+		if g, isInt := gen.(int64); ok && isInt {
+			if kw, ok := l.token(); ok && kw == pdfKeyword("R") {
+				return pdfRef{num: t, gen: g}, true
+			}
+		}
+
+		l.pos = save
+
+		return t, true
+	}
+
+	return tok, true
+}
+
+// maybeStream превращает словарь в поток, если за ним следует "stream".
+// Длина берётся из /Length, если она прямая и совпадает с "endstream",
+// иначе ищется ближайший "endstream".
+func (l *pdfLexer) maybeStream(dict pdfDict) any {
+	save := l.pos
+	l.skipSpace()
+
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		l.pos = save
+		return dict
+	}
+
+	l.pos += len("stream")
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+
+	start := l.pos
+
+	if n, ok := dict["Length"].(int64); ok && n >= 0 && n <= int64(len(l.data)-start) {
+		end := start + int(n)
+		rest := bytes.TrimLeft(l.data[end:min(end+32, len(l.data))], "\r\n \t")
+
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			l.pos = end
+			l.skipEndstream()
+
+			return &pdfStream{dict: dict, raw: l.data[start:end]}
+		}
+	}
+
+	idx := bytes.Index(l.data[start:], []byte("endstream"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return &pdfStream{dict: dict, raw: l.data[start:]}
+	}
+
+	end := start + idx
+	raw := bytes.TrimRight(l.data[start:end], "\r\n")
+	l.pos = end
+	l.skipEndstream()
+
+	return &pdfStream{dict: dict, raw: raw}
+}
+
+func (l *pdfLexer) skipEndstream() {
+	l.skipSpace()
+
+	if bytes.HasPrefix(l.data[l.pos:], []byte("endstream")) {
+		l.pos += len("endstream")
+	}
+}
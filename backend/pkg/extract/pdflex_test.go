@@ -0,0 +1,82 @@
+package extract
+
+import (
+	"bytes"
+	"testing"
+)
+
+var pdfLexerSeeds = []string{
+	"<< /Type /Page /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+	"[1 2.5 -3 (a\\)b) <48656C6C6F> /Name#20x true false null]",
+	"<< /Length 5 >>\nstream\nhello\nendstream",
+	"<< /Length 9223372036854775807 >>\nstream\nhello\nendstream",
+	"<< /Length -1 >>\nstream\r\nhello",
+	"(unterminated \\",
+	"<< /A [ [ [ [ << /B (",
+	"BT /F1 12 Tf 72 712 Td (Hello) Tj ET",
+}
+
+// TestPDFLexerStreamLength проверяет, что /Length за пределами данных
+// (в том числе переполняющая int) не приводит к панике.
+func TestPDFLexerStreamLength(t *testing.T) {
+	for _, length := range []string{"5", "6", "100", "9223372036854775807", "-5"} {
+		data := []byte("<< /Length " + length + " >>\nstream\nhello\nendstream")
+		l := &pdfLexer{data: data}
+
+		obj, ok := l.object(0)
+		if !ok {
+			t.Fatalf("Length %s: no object", length)
+		}
+
+		s, ok := obj.(*pdfStream)
+		if !ok {
+			t.Fatalf("Length %s: got %T, want stream", length, obj)
+		}
+
+		if length == "5" && !bytes.Equal(s.raw, []byte("hello")) {
+			t.Errorf("Length %s: raw = %q, want %q", length, s.raw, "hello")
+		}
+	}
+}
+
+func FuzzPDFLexer(f *testing.F) {
+	for _, seed := range pdfLexerSeeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		l := &pdfLexer{data: data}
+
+		for l.pos < len(data) {
+			pos := l.pos
+
+			if _, ok := l.object(0); !ok {
+				break
+			}
+
+			if l.pos <= pos || l.pos > len(data) {
+				t.Fatalf("position moved from %d to %d of %d", pos, l.pos, len(data))
+			}
+		}
+	})
+}
+
+// FuzzParsePDF разбирает документ целиком, минуя recover в Extract, чтобы
+// паника разборщика была видна.
+func FuzzParsePDF(f *testing.F) {
+	f.Add([]byte("%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+		"2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj\n" +
+		"3 0 obj << /Type /Page /Parent 2 0 R /Contents 4 0 R >> endobj\n" +
+		"4 0 obj << /Length 35 >>\nstream\nBT /F1 12 Tf 72 712 Td (Hello) Tj ET\nendstream endobj\n" +
+		"trailer << /Root 1 0 R >>\n%%EOF"))
+	f.Add([]byte("%PDF-1.7\n1 0 obj << /Length 9223372036854775807 >>\nstream\nx\nendstream endobj"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := parsePDF(data)
+		if err != nil {
+			return
+		}
+
+		doc.pageTexts()
+	})
+}
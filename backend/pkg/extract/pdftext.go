@@ -0,0 +1,559 @@
+package extract
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// maxFormDepth ограничивает вложенность Form XObject.
+const maxFormDepth = 8
+
+// pdfFont переводит коды символов строки в текст. Если у шрифта есть
+// /ToUnicode, используется он, иначе — кодировка простого шрифта.
+type pdfFont struct {
+	// widths — длины кодов из codespacerange; пусто — 1 байт (2 для Type0).
+	widths  []int
+	toUni   map[string]string
+	simple  [256]string
+	twoByte bool
+}
+
+func (d *pdfDocument) loadFont(v any) *pdfFont {
+	dict := d.dict(v)
+	if dict == nil {
+		return nil
+	}
+
+	f := &pdfFont{twoByte: dict["Subtype"] == pdfName("Type0")}
+
+	if s, ok := d.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := d.decode(s); err == nil {
+			f.parseCMap(data)
+		}
+	}
+
+	if !f.twoByte {
+		f.loadEncoding(d, dict)
+	}
+
+	return f
+}
+
+// parseCMap читает codespacerange, bfchar и bfrange из ToUnicode CMap.
+func (f *pdfFont) parseCMap(data []byte) {
+	f.toUni = map[string]string{}
+
+	l := &pdfLexer{data: data}
+
+	var (
+		operands []any
+		widths   = map[int]bool{}
+	)
+
+	for {
+		tok, ok := l.object(0)
+		if !ok {
+			break
+		}
+
+		kw, isKw := tok.(pdfKeyword)
+		if !isKw {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch kw {
+		case "endcodespacerange":
+			for _, o := range operands {
+				if s, ok := o.(pdfString); ok && len(s) > 0 {
+					widths[len(s)] = true
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+
+				if ok1 && ok2 {
+					f.toUni[string(src)] = decodeUTF16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 || len(lo) > 4 {
+					continue
+				}
+
+				start, end := bytesToInt(lo), bytesToInt(hi)
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := []rune(decodeUTF16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+
+					for c := start; c <= end; c++ {
+						r := append([]rune(nil), base...)
+						r[len(r)-1] += rune(c - start)
+						f.toUni[string(intToBytes(c, len(lo)))] = string(r)
+					}
+				case pdfArray:
+					for k, item := range dst {
+						if s, ok := item.(pdfString); ok && start+k <= end {
+							f.toUni[string(intToBytes(start+k, len(lo)))] = decodeUTF16BE(s)
+						}
+					}
+				}
+			}
+		}
+
+		if strings.HasPrefix(string(kw), "end") || strings.HasPrefix(string(kw), "begin") {
+			operands = operands[:0]
+		}
+	}
+
+	for w := range widths {
+		f.widths = append(f.widths, w)
+	}
+}
+
+// loadEncoding заполняет таблицу простого шрифта: базовая кодировка
+// (WinAnsi по умолчанию) и /Differences с именами глифов.
+func (f *pdfFont) loadEncoding(d *pdfDocument, dict pdfDict) {
+	base := charmap.Windows1252
+
+	var differences pdfArray
+
+	switch enc := d.resolve(dict["Encoding"]).(type) {
+	case pdfName:
+		if enc == "MacRomanEncoding" {
+			base = charmap.Macintosh
+		}
+	case pdfDict:
+		if enc["BaseEncoding"] == pdfName("MacRomanEncoding") {
+			base = charmap.Macintosh
+		}
+
+		differences, _ = d.resolve(enc["Differences"]).(pdfArray)
+	}
+
+	for i := 0; i < 256; i++ {
+		if i < 0x20 {
+			continue
+		}
+
+		f.simple[i] = string(base.DecodeByte(byte(i)))
+	}
+
+	code := 0
+
+	for _, item := range differences {
+		switch v := d.resolve(item).(type) {
+		case int64:
+			code = int(v)
+		case pdfName:
+			if code >= 0 && code < 256 {
+				if s, ok := glyphText(string(v)); ok {
+					f.simple[code] = s
+				}
+			}
+
+			code++
+		}
+	}
+}
+
+// decode переводит байты строки в текст.
+func (f *pdfFont) decode(s []byte) string {
+	if f == nil {
+		return string(charmapDecode(s))
+	}
+
+	var out strings.Builder
+
+	for len(s) > 0 {
+		n, text := f.next(s)
+		out.WriteString(text)
+		s = s[n:]
+	}
+
+	return out.String()
+}
+
+// next возвращает длину очередного кода и его текст.
+func (f *pdfFont) next(s []byte) (int, string) {
+	if f.toUni != nil {
+		widths := f.widths
+		if len(widths) == 0 {
+			widths = []int{1}
+			if f.twoByte {
+				widths = []int{2}
+			}
+		}
+
+		for _, w := range widths {
+			if w <= len(s) {
+				if text, ok := f.toUni[string(s[:w])]; ok {
+					return w, text
+				}
+			}
+		}
+	}
+
+	if f.twoByte {
+		return min(2, len(s)), ""
+	}
+
+	return 1, f.simple[s[0]]
+}
+
+func charmapDecode(s []byte) []byte {
+	out, err := charmap.Windows1252.NewDecoder().Bytes(s)
+	if err != nil {
+		return nil
+	}
+
+	return out
+}
+
+func decodeUTF16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+
+	return string(utf16.Decode(u))
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+
+	return v
+}
+
+func intToBytes(v, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+
+	return b
+}
+
+// pdfInterpreter выполняет операторы потока содержимого, которые
+// показывают текст или меняют его положение, и собирает изображения
+// страницы. Встроенные изображения (BI…EI) пропускаются: это обычно
+// мелкие элементы оформления.
+type pdfInterpreter struct {
+	doc    *pdfDocument
+	fonts  map[pdfRef]*pdfFont
+	out    strings.Builder
+	images []*pdfStream
+
+	font *pdfFont
+	// y — вертикальная позиция текущей строки, lastY — строки, где был
+	// выведен последний текст. Перевод строки и пробел откладываются до
+	// следующего вывода текста.
+	y, lastY  float64
+	shown     bool
+	breakLine bool
+	gap       bool
+}
+
+func newPDFInterpreter(doc *pdfDocument) *pdfInterpreter {
+	return &pdfInterpreter{doc: doc, fonts: map[pdfRef]*pdfFont{}}
+}
+
+// fontsOf возвращает шрифты ресурсов по именам; шрифты, заданные ссылкой,
+// разбираются один раз.
+func (in *pdfInterpreter) fontsOf(resources pdfDict) map[pdfName]*pdfFont {
+	fonts := map[pdfName]*pdfFont{}
+
+	for name, v := range in.doc.dict(resources["Font"]) {
+		ref, isRef := v.(pdfRef)
+		if !isRef {
+			fonts[name] = in.doc.loadFont(v)
+			continue
+		}
+
+		f, ok := in.fonts[ref]
+		if !ok {
+			f = in.doc.loadFont(ref)
+			in.fonts[ref] = f
+		}
+
+		fonts[name] = f
+	}
+
+	return fonts
+}
+
+func (in *pdfInterpreter) show(s pdfString) {
+	text := in.font.decode(s)
+	if text == "" {
+		return
+	}
+
+	switch {
+	case in.shown && (in.breakLine || math.Abs(in.y-in.lastY) > 1):
+		in.out.WriteByte('\n')
+	case in.shown && in.gap:
+		in.out.WriteByte(' ')
+	}
+
+	in.out.WriteString(text)
+	in.shown, in.lastY, in.breakLine, in.gap = true, in.y, false, false
+}
+
+func (in *pdfInterpreter) run(content []byte, resources pdfDict, depth int) {
+	fonts := in.fontsOf(resources)
+	l := &pdfLexer{data: content}
+
+	var operands []any
+
+	for {
+		tok, ok := l.object(0)
+		if !ok {
+			return
+		}
+
+		op, isOp := tok.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "BT":
+			in.y, in.gap = 0, true
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					in.font = fonts[name]
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				in.y += number(operands[len(operands)-1])
+				in.gap = in.gap || number(operands[len(operands)-2]) != 0
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				in.y, in.gap = number(operands[len(operands)-1]), true
+			}
+		case "T*":
+			in.breakLine = true
+		case "Tj":
+			if len(operands) >= 1 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					in.show(s)
+				}
+			}
+		case "'", "\"":
+			in.breakLine = true
+
+			if len(operands) >= 1 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					in.show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				if arr, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, item := range arr {
+						switch v := item.(type) {
+						case pdfString:
+							in.show(v)
+						default:
+							// Сдвиг больше трети em обычно означает пробел.
+							if number(v) < -300 {
+								in.gap = true
+							}
+						}
+					}
+				}
+			}
+		case "Do":
+			if len(operands) >= 1 {
+				if name, ok := operands[len(operands)-1].(pdfName); ok {
+					in.xobject(resources, name, depth)
+				}
+			}
+		case "BI":
+			skipInlineImage(l)
+		}
+
+		operands = operands[:0]
+	}
+}
+
+// xobject обрабатывает Do: изображения считаются, формы выполняются со
+// своими ресурсами.
+func (in *pdfInterpreter) xobject(resources pdfDict, name pdfName, depth int) {
+	stream, ok := in.doc.resolve(in.doc.dict(resources["XObject"])[name]).(*pdfStream)
+	if !ok {
+		return
+	}
+
+	switch stream.dict["Subtype"] {
+	case pdfName("Image"):
+		in.images = append(in.images, stream)
+	case pdfName("Form"):
+		if depth >= maxFormDepth {
+			return
+		}
+
+		data, err := in.doc.decode(stream)
+		if err != nil {
+			return
+		}
+
+		formResources := in.doc.dict(stream.dict["Resources"])
+		if formResources == nil {
+			formResources = resources
+		}
+
+		font := in.font
+		in.run(data, formResources, depth+1)
+		in.font = font
+	}
+}
+
+// skipInlineImage пропускает данные встроенного изображения до "EI".
+func skipInlineImage(l *pdfLexer) {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+
+	l.pos += idx + 2
+
+	for {
+		i := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if i < 0 {
+			l.pos = len(l.data)
+			return
+		}
+
+		end := l.pos + i
+		l.pos = end + 2
+
+		if end > 0 && isPDFSpace(l.data[end-1]) && (l.pos >= len(l.data) || isPDFSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
+
+func number(v any) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+
+	return 0
+}
+
+// glyphText переводит имя глифа из /Differences в текст: имена вида uniXXXX
+// и uXXXX, однобуквенные имена, частые имена Adobe Glyph List и кириллица
+// afii100xx из старых русских шрифтов.
+func glyphText(name string) (string, bool) {
+	if s, ok := glyphNames[name]; ok {
+		return s, true
+	}
+
+	if len(name) == 1 {
+		return name, true
+	}
+
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 {
+		if v, err := strconv.ParseUint(name[3:7], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+
+	if strings.HasPrefix(name, "afii") {
+		if v, err := strconv.Atoi(name[4:]); err == nil {
+			if r := afiiCyrillic(v); r != 0 {
+				return string(r), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func afiiCyrillic(v int) rune {
+	switch {
+	case v >= 10017 && v <= 10022:
+		return rune(0x0410 + v - 10017)
+	case v == 10023:
+		return 'Ё'
+	case v >= 10024 && v <= 10049:
+		return rune(0x0416 + v - 10024)
+	case v >= 10065 && v <= 10070:
+		return rune(0x0430 + v - 10065)
+	case v == 10071:
+		return 'ё'
+	case v >= 10072 && v <= 10097:
+		return rune(0x0436 + v - 10072)
+	}
+
+	return 0
+}
+
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#",
+	"dollar": "$", "percent": "%", "ampersand": "&", "quotesingle": "'",
+	"parenleft": "(", "parenright": ")", "asterisk": "*", "plus": "+",
+	"comma": ",", "hyphen": "-", "period": ".", "slash": "/",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+	"colon": ":", "semicolon": ";", "less": "<", "equal": "=",
+	"greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "asciicircum": "^",
+	"underscore": "_", "grave": "`", "braceleft": "{", "bar": "|",
+	"braceright": "}", "asciitilde": "~", "bullet": "•", "endash": "–",
+	"emdash": "—", "quoteleft": "‘", "quoteright": "’",
+	"quotedblleft": "“", "quotedblright": "”", "quotesinglbase": "‚",
+	"quotedblbase": "„", "ellipsis": "…", "fi": "fi", "fl": "fl",
+	"ff": "ff", "ffi": "ffi", "ffl": "ffl", "minus": "−", "periodcentered": "·",
+	"guillemotleft": "«", "guillemotright": "»", "numero": "№",
+	"afii61352": "№", "copyright": "©", "registered": "®", "trademark": "™",
+	"degree": "°", "section": "§", "paragraph": "¶", "nbspace": " ",
+	"aacute": "á", "eacute": "é", "iacute": "í", "oacute": "ó", "uacute": "ú",
+	"Aacute": "Á", "Eacute": "É", "Iacute": "Í", "Oacute": "Ó", "Uacute": "Ú",
+	"ntilde": "ñ", "Ntilde": "Ñ", "udieresis": "ü", "Udieresis": "Ü",
+	"adieresis": "ä", "Adieresis": "Ä", "odieresis": "ö", "Odieresis": "Ö",
+	"germandbls": "ß", "agrave": "à", "egrave": "è", "ccedilla": "ç",
+	"Ccedilla": "Ç", "ecircumflex": "ê", "acircumflex": "â", "ocircumflex": "ô",
+	"questiondown": "¿", "exclamdown": "¡", "ordfeminine": "ª", "ordmasculine": "º",
+}
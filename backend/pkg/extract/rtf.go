@@ -0,0 +1,210 @@
+package extract
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// rtfSkipDestinations — группы RTF, не содержащие текста документа.
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "object": true, "header": true, "headerl": true,
+	"headerr": true, "headerf": true, "footer": true, "footerl": true,
+	"footerr": true, "footerf": true, "fldinst": true, "themedata": true,
+	"colorschememapping": true, "datastore": true, "latentstyles": true,
+	"listtable": true, "listoverridetable": true, "rsidtbl": true,
+	"generator": true, "xmlnstbl": true, "mmathPr": true, "filetbl": true,
+	"revtbl": true, "bkmkstart": true, "bkmkend": true, "nonshppict": true,
+	"shppict": true, "footnote": true, "annotation": true, "atnid": true,
+	"atnauthor": true, "pgdsctbl": true, "wgrffmtfilter": true,
+}
+
+// rtfCodepages — кодовые страницы \ansicpgN, для которых есть декодер.
+var rtfCodepages = map[int]encoding.Encoding{
+	874:   charmap.Windows874,
+	1250:  charmap.Windows1250,
+	1251:  charmap.Windows1251,
+	1252:  charmap.Windows1252,
+	1253:  charmap.Windows1253,
+	1254:  charmap.Windows1254,
+	1255:  charmap.Windows1255,
+	1256:  charmap.Windows1256,
+	1257:  charmap.Windows1257,
+	1258:  charmap.Windows1258,
+	866:   charmap.CodePage866,
+	10000: charmap.Macintosh,
+}
+
+type rtfGroup struct {
+	skip bool
+	uc   int
+}
+
+// extractRTF разбирает RTF: управляющие слова, группы и escape-последовательности
+// \'hh и \uN. Служебные группы (таблицы шрифтов, картинки, поля) пропускаются.
+func extractRTF(data []byte) (string, error) {
+	var (
+		out      strings.Builder
+		stack    = []rtfGroup{{uc: 1}}
+		codepage = encoding.Encoding(charmap.Windows1252)
+		// pending — сколько символов пропустить после \uN (замена для
+		// читателей без Unicode).
+		pending int
+		// high — первая половина суррогатной пары из \uN.
+		high rune
+	)
+
+	cur := func() *rtfGroup { return &stack[len(stack)-1] }
+
+	emit := func(s string) {
+		if !cur().skip {
+			out.WriteString(s)
+		}
+	}
+
+	emitByte := func(b byte) {
+		if pending > 0 {
+			pending--
+			return
+		}
+
+		if cur().skip {
+			return
+		}
+
+		if b < 0x80 {
+			out.WriteByte(b)
+			return
+		}
+
+		decoded, err := codepage.NewDecoder().Bytes([]byte{b})
+		if err == nil {
+			out.Write(decoded)
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		switch c {
+		case '{':
+			stack = append(stack, *cur())
+			pending = 0
+		case '}':
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+
+			pending = 0
+		case '\r', '\n':
+		case '\\':
+			if i+1 >= len(data) {
+				break
+			}
+
+			i++
+			c = data[i]
+
+			switch {
+			case c == '\\' || c == '{' || c == '}':
+				emitByte(c)
+			case c == '\'':
+				if i+2 < len(data) {
+					if b, err := strconv.ParseUint(string(data[i+1:i+3]), 16, 8); err == nil {
+						emitByte(byte(b))
+					}
+
+					i += 2
+				}
+			case c == '*':
+				cur().skip = true
+			case c == '~':
+				emit(" ")
+			case c == '_':
+				emit("-")
+			case c == '\r' || c == '\n':
+				emit("\n")
+			case isASCIILetter(c):
+				start := i
+				for i < len(data) && isASCIILetter(data[i]) {
+					i++
+				}
+
+				word := string(data[start:i])
+
+				numStart := i
+				if i < len(data) && data[i] == '-' {
+					i++
+				}
+
+				for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+					i++
+				}
+
+				param, hasParam := 0, i > numStart
+				if hasParam {
+					param, _ = strconv.Atoi(string(data[numStart:i]))
+				}
+
+				// Пробел после управляющего слова — разделитель, а не текст.
+				if i >= len(data) || data[i] != ' ' {
+					i--
+				}
+
+				switch {
+				case rtfSkipDestinations[word]:
+					cur().skip = true
+				case word == "par" || word == "line" || word == "row" || word == "sect" || word == "page":
+					emit("\n")
+				case word == "tab" || word == "cell":
+					emit("\t")
+				case word == "emdash":
+					emit("—")
+				case word == "endash":
+					emit("–")
+				case word == "bullet":
+					emit("•")
+				case word == "lquote" || word == "rquote":
+					emit("'")
+				case word == "ldblquote" || word == "rdblquote":
+					emit("\"")
+				case word == "uc" && hasParam:
+					cur().uc = param
+				case word == "ansicpg" && hasParam:
+					if enc, ok := rtfCodepages[param]; ok {
+						codepage = enc
+					}
+				case word == "u" && hasParam:
+					r := rune(param)
+					if r < 0 {
+						r += 0x10000
+					}
+
+					switch {
+					case utf16.IsSurrogate(r) && r < 0xDC00:
+						high = r
+					case utf16.IsSurrogate(r):
+						emit(string(utf16.DecodeRune(high, r)))
+						high = 0
+					default:
+						emit(string(r))
+					}
+
+					pending = cur().uc
+				}
+			}
+		default:
+			emitByte(c)
+		}
+	}
+
+	return out.String(), nil
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
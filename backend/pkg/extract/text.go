@@ -0,0 +1,148 @@
+package extract
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	encunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/unicode/norm"
+)
+
+// decodeText переводит простой текст в UTF-8. Учитываются BOM UTF-8 и
+// UTF-16; текст, не являющийся корректным UTF-8, считается Windows-1251,
+// если в нём много байт кириллического диапазона, иначе Windows-1252.
+func decodeText(data []byte) (string, error) {
+	var enc encoding.Encoding
+
+	switch {
+	case bytes.HasPrefix(data, []byte("\xEF\xBB\xBF")):
+		return string(data[3:]), nil
+	case bytes.HasPrefix(data, []byte("\xFF\xFE")):
+		enc = encunicode.UTF16(encunicode.LittleEndian, encunicode.UseBOM)
+	case bytes.HasPrefix(data, []byte("\xFE\xFF")):
+		enc = encunicode.UTF16(encunicode.BigEndian, encunicode.UseBOM)
+	case utf8.Valid(data):
+		return string(data), nil
+	case looksLikeCP1251(data):
+		enc = charmap.Windows1251
+	default:
+		enc = charmap.Windows1252
+	}
+
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", ErrCorrupted
+	}
+
+	return string(out), nil
+}
+
+// looksLikeText отсекает двоичные файлы: текст почти не содержит
+// управляющих байт, кроме пробельных.
+func looksLikeText(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+
+	if bytes.HasPrefix(data, []byte("\xFF\xFE")) || bytes.HasPrefix(data, []byte("\xFE\xFF")) {
+		return true
+	}
+
+	sample := data[:min(len(data), 8192)]
+	control := 0
+
+	for _, b := range sample {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' {
+			control++
+		}
+	}
+
+	return control*100 < len(sample)
+}
+
+// looksLikeCP1251 проверяет, что старшие байты в основном попадают в
+// диапазон русских букв Windows-1251 (0xC0–0xFF).
+func looksLikeCP1251(data []byte) bool {
+	high, cyrillic := 0, 0
+
+	for _, b := range data {
+		if b >= 0x80 {
+			high++
+
+			if b >= 0xC0 {
+				cyrillic++
+			}
+		}
+	}
+
+	return high > 0 && cyrillic*10 >= high*7
+}
+
+// Normalize приводит текст к NFC, заменяет неразрывные и прочие пробелы
+// обычными, убирает управляющие символы, схлопывает повторяющиеся пробелы и
+// оставляет не больше одной пустой строки подряд.
+func Normalize(text string) string {
+	text = norm.NFC.String(text)
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\f", "\n", "\u00ad", "", "\ufeff", "").Replace(text)
+
+	var (
+		out   strings.Builder
+		blank = 0
+	)
+
+	out.Grow(len(text))
+
+	for _, line := range strings.Split(text, "\n") {
+		line = normalizeLine(line)
+
+		if line == "" {
+			blank++
+			continue
+		}
+
+		if out.Len() > 0 {
+			if blank > 0 {
+				out.WriteString("\n\n")
+			} else {
+				out.WriteByte('\n')
+			}
+		}
+
+		blank = 0
+		out.WriteString(line)
+	}
+
+	return out.String()
+}
+
+func normalizeLine(line string) string {
+	var (
+		b     strings.Builder
+		space = false
+	)
+
+	for _, r := range line {
+		switch {
+		case r == utf8.RuneError:
+			continue
+		case unicode.IsSpace(r) || r == '\u200b':
+			space = b.Len() > 0
+			continue
+		case unicode.IsControl(r) || unicode.Is(unicode.Co, r):
+			continue
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,187 @@
+// Package feed формирует RSS 2.0 и Atom 1.0 ленты.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Feed — описание ленты, не зависящее от формата.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Language    string
+	ImageURL    string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item — запись ленты.
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Category    string
+	Published   time.Time
+	Updated     time.Time
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Image         *rssImage `xml:"image,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssImage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RSS кодирует ленту в формате RSS 2.0.
+func (f Feed) RSS() ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Language:    f.Language,
+			Items:       make([]rssItem, 0, len(f.Items)),
+		},
+	}
+
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	if f.ImageURL != "" {
+		doc.Channel.Image = &rssImage{URL: f.ImageURL, Title: f.Title, Link: f.Link}
+	}
+
+	for _, it := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       it.Title,
+			Link:        it.Link,
+			Description: it.Description,
+			Category:    it.Category,
+			GUID:        rssGUID{Value: it.ID},
+			PubDate:     it.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return marshal(doc)
+}
+
+type atom struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Link     []atomLink  `xml:"link"`
+	Logo     string      `xml:"logo,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Link      atomLink      `xml:"link"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Category  *atomCategory `xml:"category,omitempty"`
+	Summary   atomText      `xml:"summary"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom кодирует ленту в формате Atom 1.0. selfLink — адрес самой ленты.
+func (f Feed) Atom(selfLink string) ([]byte, error) {
+	doc := atom{
+		Lang:     f.Language,
+		ID:       f.Link,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Link: []atomLink{
+			{Href: f.Link, Rel: "alternate"},
+			{Href: selfLink, Rel: "self"},
+		},
+		Logo:    f.ImageURL,
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+
+	for _, it := range f.Items {
+		updated := it.Updated
+		if updated.IsZero() {
+			updated = it.Published
+		}
+
+		entry := atomEntry{
+			ID:        it.ID,
+			Title:     it.Title,
+			Link:      atomLink{Href: it.Link, Rel: "alternate"},
+			Published: it.Published.UTC().Format(time.RFC3339),
+			Updated:   updated.UTC().Format(time.RFC3339),
+			Summary:   atomText{Type: "text", Value: it.Description},
+		}
+
+		if it.Category != "" {
+			entry.Category = &atomCategory{Term: it.Category}
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshal(doc)
+}
+
+func marshal(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,77 @@
+package feed
+
+import "time"
+
+// JobPosting — разметка schema.org/JobPosting в формате JSON-LD.
+// https://schema.org/JobPosting
+type JobPosting struct {
+	Context              string        `json:"@context"`
+	Type                 string        `json:"@type"`
+	Title                string        `json:"title"`
+	Description          string        `json:"description"`
+	DatePosted           string        `json:"datePosted"`
+	URL                  string        `json:"url,omitempty"`
+	Identifier           *PropertyItem `json:"identifier,omitempty"`
+	HiringOrganization   Organization  `json:"hiringOrganization"`
+	JobLocationType      string        `json:"jobLocationType,omitempty"`
+	OccupationalCategory string        `json:"occupationalCategory,omitempty"`
+	DirectApply          bool          `json:"directApply"`
+}
+
+// Organization — schema.org/Organization.
+type Organization struct {
+	Type   string `json:"@type"`
+	Name   string `json:"name"`
+	SameAs string `json:"sameAs,omitempty"`
+	Logo   string `json:"logo,omitempty"`
+}
+
+// PropertyItem — schema.org/PropertyValue.
+type PropertyItem struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// JobPostingParams — данные вакансии для разметки.
+type JobPostingParams struct {
+	ID          string
+	Title       string
+	Description string
+	Category    string
+	URL         string
+	Remote      bool
+	DirectApply bool
+	Published   time.Time
+	Org         Organization
+}
+
+// NewJobPosting собирает разметку JobPosting. Для удалённых вакансий
+// указывается jobLocationType TELECOMMUTE.
+func NewJobPosting(p JobPostingParams) JobPosting {
+	org := p.Org
+	org.Type = "Organization"
+
+	jp := JobPosting{
+		Context:     "https://schema.org/",
+		Type:        "JobPosting",
+		Title:       p.Title,
+		Description: p.Description,
+		DatePosted:  p.Published.UTC().Format(time.DateOnly),
+		URL:         p.URL,
+		Identifier: &PropertyItem{
+			Type:  "PropertyValue",
+			Name:  org.Name,
+			Value: p.ID,
+		},
+		HiringOrganization:   org,
+		OccupationalCategory: p.Category,
+		DirectApply:          p.DirectApply,
+	}
+
+	if p.Remote {
+		jp.JobLocationType = "TELECOMMUTE"
+	}
+
+	return jp
+}
@@ -0,0 +1,213 @@
+// Package ical формирует календари iCalendar (RFC 5545) для приглашений на
+// встречи (iTIP, RFC 5546) и подписок на календарь.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Методы iTIP: PUBLISH — календарь для подписки, REQUEST — приглашение или
+// его изменение, CANCEL — отмена встречи.
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Статусы события.
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Роли участников.
+const (
+	RoleRequired = "REQ-PARTICIPANT"
+	RoleOptional = "OPT-PARTICIPANT"
+)
+
+// maxLineOctets — максимальная длина строки без CRLF; более длинные строки
+// переносятся (RFC 5545, 3.1).
+const maxLineOctets = 75
+
+const timeFormat = "20060102T150405Z"
+
+// Calendar — объект VCALENDAR. Method задаётся для писем с приглашениями и
+// для лент; Name показывается клиентами как имя подписки.
+type Calendar struct {
+	ProdID string
+	Method string
+	Name   string
+	Events []Event
+}
+
+// Event — событие VEVENT. Время записывается в UTC. Sequence увеличивается
+// при каждом изменении, чтобы клиенты заменяли старую версию приглашения.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Organizer   *Person
+	Attendees   []Person
+}
+
+// Person — организатор или участник события.
+type Person struct {
+	Name  string
+	Email string
+	Role  string
+	RSVP  bool
+}
+
+// Encode возвращает календарь в текстовом виде с переводами строк CRLF.
+func (c *Calendar) Encode() []byte {
+	var w writer
+
+	w.line("BEGIN", "", "VCALENDAR")
+	w.line("VERSION", "", "2.0")
+	w.line("PRODID", "", c.ProdID)
+	w.line("CALSCALE", "", "GREGORIAN")
+
+	if c.Method != "" {
+		w.line("METHOD", "", c.Method)
+	}
+
+	if c.Name != "" {
+		w.text("X-WR-CALNAME", c.Name)
+	}
+
+	for i := range c.Events {
+		c.Events[i].encode(&w)
+	}
+
+	w.line("END", "", "VCALENDAR")
+
+	return w.buf.Bytes()
+}
+
+func (e *Event) encode(w *writer) {
+	w.line("BEGIN", "", "VEVENT")
+	w.line("UID", "", e.UID)
+	w.line("DTSTAMP", "", e.Stamp.UTC().Format(timeFormat))
+	w.line("DTSTART", "", e.Start.UTC().Format(timeFormat))
+	w.line("DTEND", "", e.End.UTC().Format(timeFormat))
+	w.line("SEQUENCE", "", strconv.Itoa(e.Sequence))
+
+	if e.Status != "" {
+		w.line("STATUS", "", e.Status)
+	}
+
+	w.text("SUMMARY", e.Summary)
+
+	if e.Description != "" {
+		w.text("DESCRIPTION", e.Description)
+	}
+
+	if e.Location != "" {
+		w.text("LOCATION", e.Location)
+	}
+
+	if e.URL != "" {
+		w.line("URL", "", e.URL)
+	}
+
+	if e.Organizer != nil {
+		w.line("ORGANIZER", params("CN", e.Organizer.Name), "mailto:"+e.Organizer.Email)
+	}
+
+	for _, a := range e.Attendees {
+		role := a.Role
+		if role == "" {
+			role = RoleRequired
+		}
+
+		p := params("CN", a.Name) + params("ROLE", role) + params("PARTSTAT", "NEEDS-ACTION")
+		if a.RSVP {
+			p += params("RSVP", "TRUE")
+		}
+
+		w.line("ATTENDEE", p, "mailto:"+a.Email)
+	}
+
+	w.line("END", "", "VEVENT")
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// text записывает свойство типа TEXT, экранируя значение.
+func (w *writer) text(name, value string) {
+	w.line(name, "", escapeText(value))
+}
+
+// line записывает свойство, перенося строку после 75 октетов так, чтобы не
+// разрывать символы UTF-8.
+func (w *writer) line(name, params, value string) {
+	line := name + params + ":" + value
+	limit := maxLineOctets
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Пробел в начале продолжения входит в длину строки.
+		limit = maxLineOctets - 1
+	}
+
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+// params возвращает параметр свойства ";NAME=value" или пустую строку для
+// пустого значения. Значения с ":", ";" и "," берутся в кавычки; кавычки и
+// управляющие символы в значениях недопустимы и удаляются.
+func params(name, value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r == '"' || r < 0x20 || r == 0x7f {
+			return -1
+		}
+
+		return r
+	}, value)
+
+	if value == "" {
+		return ""
+	}
+
+	if strings.ContainsAny(value, ":;,") {
+		value = `"` + value + `"`
+	}
+
+	return fmt.Sprintf(";%s=%s", name, value)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
@@ -0,0 +1,62 @@
+// Package mailer отправляет письма. Сейчас есть отправка через SMTP;
+// приглашения в календарь передаются частью text/calendar, которую почтовые
+// клиенты показывают как встречу с кнопками ответа.
+package mailer
+
+import (
+	"backend/pkg/config"
+	"context"
+	"errors"
+	"net/textproto"
+)
+
+var ErrRejected = errors.New("mailer: message rejected")
+
+// Address — адрес получателя с отображаемым именем.
+type Address struct {
+	Name  string
+	Email string
+}
+
+// Calendar — приглашение iCalendar. Method должен совпадать с METHOD
+// календаря в Data.
+type Calendar struct {
+	Method string
+	Data   []byte
+}
+
+// Message — письмо в виде простого текста, при необходимости с
+// приглашением.
+type Message struct {
+	To       []Address
+	Subject  string
+	Text     string
+	Calendar *Calendar
+}
+
+// Mailer отправляет письма.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New создаёт отправителя по конфигурации. Пустой Host выключает почту:
+// возвращается nil без ошибки.
+func New(cfg config.Mailer) (Mailer, error) {
+	if cfg.Host == "" {
+		return nil, nil
+	}
+
+	return NewSMTP(cfg)
+}
+
+// IsPermanent сообщает, что повторная отправка не поможет: сервер отклонил
+// письмо кодом 5xx.
+func IsPermanent(err error) bool {
+	if errors.Is(err, ErrRejected) {
+		return true
+	}
+
+	var protoErr *textproto.Error
+
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
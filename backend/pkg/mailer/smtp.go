@@ -0,0 +1,339 @@
+package mailer
+
+import (
+	"backend/pkg/config"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Режимы TLS.
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+	TLSNone     = "none"
+)
+
+const (
+	defaultSMTPTimeout = 30 * time.Second
+	// base64LineLength — длина строк base64 в теле письма (RFC 2045).
+	base64LineLength = 76
+)
+
+// SMTP отправляет письма через SMTP-сервер, открывая соединение на каждое
+// письмо. Аутентификация PLAIN выполняется только поверх TLS, кроме
+// соединений с localhost.
+type SMTP struct {
+	addr     string
+	host     string
+	tls      string
+	username string
+	password string
+	from     mail.Address
+	timeout  time.Duration
+}
+
+func NewSMTP(cfg config.Mailer) (*SMTP, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: invalid from address %q: %w", cfg.From, err)
+	}
+
+	if cfg.FromName != "" {
+		from.Name = cfg.FromName
+	}
+
+	s := &SMTP{
+		host:     cfg.Host,
+		tls:      cfg.TLS,
+		username: cfg.Username,
+		password: cfg.Password,
+		from:     *from,
+		timeout:  cfg.Timeout,
+	}
+
+	switch s.tls {
+	case "":
+		s.tls = TLSStartTLS
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return nil, fmt.Errorf("mailer: unknown tls mode %q", cfg.TLS)
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+		if s.tls == TLSImplicit {
+			port = 465
+		}
+	}
+
+	s.addr = net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	if s.password == "" {
+		s.password = os.Getenv("SMTP_PASSWORD")
+	}
+
+	if s.timeout <= 0 {
+		s.timeout = defaultSMTPTimeout
+	}
+
+	return s, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("%w: no recipients", ErrRejected)
+	}
+
+	to := make([]*mail.Address, len(msg.To))
+
+	for i, rcpt := range msg.To {
+		addr, err := mail.ParseAddress(rcpt.Email)
+		if err != nil {
+			return fmt.Errorf("%w: invalid address %q", ErrRejected, rcpt.Email)
+		}
+
+		addr.Name = rcpt.Name
+		to[i] = addr
+	}
+
+	data, err := s.compose(msg, to)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("mailer: dial %s: %w", s.addr, err)
+	}
+
+	// Отмена контекста прерывает ожидание ответа сервера.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("mailer: greeting: %w", err)
+	}
+	defer client.Close()
+
+	if s.tls == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+				return fmt.Errorf("mailer: starttls: %w", err)
+			}
+		}
+	}
+
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("mailer: auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("mailer: mail from: %w", err)
+	}
+
+	for _, addr := range to {
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("mailer: rcpt to %s: %w", addr.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("mailer: data: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("mailer: write message: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mailer: send message: %w", err)
+	}
+
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("mailer: quit: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SMTP) dial(ctx context.Context) (net.Conn, error) {
+	if s.tls == TLSImplicit {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: s.host}}
+		return dialer.DialContext(ctx, "tcp", s.addr)
+	}
+
+	var dialer net.Dialer
+
+	return dialer.DialContext(ctx, "tcp", s.addr)
+}
+
+// compose собирает письмо. Приглашение кладётся двумя частями: text/calendar
+// внутри multipart/alternative, которую клиенты показывают как встречу, и
+// вложение invite.ics для остальных клиентов.
+func (s *SMTP) compose(msg *Message, to []*mail.Address) ([]byte, error) {
+	var buf bytes.Buffer
+
+	recipients := make([]string, len(to))
+	for i, addr := range to {
+		recipients[i] = addr.String()
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", s.from.String())
+	header.Set("To", strings.Join(recipients, ", "))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(msg.Subject), " ")))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", s.messageID())
+	header.Set("MIME-Version", "1.0")
+
+	if msg.Calendar == nil {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	header.Set("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mixed.Boundary()}))
+	writeHeader(&buf, header)
+
+	var altBody bytes.Buffer
+
+	alternative := multipart.NewWriter(&altBody)
+
+	text, err := alternative.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeQuotedPrintable(text, msg.Text); err != nil {
+		return nil, err
+	}
+
+	calendar, err := alternative.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("text/calendar", map[string]string{
+			"charset": "utf-8",
+			"method":  msg.Calendar.Method,
+		})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	writeBase64(calendar, msg.Calendar.Data)
+
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": alternative.Boundary()})},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := part.Write(altBody.Bytes()); err != nil {
+		return nil, err
+	}
+
+	attachment, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType("application/ics", map[string]string{"name": "invite.ics"})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": "invite.ics"})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	writeBase64(attachment, msg.Calendar.Data)
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// messageID возвращает уникальный Message-ID в домене отправителя.
+func (s *SMTP) messageID() string {
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+
+	domain := s.host
+	if at := strings.LastIndex(s.from.Address, "@"); at >= 0 {
+		domain = s.from.Address[at+1:]
+	}
+
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, name := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(name); value != "" {
+			buf.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+
+	if _, err := qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n"))); err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
+
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	for len(encoded) > base64LineLength {
+		_, _ = w.Write([]byte(encoded[:base64LineLength] + "\r\n"))
+		encoded = encoded[base64LineLength:]
+	}
+
+	_, _ = w.Write([]byte(encoded + "\r\n"))
+}
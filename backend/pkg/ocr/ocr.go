@@ -0,0 +1,47 @@
+// Package ocr распознаёт текст на изображениях страниц отсканированных
+// резюме. Движок подключается через интерфейс Engine; сейчас есть адаптер
+// к Tesseract, который запускается локально и использует только CPU.
+package ocr
+
+import (
+	"backend/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Движки распознавания.
+const (
+	EngineTesseract = "tesseract"
+)
+
+var (
+	ErrUnavailable = errors.New("ocr: engine is not available")
+	ErrFailed      = errors.New("ocr: recognition failed")
+)
+
+// Page — результат распознавания страницы. Confidence — средняя
+// уверенность движка по словам, от 0 до 100.
+type Page struct {
+	Text       string
+	Confidence float64
+}
+
+// Engine распознаёт текст на изображении страницы (JPEG, PNG, TIFF, JP2).
+type Engine interface {
+	Name() string
+	Recognize(ctx context.Context, image []byte) (*Page, error)
+}
+
+// New создаёт движок по конфигурации. Пустой Engine выключает
+// распознавание: возвращается nil без ошибки.
+func New(cfg config.OCR) (Engine, error) {
+	switch cfg.Engine {
+	case "":
+		return nil, nil
+	case EngineTesseract:
+		return NewTesseract(cfg), nil
+	default:
+		return nil, fmt.Errorf("ocr: unknown engine %q", cfg.Engine)
+	}
+}
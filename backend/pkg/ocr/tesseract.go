@@ -0,0 +1,160 @@
+package ocr
+
+import (
+	"backend/pkg/config"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTesseractCommand = "tesseract"
+	defaultLanguages        = "eng+rus+spa"
+	defaultPageTimeout      = 2 * time.Minute
+	// maxOutputSize ограничивает вывод tesseract для одной страницы.
+	maxOutputSize = 8 << 20
+)
+
+// Tesseract запускает tesseract для каждой страницы: изображение подаётся
+// на stdin, результат читается в формате TSV, где у каждого слова есть
+// уверенность. OMP_THREAD_LIMIT=1 не даёт одному процессу занять все ядра:
+// параллелизм задаётся числом воркеров.
+type Tesseract struct {
+	command   string
+	languages string
+	timeout   time.Duration
+}
+
+func NewTesseract(cfg config.OCR) *Tesseract {
+	t := &Tesseract{
+		command:   cfg.Command,
+		languages: cfg.Languages,
+		timeout:   cfg.Timeout,
+	}
+
+	if t.command == "" {
+		t.command = defaultTesseractCommand
+	}
+
+	if t.languages == "" {
+		t.languages = defaultLanguages
+	}
+
+	if t.timeout <= 0 {
+		t.timeout = defaultPageTimeout
+	}
+
+	return t
+}
+
+func (t *Tesseract) Name() string {
+	return EngineTesseract
+}
+
+func (t *Tesseract) Recognize(ctx context.Context, image []byte) (*Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, t.command, "stdin", "stdout", "-l", t.languages, "tsv")
+	cmd.Env = append(os.Environ(), "OMP_THREAD_LIMIT=1")
+	cmd.Stdin = bytes.NewReader(image)
+
+	var stdout, stderr limitedBuffer
+	stdout.max, stderr.max = maxOutputSize, 4096
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFailed, ctx.Err())
+		}
+
+		return nil, fmt.Errorf("%w: %v: %s", ErrFailed, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseTSV(stdout.String()), nil
+}
+
+// parseTSV собирает текст из строк уровня слов (level 5). Слова одной
+// строки разделяются пробелом, строки — переводом строки, абзацы и блоки —
+// пустой строкой. Уверенность — среднее по словам с неотрицательной conf.
+func parseTSV(tsv string) *Page {
+	var (
+		out      strings.Builder
+		lastPar  string
+		lastLine string
+		sum      float64
+		words    int
+	)
+
+	for i, line := range strings.Split(tsv, "\n") {
+		if i == 0 {
+			continue
+		}
+
+		// level page block par line word left top width height conf text
+		f := strings.SplitN(strings.TrimRight(line, "\r"), "\t", 12)
+		if len(f) < 12 || f[0] != "5" {
+			continue
+		}
+
+		text := strings.TrimSpace(f[11])
+		if text == "" {
+			continue
+		}
+
+		par := f[1] + "." + f[2] + "." + f[3]
+		lineKey := par + "." + f[4]
+
+		switch {
+		case out.Len() == 0:
+		case par != lastPar:
+			out.WriteString("\n\n")
+		case lineKey != lastLine:
+			out.WriteByte('\n')
+		default:
+			out.WriteByte(' ')
+		}
+
+		out.WriteString(text)
+		lastPar, lastLine = par, lineKey
+
+		if conf, err := strconv.ParseFloat(f[10], 64); err == nil && conf >= 0 {
+			sum += conf
+			words++
+		}
+	}
+
+	page := &Page{Text: out.String()}
+	if words > 0 {
+		page.Confidence = sum / float64(words)
+	}
+
+	return page
+}
+
+// limitedBuffer отбрасывает вывод сверх max, чтобы сбойный процесс не
+// исчерпал память.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+
+	return len(p), nil
+}
+
+var _ Engine = (*Tesseract)(nil)
@@ -0,0 +1,43 @@
+package profile
+
+import (
+	"backend/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Провайдеры извлечения.
+const (
+	ProviderStub   = "stub"
+	ProviderOpenAI = "openai"
+)
+
+var (
+	// ErrUnavailable — провайдер временно недоступен (сеть, 429, 5xx);
+	// повтор может помочь.
+	ErrUnavailable = errors.New("profile: provider unavailable")
+	// ErrRejected — провайдер отклонил запрос; повтор не поможет.
+	ErrRejected = errors.New("profile: provider rejected the request")
+)
+
+// Extractor извлекает профиль из текста резюме. Результат — JSON по
+// Schema; проверять его по схеме должен вызывающий (Parse), потому что
+// языковая модель может ошибиться.
+type Extractor interface {
+	Name() string
+	Extract(ctx context.Context, text string) ([]byte, error)
+}
+
+// NewExtractor создаёт извлекатель по конфигурации; по умолчанию —
+// детерминированная локальная заглушка.
+func NewExtractor(cfg config.LLM) (Extractor, error) {
+	switch cfg.Provider {
+	case "", ProviderStub:
+		return NewStub(), nil
+	case ProviderOpenAI:
+		return NewOpenAI(cfg)
+	default:
+		return nil, fmt.Errorf("profile: unknown provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,188 @@
+package profile
+
+import (
+	"backend/pkg/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4o-mini"
+	defaultLLMTimeout    = 2 * time.Minute
+	defaultMaxInputChars = 30000
+	// maxResponseSize ограничивает ответ провайдера.
+	maxResponseSize = 4 << 20
+)
+
+const systemPrompt = `You extract structured data from resumes.
+Return a single JSON object that matches the provided JSON schema exactly.
+Use only facts stated in the resume; leave strings empty and arrays empty when a fact is missing.
+Dates are "YYYY-MM" when the month is known, otherwise "YYYY".
+A position without an end date that is ongoing has "current": true and an empty "end_date".
+Language levels are CEFR (A1-C2) or "native"; leave empty when not stated.
+Keep names of companies, institutions and skills in the language of the resume.`
+
+// OpenAI извлекает профиль через API chat completions, совместимое с
+// OpenAI, с ответом в формате json_schema и нулевой температурой.
+type OpenAI struct {
+	client   *http.Client
+	baseURL  string
+	apiKey   string
+	model    string
+	maxInput int
+}
+
+func NewOpenAI(cfg config.LLM) (*OpenAI, error) {
+	o := &OpenAI{
+		baseURL:  strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:   cfg.APIKey,
+		model:    cfg.Model,
+		maxInput: cfg.MaxInputChars,
+	}
+
+	if o.baseURL == "" {
+		o.baseURL = defaultOpenAIBaseURL
+	}
+
+	if o.apiKey == "" {
+		o.apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+
+	if o.model == "" {
+		o.model = defaultOpenAIModel
+	}
+
+	if o.maxInput <= 0 {
+		o.maxInput = defaultMaxInputChars
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultLLMTimeout
+	}
+
+	o.client = &http.Client{Timeout: timeout}
+
+	return o, nil
+}
+
+func (o *OpenAI) Name() string {
+	return ProviderOpenAI + ":" + o.model
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string        `json:"model"`
+	Messages       []chatMessage `json:"messages"`
+	Temperature    float64       `json:"temperature"`
+	ResponseFormat any           `json:"response_format"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+			Refusal string `json:"refusal"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (o *OpenAI) Extract(ctx context.Context, text string) ([]byte, error) {
+	if r := []rune(text); len(r) > o.maxInput {
+		text = string(r[:o.maxInput])
+	}
+
+	body, err := json.Marshal(chatRequest{
+		Model: o.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: text},
+		},
+		ResponseFormat: map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "candidate_profile",
+				"schema": json.RawMessage(Schema),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read response: %v", ErrUnavailable, err)
+	}
+
+	var out chatResponse
+	jsonErr := json.Unmarshal(data, &out)
+
+	if resp.StatusCode != http.StatusOK {
+		msg := strings.TrimSpace(string(data[:min(len(data), 512)]))
+		if jsonErr == nil && out.Error != nil {
+			msg = out.Error.Message
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, fmt.Errorf("%w: status %d: %s", ErrUnavailable, resp.StatusCode, msg)
+		}
+
+		return nil, fmt.Errorf("%w: status %d: %s", ErrRejected, resp.StatusCode, msg)
+	}
+
+	if jsonErr != nil {
+		return nil, fmt.Errorf("%w: decode response: %v", ErrUnavailable, jsonErr)
+	}
+
+	if len(out.Choices) == 0 {
+		return nil, fmt.Errorf("%w: empty response", ErrUnavailable)
+	}
+
+	choice := out.Choices[0]
+	if choice.Message.Refusal != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRejected, choice.Message.Refusal)
+	}
+
+	if choice.FinishReason == "length" {
+		return nil, errors.New("profile: model output was truncated")
+	}
+
+	return []byte(choice.Message.Content), nil
+}
+
+var _ Extractor = (*OpenAI)(nil)
@@ -0,0 +1,104 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch применяет JSON Merge Patch (RFC 7396) к документу: объекты
+// сливаются рекурсивно, null удаляет поле, остальные значения (в том числе
+// массивы) заменяются целиком.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p any
+
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, fmt.Errorf("%w: document: %v", ErrInvalid, err)
+		}
+	}
+
+	if len(patch) == 0 {
+		return json.Marshal(target)
+	}
+
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: patch: %v", ErrInvalid, err)
+	}
+
+	return json.Marshal(applyPatch(target, p))
+}
+
+func applyPatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+
+		t[k] = applyPatch(t[k], v)
+	}
+
+	return t
+}
+
+// ComposePatch объединяет два merge patch так, что применение результата
+// равно последовательному применению first и second. В отличие от
+// MergePatch, null сохраняется: он удаляет поле при применении.
+func ComposePatch(first, second []byte) ([]byte, error) {
+	var a, b any
+
+	if len(first) > 0 {
+		if err := json.Unmarshal(first, &a); err != nil {
+			return nil, fmt.Errorf("%w: patch: %v", ErrInvalid, err)
+		}
+	}
+
+	if err := json.Unmarshal(second, &b); err != nil {
+		return nil, fmt.Errorf("%w: patch: %v", ErrInvalid, err)
+	}
+
+	return json.Marshal(composePatch(a, b))
+}
+
+// composePatch объединяет патчи-объекты; first == nil — поле в первом
+// патче отсутствует.
+func composePatch(first, second any) any {
+	s, ok := second.(map[string]any)
+	if !ok {
+		return second
+	}
+
+	f, ok := first.(map[string]any)
+	if !ok {
+		f = map[string]any{}
+	}
+
+	for k, v := range s {
+		if _, isObject := v.(map[string]any); !isObject {
+			f[k] = v
+			continue
+		}
+
+		prev, present := f[k]
+		if _, prevObject := prev.(map[string]any); present && !prevObject {
+			// Первый патч заменил поле значением или удалил его, поэтому
+			// второй применяется к пустому объекту и становится заменой.
+			f[k] = applyPatch(nil, v)
+			continue
+		}
+
+		f[k] = composePatch(prev, v)
+	}
+
+	return f
+}
@@ -0,0 +1,364 @@
+// Package profile описывает структурированный профиль кандидата, который
+// извлекается из текста резюме: контакты, опыт работы с датами,
+// образование, навыки, языки и сертификаты. Схема версионируется: Version
+// меняется при несовместимых изменениях, Schema — её JSON Schema для
+// провайдеров LLM и клиентов API.
+package profile
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Version — текущая версия схемы профиля.
+const Version = 1
+
+// Schema — JSON Schema текущей версии профиля.
+//
+//go:embed schema/v1.json
+var Schema []byte
+
+// Уровни владения языком: CEFR и родной язык.
+var languageLevels = map[string]bool{
+	"": true, "A1": true, "A2": true, "B1": true, "B2": true, "C1": true, "C2": true, "native": true,
+}
+
+// Ограничения размеров полей.
+const (
+	maxShort       = 300
+	maxLong        = 5000
+	maxItems       = 100
+	maxSkills      = 200
+	maxSkillLength = 100
+)
+
+var (
+	ErrInvalid = errors.New("profile: invalid")
+
+	// datePattern — дата с точностью до месяца или года: 2021-03 или 2021.
+	datePattern  = regexp.MustCompile(`^\d{4}(-(0[1-9]|1[0-2]))?$`)
+	emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+)
+
+// Profile — структурированный профиль кандидата.
+type Profile struct {
+	SchemaVersion  int             `json:"schema_version"`
+	Contacts       Contacts        `json:"contacts"`
+	Summary        string          `json:"summary"`
+	Experience     []Experience    `json:"experience"`
+	Education      []Education     `json:"education"`
+	Skills         []string        `json:"skills"`
+	Languages      []Language      `json:"languages"`
+	Certifications []Certification `json:"certifications"`
+}
+
+type Contacts struct {
+	FullName string   `json:"full_name"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Location string   `json:"location"`
+	Links    []string `json:"links"`
+}
+
+// Experience — место работы. Даты — "YYYY-MM" или "YYYY"; у текущего
+// места EndDate пустая и Current = true.
+type Experience struct {
+	Company     string `json:"company"`
+	Title       string `json:"title"`
+	Location    string `json:"location"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Current     bool   `json:"current"`
+	Description string `json:"description"`
+}
+
+type Education struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	Field       string `json:"field"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+}
+
+// Language — язык и уровень по CEFR (A1…C2) или "native"; пустой уровень —
+// не указан.
+type Language struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+type Certification struct {
+	Name   string `json:"name"`
+	Issuer string `json:"issuer"`
+	Date   string `json:"date"`
+}
+
+// ValidationError перечисляет нарушения схемы с путями полей.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "profile: invalid: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
+
+// Parse строго разбирает профиль (неизвестные поля — ошибка), дополняет
+// пустые списки и проверяет его по схеме.
+func Parse(data []byte) (*Profile, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var p Profile
+	if err := dec.Decode(&p); err != nil {
+		return nil, &ValidationError{Problems: []string{err.Error()}}
+	}
+
+	if dec.More() {
+		return nil, &ValidationError{Problems: []string{"trailing data after the profile"}}
+	}
+
+	p.normalize()
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// normalize обрезает пробелы и заменяет nil-списки пустыми, чтобы
+// JSON всегда содержал все поля схемы.
+func (p *Profile) normalize() {
+	trim := func(s *string) { *s = strings.TrimSpace(*s) }
+
+	trim(&p.Contacts.FullName)
+	trim(&p.Contacts.Email)
+	trim(&p.Contacts.Phone)
+	trim(&p.Contacts.Location)
+	trim(&p.Summary)
+
+	for i := range p.Experience {
+		e := &p.Experience[i]
+		for _, s := range []*string{&e.Company, &e.Title, &e.Location, &e.StartDate, &e.EndDate, &e.Description} {
+			trim(s)
+		}
+	}
+
+	for i := range p.Education {
+		e := &p.Education[i]
+		for _, s := range []*string{&e.Institution, &e.Degree, &e.Field, &e.StartDate, &e.EndDate} {
+			trim(s)
+		}
+	}
+
+	for i := range p.Languages {
+		trim(&p.Languages[i].Name)
+		trim(&p.Languages[i].Level)
+	}
+
+	for i := range p.Certifications {
+		c := &p.Certifications[i]
+		for _, s := range []*string{&c.Name, &c.Issuer, &c.Date} {
+			trim(s)
+		}
+	}
+
+	skills := p.Skills[:0]
+	for _, s := range p.Skills {
+		if s = strings.TrimSpace(s); s != "" {
+			skills = append(skills, s)
+		}
+	}
+
+	p.Skills = skills
+
+	if p.Contacts.Links == nil {
+		p.Contacts.Links = []string{}
+	}
+
+	if p.Experience == nil {
+		p.Experience = []Experience{}
+	}
+
+	if p.Education == nil {
+		p.Education = []Education{}
+	}
+
+	if p.Skills == nil {
+		p.Skills = []string{}
+	}
+
+	if p.Languages == nil {
+		p.Languages = []Language{}
+	}
+
+	if p.Certifications == nil {
+		p.Certifications = []Certification{}
+	}
+}
+
+// Validate проверяет профиль по схеме текущей версии.
+func (p *Profile) Validate() error {
+	var problems []string
+
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	length := func(path, value string, max int) {
+		if utf8.RuneCountInString(value) > max {
+			add("%s: longer than %d characters", path, max)
+		}
+	}
+
+	date := func(path, value string) {
+		if value != "" && !datePattern.MatchString(value) {
+			add("%s: expected YYYY-MM or YYYY", path)
+		}
+	}
+
+	period := func(path, start, end string) {
+		date(path+".start_date", start)
+		date(path+".end_date", end)
+
+		// Даты сравниваются как строки по общей точности: год с годом,
+		// месяц с месяцем.
+		if datePattern.MatchString(start) && datePattern.MatchString(end) {
+			n := min(len(start), len(end))
+			if end[:n] < start[:n] {
+				add("%s: end_date is before start_date", path)
+			}
+		}
+	}
+
+	if p.SchemaVersion != Version {
+		add("schema_version: expected %d", Version)
+	}
+
+	length("contacts.full_name", p.Contacts.FullName, maxShort)
+	length("contacts.phone", p.Contacts.Phone, 32)
+	length("contacts.location", p.Contacts.Location, maxShort)
+
+	if p.Contacts.Email != "" && !emailPattern.MatchString(p.Contacts.Email) {
+		add("contacts.email: invalid email")
+	}
+
+	if len(p.Contacts.Links) > maxItems {
+		add("contacts.links: more than %d items", maxItems)
+	}
+
+	for i, link := range p.Contacts.Links {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("contacts.links[%d]: expected an http(s) URL", i)
+		}
+	}
+
+	length("summary", p.Summary, maxLong)
+
+	if len(p.Experience) > maxItems {
+		add("experience: more than %d items", maxItems)
+	}
+
+	for i, e := range p.Experience {
+		path := fmt.Sprintf("experience[%d]", i)
+
+		if e.Company == "" && e.Title == "" {
+			add("%s: company or title is required", path)
+		}
+
+		length(path+".company", e.Company, maxShort)
+		length(path+".title", e.Title, maxShort)
+		length(path+".location", e.Location, maxShort)
+		length(path+".description", e.Description, maxLong)
+		period(path, e.StartDate, e.EndDate)
+
+		if e.Current && e.EndDate != "" {
+			add("%s: current position has an end_date", path)
+		}
+	}
+
+	if len(p.Education) > maxItems {
+		add("education: more than %d items", maxItems)
+	}
+
+	for i, e := range p.Education {
+		path := fmt.Sprintf("education[%d]", i)
+
+		if e.Institution == "" {
+			add("%s.institution: required", path)
+		}
+
+		length(path+".institution", e.Institution, maxShort)
+		length(path+".degree", e.Degree, maxShort)
+		length(path+".field", e.Field, maxShort)
+		period(path, e.StartDate, e.EndDate)
+	}
+
+	if len(p.Skills) > maxSkills {
+		add("skills: more than %d items", maxSkills)
+	}
+
+	for i, s := range p.Skills {
+		length(fmt.Sprintf("skills[%d]", i), s, maxSkillLength)
+	}
+
+	if len(p.Languages) > maxItems {
+		add("languages: more than %d items", maxItems)
+	}
+
+	for i, l := range p.Languages {
+		path := fmt.Sprintf("languages[%d]", i)
+
+		if l.Name == "" {
+			add("%s.name: required", path)
+		}
+
+		length(path+".name", l.Name, maxSkillLength)
+
+		if !languageLevels[l.Level] {
+			add("%s.level: expected A1, A2, B1, B2, C1, C2 or native", path)
+		}
+	}
+
+	if len(p.Certifications) > maxItems {
+		add("certifications: more than %d items", maxItems)
+	}
+
+	for i, c := range p.Certifications {
+		path := fmt.Sprintf("certifications[%d]", i)
+
+		if c.Name == "" {
+			add("%s.name: required", path)
+		}
+
+		length(path+".name", c.Name, maxShort)
+		length(path+".issuer", c.Issuer, maxShort)
+		date(path+".date", c.Date)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// New возвращает пустой профиль текущей версии со всеми полями схемы.
+func New() *Profile {
+	p := &Profile{SchemaVersion: Version}
+	p.normalize()
+
+	return p
+}
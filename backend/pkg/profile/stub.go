@@ -0,0 +1,605 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Stub извлекает профиль правилами, без модели: контакты — регулярными
+// выражениями, остальное — по заголовкам разделов на английском, русском
+// и испанском. Результат зависит только от текста, поэтому заглушка
+// подходит для разработки и тестов и как запасной вариант без LLM.
+type Stub struct{}
+
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+func (s *Stub) Name() string {
+	return ProviderStub
+}
+
+func (s *Stub) Extract(_ context.Context, text string) ([]byte, error) {
+	return json.Marshal(stubProfile(text))
+}
+
+var _ Extractor = (*Stub)(nil)
+
+// Разделы резюме.
+const (
+	sectionNone = iota
+	sectionSummary
+	sectionExperience
+	sectionEducation
+	sectionSkills
+	sectionLanguages
+	sectionCertifications
+	sectionOther
+)
+
+// sectionHeadings — заголовки разделов в нижнем регистре без двоеточия.
+var sectionHeadings = map[string]int{
+	"summary": sectionSummary, "about": sectionSummary, "about me": sectionSummary,
+	"profile": sectionSummary, "professional summary": sectionSummary, "objective": sectionSummary,
+	"о себе": sectionSummary, "обо мне": sectionSummary, "резюме": sectionSummary,
+	"resumen": sectionSummary, "perfil": sectionSummary, "sobre mí": sectionSummary, "acerca de mí": sectionSummary,
+
+	"experience": sectionExperience, "work experience": sectionExperience,
+	"professional experience": sectionExperience, "employment": sectionExperience,
+	"employment history": sectionExperience, "work history": sectionExperience,
+	"опыт": sectionExperience, "опыт работы": sectionExperience, "трудовой опыт": sectionExperience,
+	"experiencia": sectionExperience, "experiencia laboral": sectionExperience,
+	"experiencia profesional": sectionExperience,
+
+	"education": sectionEducation, "образование": sectionEducation,
+	"educación": sectionEducation, "formación": sectionEducation, "formación académica": sectionEducation,
+
+	"skills": sectionSkills, "technical skills": sectionSkills, "key skills": sectionSkills,
+	"core skills": sectionSkills, "technologies": sectionSkills,
+	"навыки": sectionSkills, "ключевые навыки": sectionSkills, "технологии": sectionSkills,
+	"профессиональные навыки": sectionSkills,
+	"habilidades": sectionSkills, "competencias": sectionSkills, "conocimientos": sectionSkills,
+
+	"languages": sectionLanguages, "языки": sectionLanguages, "знание языков": sectionLanguages,
+	"иностранные языки": sectionLanguages, "idiomas": sectionLanguages,
+
+	"certifications": sectionCertifications, "certificates": sectionCertifications,
+	"licenses & certifications": sectionCertifications, "courses": sectionCertifications,
+	"сертификаты": sectionCertifications, "курсы": sectionCertifications,
+	"курсы и сертификаты": sectionCertifications,
+	"certificaciones":     sectionCertifications, "certificados": sectionCertifications, "cursos": sectionCertifications,
+
+	"projects": sectionOther, "interests": sectionOther, "hobbies": sectionOther,
+	"references": sectionOther, "contacts": sectionOther, "contact": sectionOther,
+	"проекты": sectionOther, "хобби": sectionOther, "контакты": sectionOther,
+	"proyectos": sectionOther, "intereses": sectionOther, "contacto": sectionOther,
+}
+
+var (
+	stubEmail = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	stubPhone = regexp.MustCompile(`\+?\d[\d\s().\-]{8,}\d`)
+	stubLink  = regexp.MustCompile(`(?i)\b(?:https?://|www\.|(?:linkedin|github|gitlab)\.com/)[^\s,;()<>"]+`)
+
+	// stubDate — дата в резюме: 03.2020, 2020-03, "Mar 2020", "март 2020", 2020.
+	stubDate = `(?:\d{1,2}[./]\d{4}|\d{4}[-./]\d{1,2}|` + stubMonthName + `\.?\s+\d{4}|\d{4})`
+	// stubMonthName — название месяца на английском, русском или испанском.
+	stubMonthName = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec|янв|фев|мар|апр|ма[йя]|июн|июл|авг|сен|окт|ноя|дек|ene|abr|ago|dic)[A-Za-zА-Яа-яЁё]*`
+	// stubPresent — «по настоящее время».
+	stubPresent = `(?:present|now|current|today|по\s+настоящее\s+время|настоящее\s+время|по\s+н\.?\s*в\.?|н\.?\s*в\.?|сейчас|actualidad|actual|presente|hoy)`
+	stubRange   = regexp.MustCompile(`(?i)(` + stubDate + `)\s*(?:-|–|—|to|по|a|hasta)\s*(` + stubDate + `|` + stubPresent + `)`)
+	stubSingle  = regexp.MustCompile(`(?i)` + stubDate)
+
+	stubYear        = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+	stubNumMonth    = regexp.MustCompile(`^(\d{1,2})[./](\d{4})$`)
+	stubIsoMonth    = regexp.MustCompile(`^(\d{4})[-./](\d{1,2})$`)
+	stubNameMonth   = regexp.MustCompile(`(?i)^(` + stubMonthName + `)\.?\s+(\d{4})$`)
+	stubPresentRe   = regexp.MustCompile(`(?i)^` + stubPresent + `$`)
+	stubCEFR        = regexp.MustCompile(`\b([ABC][12])\b`)
+	stubListSplit   = regexp.MustCompile(`\s*(?:[,;•·|]|\s[-–—]\s|\t)\s*`)
+	stubBullet      = regexp.MustCompile(`^[\s•·*\-–—▪●○◦]+`)
+	stubInstitution = regexp.MustCompile(`(?i)(universit|college|institut|school|academ|университет|институт|академи|колледж|училищ|школ|universidad|escuela|instituto|academia)`)
+	stubDegree      = regexp.MustCompile(`(?i)(bachelor|master|ph\.?d|mba|b\.?sc|m\.?sc|associate|бакалавр|магистр|специалист|кандидат|аспирант|licenciatura|grado|máster|doctorado|ingenier)`)
+)
+
+// stubMonths — первые три буквы названий месяцев.
+var stubMonths = map[string]string{
+	"jan": "01", "feb": "02", "mar": "03", "apr": "04", "may": "05", "jun": "06",
+	"jul": "07", "aug": "08", "sep": "09", "oct": "10", "nov": "11", "dec": "12",
+	"янв": "01", "фев": "02", "мар": "03", "апр": "04", "мая": "05", "май": "05",
+	"июн": "06", "июл": "07", "авг": "08", "сен": "09", "окт": "10", "ноя": "11", "дек": "12",
+	"ene": "01", "abr": "04", "ago": "08", "dic": "12",
+}
+
+// stubLanguages — названия языков на английском, русском и испанском.
+var stubLanguages = map[string]string{
+	"english": "English", "английский": "English", "inglés": "English", "ingles": "English",
+	"russian": "Russian", "русский": "Russian", "ruso": "Russian",
+	"spanish": "Spanish", "испанский": "Spanish", "español": "Spanish", "espanol": "Spanish",
+	"german": "German", "немецкий": "German", "alemán": "German",
+	"french": "French", "французский": "French", "francés": "French",
+	"italian": "Italian", "итальянский": "Italian", "italiano": "Italian",
+	"portuguese": "Portuguese", "португальский": "Portuguese", "portugués": "Portuguese",
+	"chinese": "Chinese", "китайский": "Chinese", "chino": "Chinese",
+	"ukrainian": "Ukrainian", "украинский": "Ukrainian", "ucraniano": "Ukrainian",
+	"japanese": "Japanese", "японский": "Japanese", "japonés": "Japanese",
+}
+
+// stubLevels — словесные уровни владения языком и их CEFR-эквиваленты.
+// Порядок важен: более длинные формулировки проверяются раньше.
+var stubLevels = []struct{ word, level string }{
+	{"upper-intermediate", "B2"}, {"upper intermediate", "B2"}, {"pre-intermediate", "A2"},
+	{"intermediate", "B1"}, {"elementary", "A2"}, {"beginner", "A1"}, {"basic", "A2"},
+	{"advanced", "C1"}, {"fluent", "C1"}, {"proficient", "C2"}, {"native", "native"},
+	{"mother tongue", "native"},
+	{"выше среднего", "B2"}, {"средний", "B1"}, {"базовый", "A2"}, {"начальный", "A1"},
+	{"продвинутый", "C1"}, {"свободн", "C1"}, {"родной", "native"},
+	{"intermedio", "B1"}, {"básico", "A2"}, {"avanzado", "C1"}, {"fluido", "C1"},
+	{"nativo", "native"}, {"lengua materna", "native"},
+}
+
+// stubSection возвращает раздел, если строка — его заголовок.
+func stubSection(line string) (int, bool) {
+	l := strings.ToLower(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line), ":")))
+	if utf8.RuneCountInString(l) > 40 {
+		return 0, false
+	}
+
+	section, ok := sectionHeadings[l]
+
+	return section, ok
+}
+
+func stubProfile(text string) *Profile {
+	p := &Profile{SchemaVersion: Version}
+
+	lines := strings.Split(text, "\n")
+	sections := map[int][]string{}
+	current := sectionNone
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if section, ok := stubSection(line); ok {
+			current = section
+			continue
+		}
+
+		sections[current] = append(sections[current], line)
+	}
+
+	p.Contacts = stubContacts(text, sections[sectionNone])
+	p.Summary = clip(strings.TrimSpace(strings.Join(sections[sectionSummary], "\n")), maxLong)
+	p.Experience = stubExperience(sections[sectionExperience])
+	p.Education = stubEducation(sections[sectionEducation])
+	p.Skills = stubSkills(sections[sectionSkills])
+	p.Languages = stubLanguageList(sections[sectionLanguages])
+	p.Certifications = stubCertifications(sections[sectionCertifications])
+
+	p.normalize()
+
+	return p
+}
+
+func stubContacts(text string, header []string) Contacts {
+	c := Contacts{Links: []string{}}
+
+	if m := stubEmail.FindString(text); m != "" {
+		c.Email = clip(m, maxShort)
+	}
+
+	for _, m := range stubPhone.FindAllString(text, -1) {
+		digits := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return r
+			}
+
+			return -1
+		}, m)
+
+		// Диапазоны лет ("2019 - 2021") похожи на телефон; телефон — это
+		// 10–15 цифр.
+		if len(digits) >= 10 && len(digits) <= 15 && !stubRange.MatchString(m) {
+			c.Phone = clip(strings.TrimSpace(m), 32)
+			break
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, m := range stubLink.FindAllString(text, -1) {
+		m = strings.TrimRight(m, ".")
+		if !strings.HasPrefix(strings.ToLower(m), "http") {
+			m = "https://" + m
+		}
+
+		if !seen[m] && len(c.Links) < maxItems && utf8.RuneCountInString(m) <= maxShort {
+			seen[m] = true
+			c.Links = append(c.Links, m)
+		}
+	}
+
+	for _, line := range header {
+		if line == "" {
+			continue
+		}
+
+		if stubLooksLikeName(line) {
+			c.FullName = line
+		}
+
+		break
+	}
+
+	return c
+}
+
+// stubLooksLikeName — строка из 2–4 слов, состоящих только из букв.
+func stubLooksLikeName(line string) bool {
+	words := strings.Fields(line)
+	if len(words) < 2 || len(words) > 4 {
+		return false
+	}
+
+	for _, w := range words {
+		for _, r := range w {
+			if !unicode.IsLetter(r) && r != '-' && r != '\'' && r != '.' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// stubParseDate переводит дату резюме в "YYYY-MM" или "YYYY".
+func stubParseDate(s string) string {
+	s = strings.TrimSpace(s)
+
+	if m := stubNumMonth.FindStringSubmatch(s); m != nil {
+		return monthDate(m[2], m[1])
+	}
+
+	if m := stubIsoMonth.FindStringSubmatch(s); m != nil {
+		return monthDate(m[1], m[2])
+	}
+
+	if m := stubNameMonth.FindStringSubmatch(s); m != nil {
+		r := []rune(strings.ToLower(m[1]))
+		if month, ok := stubMonths[string(r[:3])]; ok {
+			return m[2] + "-" + month
+		}
+
+		return m[2]
+	}
+
+	if y := stubYear.FindString(s); y != "" && len(s) == 4 {
+		return y
+	}
+
+	return ""
+}
+
+func monthDate(year, month string) string {
+	if len(month) == 1 {
+		month = "0" + month
+	}
+
+	if month < "01" || month > "12" {
+		return year
+	}
+
+	return year + "-" + month
+}
+
+// stubPeriod ищет в строке диапазон дат и возвращает его и строку без него.
+func stubPeriod(line string) (start, end string, current bool, rest string, ok bool) {
+	loc := stubRange.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", "", false, line, false
+	}
+
+	start = stubParseDate(line[loc[2]:loc[3]])
+	endText := line[loc[4]:loc[5]]
+
+	if stubPresentRe.MatchString(strings.TrimSpace(endText)) {
+		current = true
+	} else {
+		end = stubParseDate(endText)
+	}
+
+	if start != "" && end != "" {
+		n := min(len(start), len(end))
+		if end[:n] < start[:n] {
+			end = ""
+		}
+	}
+
+	rest = strings.TrimSpace(line[:loc[0]] + " " + line[loc[1]:])
+	rest = strings.Trim(rest, " ,|–—-()")
+
+	return start, end, current, rest, true
+}
+
+// stubSplitTitle делит заголовок места работы на должность и компанию:
+// "Go Developer at Acme", "Go Developer, Acme", "Acme — Go Developer".
+func stubSplitTitle(s string) (title, company string) {
+	for _, sep := range []string{" at ", " @ ", " в ", " en ", ", ", " | ", " — ", " – ", " - "} {
+		if i := strings.Index(s, sep); i > 0 {
+			return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):])
+		}
+	}
+
+	return s, ""
+}
+
+func stubExperience(lines []string) []Experience {
+	var (
+		items []Experience
+		prev  string
+		desc  []string
+	)
+
+	flush := func() {
+		if len(items) > 0 {
+			items[len(items)-1].Description = clip(strings.TrimSpace(strings.Join(desc, "\n")), maxLong)
+		}
+
+		desc = nil
+	}
+
+	for _, line := range lines {
+		start, end, current, rest, ok := stubPeriod(line)
+		if !ok {
+			if line != "" {
+				desc = append(desc, strings.TrimSpace(stubBullet.ReplaceAllString(line, "")))
+			}
+
+			prev = line
+
+			continue
+		}
+
+		// Заголовок — текст строки с датами или предыдущая строка, если
+		// она ещё не ушла в описание прошлого места.
+		header := rest
+		if header == "" && prev != "" && len(desc) > 0 && desc[len(desc)-1] == prev {
+			header = prev
+			desc = desc[:len(desc)-1]
+		}
+
+		flush()
+
+		if len(items) >= maxItems {
+			break
+		}
+
+		title, company := stubSplitTitle(header)
+		items = append(items, Experience{
+			Title:     clip(title, maxShort),
+			Company:   clip(company, maxShort),
+			StartDate: start,
+			EndDate:   end,
+			Current:   current,
+		})
+
+		if items[len(items)-1].Title == "" && items[len(items)-1].Company == "" {
+			items[len(items)-1].Title = "—"
+		}
+
+		prev = ""
+	}
+
+	flush()
+
+	return items
+}
+
+func stubEducation(lines []string) []Education {
+	var items []Education
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" || !stubInstitution.MatchString(line) {
+			continue
+		}
+
+		e := Education{Institution: line}
+
+		start, end, _, rest, ok := stubPeriod(line)
+		if ok {
+			e.Institution, e.StartDate, e.EndDate = rest, start, end
+		} else if y := stubYear.FindAllString(line, -1); len(y) == 1 {
+			e.EndDate = y[0]
+		}
+
+		// Степень и специальность обычно на соседней строке.
+		for _, j := range []int{i + 1, i - 1} {
+			if j >= 0 && j < len(lines) && stubDegree.MatchString(lines[j]) && !stubInstitution.MatchString(lines[j]) {
+				e.Degree, e.Field = stubSplitTitle(lines[j])
+
+				if s, en, _, r, ok := stubPeriod(lines[j]); ok && e.StartDate == "" {
+					e.StartDate, e.EndDate = s, en
+					e.Degree, e.Field = stubSplitTitle(r)
+				}
+
+				break
+			}
+		}
+
+		// "Bachelor of Computer Science, MIT" — степень и вуз в одной строке.
+		if e.Degree == "" && stubDegree.MatchString(e.Institution) {
+			if a, b := stubSplitTitle(e.Institution); b != "" {
+				if stubInstitution.MatchString(b) {
+					e.Degree, e.Institution = a, b
+				} else {
+					e.Institution, e.Degree = a, b
+				}
+			}
+		}
+
+		e.Institution = clip(strings.Trim(e.Institution, " ,"), maxShort)
+		e.Degree = clip(e.Degree, maxShort)
+		e.Field = clip(e.Field, maxShort)
+
+		if e.Institution != "" {
+			items = append(items, e)
+		}
+
+		if len(items) >= maxItems {
+			break
+		}
+	}
+
+	return items
+}
+
+func stubSkills(lines []string) []string {
+	seen := map[string]bool{}
+
+	var skills []string
+
+	for _, line := range lines {
+		line = stubBullet.ReplaceAllString(line, "")
+
+		// "Languages: Go, Python" — подпись группы навыков отбрасывается.
+		if i := strings.Index(line, ":"); i > 0 && i < 40 {
+			line = line[i+1:]
+		}
+
+		for _, s := range stubListSplit.Split(line, -1) {
+			s = strings.Trim(s, " .")
+			key := strings.ToLower(s)
+
+			if s == "" || seen[key] || utf8.RuneCountInString(s) > maxSkillLength {
+				continue
+			}
+
+			seen[key] = true
+			skills = append(skills, s)
+
+			if len(skills) >= maxSkills {
+				return skills
+			}
+		}
+	}
+
+	return skills
+}
+
+func stubLanguageList(lines []string) []Language {
+	seen := map[string]bool{}
+
+	var items []Language
+
+	for _, line := range lines {
+		// "English (native), Spanish — B2": уровень относится к языку
+		// своего фрагмента строки.
+		for _, part := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' }) {
+			lower := strings.ToLower(part)
+
+			var names []string
+			for word, name := range stubLanguages {
+				if strings.Contains(lower, word) && !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+
+			// Карта обходится в случайном порядке; сортируем по позиции в строке.
+			sort.Slice(names, func(a, b int) bool {
+				return stubIndex(lower, names[a]) < stubIndex(lower, names[b])
+			})
+
+			level := ""
+			if len(names) == 1 {
+				level = stubLevel(part)
+			}
+
+			for _, name := range names {
+				items = append(items, Language{Name: name, Level: level})
+			}
+		}
+	}
+
+	return items
+}
+
+// stubLevel определяет уровень владения языком по CEFR или словесному
+// описанию.
+func stubLevel(s string) string {
+	if m := stubCEFR.FindString(s); m != "" {
+		return m
+	}
+
+	lower := strings.ToLower(s)
+	for _, l := range stubLevels {
+		if strings.Contains(lower, l.word) {
+			return l.level
+		}
+	}
+
+	return ""
+}
+
+// stubIndex — позиция первого упоминания языка name в строке.
+func stubIndex(lower, name string) int {
+	best := len(lower)
+
+	for word, n := range stubLanguages {
+		if n == name {
+			if i := strings.Index(lower, word); i >= 0 && i < best {
+				best = i
+			}
+		}
+	}
+
+	return best
+}
+
+func stubCertifications(lines []string) []Certification {
+	var items []Certification
+
+	for _, line := range lines {
+		line = strings.TrimSpace(stubBullet.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+
+		c := Certification{}
+
+		if loc := stubSingle.FindStringIndex(line); loc != nil {
+			if d := stubParseDate(line[loc[0]:loc[1]]); d != "" {
+				c.Date = d
+				line = strings.Trim(strings.TrimSpace(line[:loc[0]]+" "+line[loc[1]:]), " ,()|–—-")
+			}
+		}
+
+		c.Name, c.Issuer = stubSplitTitle(line)
+		c.Name = clip(c.Name, maxShort)
+		c.Issuer = clip(c.Issuer, maxShort)
+
+		if c.Name != "" {
+			items = append(items, c)
+		}
+
+		if len(items) >= maxItems {
+			break
+		}
+	}
+
+	return items
+}
+
+// clip обрезает строку до max символов.
+func clip(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max])
+}
@@ -2,15 +2,16 @@ package rbac
 
 import (
 	"backend/pkg/svc"
-	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
 	"github.com/jackc/pgx/v5"
 	pgxadapter "github.com/pckhoi/casbin-pgx-adapter/v3"
 	"go.uber.org/zap"
@@ -18,21 +19,76 @@ import (
 
 var _ svc.Service = (*CasbinClient)(nil)
 
+// permissionPrefix отличает субъекты-разрешения ("perm:jobs:read") от ролей
+// и пользователей. Разрешение владеет конкретными правилами path/method
+// из policy.csv, а роли получают его через grouping-политику.
+const permissionPrefix = "perm:"
+
+// AnyDomain — домен встроенных политик, действующих во всех организациях.
+const AnyDomain = "*"
+
 type CasbinClient struct {
-	log       *zap.Logger
-	connConf  *pgx.ConnConfig
-	modelPath string
+	log        *zap.Logger
+	connConf   *pgx.ConnConfig
+	modelPath  string
+	policySync string
 
 	adapter  *pgxadapter.Adapter
-	enforcer *casbin.Enforcer
+	enforcer *casbin.SyncedEnforcer
+	watcher  *Watcher
+}
+
+type Option func(*CasbinClient)
+
+// WithPolicySync задаёт режим синхронизации policy.csv при запуске:
+// PolicySyncAdd (по умолчанию), PolicySyncPrune или PolicySyncNone.
+func WithPolicySync(mode string) Option {
+	return func(c *CasbinClient) {
+		if mode != "" {
+			c.policySync = mode
+		}
+	}
 }
 
-func NewCasbinClient(log *zap.Logger, connConf *pgx.ConnConfig, modelPath string) *CasbinClient {
-	return &CasbinClient{
-		log:       log,
-		connConf:  connConf,
-		modelPath: modelPath,
+func NewCasbinClient(log *zap.Logger, connConf *pgx.ConnConfig, modelPath string, opts ...Option) *CasbinClient {
+	c := &CasbinClient{
+		log:        log,
+		connConf:   connConf,
+		modelPath:  modelPath,
+		policySync: PolicySyncAdd,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
+}
+
+// PolicyPathFor возвращает путь к policy.csv, лежащему рядом с model.conf.
+func PolicyPathFor(modelPath string) string {
+	return filepath.Join(filepath.Dir(modelPath), "policy.csv")
+}
+
+// RetiredPolicyPathFor возвращает путь к policy_retired.csv, лежащему
+// рядом с model.conf.
+func RetiredPolicyPathFor(modelPath string) string {
+	return filepath.Join(filepath.Dir(modelPath), "policy_retired.csv")
+}
+
+// PolicyPath возвращает путь к policy.csv этого клиента.
+func (c *CasbinClient) PolicyPath() string {
+	return PolicyPathFor(c.modelPath)
+}
+
+// RetiredPolicyPath возвращает путь к policy_retired.csv этого клиента.
+func (c *CasbinClient) RetiredPolicyPath() string {
+	return RetiredPolicyPathFor(c.modelPath)
+}
+
+// ModelPath возвращает путь к model.conf.
+func (c *CasbinClient) ModelPath() string {
+	return c.modelPath
 }
 
 func (c *CasbinClient) Name() string {
@@ -43,7 +99,7 @@ func (c *CasbinClient) DependsOn() []string {
 	return []string{"db"}
 }
 
-func (c *CasbinClient) Init(_ context.Context) error {
+func (c *CasbinClient) Init(ctx context.Context) error {
 	adapter, err := pgxadapter.NewAdapter(c.connConf)
 	if err != nil {
 		return fmt.Errorf("failed to create casbin pgx adapter: %w", err)
@@ -51,21 +107,44 @@ func (c *CasbinClient) Init(_ context.Context) error {
 
 	c.adapter = adapter
 
-	enforcer, err := casbin.NewEnforcer(c.modelPath, adapter)
+	// Политики меняются во время работы (кастомные роли, приглашения),
+	// поэтому используем потокобезопасный enforcer.
+	enforcer, err := casbin.NewSyncedEnforcer(c.modelPath, adapter)
 	if err != nil {
 		return fmt.Errorf("failed to create casbin enforcer: %w", err)
 	}
 
+	// Связи из домена "*" (встроенные роли -> разрешения) действуют
+	// в каждой организации.
+	enforcer.AddNamedDomainMatchingFunc("g", "keyMatch", util.KeyMatch)
+
+	// Изменения политик на других репликах приходят через watcher.
+	// Подписываемся до загрузки политик, чтобы не пропустить изменения
+	// между LoadPolicy и LISTEN.
+	watcher := NewWatcher(c.log, c.connConf)
+	if err := watcher.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to start casbin watcher: %w", err)
+	}
+
+	c.watcher = watcher
+
+	if err := enforcer.SetWatcher(watcher); err != nil {
+		return fmt.Errorf("failed to set casbin watcher: %w", err)
+	}
+
+	if err := watcher.SetUpdateCallback(c.applyUpdate); err != nil {
+		return fmt.Errorf("failed to set casbin watcher callback: %w", err)
+	}
+
 	if err := enforcer.LoadPolicy(); err != nil {
 		return fmt.Errorf("failed to load casbin policy: %w", err)
 	}
 
 	c.enforcer = enforcer
 
-	// Синхронизируем политики из policy.csv при каждом запуске.
-	// Метод AddPolicy идемпотентен: он добавит в БД только новые строки из CSV,
-	// которых там ещё нет.
-	if err := c.seedDefaultPolicies(); err != nil {
+	// Синхронизируем политики из policy.csv при каждом запуске
+	// в режиме, заданном WithPolicySync.
+	if err := c.syncPolicyFile(); err != nil {
 		return fmt.Errorf("failed to sync default policies: %w", err)
 	}
 
@@ -79,15 +158,24 @@ func (c *CasbinClient) HealthCheck(_ context.Context) error {
 		return errors.New("casbin enforcer is not initialized")
 	}
 
+	if err := c.watcher.Healthy(); err != nil {
+		return err
+	}
+
 	c.log.Debug("casbin health check passed")
 	return nil
 }
 
-func (c *CasbinClient) Run(_ context.Context) error {
-	return nil
+// Run слушает изменения политик от других реплик до остановки приложения.
+func (c *CasbinClient) Run(ctx context.Context) error {
+	return c.watcher.Listen(ctx, c.reload)
 }
 
 func (c *CasbinClient) Stop(_ context.Context) error {
+	if c.watcher != nil {
+		c.watcher.Close()
+	}
+
 	if c.adapter != nil {
 		c.adapter.Close()
 	}
@@ -96,10 +184,90 @@ func (c *CasbinClient) Stop(_ context.Context) error {
 	return nil
 }
 
+// reload полностью перечитывает политики из БД.
+func (c *CasbinClient) reload() {
+	if err := c.enforcer.LoadPolicy(); err != nil {
+		c.log.Error("casbin policy reload failed", zap.Error(err))
+		return
+	}
+
+	c.log.Info("casbin policy reloaded")
+}
+
+// applyUpdate применяет изменение, полученное от другой реплики.
+// Правила уже сохранены в БД инициатором, поэтому автосохранение
+// на время применения отключается. Блокировка enforcer'а удерживается
+// всё это время, так что локальные изменения не попадают в окно
+// с выключенным автосохранением.
+func (c *CasbinClient) applyUpdate(payload string) {
+	var msg watcherMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		c.log.Error("casbin watcher: malformed message", zap.Error(err))
+		return
+	}
+
+	if msg.Op == opReload {
+		c.reload()
+		return
+	}
+
+	lock := c.enforcer.GetLock()
+	lock.Lock()
+	defer lock.Unlock()
+
+	e := c.enforcer.Enforcer
+	e.EnableAutoSave(false)
+	defer e.EnableAutoSave(true)
+
+	var err error
+
+	switch msg.Op {
+	case opAddPolicies:
+		_, err = e.SelfAddPoliciesEx(msg.Sec, msg.Ptype, msg.Rules)
+	case opRemovePolicies:
+		_, err = e.SelfRemovePolicies(msg.Sec, msg.Ptype, msg.Rules)
+	case opRemoveFiltered:
+		_, err = e.SelfRemoveFilteredPolicy(msg.Sec, msg.Ptype, msg.FieldIndex, msg.FieldValues...)
+	case opUpdatePolicies:
+		_, err = e.SelfUpdatePolicies(msg.Sec, msg.Ptype, msg.Rules, msg.NewRules)
+	default:
+		err = fmt.Errorf("unknown operation %q", msg.Op)
+	}
+
+	if err != nil {
+		c.log.Error("casbin watcher: apply update failed, scheduling full reload", zap.String("op", msg.Op), zap.Error(err))
+
+		// LoadPolicy берёт ту же блокировку, поэтому запускаем его после выхода.
+		go c.reload()
+	}
+}
+
+// Resource описывает атрибуты объекта запроса, которые проверяет матчер
+// model.conf. Пустой JobID означает, что запрос не привязан к вакансии.
+type Resource struct {
+	// TeamID — организация, которой принадлежит вакансия.
+	TeamID string
+	// JobID — вакансия из параметров маршрута.
+	JobID string
+	// Granted — у пользователя есть запись в hiring.t_job_access.
+	Granted bool
+}
+
 // Enforce проверяет, имеет ли sub (userID) в домене dom (orgID)
-// доступ к ресурсу obj с действием act.
-func (c *CasbinClient) Enforce(sub, dom, obj, act string) (bool, error) {
-	return c.enforcer.Enforce(sub, dom, obj, act)
+// доступ к ресурсу obj с действием act с учётом атрибутов res.
+func (c *CasbinClient) Enforce(sub, dom, obj, act string, res Resource) (bool, error) {
+	return c.enforcer.Enforce(sub, dom, obj, act, res)
+}
+
+// HasPermissionInDomain проверяет, получает ли пользователь разрешение
+// permission в организации через любую из своих ролей.
+func (c *CasbinClient) HasPermissionInDomain(user, permission, domain string) (bool, error) {
+	ok, err := c.enforcer.GetRoleManager().HasLink(user, permissionPrefix+permission, domain)
+	if err != nil {
+		return false, fmt.Errorf("check permission %q for user %q in domain %q: %w", permission, user, domain, err)
+	}
+
+	return ok, nil
 }
 
 // AddRoleForUserInDomain назначает роль пользователю в организации.
@@ -144,60 +312,199 @@ func (c *CasbinClient) DeleteAllRolesInDomain(domain string) (bool, error) {
 	return ok, nil
 }
 
-// seedDefaultPolicies загружает стартовые политики из policy.csv.
-// Вызывается один раз при первом запуске, когда в БД нет ни одной политики.
-func (c *CasbinClient) seedDefaultPolicies() error {
-	policyPath := filepath.Join(filepath.Dir(c.modelPath), "policy.csv")
+// SetPermissionsForRoleInDomain заменяет набор разрешений роли в организации.
+// Разрешения передаются без префикса, например "candidates:read".
+func (c *CasbinClient) SetPermissionsForRoleInDomain(role, domain string, permissions []string) error {
+	if _, err := c.enforcer.RemoveFilteredGroupingPolicy(0, role, "", domain); err != nil {
+		return fmt.Errorf("clear permissions of role %q in domain %q: %w", role, domain, err)
+	}
+
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rules := make([][]string, len(permissions))
+	for i, p := range permissions {
+		rules[i] = []string{role, permissionPrefix + p, domain}
+	}
+
+	if _, err := c.enforcer.AddGroupingPolicies(rules); err != nil {
+		return fmt.Errorf("add permissions for role %q in domain %q: %w", role, domain, err)
+	}
+
+	return nil
+}
 
-	f, err := os.Open(policyPath)
+// GetPermissionsForRoleInDomain возвращает разрешения, выданные роли
+// непосредственно в указанном домене (без учёта наследования).
+func (c *CasbinClient) GetPermissionsForRoleInDomain(role, domain string) ([]string, error) {
+	rules, err := c.enforcer.GetFilteredGroupingPolicy(0, role, "", domain)
 	if err != nil {
-		return fmt.Errorf("open policy file %q: %w", policyPath, err)
+		return nil, fmt.Errorf("get permissions of role %q in domain %q: %w", role, domain, err)
 	}
-	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	permissions := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if p, ok := strings.CutPrefix(rule[1], permissionPrefix); ok {
+			permissions = append(permissions, p)
 		}
+	}
 
-		parts := strings.Split(line, ",")
-		for i := range parts {
-			parts[i] = strings.TrimSpace(parts[i])
-		}
+	return permissions, nil
+}
 
-		if len(parts) < 2 {
-			continue
-		}
+// DeleteRoleInDomain удаляет роль в организации вместе с её разрешениями
+// и назначениями пользователям.
+func (c *CasbinClient) DeleteRoleInDomain(role, domain string) error {
+	if _, err := c.enforcer.RemoveFilteredGroupingPolicy(0, role, "", domain); err != nil {
+		return fmt.Errorf("delete permissions of role %q in domain %q: %w", role, domain, err)
+	}
 
-		switch parts[0] {
-		case "p":
-			args := make([]interface{}, len(parts)-1)
-			for i, v := range parts[1:] {
-				args[i] = v
-			}
+	if _, err := c.enforcer.RemoveFilteredGroupingPolicy(1, role, domain); err != nil {
+		return fmt.Errorf("delete assignments of role %q in domain %q: %w", role, domain, err)
+	}
 
-			if _, err := c.enforcer.AddPolicy(args...); err != nil {
-				return fmt.Errorf("add policy %v: %w", parts, err)
-			}
-		case "g":
-			args := make([]interface{}, len(parts)-1)
-			for i, v := range parts[1:] {
-				args[i] = v
-			}
+	return nil
+}
 
-			if _, err := c.enforcer.AddGroupingPolicy(args...); err != nil {
-				return fmt.Errorf("add grouping policy %v: %w", parts, err)
+// syncPolicyFile проверяет policy.csv и применяет его к БД согласно
+// режиму policySync. Правила из policy_retired.csv удаляются в обоих
+// режимах, кроме none. Правила организаций не затрагиваются ни в одном
+// режиме.
+func (c *CasbinClient) syncPolicyFile() error {
+	switch c.policySync {
+	case PolicySyncNone:
+		return nil
+	case PolicySyncAdd, PolicySyncPrune:
+	default:
+		return fmt.Errorf("unknown policy sync mode %q", c.policySync)
+	}
+
+	policyPath := c.PolicyPath()
+
+	rules, err := ReadPolicyFile(policyPath)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidatePolicy(c.modelPath, rules); err != nil {
+		return fmt.Errorf("invalid policy file %q: %w", policyPath, err)
+	}
+
+	retiredPath := c.RetiredPolicyPath()
+
+	retired, err := ReadRetiredPolicy(retiredPath)
+	if err != nil {
+		return err
+	}
+
+	if err := errors.Join(ValidatePolicy(c.modelPath, retired), ValidateRetired(rules, retired)); err != nil {
+		return fmt.Errorf("invalid retired policy file %q: %w", retiredPath, err)
+	}
+
+	diff, err := c.DiffPolicy(rules, retired)
+	if err != nil {
+		return err
+	}
+
+	prune := c.policySync == PolicySyncPrune
+
+	if err := c.ApplyPolicy(diff, prune); err != nil {
+		return err
+	}
+
+	if !prune && len(diff.Removed) > 0 {
+		c.log.Warn("stored casbin policies missing from policy file, run with prune to remove them",
+			zap.Stringers("rules", diff.Removed))
+	}
+
+	c.log.Info("synced casbin policies",
+		zap.String("file", policyPath),
+		zap.String("mode", c.policySync),
+		zap.Int("added", len(diff.Added)),
+		zap.Int("removed", len(diff.Removed)),
+		zap.Int("retired", len(diff.Retired)),
+	)
+
+	return nil
+}
+
+// PolicyMatch — политика, доступная пользователю, с отметкой о том,
+// какие части запроса она покрывает.
+type PolicyMatch struct {
+	Policy        []string `json:"policy"`
+	ObjectMatches bool     `json:"object_matches"`
+	ActionMatches bool     `json:"action_matches"`
+}
+
+// Explanation — результат Explain.
+type Explanation struct {
+	Allowed bool `json:"allowed"`
+	// Matched — политика, разрешившая запрос.
+	Matched []string `json:"matched,omitempty"`
+	// Roles — все роли и разрешения пользователя в домене с учётом наследования.
+	Roles []string `json:"roles"`
+	// Closest — политики ролей пользователя, отсортированные по близости
+	// к запросу: сначала совпавшие и по объекту, и по действию.
+	Closest []PolicyMatch `json:"closest"`
+}
+
+// Explain выполняет Enforce в режиме объяснения и дополняет ответ
+// ближайшими политиками, чтобы при отказе было видно, какого правила
+// не хватает.
+func (c *CasbinClient) Explain(sub, dom, obj, act string, res Resource) (*Explanation, error) {
+	allowed, matched, err := c.enforcer.EnforceEx(sub, dom, obj, act, res)
+	if err != nil {
+		return nil, fmt.Errorf("enforce: %w", err)
+	}
+
+	roles, err := c.enforcer.GetImplicitRolesForUser(sub, dom)
+	if err != nil {
+		return nil, fmt.Errorf("get implicit roles: %w", err)
+	}
+
+	var closest []PolicyMatch
+
+	for _, subject := range append([]string{sub}, roles...) {
+		policies, err := c.enforcer.GetFilteredPolicy(0, subject)
+		if err != nil {
+			return nil, fmt.Errorf("get policies of %q: %w", subject, err)
+		}
+
+		for _, p := range policies {
+			if p[1] != dom && p[1] != AnyDomain {
+				continue
 			}
+
+			closest = append(closest, PolicyMatch{
+				Policy:        p,
+				ObjectMatches: util.KeyMatch(obj, p[2]),
+				ActionMatches: util.RegexMatch(act, p[3]),
+			})
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("scan policy file: %w", err)
+	score := func(m PolicyMatch) int {
+		s := 0
+		if m.ObjectMatches {
+			s += 2
+		}
+
+		if m.ActionMatches {
+			s++
+		}
+
+		return s
 	}
 
-	c.log.Info("seeded default casbin policies", zap.String("file", policyPath))
+	slices.SortStableFunc(closest, func(a, b PolicyMatch) int {
+		return score(b) - score(a)
+	})
 
-	return nil
+	return &Explanation{
+		Allowed: allowed,
+		Matched: matched,
+		Roles:   roles,
+		Closest: closest,
+	}, nil
 }
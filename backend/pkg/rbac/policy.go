@@ -0,0 +1,314 @@
+package rbac
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+)
+
+// Режимы синхронизации policy.csv с БД при запуске.
+const (
+	// PolicySyncAdd добавляет недостающие правила и ничего не удаляет.
+	PolicySyncAdd = "add"
+	// PolicySyncPrune приводит управляемые правила в БД в точное
+	// соответствие с policy.csv.
+	PolicySyncPrune = "prune"
+	// PolicySyncNone не трогает политики в БД.
+	PolicySyncNone = "none"
+)
+
+// PolicyRule — одна строка policy.csv.
+type PolicyRule struct {
+	Line   int
+	Ptype  string
+	Values []string
+}
+
+func (r PolicyRule) String() string {
+	return strings.Join(append([]string{r.Ptype}, r.Values...), ", ")
+}
+
+// PolicyDiff — расхождение между policy.csv и сохранёнными правилами.
+type PolicyDiff struct {
+	// Added — правила из файла, которых нет в БД.
+	Added []PolicyRule
+	// Removed — управляемые правила из БД, которых нет в файле.
+	Removed []PolicyRule
+	// Retired — правила из policy_retired.csv, ещё сохранённые в БД. В
+	// отличие от Removed они удаляются при любой синхронизации.
+	Retired []PolicyRule
+}
+
+func (d PolicyDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retired) == 0
+}
+
+// Управляемыми считаются правила домена "*": policy.csv описывает только
+// их, а правила конкретных организаций (назначения ролей, кастомные роли)
+// всегда привязаны к ID организации и при очистке не затрагиваются.
+// domainIndex — позиция домена в строке правила каждого типа.
+var domainIndex = map[string]int{
+	"p": 1,
+	"g": 2,
+}
+
+func isManaged(ptype string, values []string) bool {
+	i, ok := domainIndex[ptype]
+	return ok && i < len(values) && values[i] == AnyDomain
+}
+
+// ReadPolicyFile разбирает policy.csv. Пустые строки и комментарии
+// пропускаются.
+func ReadPolicyFile(path string) ([]PolicyRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open policy file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	var rules []PolicyRule
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		parts := strings.Split(text, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+
+		rules = append(rules, PolicyRule{
+			Line:   line,
+			Ptype:  parts[0],
+			Values: parts[1:],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan policy file: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ReadRetiredPolicy разбирает policy_retired.csv — правила, убранные из
+// policy.csv, которые нельзя оставлять в БД уже развёрнутых окружений.
+// Отсутствие файла не считается ошибкой.
+func ReadRetiredPolicy(path string) ([]PolicyRule, error) {
+	rules, err := ReadPolicyFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	return rules, err
+}
+
+// ValidateRetired проверяет, что ни одно из выведенных правил не
+// возвращено в policy.csv.
+func ValidateRetired(rules, retired []PolicyRule) error {
+	active := make(map[string]int, len(rules))
+	for _, rule := range rules {
+		active[rule.String()] = rule.Line
+	}
+
+	var errs error
+
+	for _, rule := range retired {
+		if line, ok := active[rule.String()]; ok {
+			errs = errors.Join(errs, fmt.Errorf("line %d (%s): retired rule is still in the policy file at line %d", rule.Line, rule, line))
+		}
+	}
+
+	return errs
+}
+
+// ValidatePolicy проверяет правила на соответствие model.conf: тип правила
+// объявлен в модели, число полей совпадает с определением, поля не пусты,
+// домен равен "*", действие — корректное регулярное выражение,
+// дубликатов нет. Возвращает все найденные ошибки сразу.
+func ValidatePolicy(modelPath string, rules []PolicyRule) error {
+	m, err := model.NewModelFromFile(modelPath)
+	if err != nil {
+		return fmt.Errorf("load model %q: %w", modelPath, err)
+	}
+
+	var errs error
+
+	seen := make(map[string]int, len(rules))
+
+	for _, rule := range rules {
+		fail := func(format string, args ...any) {
+			errs = errors.Join(errs, fmt.Errorf("line %d (%s): %s", rule.Line, rule, fmt.Sprintf(format, args...)))
+		}
+
+		sec, ok := sectionOf(rule.Ptype)
+		if !ok || m[sec][rule.Ptype] == nil {
+			fail("policy type %q is not defined in the model", rule.Ptype)
+			continue
+		}
+
+		tokens := m[sec][rule.Ptype].Tokens
+		if len(rule.Values) != len(tokens) {
+			fail("expected %d fields, got %d", len(tokens), len(rule.Values))
+			continue
+		}
+
+		if slices.Contains(rule.Values, "") {
+			fail("empty field")
+		}
+
+		if !isManaged(rule.Ptype, rule.Values) {
+			fail("domain must be %q; team-specific rules do not belong in the policy file", AnyDomain)
+		}
+
+		if act := slices.Index(tokens, rule.Ptype+"_act"); act >= 0 {
+			if _, err := regexp.Compile(rule.Values[act]); err != nil {
+				fail("invalid action pattern: %v", err)
+			}
+		}
+
+		key := rule.String()
+		if prev, dup := seen[key]; dup {
+			fail("duplicate of line %d", prev)
+		}
+
+		seen[key] = rule.Line
+	}
+
+	return errs
+}
+
+func sectionOf(ptype string) (string, bool) {
+	switch {
+	case strings.HasPrefix(ptype, "p"):
+		return "p", true
+	case strings.HasPrefix(ptype, "g"):
+		return "g", true
+	default:
+		return "", false
+	}
+}
+
+// DiffPolicy сравнивает правила файла с управляемыми правилами,
+// загруженными в enforcer из БД. Сохранённые правила из retired попадают
+// в Retired, а не в Removed.
+func (c *CasbinClient) DiffPolicy(rules, retired []PolicyRule) (PolicyDiff, error) {
+	stored := make(map[string]PolicyRule)
+
+	for ptype := range domainIndex {
+		var (
+			values [][]string
+			err    error
+		)
+
+		if ptype == "g" {
+			values, err = c.enforcer.GetGroupingPolicy()
+		} else {
+			values, err = c.enforcer.GetPolicy()
+		}
+
+		if err != nil {
+			return PolicyDiff{}, fmt.Errorf("get stored %s policies: %w", ptype, err)
+		}
+
+		for _, v := range values {
+			if isManaged(ptype, v) {
+				rule := PolicyRule{Ptype: ptype, Values: v}
+				stored[rule.String()] = rule
+			}
+		}
+	}
+
+	var diff PolicyDiff
+
+	for _, rule := range rules {
+		key := rule.String()
+		if _, ok := stored[key]; ok {
+			delete(stored, key)
+			continue
+		}
+
+		diff.Added = append(diff.Added, rule)
+	}
+
+	for _, rule := range retired {
+		key := rule.String()
+		if _, ok := stored[key]; ok {
+			delete(stored, key)
+			diff.Retired = append(diff.Retired, rule)
+		}
+	}
+
+	for _, rule := range stored {
+		diff.Removed = append(diff.Removed, rule)
+	}
+
+	slices.SortFunc(diff.Removed, func(a, b PolicyRule) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return diff, nil
+}
+
+// ApplyPolicy добавляет правила diff.Added, удаляет diff.Retired и, если
+// prune, diff.Removed. Изменения сохраняются в БД и рассылаются репликам
+// через watcher.
+func (c *CasbinClient) ApplyPolicy(diff PolicyDiff, prune bool) error {
+	for ptype, rules := range groupByPtype(diff.Added) {
+		var err error
+		if ptype == "g" {
+			_, err = c.enforcer.AddGroupingPolicies(rules)
+		} else {
+			_, err = c.enforcer.AddPolicies(rules)
+		}
+
+		if err != nil {
+			return fmt.Errorf("add %s policies: %w", ptype, err)
+		}
+	}
+
+	if err := c.removePolicies(diff.Retired); err != nil {
+		return err
+	}
+
+	if !prune {
+		return nil
+	}
+
+	return c.removePolicies(diff.Removed)
+}
+
+func (c *CasbinClient) removePolicies(removed []PolicyRule) error {
+	for ptype, rules := range groupByPtype(removed) {
+		var err error
+		if ptype == "g" {
+			_, err = c.enforcer.RemoveGroupingPolicies(rules)
+		} else {
+			_, err = c.enforcer.RemovePolicies(rules)
+		}
+
+		if err != nil {
+			return fmt.Errorf("remove %s policies: %w", ptype, err)
+		}
+	}
+
+	return nil
+}
+
+func groupByPtype(rules []PolicyRule) map[string][][]string {
+	grouped := make(map[string][][]string)
+	for _, rule := range rules {
+		grouped[rule.Ptype] = append(grouped[rule.Ptype], rule.Values)
+	}
+
+	return grouped
+}
@@ -0,0 +1,340 @@
+package rbac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	_ persist.WatcherEx        = (*Watcher)(nil)
+	_ persist.UpdatableWatcher = (*Watcher)(nil)
+)
+
+const (
+	// watcherChannel — канал LISTEN/NOTIFY, общий для всех реплик.
+	watcherChannel = "casbin_policy"
+	// maxNotifyPayload чуть меньше лимита Postgres в 8000 байт.
+	// Более крупные изменения рассылаются как команда полной перезагрузки.
+	maxNotifyPayload = 7900
+
+	watcherTimeout    = 5 * time.Second
+	watcherMinBackoff = time.Second
+	watcherMaxBackoff = 30 * time.Second
+)
+
+// Операции, которые передаются между репликами.
+const (
+	opReload         = "reload"
+	opAddPolicies    = "add_policies"
+	opRemovePolicies = "remove_policies"
+	opRemoveFiltered = "remove_filtered_policy"
+	opUpdatePolicies = "update_policies"
+)
+
+// watcherMessage — полезная нагрузка NOTIFY.
+type watcherMessage struct {
+	Instance    string     `json:"instance"`
+	Op          string     `json:"op"`
+	Sec         string     `json:"sec,omitempty"`
+	Ptype       string     `json:"ptype,omitempty"`
+	Rules       [][]string `json:"rules,omitempty"`
+	NewRules    [][]string `json:"new_rules,omitempty"`
+	FieldIndex  int        `json:"field_index,omitempty"`
+	FieldValues []string   `json:"field_values,omitempty"`
+}
+
+// Watcher синхронизирует политики между репликами API через Postgres
+// LISTEN/NOTIFY. Каждое изменение, сделанное через enforcer, рассылается
+// остальным экземплярам, которые применяют его инкрементально.
+// Собственные сообщения экземпляр игнорирует.
+type Watcher struct {
+	log        *zap.Logger
+	connConf   *pgx.ConnConfig
+	instanceID string
+
+	// listener используется только горутиной Listen. До её запуска
+	// соединение закрывает Close.
+	listener *pgx.Conn
+
+	listenMu  sync.Mutex
+	listening bool
+	closed    bool
+
+	notifyMu sync.Mutex
+	notifier *pgx.Conn
+
+	callback func(string)
+
+	healthMu   sync.RWMutex
+	listenErr  error
+	publishErr error
+}
+
+func NewWatcher(log *zap.Logger, connConf *pgx.ConnConfig) *Watcher {
+	return &Watcher{
+		log:        log,
+		connConf:   connConf,
+		instanceID: uuid.New().String(),
+	}
+}
+
+// Connect открывает соединение-подписчик и выполняет LISTEN.
+func (w *Watcher) Connect(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, w.connConf)
+	if err != nil {
+		return fmt.Errorf("connect listener: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{watcherChannel}.Sanitize()); err != nil {
+		_ = conn.Close(ctx)
+		return fmt.Errorf("listen %q: %w", watcherChannel, err)
+	}
+
+	w.listener = conn
+
+	return nil
+}
+
+// Listen получает уведомления до отмены ctx. При обрыве соединения
+// переподключается с экспоненциальной задержкой и вызывает onReconnect,
+// чтобы перечитать политики, изменённые за время простоя.
+func (w *Watcher) Listen(ctx context.Context, onReconnect func()) error {
+	w.listenMu.Lock()
+	if w.closed {
+		w.listenMu.Unlock()
+		return nil
+	}
+	w.listening = true
+	w.listenMu.Unlock()
+
+	defer func() {
+		if w.listener != nil {
+			_ = w.listener.Close(context.Background())
+			w.listener = nil
+		}
+	}()
+
+	backoff := watcherMinBackoff
+
+	for {
+		if w.listener == nil {
+			if err := w.Connect(ctx); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				w.setListenErr(err)
+				w.log.Error("casbin watcher reconnect failed", zap.Error(err), zap.Duration("retry_in", backoff))
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(backoff):
+				}
+
+				backoff = min(backoff*2, watcherMaxBackoff)
+
+				continue
+			}
+
+			backoff = watcherMinBackoff
+			w.setListenErr(nil)
+			w.log.Info("casbin watcher reconnected")
+
+			onReconnect()
+		}
+
+		n, err := w.listener.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			w.setListenErr(err)
+			w.log.Error("casbin watcher disconnected", zap.Error(err))
+
+			_ = w.listener.Close(context.Background())
+			w.listener = nil
+
+			continue
+		}
+
+		w.dispatch(n.Payload)
+	}
+}
+
+func (w *Watcher) dispatch(payload string) {
+	var msg watcherMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		w.log.Error("casbin watcher: malformed message", zap.Error(err))
+		return
+	}
+
+	if msg.Instance == w.instanceID || w.callback == nil {
+		return
+	}
+
+	w.callback(payload)
+}
+
+// Healthy возвращает ошибку, если подписка или рассылка не работают.
+func (w *Watcher) Healthy() error {
+	w.healthMu.RLock()
+	defer w.healthMu.RUnlock()
+
+	var errs error
+	if w.listenErr != nil {
+		errs = errors.Join(errs, fmt.Errorf("casbin watcher listener: %w", w.listenErr))
+	}
+
+	if w.publishErr != nil {
+		errs = errors.Join(errs, fmt.Errorf("casbin watcher publisher: %w", w.publishErr))
+	}
+
+	return errs
+}
+
+func (w *Watcher) setListenErr(err error) {
+	w.healthMu.Lock()
+	w.listenErr = err
+	w.healthMu.Unlock()
+}
+
+func (w *Watcher) setPublishErr(err error) {
+	w.healthMu.Lock()
+	w.publishErr = err
+	w.healthMu.Unlock()
+}
+
+// publish отправляет сообщение остальным репликам. Ошибка рассылки
+// не отменяет уже сохранённое изменение: она логируется и попадает
+// в Healthy, а enforcer получает nil.
+func (w *Watcher) publish(msg watcherMessage) error {
+	msg.Instance = w.instanceID
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal watcher message: %w", err)
+	}
+
+	if len(payload) > maxNotifyPayload {
+		payload, err = json.Marshal(watcherMessage{Instance: w.instanceID, Op: opReload})
+		if err != nil {
+			return fmt.Errorf("marshal watcher message: %w", err)
+		}
+	}
+
+	w.notifyMu.Lock()
+	defer w.notifyMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), watcherTimeout)
+	defer cancel()
+
+	// Одна повторная попытка на свежем соединении, если старое оборвалось.
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.notifier == nil {
+			w.notifier, err = pgx.ConnectConfig(ctx, w.connConf)
+			if err != nil {
+				w.notifier = nil
+				continue
+			}
+		}
+
+		if _, err = w.notifier.Exec(ctx, "SELECT pg_notify($1, $2)", watcherChannel, string(payload)); err == nil {
+			w.setPublishErr(nil)
+			return nil
+		}
+
+		_ = w.notifier.Close(ctx)
+		w.notifier = nil
+	}
+
+	w.setPublishErr(err)
+	w.log.Error("casbin watcher publish failed", zap.String("op", msg.Op), zap.Error(err))
+
+	return nil
+}
+
+func (w *Watcher) SetUpdateCallback(callback func(string)) error {
+	w.callback = callback
+	return nil
+}
+
+func (w *Watcher) Update() error {
+	return w.publish(watcherMessage{Op: opReload})
+}
+
+// Close закрывает соединение для публикации, а также подписчика,
+// если Listen не запускался (например, в CLI). Запущенный Listen
+// закрывает своё соединение сам при отмене ctx.
+func (w *Watcher) Close() {
+	w.listenMu.Lock()
+	w.closed = true
+	if !w.listening && w.listener != nil {
+		_ = w.listener.Close(context.Background())
+		w.listener = nil
+	}
+	w.listenMu.Unlock()
+
+	w.notifyMu.Lock()
+	defer w.notifyMu.Unlock()
+
+	if w.notifier != nil {
+		_ = w.notifier.Close(context.Background())
+		w.notifier = nil
+	}
+}
+
+func (w *Watcher) UpdateForAddPolicy(sec, ptype string, params ...string) error {
+	return w.UpdateForAddPolicies(sec, ptype, params)
+}
+
+func (w *Watcher) UpdateForRemovePolicy(sec, ptype string, params ...string) error {
+	return w.UpdateForRemovePolicies(sec, ptype, params)
+}
+
+func (w *Watcher) UpdateForRemoveFilteredPolicy(sec, ptype string, fieldIndex int, fieldValues ...string) error {
+	return w.publish(watcherMessage{
+		Op:          opRemoveFiltered,
+		Sec:         sec,
+		Ptype:       ptype,
+		FieldIndex:  fieldIndex,
+		FieldValues: fieldValues,
+	})
+}
+
+func (w *Watcher) UpdateForSavePolicy(_ model.Model) error {
+	return w.Update()
+}
+
+func (w *Watcher) UpdateForAddPolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(watcherMessage{Op: opAddPolicies, Sec: sec, Ptype: ptype, Rules: rules})
+}
+
+func (w *Watcher) UpdateForRemovePolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(watcherMessage{Op: opRemovePolicies, Sec: sec, Ptype: ptype, Rules: rules})
+}
+
+func (w *Watcher) UpdateForUpdatePolicy(sec string, ptype string, oldRule, newRule []string) error {
+	return w.UpdateForUpdatePolicies(sec, ptype, [][]string{oldRule}, [][]string{newRule})
+}
+
+func (w *Watcher) UpdateForUpdatePolicies(sec string, ptype string, oldRules, newRules [][]string) error {
+	return w.publish(watcherMessage{
+		Op:       opUpdatePolicies,
+		Sec:      sec,
+		Ptype:    ptype,
+		Rules:    oldRules,
+		NewRules: newRules,
+	})
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Local хранит объекты в каталоге локальной файловой системы. Тип
+// содержимого сохраняется рядом с объектом в файле "<имя>.type".
+type Local struct {
+	root string
+}
+
+var _ Storage = (*Local)(nil)
+
+func NewLocal(root string) (*Local, error) {
+	if root == "" {
+		return nil, errors.New("storage: local root is empty")
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("storage: create root %q: %w", root, err)
+	}
+
+	return &Local{root: root}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put пишет во временный файл и переименовывает его, чтобы читатели
+// не увидели частично записанный объект.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, _ int64, contentType string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("storage: create dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: create temp file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, &ctxReader{ctx: ctx, r: r}); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("storage: write %q: %w", key, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("storage: close %q: %w", key, err)
+	}
+
+	if err := os.WriteFile(p+".type", []byte(contentType), 0o640); err != nil {
+		return fmt.Errorf("storage: write content type: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("storage: rename %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, *Object, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+
+		return nil, nil, fmt.Errorf("storage: open %q: %w", key, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("storage: stat %q: %w", key, err)
+	}
+
+	contentType, _ := os.ReadFile(p + ".type")
+
+	return f, &Object{
+		Key:         key,
+		Size:        info.Size(),
+		ContentType: string(contentType),
+	}, nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: delete %q: %w", key, err)
+	}
+
+	_ = os.Remove(p + ".type")
+
+	return nil
+}
+
+// PresignGet не поддерживается: локальные файлы отдаются только через API.
+func (l *Local) PresignGet(context.Context, string, time.Duration) (string, error) {
+	return "", ErrUnsupported
+}
+
+// ctxReader прерывает копирование при отмене контекста.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(p)
+}
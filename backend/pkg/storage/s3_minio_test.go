@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"backend/pkg/config"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newMinIOTestS3 подключается к MinIO из docker-compose. Тест запускается
+// только при заданном S3_TEST_ENDPOINT, например:
+//
+//	S3_TEST_ENDPOINT=http://localhost:9000 \
+//	S3_TEST_ACCESS_KEY=ai_hr_minio S3_TEST_SECRET_KEY=ai_hr_minio_password \
+//	go test ./pkg/storage -run MinIO
+//
+// Бакет (S3_TEST_BUCKET, по умолчанию storage-test) создаётся при
+// необходимости.
+func newMinIOTestS3(t *testing.T) *S3 {
+	t.Helper()
+
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+
+	bucket := os.Getenv("S3_TEST_BUCKET")
+	if bucket == "" {
+		bucket = "storage-test"
+	}
+
+	s, err := NewS3(config.S3Storage{
+		Endpoint:        endpoint,
+		Bucket:          bucket,
+		AccessKeyID:     os.Getenv("S3_TEST_ACCESS_KEY"),
+		SecretAccessKey: os.Getenv("S3_TEST_SECRET_KEY"),
+		PathStyle:       true,
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, u.String(), http.NoBody)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+
+	resp, err := s.do(req)
+	if err != nil && !strings.Contains(err.Error(), "BucketAlreadyOwnedByYou") {
+		t.Fatalf("create bucket %q: %v", bucket, err)
+	}
+
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+
+	return s
+}
+
+func TestMinIOPutGetDelete(t *testing.T) {
+	s := newMinIOTestS3(t)
+	ctx := context.Background()
+
+	key := "tests/" + time.Now().UTC().Format("20060102T150405.000000000") + "/résumé 1.txt"
+	body := []byte("hello, storage")
+
+	tests := []struct {
+		name string
+		size int64
+	}{
+		{name: "known size", size: int64(len(body))},
+		{name: "unknown size", size: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Put(ctx, key, bytes.NewReader(body), tt.size, "text/plain"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			rc, obj, err := s.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+
+			got, err := io.ReadAll(rc)
+			_ = rc.Close()
+
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+
+			if !bytes.Equal(got, body) {
+				t.Errorf("body = %q, want %q", got, body)
+			}
+
+			if obj.Size != int64(len(body)) || obj.ContentType != "text/plain" {
+				t.Errorf("object = %+v, want size %d and text/plain", obj, len(body))
+			}
+		})
+	}
+
+	link, err := s.PresignGet(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet: %v", err)
+	}
+
+	resp, err := http.Get(link)
+	if err != nil {
+		t.Fatalf("get presigned: %v", err)
+	}
+
+	got, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(got, body) {
+		t.Errorf("presigned get = %d %q, want 200 %q", resp.StatusCode, got, body)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// sniffLen — сколько байт читается для определения типа. Для DOCX и ODT
+// сигнатура лежит в первых записях zip-архива, поэтому берётся больше, чем
+// 512 байт у http.DetectContentType.
+const sniffLen = 3072
+
+// Sniff определяет тип содержимого по первым байтам r. Возвращает тип и
+// reader, который отдаёт поток целиком, включая прочитанное начало.
+func Sniff(r io.Reader) (*mimetype.MIME, io.Reader, error) {
+	head := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, nil, fmt.Errorf("storage: sniff: %w", err)
+	}
+
+	head = head[:n]
+
+	return mimetype.Detect(head), io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// SniffMatches сообщает, совпадает ли определённый тип (или один из его
+// родителей, например zip для DOCX) с одним из allowed.
+func SniffMatches(mtype *mimetype.MIME, allowed ...string) bool {
+	for m := mtype; m != nil; m = m.Parent() {
+		for _, a := range allowed {
+			if m.Is(a) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
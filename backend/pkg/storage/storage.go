@@ -0,0 +1,156 @@
+// Package storage хранит загруженные файлы (резюме, вложения) во внешнем
+// хранилище. Ключ объекта — путь вида "a/b/c.pdf" без ведущего слеша;
+// объекты команды лежат под префиксом TeamKey.
+package storage
+
+import (
+	"backend/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Драйверы хранилища.
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound    = errors.New("storage: object not found")
+	ErrInvalidKey  = errors.New("storage: invalid key")
+	ErrTooLarge    = errors.New("storage: object too large")
+	ErrUnsupported = errors.New("storage: operation not supported by driver")
+)
+
+// Object — метаданные сохранённого объекта.
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+}
+
+// Storage — хранилище объектов.
+type Storage interface {
+	// Put сохраняет объект. size может быть -1, если размер неизвестен.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get открывает объект на чтение. Вызывающий обязан закрыть reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Delete удаляет объект. Отсутствующий объект не считается ошибкой
+	// у всех драйверов, поэтому ErrNotFound проверять не обязательно.
+	Delete(ctx context.Context, key string) error
+	// PresignGet возвращает ссылку на скачивание, действующую ttl.
+	// Драйверы без такой возможности возвращают ErrUnsupported.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New создаёт хранилище по конфигурации. Если задан MaxFileSize, Put
+// отклоняет объекты больше него с ErrTooLarge.
+func New(cfg config.Storage) (Storage, error) {
+	var (
+		s   Storage
+		err error
+	)
+
+	switch cfg.Driver {
+	case "", DriverLocal:
+		s, err = NewLocal(cfg.Local.Root)
+	case DriverS3:
+		s, err = NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxFileSize > 0 {
+		s = &limited{Storage: s, max: cfg.MaxFileSize}
+	}
+
+	return s, nil
+}
+
+// WithLimit возвращает s с пределом размера объектов max вместо заданного в
+// New; max <= 0 снимает предел. Нужен для файлов со своим лимитом, например
+// архивов импорта.
+func WithLimit(s Storage, max int64) Storage {
+	if l, ok := s.(*limited); ok {
+		s = l.Storage
+	}
+
+	if max <= 0 {
+		return s
+	}
+
+	return &limited{Storage: s, max: max}
+}
+
+// TeamKey строит ключ объекта команды: "teams/<teamID>/<parts...>".
+func TeamKey(teamID string, parts ...string) string {
+	return path.Join(append([]string{"teams", teamID}, parts...)...)
+}
+
+// InTeam сообщает, лежит ли ключ под префиксом команды.
+func InTeam(key, teamID string) bool {
+	return teamID != "" && strings.HasPrefix(key, TeamKey(teamID)+"/")
+}
+
+// limited ограничивает размер сохраняемых объектов.
+type limited struct {
+	Storage
+	max int64
+}
+
+func (l *limited) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if size > l.max {
+		return ErrTooLarge
+	}
+
+	return l.Storage.Put(ctx, key, &maxReader{r: r, left: l.max}, size, contentType)
+}
+
+// maxReader возвращает ErrTooLarge, если из r прочитано больше left байт.
+// Нужен, когда размер заранее неизвестен или указан неверно.
+type maxReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (m *maxReader) Read(p []byte) (int, error) {
+	if m.left < 0 {
+		return 0, ErrTooLarge
+	}
+
+	if int64(len(p)) > m.left+1 {
+		p = p[:m.left+1]
+	}
+
+	n, err := m.r.Read(p)
+	m.left -= int64(n)
+
+	if m.left < 0 {
+		return n, ErrTooLarge
+	}
+
+	return n, err
+}
+
+// cleanKey проверяет ключ: он должен быть относительным путём без "..".
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return cleaned, nil
+}